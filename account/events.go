@@ -0,0 +1,71 @@
+package account
+
+import (
+	"gobanking/model"
+	"sync"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before it
+// is dropped.
+const subscriberBuffer = 64
+
+// broker fans committed transactions out to in-process subscribers such as
+// gRPC streams. Events are not persisted and only reach subscribers of this
+// instance; webhooks remain the durable delivery path.
+type broker struct {
+	mu   sync.Mutex
+	subs map[*subscription]struct{}
+}
+
+type subscription struct {
+	noRekening string
+	ch         chan model.Transaksi
+}
+
+func newBroker() *broker {
+	return &broker{subs: make(map[*subscription]struct{})}
+}
+
+// subscribe registers a subscriber for noRekening, or for every account when
+// noRekening is empty. The channel is closed by cancel, or early when the
+// subscriber falls more than subscriberBuffer events behind.
+func (b *broker) subscribe(noRekening string) (<-chan model.Transaksi, func()) {
+	sub := &subscription{noRekening: noRekening, ch: make(chan model.Transaksi, subscriberBuffer)}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(sub)
+	}
+	return sub.ch, cancel
+}
+
+func (b *broker) publish(transaksi model.Transaksi) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		if sub.noRekening != "" && sub.noRekening != transaksi.NoRekening {
+			continue
+		}
+		select {
+		case sub.ch <- transaksi:
+		default:
+			// Never block a transaction on a slow reader; closing tells it
+			// that it missed events.
+			b.remove(sub)
+		}
+	}
+}
+
+// remove must be called with mu held.
+func (b *broker) remove(sub *subscription) {
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
@@ -0,0 +1,79 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gobanking/fx"
+	"gobanking/model"
+	"gobanking/repository"
+	"time"
+)
+
+var (
+	// ErrRateNotFound refuses a cross-currency transfer while either
+	// currency has no rate in effect.
+	ErrRateNotFound = fx.ErrRateNotFound
+	ErrRateExists   = errors.New("kurs dengan waktu berlaku yang sama sudah ada")
+)
+
+// Rates returns the rate in effect now of every currency that has one.
+func (s *Service) Rates(ctx context.Context) ([]model.Kurs, error) {
+	return s.store.FX().ListKursAt(ctx, time.Now())
+}
+
+// SetRates validates and stores a rate sheet in one transaction: either
+// every rate is stored or none is. A rate takes over from the previous one
+// of its currency at its BerlakuMulai.
+func (s *Service) SetRates(ctx context.Context, rates []model.Kurs) error {
+	for _, k := range rates {
+		if err := fx.ValidateRate(k); err != nil {
+			return err
+		}
+	}
+	return s.store.WithinTx(ctx, func(tx repository.Store) error {
+		for i := range rates {
+			err := tx.FX().CreateKurs(ctx, &rates[i])
+			if errors.Is(err, repository.ErrDuplicate) {
+				return fmt.Errorf("%w: %s %s", ErrRateExists, rates[i].MataUang, rates[i].BerlakuMulai.Format(time.RFC3339))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// convert prices a transfer with the rates in effect now, read inside tx so
+// the quote and the legs commit together.
+func convert(ctx context.Context, tx repository.Store, nominal float64, asal, tujuan string) (fx.Quote, error) {
+	return fx.Convert(nominal, asal, tujuan, ratesAt(ctx, tx, time.Now()))
+}
+
+// EquivalentIDR values nominal of mataUang in IDR at the current mid rate.
+func (s *Service) EquivalentIDR(ctx context.Context, mataUang string, nominal float64) (float64, error) {
+	return fx.Equivalent(nominal, mataUang, ratesAt(ctx, s.store, time.Now()))
+}
+
+// ExceedsIDR reports whether nominal of mataUang is worth more than limit,
+// a threshold set in IDR such as the OTP threshold. An amount that cannot
+// be valued for want of a rate counts as exceeding it, so the stricter path
+// is taken.
+func (s *Service) ExceedsIDR(ctx context.Context, mataUang string, nominal, limit float64) (bool, error) {
+	idr, err := s.EquivalentIDR(ctx, mataUang, nominal)
+	if errors.Is(err, ErrRateNotFound) {
+		return true, nil
+	}
+	return idr > limit, err
+}
+
+func ratesAt(ctx context.Context, store repository.Store, t time.Time) fx.RateFunc {
+	return func(mataUang string) (model.Kurs, error) {
+		kurs, err := store.FX().KursAt(ctx, mataUang, t)
+		if errors.Is(err, repository.ErrNotFound) {
+			return kurs, ErrRateNotFound
+		}
+		return kurs, err
+	}
+}
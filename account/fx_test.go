@@ -0,0 +1,171 @@
+package account_test
+
+import (
+	"context"
+	"errors"
+	"gobanking/account"
+	"gobanking/model"
+	"gobanking/notifier"
+	"gobanking/repository"
+	"io"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+)
+
+func newService(t *testing.T) (*account.Service, *repository.MemoryStore) {
+	t.Helper()
+	store := repository.NewMemoryStore()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return account.NewService(store, notifier.LogNotifier{Logger: logger}), store
+}
+
+func openAccount(t *testing.T, s *account.Service, suffix, mataUang string, saldo float64) model.Nasabah {
+	t.Helper()
+	ctx := context.Background()
+	n, err := s.OpenAccount(ctx, account.OpenAccountInput{
+		Nama:     "Nasabah " + suffix,
+		NIK:      "nik-" + suffix,
+		NoHP:     "08" + suffix,
+		MataUang: mataUang,
+	})
+	if err != nil {
+		t.Fatalf("OpenAccount(%s): %v", suffix, err)
+	}
+	if saldo > 0 {
+		if n, err = s.Deposit(ctx, n.NoRekening, saldo, ""); err != nil {
+			t.Fatalf("Deposit(%s): %v", suffix, err)
+		}
+	}
+	return n
+}
+
+// setUSD stores a USD sheet of 14900 buy, 15100 sell: a 15000 mid rate.
+func setUSD(t *testing.T, s *account.Service) {
+	t.Helper()
+	err := s.SetRates(context.Background(), []model.Kurs{{
+		MataUang:     "USD",
+		Beli:         14900,
+		Jual:         15100,
+		BerlakuMulai: time.Now().Add(-time.Minute),
+		Sumber:       "api",
+	}})
+	if err != nil {
+		t.Fatalf("SetRates: %v", err)
+	}
+}
+
+func TestTransferAcrossCurrenciesBooksConversion(t *testing.T) {
+	s, store := newService(t)
+	setUSD(t, s)
+	usd := openAccount(t, s, "1", "USD", 100)
+	idr := openAccount(t, s, "2", "IDR", 0)
+
+	res, err := s.Transfer(context.Background(), account.TransferInput{
+		Sumber:  usd.NoRekening,
+		Tujuan:  idr.NoRekening,
+		Nominal: 10,
+	})
+	if err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+
+	// The bank buys the customer's dollars at 14900 and keeps the spread to
+	// the 15000 mid rate.
+	k := res.Konversi
+	if k == nil {
+		t.Fatal("Konversi = nil for a USD to IDR transfer")
+	}
+	if k.MataUangAsal != "USD" || k.MataUangTujuan != "IDR" || k.NominalTujuan != 149000 || k.LabaRugi != 1000 {
+		t.Fatalf("Konversi = %+v, want 10 USD -> 149000 IDR with 1000 gain", *k)
+	}
+	if res.Debit.MataUang != "USD" || res.Debit.Nominal != 10 {
+		t.Fatalf("Debit = %v %s, want 10 USD", res.Debit.Nominal, res.Debit.MataUang)
+	}
+	if res.Kredit.MataUang != "IDR" || res.Kredit.Nominal != 149000 {
+		t.Fatalf("Kredit = %v %s, want 149000 IDR", res.Kredit.Nominal, res.Kredit.MataUang)
+	}
+	if got, _ := s.Balance(context.Background(), usd.NoRekening); got != 90 {
+		t.Fatalf("USD saldo = %v, want 90", got)
+	}
+	if got, _ := s.Balance(context.Background(), idr.NoRekening); got != 149000 {
+		t.Fatalf("IDR saldo = %v, want 149000", got)
+	}
+
+	var gain *model.JurnalGL
+	for _, j := range store.Jurnal() {
+		if j.TransaksiID == res.Debit.ID && j.Jenis == model.JenisLabaKurs {
+			j := j
+			gain = &j
+		}
+	}
+	if gain == nil {
+		t.Fatal("no laba_kurs journal line for the conversion")
+	}
+	if gain.AkunDebit != model.AkunKliring || gain.AkunKredit != model.AkunPendapatanKurs ||
+		gain.MataUang != "IDR" || gain.Nominal != 1000 {
+		t.Fatalf("laba_kurs = %+v, want 1000 IDR from clearing to FX income", *gain)
+	}
+}
+
+// TestTransferAcrossCurrenciesClearsAtMidRate checks that, valued at the mid
+// rate, the clearing account nets to zero once the FX gain is posted.
+func TestTransferAcrossCurrenciesClearsAtMidRate(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		sumber, tujuan string
+		saldo, nominal float64
+	}{
+		{"USD ke IDR", "USD", "IDR", 100, 10},
+		{"IDR ke USD", "IDR", "USD", 1_000_000, 151_000},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store := newService(t)
+			setUSD(t, s)
+			from := openAccount(t, s, "1", tt.sumber, tt.saldo)
+			to := openAccount(t, s, "2", tt.tujuan, 0)
+
+			res, err := s.Transfer(context.Background(), account.TransferInput{
+				Sumber: from.NoRekening, Tujuan: to.NoRekening, Nominal: tt.nominal,
+			})
+			if err != nil {
+				t.Fatalf("Transfer: %v", err)
+			}
+
+			mid := map[string]float64{"IDR": 1, "USD": 15000}
+			clearing := 0.0
+			for _, j := range store.Jurnal() {
+				if j.TransaksiID != res.Debit.ID && j.TransaksiID != res.Kredit.ID {
+					continue
+				}
+				idr := j.Nominal * mid[j.MataUang]
+				if j.AkunKredit == model.AkunKliring {
+					clearing += idr
+				}
+				if j.AkunDebit == model.AkunKliring {
+					clearing -= idr
+				}
+			}
+			if math.Abs(clearing) > 1e-6 {
+				t.Fatalf("clearing nets to %v IDR, want 0", clearing)
+			}
+		})
+	}
+}
+
+func TestTransferAcrossCurrenciesNeedsRate(t *testing.T) {
+	s, _ := newService(t)
+	usd := openAccount(t, s, "1", "USD", 100)
+	idr := openAccount(t, s, "2", "IDR", 0)
+
+	_, err := s.Transfer(context.Background(), account.TransferInput{
+		Sumber: usd.NoRekening, Tujuan: idr.NoRekening, Nominal: 10,
+	})
+	if !errors.Is(err, account.ErrRateNotFound) {
+		t.Fatalf("Transfer without a USD rate: err = %v, want ErrRateNotFound", err)
+	}
+	if got, _ := s.Balance(context.Background(), usd.NoRekening); got != 100 {
+		t.Fatalf("USD saldo = %v after a refused transfer, want 100", got)
+	}
+}
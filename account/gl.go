@@ -0,0 +1,78 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gobanking/model"
+	"gobanking/repository"
+	"time"
+)
+
+// ErrNoPostingRule means a transaction's Jenis has no GL posting rule, so
+// it cannot be booked.
+var ErrNoPostingRule = errors.New("aturan posting GL tidak ditemukan")
+
+// book records transaksi and its GL journal in tx. Every balance change
+// goes through here so the customer deposit account always matches the
+// sum of balances. A transaksi without a TanggalBisnis gets the current
+// posting date. CreateTransaksi errors are returned unwrapped.
+func book(ctx context.Context, tx repository.Store, transaksi *model.Transaksi) error {
+	if transaksi.TanggalBisnis.IsZero() {
+		tanggal, err := tx.Calendar().TanggalBisnis(ctx)
+		if err != nil {
+			return fmt.Errorf("gagal membaca tanggal bisnis: %w", err)
+		}
+		transaksi.TanggalBisnis = tanggal.Posting()
+	}
+	if err := tx.Nasabah().CreateTransaksi(ctx, transaksi); err != nil {
+		return err
+	}
+
+	aturan, err := tx.GL().AturanPosting(ctx, transaksi.Jenis)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrNoPostingRule, transaksi.Jenis)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.GL().CreateJurnal(ctx, &model.JurnalGL{
+		TransaksiID: transaksi.ID,
+		Jenis:       transaksi.Jenis,
+		AkunDebit:   aturan.AkunDebit,
+		AkunKredit:  aturan.AkunKredit,
+		Nominal:     transaksi.Nominal,
+		MataUang:    transaksi.MataUang,
+		Tanggal:     transaksi.CreatedAt,
+	})
+}
+
+// bookKurs posts the FX gain or loss of konversi, in IDR, on the journal
+// of its debit leg. The clearing account holds both legs in their own
+// currencies, so at mid rates it nets to zero only with this line.
+func bookKurs(ctx context.Context, tx repository.Store, konversi *model.KonversiValas, tanggal time.Time) error {
+	jenis, nominal := model.JenisLabaKurs, konversi.LabaRugi
+	if nominal < 0 {
+		jenis, nominal = model.JenisRugiKurs, -nominal
+	}
+	if nominal == 0 {
+		return nil
+	}
+
+	aturan, err := tx.GL().AturanPosting(ctx, jenis)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrNoPostingRule, jenis)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.GL().CreateJurnal(ctx, &model.JurnalGL{
+		TransaksiID: konversi.TransaksiDebitID,
+		Jenis:       jenis,
+		AkunDebit:   aturan.AkunDebit,
+		AkunKredit:  aturan.AkunKredit,
+		Nominal:     nominal,
+		MataUang:    model.MataUangIDR,
+		Tanggal:     tanggal,
+	})
+}
@@ -0,0 +1,78 @@
+package account_test
+
+import (
+	"context"
+	"gobanking/account"
+	"gobanking/model"
+	"math"
+	"testing"
+)
+
+// TestJournalTracksBalances books one of every customer movement and checks,
+// per currency, that the customer deposit account holds exactly the sum of
+// the balances and that every account of the journal nets to zero.
+func TestJournalTracksBalances(t *testing.T) {
+	ctx := context.Background()
+	s, store := newService(t)
+	setUSD(t, s)
+	a := openAccount(t, s, "1", "IDR", 1_000_000)
+	b := openAccount(t, s, "2", "IDR", 0)
+	c := openAccount(t, s, "3", "USD", 100)
+
+	if _, err := s.Withdraw(ctx, a.NoRekening, 150_000); err != nil {
+		t.Fatalf("Withdraw: %v", err)
+	}
+	if _, err := s.Transfer(ctx, account.TransferInput{Sumber: a.NoRekening, Tujuan: b.NoRekening, Nominal: 200_000}); err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+	if _, err := s.Transfer(ctx, account.TransferInput{Sumber: c.NoRekening, Tujuan: a.NoRekening, Nominal: 10}); err != nil {
+		t.Fatalf("Transfer USD: %v", err)
+	}
+	if _, err := s.Transfer(ctx, account.TransferInput{Sumber: b.NoRekening, Tujuan: c.NoRekening, Nominal: 151_000}); err != nil {
+		t.Fatalf("Transfer IDR to USD: %v", err)
+	}
+	if _, err := s.Deposit(ctx, b.NoRekening, 50_000, ""); err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	tabung := lastTransaksi(t, s, store, b.NoRekening, model.JenisTabung)
+	if _, err := s.Reverse(ctx, account.ReverseInput{TransaksiID: tabung.ID}); err != nil {
+		t.Fatalf("Reverse: %v", err)
+	}
+
+	saldo := map[string]float64{}
+	for _, n := range []model.Nasabah{a, b, c} {
+		got, err := s.Account(ctx, n.NoRekening)
+		if err != nil {
+			t.Fatalf("Account: %v", err)
+		}
+		saldo[got.MataUang] += got.Saldo
+	}
+
+	// net is debit minus credit per currency and account.
+	net := map[string]map[string]float64{}
+	for _, j := range store.Jurnal() {
+		if j.AkunDebit == j.AkunKredit {
+			t.Fatalf("journal line %+v debits and credits the same account", j)
+		}
+		if net[j.MataUang] == nil {
+			net[j.MataUang] = map[string]float64{}
+		}
+		net[j.MataUang][j.AkunDebit] += j.Nominal
+		net[j.MataUang][j.AkunKredit] -= j.Nominal
+	}
+	for mataUang, accounts := range net {
+		sum := 0.0
+		for _, v := range accounts {
+			sum += v
+		}
+		if math.Abs(sum) > 1e-6 {
+			t.Errorf("%s journal nets to %v, want 0", mataUang, sum)
+		}
+		if liabilitas := -accounts[model.AkunSimpananNasabah]; math.Abs(liabilitas-saldo[mataUang]) > 1e-6 {
+			t.Errorf("%s customer deposits = %v, want the balances' sum %v", mataUang, liabilitas, saldo[mataUang])
+		}
+	}
+	if len(net) != 2 {
+		t.Fatalf("journal currencies = %v, want IDR and USD", net)
+	}
+}
@@ -0,0 +1,57 @@
+package account_test
+
+import (
+	"context"
+	"gobanking/account"
+	"gobanking/notifier"
+	"gobanking/notifier/notifiertest"
+	"gobanking/repository"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestDepositEmailsCustomer deposits into an account with an email address
+// and checks the English deposit notice reaches the fake SMTP server.
+func TestDepositEmailsCustomer(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv, err := notifiertest.NewSMTPServer()
+	if err != nil {
+		t.Fatalf("NewSMTPServer: %v", err)
+	}
+	defer srv.Close()
+
+	router := notifier.NewRouter()
+	router.Register(notifier.ChannelSMS, notifier.LogNotifier{Logger: logger})
+	router.Register(notifier.ChannelEmail, notifier.SMTPNotifier{Addr: srv.Addr(), From: "bank@example.com"})
+	async := notifier.NewAsync(router, logger, 8)
+	async.Start(1)
+	s := account.NewService(repository.NewMemoryStore(), async)
+
+	ctx := context.Background()
+	n, err := s.OpenAccount(ctx, account.OpenAccountInput{
+		Nama:   "Siti",
+		NIK:    "nik-1",
+		NoHP:   "081",
+		Email:  "siti@example.com",
+		Bahasa: notifier.LangEN,
+	})
+	if err != nil {
+		t.Fatalf("OpenAccount: %v", err)
+	}
+	if _, err := s.Deposit(ctx, n.NoRekening, 75000, ""); err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	async.Close()
+
+	mails := srv.Mails()
+	if len(mails) != 1 || mails[0].To[0] != "siti@example.com" {
+		t.Fatalf("mails = %+v, want one to siti@example.com", mails)
+	}
+	if !strings.Contains(mails[0].Data, "Subject: Deposit received") ||
+		!strings.Contains(mails[0].Data, account.MaskNoRekening(n.NoRekening)) ||
+		strings.Contains(mails[0].Data, n.NoRekening) {
+		t.Fatalf("mail is not the masked English deposit notice:\n%s", mails[0].Data)
+	}
+}
@@ -0,0 +1,130 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"gobanking/metrics"
+	"gobanking/model"
+	"gobanking/repository"
+	"gobanking/tracing"
+	"gobanking/webhook"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var ErrNotPostable = errors.New("jenis posting harus bunga, biaya atau pajak")
+
+// PostingInput is an entry the bank books on an account itself: interest
+// credited, or a fee or withheld tax debited. A non-empty Referensi makes
+// the posting idempotent per account.
+type PostingInput struct {
+	NoRekening string
+	// Jenis is model.JenisBunga, JenisBiaya or JenisPajak.
+	Jenis     string
+	Nominal   float64
+	Referensi string
+	// TanggalBisnis is the business date to book on; the current posting
+	// date when zero.
+	TanggalBisnis time.Time
+}
+
+// Post books a bank-initiated entry. Frozen accounts are posted to like
+// any other; a debit larger than the balance fails with
+// ErrInsufficientFunds. If Referensi was already used on the account
+// nothing is booked and ErrDuplicateReference is returned with the earlier
+// entry. The customer is not notified; the entry shows on the statement.
+func (s *Service) Post(ctx context.Context, in PostingInput) (model.Transaksi, error) {
+	var event string
+	switch in.Jenis {
+	case model.JenisBunga:
+		event = webhook.EventBunga
+	case model.JenisBiaya:
+		event = webhook.EventBiaya
+	case model.JenisPajak:
+		event = webhook.EventPajak
+	default:
+		return model.Transaksi{}, ErrNotPostable
+	}
+	if in.Nominal <= 0 {
+		return model.Transaksi{}, ErrInvalidAmount
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, "nasabah.posting", trace.WithAttributes(
+		attribute.String("jenis", in.Jenis),
+	))
+	defer span.End()
+
+	var transaksi, previous model.Transaksi
+	err := s.store.WithinTx(ctx, func(tx repository.Store) error {
+		var err error
+		previous, err = tx.Nasabah().FindTransaksiByReferensi(ctx, in.NoRekening, in.Referensi)
+		if err == nil {
+			return ErrDuplicateReference
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+
+		nasabah, err := tx.Nasabah().LockByNoRekening(ctx, in.NoRekening)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAccountNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if in.Jenis == model.JenisBunga {
+			nasabah.Saldo += in.Nominal
+		} else {
+			if nasabah.Saldo < in.Nominal {
+				return ErrInsufficientFunds
+			}
+			nasabah.Saldo -= in.Nominal
+		}
+		if err := tx.Nasabah().UpdateSaldo(ctx, nasabah.ID, nasabah.Saldo); err != nil {
+			return err
+		}
+
+		transaksi = model.Transaksi{
+			NasabahID:     nasabah.ID,
+			NoRekening:    nasabah.NoRekening,
+			Jenis:         in.Jenis,
+			Nominal:       in.Nominal,
+			SaldoAkhir:    nasabah.Saldo,
+			MataUang:      nasabah.MataUang,
+			Referensi:     in.Referensi,
+			TanggalBisnis: in.TanggalBisnis,
+		}
+		err = book(ctx, tx, &transaksi)
+		if errors.Is(err, repository.ErrDuplicate) {
+			return ErrDuplicateReference
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Outbox().Enqueue(ctx, event, nasabah.NoRekening, transaksi)
+	})
+	switch {
+	case errors.Is(err, ErrDuplicateReference):
+		if previous.ID == 0 {
+			previous, _ = s.store.Nasabah().FindTransaksiByReferensi(ctx, in.NoRekening, in.Referensi)
+		}
+		return previous, err
+	case errors.Is(err, ErrInsufficientFunds):
+		metrics.InsufficientBalanceTotal.Inc()
+		return transaksi, err
+	case err != nil:
+		if !errors.Is(err, ErrAccountNotFound) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return transaksi, err
+	}
+
+	metrics.TransactionsTotal.WithLabelValues(in.Jenis).Inc()
+	metrics.TransactionAmountTotal.WithLabelValues(in.Jenis, transaksi.MataUang).Add(in.Nominal)
+	s.events.publish(transaksi)
+	return transaksi, nil
+}
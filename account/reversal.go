@@ -0,0 +1,174 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"gobanking/metrics"
+	"gobanking/model"
+	"gobanking/notifier"
+	"gobanking/repository"
+	"gobanking/tracing"
+	"gobanking/webhook"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrTransaksiNotFound = errors.New("transaksi tidak ditemukan")
+	ErrNotReversible     = errors.New("transaksi tidak dapat dikoreksi")
+	ErrAlreadyReversed   = errors.New("transaksi sudah dikoreksi")
+)
+
+// ReverseInput identifies the Transaksi to reverse. Alasan is passed on to
+// the customer notification.
+type ReverseInput struct {
+	TransaksiID uint
+	Alasan      string
+	// AllowOverdraft lets the reversal of a deposit that has already been
+	// spent take the balance below zero. Without it such a reversal fails
+	// with ErrInsufficientFunds.
+	AllowOverdraft bool
+}
+
+// ReverseResult carries the account after the koreksi entry was booked.
+type ReverseResult struct {
+	Nasabah   model.Nasabah
+	Koreksi   model.Transaksi
+	Overdraft bool
+}
+
+// Transaksi returns a journal entry by ID.
+func (s *Service) Transaksi(ctx context.Context, id uint) (model.Transaksi, error) {
+	transaksi, err := s.store.Nasabah().FindTransaksiByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return transaksi, ErrTransaksiNotFound
+	}
+	return transaksi, err
+}
+
+// Reverse books an equal and opposite koreksi entry for a tabung or tarik,
+// linked to it through ReversalOfID: a tabung is undone by a koreksi_debit
+// and a tarik by a koreksi_kredit. Transfers and koreksi entries cannot be
+// reversed. Frozen accounts are corrected like any other.
+//
+// A transaction is reversed at most once. A second attempt returns
+// ErrAlreadyReversed with the existing koreksi in Koreksi.
+func (s *Service) Reverse(ctx context.Context, in ReverseInput) (ReverseResult, error) {
+	ctx, span := tracing.Tracer().Start(ctx, "nasabah.koreksi", trace.WithAttributes(
+		attribute.Int64("transaksi_id", int64(in.TransaksiID)),
+	))
+	defer span.End()
+
+	var result ReverseResult
+	err := s.store.WithinTx(ctx, func(tx repository.Store) error {
+		original, err := tx.Nasabah().FindTransaksiByID(ctx, in.TransaksiID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTransaksiNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if original.Jenis != model.JenisTabung && original.Jenis != model.JenisTarik {
+			return ErrNotReversible
+		}
+		previous, err := tx.Nasabah().FindReversalOf(ctx, original.ID)
+		if err == nil {
+			result.Koreksi = previous
+			return ErrAlreadyReversed
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+
+		nasabah, err := tx.Nasabah().LockByNoRekening(ctx, original.NoRekening)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAccountNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		koreksi := model.Transaksi{
+			NasabahID:    nasabah.ID,
+			NoRekening:   nasabah.NoRekening,
+			Nominal:      original.Nominal,
+			MataUang:     original.MataUang,
+			ReversalOfID: &original.ID,
+		}
+		overdraft := false
+		if original.Jenis == model.JenisTabung {
+			koreksi.Jenis = model.JenisKoreksiDebit
+			// The deposit may have been spent in the meantime.
+			overdraft = nasabah.Saldo < original.Nominal
+			if overdraft && !in.AllowOverdraft {
+				result.Nasabah = nasabah
+				return ErrInsufficientFunds
+			}
+			nasabah.Saldo -= original.Nominal
+		} else {
+			koreksi.Jenis = model.JenisKoreksiKredit
+			nasabah.Saldo += original.Nominal
+		}
+		koreksi.SaldoAkhir = nasabah.Saldo
+
+		if overdraft {
+			err = tx.Nasabah().Overdraw(ctx, nasabah.ID, nasabah.Saldo)
+			if nasabah.OverdraftAt == nil {
+				now := time.Now()
+				nasabah.OverdraftAt = &now
+			}
+		} else {
+			err = tx.Nasabah().UpdateSaldo(ctx, nasabah.ID, nasabah.Saldo)
+			if nasabah.Saldo >= 0 {
+				nasabah.OverdraftAt = nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		err = book(ctx, tx, &koreksi)
+		if errors.Is(err, repository.ErrDuplicate) {
+			// A concurrent reversal of the same transaction won.
+			return ErrAlreadyReversed
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Outbox().Enqueue(ctx, webhook.EventKoreksi, nasabah.NoRekening, koreksi); err != nil {
+			return err
+		}
+
+		result = ReverseResult{Nasabah: nasabah, Koreksi: koreksi, Overdraft: overdraft}
+		return nil
+	})
+	switch {
+	case errors.Is(err, ErrAlreadyReversed):
+		if result.Koreksi.ID == 0 {
+			result.Koreksi, _ = s.store.Nasabah().FindReversalOf(ctx, in.TransaksiID)
+		}
+		return ReverseResult{Koreksi: result.Koreksi}, err
+	case errors.Is(err, ErrInsufficientFunds):
+		return result, err
+	case err != nil:
+		if !errors.Is(err, ErrTransaksiNotFound) && !errors.Is(err, ErrNotReversible) && !errors.Is(err, ErrAccountNotFound) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return ReverseResult{}, err
+	}
+
+	k := result.Koreksi
+	metrics.TransactionsTotal.WithLabelValues(k.Jenis).Inc()
+	metrics.TransactionAmountTotal.WithLabelValues(k.Jenis, k.MataUang).Add(k.Nominal)
+	s.events.publish(k)
+	s.NotifyCustomer(ctx, result.Nasabah, notifier.TemplateKoreksi, map[string]any{
+		"Nominal": k.Nominal,
+		"Saldo":   result.Nasabah.Saldo,
+		"Alasan":  in.Alasan,
+	})
+	return result, nil
+}
@@ -0,0 +1,146 @@
+package account_test
+
+import (
+	"context"
+	"errors"
+	"gobanking/account"
+	"gobanking/model"
+	"gobanking/repository"
+	"testing"
+)
+
+// lastTransaksi returns the newest entry of jenis on noRekening, found
+// through its journal line.
+func lastTransaksi(t *testing.T, s *account.Service, store *repository.MemoryStore, noRekening, jenis string) model.Transaksi {
+	t.Helper()
+	jurnal := store.Jurnal()
+	for i := len(jurnal) - 1; i >= 0; i-- {
+		if jurnal[i].Jenis != jenis {
+			continue
+		}
+		transaksi, err := s.Transaksi(context.Background(), jurnal[i].TransaksiID)
+		if err != nil {
+			t.Fatalf("Transaksi(%d): %v", jurnal[i].TransaksiID, err)
+		}
+		if transaksi.NoRekening == noRekening {
+			return transaksi
+		}
+	}
+	t.Fatalf("no %s entry on %s", jenis, noRekening)
+	return model.Transaksi{}
+}
+
+func TestReverseDepositOnce(t *testing.T) {
+	ctx := context.Background()
+	s, store := newService(t)
+	n := openAccount(t, s, "1", "IDR", 100000)
+	tabung := lastTransaksi(t, s, store, n.NoRekening, model.JenisTabung)
+
+	res, err := s.Reverse(ctx, account.ReverseInput{TransaksiID: tabung.ID, Alasan: "salah setor"})
+	if err != nil {
+		t.Fatalf("Reverse: %v", err)
+	}
+	k := res.Koreksi
+	if k.Jenis != model.JenisKoreksiDebit || k.Nominal != 100000 || k.ReversalOfID == nil || *k.ReversalOfID != tabung.ID {
+		t.Fatalf("Koreksi = %+v, want a 100000 koreksi_debit of %d", k, tabung.ID)
+	}
+	if res.Overdraft || res.Nasabah.Saldo != 0 || k.SaldoAkhir != 0 {
+		t.Fatalf("saldo = %v (SaldoAkhir %v, overdraft %v), want 0 without overdraft", res.Nasabah.Saldo, k.SaldoAkhir, res.Overdraft)
+	}
+	// The koreksi undoes the deposit's journal line: customer deposits
+	// debited, cash credited.
+	var lines []model.JurnalGL
+	for _, j := range store.Jurnal() {
+		if j.TransaksiID == k.ID {
+			lines = append(lines, j)
+		}
+	}
+	if len(lines) != 1 || lines[0].AkunDebit != model.AkunSimpananNasabah || lines[0].AkunKredit != model.AkunKas || lines[0].Nominal != 100000 {
+		t.Fatalf("koreksi journal = %+v, want 100000 from customer deposits to cash", lines)
+	}
+
+	again, err := s.Reverse(ctx, account.ReverseInput{TransaksiID: tabung.ID})
+	if !errors.Is(err, account.ErrAlreadyReversed) || again.Koreksi.ID != k.ID {
+		t.Fatalf("second Reverse = %d, %v; want ErrAlreadyReversed with koreksi %d", again.Koreksi.ID, err, k.ID)
+	}
+	if saldo, _ := s.Balance(ctx, n.NoRekening); saldo != 0 {
+		t.Fatalf("saldo after second Reverse = %v, want 0", saldo)
+	}
+}
+
+func TestReverseWithdrawal(t *testing.T) {
+	ctx := context.Background()
+	s, store := newService(t)
+	n := openAccount(t, s, "1", "IDR", 100000)
+	if _, err := s.Withdraw(ctx, n.NoRekening, 30000); err != nil {
+		t.Fatalf("Withdraw: %v", err)
+	}
+	tarik := lastTransaksi(t, s, store, n.NoRekening, model.JenisTarik)
+
+	res, err := s.Reverse(ctx, account.ReverseInput{TransaksiID: tarik.ID})
+	if err != nil {
+		t.Fatalf("Reverse: %v", err)
+	}
+	if res.Koreksi.Jenis != model.JenisKoreksiKredit || res.Nasabah.Saldo != 100000 {
+		t.Fatalf("Reverse = %s, saldo %v; want koreksi_kredit back to 100000", res.Koreksi.Jenis, res.Nasabah.Saldo)
+	}
+}
+
+// TestReverseSpentDeposit reverses a deposit that was mostly withdrawn
+// again: refused without AllowOverdraft, booked into a negative balance
+// with it.
+func TestReverseSpentDeposit(t *testing.T) {
+	ctx := context.Background()
+	s, store := newService(t)
+	n := openAccount(t, s, "1", "IDR", 100000)
+	tabung := lastTransaksi(t, s, store, n.NoRekening, model.JenisTabung)
+	if _, err := s.Withdraw(ctx, n.NoRekening, 80000); err != nil {
+		t.Fatalf("Withdraw: %v", err)
+	}
+
+	_, err := s.Reverse(ctx, account.ReverseInput{TransaksiID: tabung.ID})
+	if !errors.Is(err, account.ErrInsufficientFunds) {
+		t.Fatalf("Reverse without overdraft: err = %v, want ErrInsufficientFunds", err)
+	}
+	if saldo, _ := s.Balance(ctx, n.NoRekening); saldo != 20000 {
+		t.Fatalf("saldo after refused Reverse = %v, want 20000", saldo)
+	}
+
+	res, err := s.Reverse(ctx, account.ReverseInput{TransaksiID: tabung.ID, AllowOverdraft: true})
+	if err != nil {
+		t.Fatalf("Reverse with overdraft: %v", err)
+	}
+	if !res.Overdraft || res.Nasabah.Saldo != -80000 || res.Koreksi.SaldoAkhir != -80000 {
+		t.Fatalf("Reverse = saldo %v, overdraft %v; want -80000 and overdraft", res.Nasabah.Saldo, res.Overdraft)
+	}
+	got, err := s.Account(ctx, n.NoRekening)
+	if err != nil {
+		t.Fatalf("Account: %v", err)
+	}
+	if got.Saldo != -80000 || got.OverdraftAt == nil {
+		t.Fatalf("account = saldo %v, overdraft_at %v; want -80000 and set", got.Saldo, got.OverdraftAt)
+	}
+	if _, err := s.Withdraw(ctx, n.NoRekening, 1000); err == nil {
+		t.Fatal("Withdraw from an overdrawn account succeeded")
+	}
+}
+
+func TestReverseRefusesOtherEntries(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newService(t)
+	from := openAccount(t, s, "1", "IDR", 100000)
+	to := openAccount(t, s, "2", "IDR", 0)
+	res, err := s.Transfer(ctx, account.TransferInput{Sumber: from.NoRekening, Tujuan: to.NoRekening, Nominal: 10000})
+	if err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+
+	for _, id := range []uint{res.Debit.ID, res.Kredit.ID} {
+		if _, err := s.Reverse(ctx, account.ReverseInput{TransaksiID: id}); !errors.Is(err, account.ErrNotReversible) {
+			t.Fatalf("Reverse transfer entry %d: err = %v, want ErrNotReversible", id, err)
+		}
+	}
+	if _, err := s.Reverse(ctx, account.ReverseInput{TransaksiID: 999999}); !errors.Is(err, account.ErrTransaksiNotFound) {
+		t.Fatalf("Reverse unknown entry: err = %v, want ErrTransaksiNotFound", err)
+	}
+}
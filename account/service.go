@@ -0,0 +1,364 @@
+// Package account holds the customer account rules (opening, deposits,
+// withdrawals, transfers, beneficiaries) independent of any transport, so
+// HTTP handlers, gRPC and batch jobs share one implementation.
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gobanking/logging"
+	"gobanking/metrics"
+	"gobanking/model"
+	"gobanking/notifier"
+	"gobanking/repository"
+	"gobanking/tracing"
+	"gobanking/webhook"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrAccountNotFound     = errors.New("rekening tidak ditemukan")
+	ErrDuplicateAccount    = errors.New("NIK atau No Handphone sudah terdaftar")
+	ErrInsufficientFunds   = errors.New("saldo tidak mencukupi")
+	ErrAccountFrozen       = errors.New("rekening dibekukan")
+	ErrInvalidAmount       = errors.New("nominal harus lebih dari 0")
+	ErrBeneficiaryNotFound = errors.New("rekening tujuan tidak ditemukan")
+	ErrBeneficiaryExists   = errors.New("penerima sudah terdaftar")
+	ErrNotBeneficiary      = errors.New("rekening tujuan belum terdaftar sebagai penerima")
+	ErrPhoneInUse          = errors.New("No Handphone sudah terdaftar")
+)
+
+// OpenAccountInput is what a customer supplies to open an account.
+type OpenAccountInput struct {
+	Nama   string
+	NIK    string
+	NoHP   string
+	Email  string
+	Bahasa string
+	// MataUang is the ISO 4217 account currency, IDR when empty.
+	MataUang string
+}
+
+// Service is the AccountService. Every method takes the caller's context
+// and returns one of the errors above for rule violations.
+type Service struct {
+	store    repository.Store
+	notifier notifier.Notifier
+	events   *broker
+}
+
+func NewService(store repository.Store, n notifier.Notifier) *Service {
+	return &Service{store: store, notifier: n, events: newBroker()}
+}
+
+// OpenAccount registers a customer with a new account number and zero
+// balance.
+func (s *Service) OpenAccount(ctx context.Context, in OpenAccountInput) (model.Nasabah, error) {
+	exists, err := s.store.Nasabah().ExistsByNIKOrNoHP(ctx, in.NIK, in.NoHP)
+	if err != nil {
+		return model.Nasabah{}, err
+	}
+	if exists {
+		return model.Nasabah{}, ErrDuplicateAccount
+	}
+
+	nasabah := model.Nasabah{
+		Nama:       in.Nama,
+		NIK:        in.NIK,
+		NoHP:       in.NoHP,
+		Email:      in.Email,
+		Bahasa:     in.Bahasa,
+		MataUang:   in.MataUang,
+		NoRekening: generateNoRekening(),
+		Saldo:      0,
+	}
+	err = s.store.Nasabah().Create(ctx, &nasabah)
+	if errors.Is(err, repository.ErrDuplicate) {
+		// Lost a race with a concurrent registration.
+		return model.Nasabah{}, ErrDuplicateAccount
+	}
+	return nasabah, err
+}
+
+// Account returns the customer owning noRekening.
+func (s *Service) Account(ctx context.Context, noRekening string) (model.Nasabah, error) {
+	nasabah, err := s.store.Nasabah().FindByNoRekening(ctx, noRekening)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nasabah, ErrAccountNotFound
+	}
+	return nasabah, err
+}
+
+// Balance returns the current balance of noRekening.
+func (s *Service) Balance(ctx context.Context, noRekening string) (float64, error) {
+	nasabah, err := s.Account(ctx, noRekening)
+	return nasabah.Saldo, err
+}
+
+// Deposit adds nominal to the account and returns it with the new balance.
+// A non-empty referensi, the channel's own reference for the deposit, is
+// what reconciliation matches settlement files on; reusing one on the same
+// account books nothing and returns ErrDuplicateReference.
+func (s *Service) Deposit(ctx context.Context, noRekening string, nominal float64, referensi string) (model.Nasabah, error) {
+	return s.mutasi(ctx, noRekening, nominal, model.JenisTabung, referensi)
+}
+
+// Withdraw takes nominal from the account. On ErrInsufficientFunds the
+// returned account carries the unchanged balance.
+func (s *Service) Withdraw(ctx context.Context, noRekening string, nominal float64) (model.Nasabah, error) {
+	return s.mutasi(ctx, noRekening, nominal, model.JenisTarik, "")
+}
+
+// Subscribe streams the transactions committed by this instance for
+// noRekening, or for every account when it is empty. The channel closes
+// after cancel, or early if the reader falls too far behind.
+func (s *Service) Subscribe(noRekening string) (<-chan model.Transaksi, func()) {
+	return s.events.subscribe(noRekening)
+}
+
+// AddBeneficiary saves noRekeningTujuan as a transfer beneficiary of
+// noRekening. Both accounts must exist.
+func (s *Service) AddBeneficiary(ctx context.Context, noRekening, noRekeningTujuan, alias string) (model.Penerima, error) {
+	if err := s.CheckBeneficiary(ctx, noRekening, noRekeningTujuan); err != nil {
+		return model.Penerima{}, err
+	}
+
+	penerima := model.Penerima{
+		NoRekening:       noRekening,
+		NoRekeningTujuan: noRekeningTujuan,
+		Alias:            alias,
+	}
+	err := s.store.Nasabah().CreatePenerima(ctx, &penerima)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return penerima, ErrBeneficiaryExists
+	}
+	return penerima, err
+}
+
+// CheckBeneficiary validates both accounts before a beneficiary is added.
+func (s *Service) CheckBeneficiary(ctx context.Context, noRekening, noRekeningTujuan string) error {
+	if _, err := s.Account(ctx, noRekening); err != nil {
+		return err
+	}
+	_, err := s.Account(ctx, noRekeningTujuan)
+	if errors.Is(err, ErrAccountNotFound) {
+		return ErrBeneficiaryNotFound
+	}
+	return err
+}
+
+// Beneficiaries lists the saved beneficiaries of noRekening.
+func (s *Service) Beneficiaries(ctx context.Context, noRekening string) ([]model.Penerima, error) {
+	return s.store.Nasabah().ListPenerima(ctx, noRekening)
+}
+
+// BeneficiarySet returns the saved beneficiaries of noRekening keyed by
+// account number, for callers that check many destinations at once.
+func (s *Service) BeneficiarySet(ctx context.Context, noRekening string) (map[string]bool, error) {
+	penerima, err := s.Beneficiaries(ctx, noRekening)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(penerima))
+	for _, p := range penerima {
+		set[p.NoRekeningTujuan] = true
+	}
+	return set, nil
+}
+
+// CheckRegisteredBeneficiary returns ErrNotBeneficiary unless
+// noRekeningTujuan is a saved beneficiary of noRekening. Transfers that run
+// without the customer present, such as payroll rows and standing orders,
+// may only credit saved beneficiaries, which were confirmed by OTP.
+func (s *Service) CheckRegisteredBeneficiary(ctx context.Context, noRekening, noRekeningTujuan string) error {
+	set, err := s.BeneficiarySet(ctx, noRekening)
+	if err != nil {
+		return err
+	}
+	if !set[noRekeningTujuan] {
+		return ErrNotBeneficiary
+	}
+	return nil
+}
+
+// CheckPhoneAvailable reports ErrPhoneInUse when noHP belongs to another
+// account.
+func (s *Service) CheckPhoneAvailable(ctx context.Context, noRekening, noHP string) error {
+	used, err := s.store.Nasabah().NoHPUsedByOther(ctx, noHP, noRekening)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrPhoneInUse
+	}
+	return nil
+}
+
+// ChangePhone moves the account to a new phone number.
+func (s *Service) ChangePhone(ctx context.Context, noRekening, noHP string) error {
+	err := s.store.Nasabah().UpdateNoHP(ctx, noRekening, noHP)
+	switch {
+	case errors.Is(err, repository.ErrDuplicate):
+		return ErrPhoneInUse
+	case errors.Is(err, repository.ErrNotFound):
+		return ErrAccountNotFound
+	}
+	return err
+}
+
+// mutasi applies a deposit or withdrawal under a row lock, journals it as a
+// Transaksi and queues the matching webhook event in the same transaction.
+// Subscribers and the customer are notified once it commits.
+func (s *Service) mutasi(ctx context.Context, noRekening string, nominal float64, jenis, referensi string) (model.Nasabah, error) {
+	if nominal <= 0 {
+		return model.Nasabah{}, ErrInvalidAmount
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, "nasabah.mutasi", trace.WithAttributes(
+		attribute.String("jenis", jenis),
+	))
+	defer span.End()
+
+	var (
+		nasabah   model.Nasabah
+		transaksi model.Transaksi
+	)
+	err := s.store.WithinTx(ctx, func(tx repository.Store) error {
+		if referensi != "" {
+			_, err := tx.Nasabah().FindTransaksiByReferensi(ctx, noRekening, referensi)
+			if err == nil {
+				return ErrDuplicateReference
+			}
+			if !errors.Is(err, repository.ErrNotFound) {
+				return err
+			}
+		}
+
+		var err error
+		nasabah, err = tx.Nasabah().LockByNoRekening(ctx, noRekening)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAccountNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if nasabah.FrozenAt != nil {
+			return ErrAccountFrozen
+		}
+
+		event := webhook.EventTabung
+		if jenis == model.JenisTarik {
+			if nasabah.Saldo < nominal {
+				return ErrInsufficientFunds
+			}
+			nasabah.Saldo -= nominal
+			event = webhook.EventTarik
+		} else {
+			nasabah.Saldo += nominal
+		}
+
+		if err := tx.Nasabah().UpdateSaldo(ctx, nasabah.ID, nasabah.Saldo); err != nil {
+			return err
+		}
+
+		transaksi = model.Transaksi{
+			NasabahID:  nasabah.ID,
+			NoRekening: nasabah.NoRekening,
+			Jenis:      jenis,
+			Nominal:    nominal,
+			SaldoAkhir: nasabah.Saldo,
+			MataUang:   nasabah.MataUang,
+			Referensi:  referensi,
+		}
+		err = book(ctx, tx, &transaksi)
+		if errors.Is(err, repository.ErrDuplicate) {
+			return ErrDuplicateReference
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Outbox().Enqueue(ctx, event, nasabah.NoRekening, transaksi)
+	})
+	if errors.Is(err, ErrInsufficientFunds) {
+		metrics.InsufficientBalanceTotal.Inc()
+		// The locked row read still reflects the untouched balance.
+		return nasabah, err
+	}
+	if err != nil {
+		if !errors.Is(err, ErrAccountNotFound) && !errors.Is(err, ErrAccountFrozen) && !errors.Is(err, ErrDuplicateReference) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return nasabah, err
+	}
+
+	metrics.TransactionsTotal.WithLabelValues(jenis).Inc()
+	metrics.TransactionAmountTotal.WithLabelValues(jenis, nasabah.MataUang).Add(nominal)
+	s.events.publish(transaksi)
+
+	template := notifier.TemplateTabung
+	if jenis == model.JenisTarik {
+		template = notifier.TemplateTarik
+	}
+	s.notify(ctx, nasabah, template, nominal)
+	return nasabah, nil
+}
+
+// notify tells the customer about a committed balance change by SMS and, if
+// they registered one, by email. Delivery is asynchronous so a failure here
+// never affects the transaction.
+func (s *Service) notify(ctx context.Context, nasabah model.Nasabah, template string, nominal float64) {
+	s.NotifyCustomer(ctx, nasabah, template, map[string]any{
+		"Nominal": nominal,
+		"Saldo":   nasabah.Saldo,
+	})
+}
+
+// NotifyCustomer renders template in the customer's language and sends it
+// by SMS and, if set, email. Nama, the masked NoRekening, the account's
+// MataUang and Waktu are filled in; data supplies the rest and may
+// override them.
+func (s *Service) NotifyCustomer(ctx context.Context, nasabah model.Nasabah, template string, data map[string]any) {
+	ctx, span := tracing.Tracer().Start(ctx, "nasabah.notifikasi")
+	defer span.End()
+
+	values := map[string]any{
+		"Nama":       nasabah.Nama,
+		"NoRekening": MaskNoRekening(nasabah.NoRekening),
+		"MataUang":   nasabah.MataUang,
+		"Waktu":      time.Now().Format("02-01-2006 15:04"),
+	}
+	for k, v := range data {
+		values[k] = v
+	}
+	subject, body, err := notifier.Render(template, nasabah.Bahasa, values)
+	if err != nil {
+		logging.FromContext(ctx).Error("gagal menyusun notifikasi", "error", err)
+		return
+	}
+
+	s.notifier.Send(ctx, notifier.Message{Channel: notifier.ChannelSMS, To: nasabah.NoHP, Subject: subject, Body: body})
+	if nasabah.Email != "" {
+		s.notifier.Send(ctx, notifier.Message{Channel: notifier.ChannelEmail, To: nasabah.Email, Subject: subject, Body: body})
+	}
+}
+
+func generateNoRekening() string {
+	return fmt.Sprintf("%010d", rand.Intn(9000000000)+1000000000)
+}
+
+// MaskNoRekening hides all but the last four digits of an account number
+// for customer messages.
+func MaskNoRekening(noRekening string) string {
+	if len(noRekening) <= 4 {
+		return noRekening
+	}
+	return "***" + noRekening[len(noRekening)-4:]
+}
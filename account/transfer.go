@@ -0,0 +1,246 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"gobanking/fx"
+	"gobanking/metrics"
+	"gobanking/model"
+	"gobanking/notifier"
+	"gobanking/repository"
+	"gobanking/tracing"
+	"gobanking/webhook"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrSameAccount        = errors.New("rekening sumber dan tujuan sama")
+	ErrDuplicateReference = errors.New("referensi sudah dipakai")
+)
+
+// TransferInput moves Nominal, in the source account's currency, from
+// Sumber to Tujuan. A non-empty Referensi makes the transfer idempotent per
+// source account.
+type TransferInput struct {
+	Sumber    string
+	Tujuan    string
+	Nominal   float64
+	Referensi string
+}
+
+// TransferResult holds both journal entries; Sumber carries the source
+// account after the debit. Konversi is set when the accounts' currencies
+// differ.
+type TransferResult struct {
+	Sumber   model.Nasabah
+	Debit    model.Transaksi
+	Kredit   model.Transaksi
+	Konversi *model.KonversiValas
+}
+
+// Transfer debits the source and credits the destination in one
+// transaction, journaling a transfer_keluar and a transfer_masuk entry.
+//
+// If Referensi was already used on the source account nothing moves and
+// ErrDuplicateReference is returned with the earlier debit entry in
+// Debit, so a retried caller can tell the money has already gone.
+//
+// Between accounts in different currencies the kredit is converted at the
+// rates in effect (see package fx) and a KonversiValas records the rates and
+// the bank's FX gain or loss, all in the same transaction.
+//
+// Only the recipient is notified. The sender learns about the debit from
+// whatever initiated it; a payroll batch would otherwise send one message
+// per row.
+func (s *Service) Transfer(ctx context.Context, in TransferInput) (TransferResult, error) {
+	if in.Nominal <= 0 {
+		return TransferResult{}, ErrInvalidAmount
+	}
+	if in.Sumber == in.Tujuan {
+		return TransferResult{}, ErrSameAccount
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, "nasabah.transfer", trace.WithAttributes(
+		attribute.Bool("referensi", in.Referensi != ""),
+	))
+	defer span.End()
+
+	var (
+		result   TransferResult
+		tujuan   model.Nasabah
+		previous model.Transaksi
+	)
+	err := s.store.WithinTx(ctx, func(tx repository.Store) error {
+		if in.Referensi != "" {
+			var err error
+			previous, err = tx.Nasabah().FindTransaksiByReferensi(ctx, in.Sumber, in.Referensi)
+			if err == nil {
+				return ErrDuplicateReference
+			}
+			if !errors.Is(err, repository.ErrNotFound) {
+				return err
+			}
+		}
+
+		sumber, tujuanLocked, err := lockPair(ctx, tx, in.Sumber, in.Tujuan)
+		if err != nil {
+			return err
+		}
+		if sumber.Saldo < in.Nominal {
+			result.Sumber = sumber
+			return ErrInsufficientFunds
+		}
+
+		nominalKredit := in.Nominal
+		var quote *fx.Quote
+		if sumber.MataUang != tujuanLocked.MataUang {
+			q, err := convert(ctx, tx, in.Nominal, sumber.MataUang, tujuanLocked.MataUang)
+			if err != nil {
+				return err
+			}
+			if q.NominalTujuan <= 0 {
+				// Too small to be worth one minor unit of the destination.
+				return ErrInvalidAmount
+			}
+			quote, nominalKredit = &q, q.NominalTujuan
+		}
+
+		sumber.Saldo -= in.Nominal
+		tujuanLocked.Saldo += nominalKredit
+
+		if err := tx.Nasabah().UpdateSaldo(ctx, sumber.ID, sumber.Saldo); err != nil {
+			return err
+		}
+		if err := tx.Nasabah().UpdateSaldo(ctx, tujuanLocked.ID, tujuanLocked.Saldo); err != nil {
+			return err
+		}
+
+		debit := model.Transaksi{
+			NasabahID:       sumber.ID,
+			NoRekening:      sumber.NoRekening,
+			Jenis:           model.JenisTransferKeluar,
+			Nominal:         in.Nominal,
+			SaldoAkhir:      sumber.Saldo,
+			MataUang:        sumber.MataUang,
+			Referensi:       in.Referensi,
+			NoRekeningLawan: tujuanLocked.NoRekening,
+		}
+		err = book(ctx, tx, &debit)
+		if errors.Is(err, repository.ErrDuplicate) {
+			// A concurrent transfer with the same reference won.
+			return ErrDuplicateReference
+		}
+		if err != nil {
+			return err
+		}
+		kredit := model.Transaksi{
+			NasabahID:       tujuanLocked.ID,
+			NoRekening:      tujuanLocked.NoRekening,
+			Jenis:           model.JenisTransferMasuk,
+			Nominal:         nominalKredit,
+			SaldoAkhir:      tujuanLocked.Saldo,
+			MataUang:        tujuanLocked.MataUang,
+			Referensi:       in.Referensi,
+			NoRekeningLawan: sumber.NoRekening,
+		}
+		if err := book(ctx, tx, &kredit); err != nil {
+			return err
+		}
+
+		var konversi *model.KonversiValas
+		if quote != nil {
+			konversi = &model.KonversiValas{
+				TransaksiDebitID:  debit.ID,
+				TransaksiKreditID: kredit.ID,
+				MataUangAsal:      quote.MataUangAsal,
+				NominalAsal:       quote.NominalAsal,
+				MataUangTujuan:    quote.MataUangTujuan,
+				NominalTujuan:     quote.NominalTujuan,
+				Kurs:              quote.Kurs,
+				LabaRugi:          quote.LabaRugi,
+			}
+			if quote.KursAsal != nil {
+				konversi.KursAsalID = &quote.KursAsal.ID
+			}
+			if quote.KursTujuan != nil {
+				konversi.KursTujuanID = &quote.KursTujuan.ID
+			}
+			if err := tx.FX().CreateKonversi(ctx, konversi); err != nil {
+				return err
+			}
+			if err := bookKurs(ctx, tx, konversi, debit.CreatedAt); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Outbox().Enqueue(ctx, webhook.EventTransferKeluar, sumber.NoRekening, debit); err != nil {
+			return err
+		}
+		if err := tx.Outbox().Enqueue(ctx, webhook.EventTransferMasuk, tujuanLocked.NoRekening, kredit); err != nil {
+			return err
+		}
+
+		result = TransferResult{Sumber: sumber, Debit: debit, Kredit: kredit, Konversi: konversi}
+		tujuan = tujuanLocked
+		return nil
+	})
+	switch {
+	case errors.Is(err, ErrDuplicateReference):
+		if previous.ID == 0 {
+			// Lost the race above; the winner has committed by now.
+			previous, _ = s.store.Nasabah().FindTransaksiByReferensi(ctx, in.Sumber, in.Referensi)
+		}
+		return TransferResult{Debit: previous}, err
+	case errors.Is(err, ErrInsufficientFunds):
+		metrics.InsufficientBalanceTotal.Inc()
+		return result, err
+	case err != nil:
+		if !errors.Is(err, ErrAccountNotFound) && !errors.Is(err, ErrBeneficiaryNotFound) &&
+			!errors.Is(err, ErrAccountFrozen) && !errors.Is(err, ErrRateNotFound) && !errors.Is(err, ErrInvalidAmount) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return TransferResult{}, err
+	}
+
+	for _, t := range []model.Transaksi{result.Debit, result.Kredit} {
+		metrics.TransactionsTotal.WithLabelValues(t.Jenis).Inc()
+		metrics.TransactionAmountTotal.WithLabelValues(t.Jenis, t.MataUang).Add(t.Nominal)
+		s.events.publish(t)
+	}
+	s.notify(ctx, tujuan, notifier.TemplateTransferMasuk, result.Kredit.Nominal)
+	return result, nil
+}
+
+// lockPair locks both accounts in account-number order so that opposite
+// transfers between the same pair cannot deadlock. A missing destination
+// is ErrBeneficiaryNotFound; a frozen account on either side refuses the
+// transfer.
+func lockPair(ctx context.Context, tx repository.Store, sumber, tujuan string) (model.Nasabah, model.Nasabah, error) {
+	order := []string{sumber, tujuan}
+	if tujuan < sumber {
+		order = []string{tujuan, sumber}
+	}
+
+	locked := make(map[string]model.Nasabah, 2)
+	for _, noRekening := range order {
+		nasabah, err := tx.Nasabah().LockByNoRekening(ctx, noRekening)
+		if errors.Is(err, repository.ErrNotFound) {
+			if noRekening == tujuan {
+				return model.Nasabah{}, model.Nasabah{}, ErrBeneficiaryNotFound
+			}
+			return model.Nasabah{}, model.Nasabah{}, ErrAccountNotFound
+		}
+		if err != nil {
+			return model.Nasabah{}, model.Nasabah{}, err
+		}
+		if nasabah.FrozenAt != nil {
+			return model.Nasabah{}, model.Nasabah{}, ErrAccountFrozen
+		}
+		locked[noRekening] = nasabah
+	}
+	return locked[sumber], locked[tujuan], nil
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"gobanking/admin"
+	"gobanking/config"
+	"gobanking/database"
+	"gobanking/model"
+	"io"
+	"os"
+	"os/user"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const adminUsage = `usage: gobanking admin <command> [flags]
+
+commands:
+  user create --email <email> [--role user|staff|admin] [--password <p>]
+  user disable --email <email>
+  user enable --email <email>
+  user reset-password --email <email> [--password <p>]
+  nasabah show <no_rekening>
+  nasabah search <query> [--limit 20]
+  account freeze <no_rekening>
+  account unfreeze <no_rekening>
+  ledger verify
+  fx import <file.csv>
+  fx list
+  jwt mint --subject <service> [--role user|staff|admin] [--ttl 720h]
+
+flags accepted by every command:
+  --dry-run          run the change in a transaction and roll it back
+  --json             print the result as JSON
+  --operator <name>  who ran the command, for the audit log (default $USER)
+
+An omitted --password is generated and printed once. Every run, including
+dry runs and failures, is recorded in admin_audit_logs.
+
+fx import reads a CSV with the columns mata_uang, beli and jual, quoted in
+IDR, and an optional berlaku_mulai (RFC 3339, default now). The whole file
+is loaded or none of it.
+`
+
+// adminParams holds the command-specific flags; each command registers only
+// the ones it uses.
+type adminParams struct {
+	email    string
+	password string
+	role     string
+	subject  string
+	limit    int
+	ttl      time.Duration
+}
+
+type adminCommand struct {
+	// args is the number of positional arguments the command takes.
+	args  int
+	flags func(fs *flag.FlagSet, p *adminParams)
+	run   func(ctx context.Context, svc *admin.Service, p *adminParams, args []string) (any, error)
+}
+
+var adminCommands = map[string]adminCommand{
+	"user create": {
+		flags: func(fs *flag.FlagSet, p *adminParams) {
+			fs.StringVar(&p.email, "email", "", "email of the new user")
+			fs.StringVar(&p.password, "password", "", "password; generated when empty")
+			fs.StringVar(&p.role, "role", model.RoleUser, "user, staff or admin")
+		},
+		run: func(ctx context.Context, svc *admin.Service, p *adminParams, _ []string) (any, error) {
+			return svc.CreateUser(ctx, p.email, p.password, p.role)
+		},
+	},
+	"user disable": {
+		flags: emailFlag,
+		run: func(ctx context.Context, svc *admin.Service, p *adminParams, _ []string) (any, error) {
+			return svc.SetUserDisabled(ctx, p.email, true)
+		},
+	},
+	"user enable": {
+		flags: emailFlag,
+		run: func(ctx context.Context, svc *admin.Service, p *adminParams, _ []string) (any, error) {
+			return svc.SetUserDisabled(ctx, p.email, false)
+		},
+	},
+	"user reset-password": {
+		flags: func(fs *flag.FlagSet, p *adminParams) {
+			emailFlag(fs, p)
+			fs.StringVar(&p.password, "password", "", "new password; generated when empty")
+		},
+		run: func(ctx context.Context, svc *admin.Service, p *adminParams, _ []string) (any, error) {
+			return svc.ResetPassword(ctx, p.email, p.password)
+		},
+	},
+	"nasabah show": {
+		args: 1,
+		run: func(ctx context.Context, svc *admin.Service, _ *adminParams, args []string) (any, error) {
+			return svc.ShowNasabah(ctx, args[0])
+		},
+	},
+	"nasabah search": {
+		args: 1,
+		flags: func(fs *flag.FlagSet, p *adminParams) {
+			fs.IntVar(&p.limit, "limit", 20, "maximum number of results")
+		},
+		run: func(ctx context.Context, svc *admin.Service, p *adminParams, args []string) (any, error) {
+			return svc.SearchNasabah(ctx, args[0], p.limit)
+		},
+	},
+	"account freeze": {
+		args: 1,
+		run: func(ctx context.Context, svc *admin.Service, _ *adminParams, args []string) (any, error) {
+			return svc.SetFrozen(ctx, args[0], true)
+		},
+	},
+	"account unfreeze": {
+		args: 1,
+		run: func(ctx context.Context, svc *admin.Service, _ *adminParams, args []string) (any, error) {
+			return svc.SetFrozen(ctx, args[0], false)
+		},
+	},
+	"ledger verify": {
+		run: func(ctx context.Context, svc *admin.Service, _ *adminParams, _ []string) (any, error) {
+			return svc.VerifyLedger(ctx)
+		},
+	},
+	"fx import": {
+		args: 1,
+		run: func(ctx context.Context, svc *admin.Service, _ *adminParams, args []string) (any, error) {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+			return svc.ImportKurs(ctx, file)
+		},
+	},
+	"fx list": {
+		run: func(ctx context.Context, svc *admin.Service, _ *adminParams, _ []string) (any, error) {
+			return svc.ListKurs(ctx)
+		},
+	},
+	"jwt mint": {
+		flags: func(fs *flag.FlagSet, p *adminParams) {
+			fs.StringVar(&p.subject, "subject", "", "name of the service the token is for")
+			fs.StringVar(&p.role, "role", model.RoleUser, "user, staff or admin")
+			fs.DurationVar(&p.ttl, "ttl", 30*24*time.Hour, "lifetime, at most 8760h")
+		},
+		run: func(_ context.Context, svc *admin.Service, p *adminParams, _ []string) (any, error) {
+			return svc.MintToken(p.subject, p.role, p.ttl)
+		},
+	},
+}
+
+func emailFlag(fs *flag.FlagSet, p *adminParams) {
+	fs.StringVar(&p.email, "email", "", "email of the user")
+}
+
+// runAdmin implements the `admin` subcommand and returns the exit code.
+func runAdmin(args []string) int {
+	if len(args) < 2 {
+		fmt.Fprint(os.Stderr, adminUsage)
+		return 2
+	}
+	name := args[0] + " " + args[1]
+	cmd, ok := adminCommands[name]
+	if !ok {
+		fmt.Fprint(os.Stderr, adminUsage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("gobanking admin "+name, flag.ContinueOnError)
+	var (
+		dryRun   bool
+		asJSON   bool
+		operator string
+		params   adminParams
+	)
+	fs.BoolVar(&dryRun, "dry-run", false, "roll back any change")
+	fs.BoolVar(&asJSON, "json", false, "print JSON")
+	fs.StringVar(&operator, "operator", defaultOperator(), "operator recorded in the audit log")
+	if cmd.flags != nil {
+		cmd.flags(fs, &params)
+	}
+
+	positional, err := parseInterspersed(fs, args[2:])
+	if err != nil {
+		return 2
+	}
+	if len(positional) != cmd.args {
+		fmt.Fprintf(os.Stderr, "%s membutuhkan %d argumen\n\n%s", name, cmd.args, adminUsage)
+		return 2
+	}
+	if operator == "" {
+		fmt.Fprintln(os.Stderr, "--operator harus diisi")
+		return 2
+	}
+
+	cfg, err := config.Load(nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "konfigurasi tidak valid:\n%v\n", err)
+		return 2
+	}
+	db, err := database.Connect(cfg)
+	if err != nil {
+		return 1
+	}
+	ctx := context.Background()
+
+	// The audit table and the columns the commands touch come from
+	// migrations, so refuse to run against an older schema.
+	if err := database.CheckMigrations(ctx, db); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, "jalankan `gobanking migrate up` terlebih dahulu")
+		return 1
+	}
+
+	// gorm's default logger writes to stdout and would corrupt --json
+	// output; errors are returned and reported below instead.
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+
+	svc := admin.NewService(db, cfg, dryRun)
+	result, cmdErr := cmd.run(ctx, svc, &params, positional)
+
+	if err := svc.Audit(ctx, operator, name, auditArgs(fs, positional), cmdErr); err != nil {
+		fmt.Fprintln(os.Stderr, "gagal mencatat audit:", err)
+		return 1
+	}
+	if cmdErr != nil {
+		fmt.Fprintln(os.Stderr, cmdErr)
+		return 1
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(result)
+	} else {
+		printAdminResult(os.Stdout, result, dryRun)
+	}
+
+	if report, ok := result.(admin.LedgerReport); ok && !report.OK() {
+		return 1
+	}
+	return 0
+}
+
+// parseInterspersed lets flags follow positional arguments, as in
+// `nasabah show 1234567890 --json`.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// auditArgs lists the flags that were set and the positional arguments,
+// masking passwords.
+func auditArgs(fs *flag.FlagSet, positional []string) map[string]string {
+	args := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		value := f.Value.String()
+		if f.Name == "password" {
+			value = "***"
+		}
+		args[f.Name] = value
+	})
+	if len(positional) > 0 {
+		args["args"] = strings.Join(positional, " ")
+	}
+	return args
+}
+
+func defaultOperator() string {
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+func printAdminResult(w io.Writer, result any, dryRun bool) {
+	if dryRun {
+		fmt.Fprintln(w, "dry run: tidak ada perubahan yang disimpan")
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+
+	switch r := result.(type) {
+	case admin.UserResult:
+		fmt.Fprintf(tw, "id\t%d\nemail\t%s\nrole\t%s\ndisabled_at\t%s\nchanged\t%t\n",
+			r.ID, r.Email, r.Role, formatTime(r.DisabledAt), r.Changed)
+		if r.Password != "" {
+			fmt.Fprintf(tw, "password\t%s\n", r.Password)
+		}
+
+	case admin.NasabahResult:
+		fmt.Fprintf(tw, "id\t%d\nnama\t%s\nnik\t%s\nno_hp\t%s\nemail\t%s\nbahasa\t%s\nno_rekening\t%s\nsaldo\t%.2f %s\nfrozen_at\t%s\ncreated_at\t%s\n",
+			r.ID, r.Nama, r.NIK, r.NoHP, r.Email, r.Bahasa, r.NoRekening, r.Saldo, r.MataUang, formatTime(r.FrozenAt), formatTime(&r.CreatedAt))
+
+	case []admin.NasabahResult:
+		fmt.Fprintln(tw, "NO_REKENING\tNAMA\tNIK\tNO_HP\tSALDO\tMATA_UANG\tFROZEN")
+		for _, n := range r {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.2f\t%s\t%t\n", n.NoRekening, n.Nama, n.NIK, n.NoHP, n.Saldo, n.MataUang, n.FrozenAt != nil)
+		}
+
+	case admin.LedgerReport:
+		fmt.Fprintf(tw, "rekening diperiksa\t%d\ntransaksi diperiksa\t%d\nmasalah\t%d\n", r.Accounts, r.Transactions, len(r.Issues))
+		if len(r.Issues) > 0 {
+			fmt.Fprintln(tw, "\nNO_REKENING\tTRANSAKSI\tMASALAH\tSEHARUSNYA\tTERCATAT")
+			for _, i := range r.Issues {
+				fmt.Fprintf(tw, "%s\t%d\t%s\t%.2f\t%.2f\n", i.NoRekening, i.TransaksiID, i.Problem, i.Expected, i.Actual)
+			}
+		}
+
+	case []model.KursResponse:
+		fmt.Fprintln(tw, "MATA_UANG\tBELI\tJUAL\tTENGAH\tBERLAKU_MULAI\tSUMBER")
+		for _, k := range r {
+			fmt.Fprintf(tw, "%s\t%.4f\t%.4f\t%.4f\t%s\t%s\n", k.MataUang, k.Beli, k.Jual, k.Tengah, k.BerlakuMulai.Format(time.RFC3339), k.Sumber)
+		}
+
+	case admin.TokenResult:
+		fmt.Fprintf(tw, "subject\t%s\nrole\t%s\nexpires_at\t%s\n", r.Subject, r.Role, r.ExpiresAt.Format(time.RFC3339))
+		if r.Token != "" {
+			fmt.Fprintf(tw, "token\t%s\n", r.Token)
+		}
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,104 @@
+// Package admin implements the operator commands behind `gobanking admin`.
+// Every mutation runs in a transaction that is rolled back in dry-run mode,
+// so a dry run still hits the same constraints as the real change.
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"gobanking/config"
+	"gobanking/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user tidak ditemukan")
+	ErrUserExists      = errors.New("email sudah terdaftar")
+	ErrAccountNotFound = errors.New("rekening tidak ditemukan")
+	ErrInvalidRole     = errors.New("role harus user, staff atau admin")
+	ErrWeakPassword    = errors.New("password minimal 6 karakter")
+
+	// errDryRun rolls back a dry-run transaction.
+	errDryRun = errors.New("dry run")
+)
+
+type Service struct {
+	db     *gorm.DB
+	cfg    *config.Config
+	dryRun bool
+}
+
+// NewService returns a Service whose mutations are rolled back when dryRun
+// is set.
+func NewService(db *gorm.DB, cfg *config.Config, dryRun bool) *Service {
+	return &Service{db: db, cfg: cfg, dryRun: dryRun}
+}
+
+// mutate runs fn in a transaction, rolling it back in dry-run mode.
+func (s *Service) mutate(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+		if s.dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		return nil
+	}
+	return err
+}
+
+// Audit stores one command run, dry runs and failures included, and writes
+// it to the application log so AUDIT_LOG_PATH receives it too. Secrets must
+// already be masked in args.
+func (s *Service) Audit(ctx context.Context, operator, command string, args map[string]string, cmdErr error) error {
+	encoded, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	entry := model.AdminAuditLog{
+		Operator: operator,
+		Command:  command,
+		Args:     string(encoded),
+		DryRun:   s.dryRun,
+		Result:   model.AdminResultOK,
+	}
+	if cmdErr != nil {
+		entry.Result = model.AdminResultError
+		entry.Error = cmdErr.Error()
+	}
+
+	s.cfg.Logger.Info("perintah admin",
+		"operator", operator,
+		"command", command,
+		"args", args,
+		"dry_run", s.dryRun,
+		"result", entry.Result,
+		"error", entry.Error,
+	)
+	// The audit row is written outside any dry-run transaction.
+	return s.db.WithContext(ctx).Create(&entry).Error
+}
+
+func validRole(role string) bool {
+	switch role {
+	case model.RoleUser, model.RoleStaff, model.RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// generatePassword returns a random 16-character password.
+func generatePassword() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,65 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gobanking/fx"
+	"gobanking/model"
+	"gobanking/repository"
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrKursExists = errors.New("kurs dengan waktu berlaku yang sama sudah ada")
+
+// ImportKurs loads a rate sheet in the format of fx.ParseCSV. The file is
+// stored whole or not at all.
+func (s *Service) ImportKurs(ctx context.Context, r io.Reader) ([]model.KursResponse, error) {
+	rates, err := fx.ParseCSV(r, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.mutate(ctx, func(tx *gorm.DB) error {
+		for i := range rates {
+			var existing int64
+			if err := tx.Model(&model.Kurs{}).
+				Where("mata_uang = ? AND berlaku_mulai = ?", rates[i].MataUang, rates[i].BerlakuMulai).
+				Count(&existing).Error; err != nil {
+				return err
+			}
+			if existing > 0 {
+				return fmt.Errorf("%w: %s %s", ErrKursExists, rates[i].MataUang, rates[i].BerlakuMulai.Format(time.RFC3339))
+			}
+			if err := tx.Create(&rates[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return kursResults(rates), nil
+}
+
+// ListKurs returns the rate in effect now of every currency that has one.
+func (s *Service) ListKurs(ctx context.Context) ([]model.KursResponse, error) {
+	// The query GET /fx-rates runs; nothing is written, so no outbox.
+	rates, err := repository.NewGormStore(s.db, nil).FX().ListKursAt(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return kursResults(rates), nil
+}
+
+func kursResults(rates []model.Kurs) []model.KursResponse {
+	results := make([]model.KursResponse, len(rates))
+	for i, k := range rates {
+		results[i] = fx.Response(k)
+	}
+	return results
+}
@@ -0,0 +1,149 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"gobanking/model"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+// ledgerTolerance absorbs float rounding in saldo columns.
+const ledgerTolerance = 0.005
+
+type LedgerIssue struct {
+	NoRekening  string  `json:"no_rekening,omitempty"`
+	TransaksiID uint    `json:"transaksi_id,omitempty"`
+	Problem     string  `json:"problem"`
+	Expected    float64 `json:"expected"`
+	Actual      float64 `json:"actual"`
+}
+
+type LedgerReport struct {
+	Accounts     int           `json:"accounts"`
+	Transactions int           `json:"transactions"`
+	Issues       []LedgerIssue `json:"issues"`
+}
+
+func (r LedgerReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// VerifyLedger replays each account's Transaksi journal from zero, opening
+// balance first, and checks every saldo_akhir and the final balance against
+// nasabahs.saldo. It also reports journal entries whose account no longer
+// exists, entries that were never posted to the general ledger and
+// currency conversions whose FX gain or loss was not posted.
+func (s *Service) VerifyLedger(ctx context.Context) (LedgerReport, error) {
+	report := LedgerReport{Issues: []LedgerIssue{}}
+	db := s.db.WithContext(ctx)
+
+	var accounts []model.Nasabah
+	err := db.Order("id").FindInBatches(&accounts, 500, func(tx *gorm.DB, batch int) error {
+		for _, nasabah := range accounts {
+			var journal []model.Transaksi
+			err := db.Where("nasabah_id = ?", nasabah.ID).
+				Order("CASE WHEN jenis = '" + model.JenisSaldoAwal + "' THEN 0 ELSE 1 END, id").
+				Find(&journal).Error
+			if err != nil {
+				return err
+			}
+			report.Accounts++
+			report.Transactions += len(journal)
+			report.Issues = append(report.Issues, replay(nasabah, journal)...)
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return report, err
+	}
+
+	var orphans []model.Transaksi
+	err = db.Where("nasabah_id NOT IN (?)", db.Model(&model.Nasabah{}).Select("id")).Order("id").Find(&orphans).Error
+	if err != nil {
+		return report, err
+	}
+	for _, t := range orphans {
+		report.Transactions++
+		report.Issues = append(report.Issues, LedgerIssue{
+			NoRekening:  t.NoRekening,
+			TransaksiID: t.ID,
+			Problem:     "transaksi tanpa rekening",
+			Actual:      t.Nominal,
+		})
+	}
+
+	var unposted []model.Transaksi
+	err = db.Where("id NOT IN (?)", db.Model(&model.JurnalGL{}).Select("transaksi_id")).Order("id").Find(&unposted).Error
+	if err != nil {
+		return report, err
+	}
+	for _, t := range unposted {
+		report.Issues = append(report.Issues, LedgerIssue{
+			NoRekening:  t.NoRekening,
+			TransaksiID: t.ID,
+			Problem:     "transaksi tanpa jurnal GL",
+			Actual:      t.Nominal,
+		})
+	}
+
+	var konversi []model.KonversiValas
+	err = db.Where("laba_rugi <> 0 AND transaksi_debit_id NOT IN (?)",
+		db.Model(&model.JurnalGL{}).Select("transaksi_id").Where("jenis IN ?", []string{model.JenisLabaKurs, model.JenisRugiKurs})).
+		Order("id").Find(&konversi).Error
+	if err != nil {
+		return report, err
+	}
+	for _, k := range konversi {
+		report.Issues = append(report.Issues, LedgerIssue{
+			TransaksiID: k.TransaksiDebitID,
+			Problem:     "laba rugi kurs tanpa jurnal GL",
+			Actual:      k.LabaRugi,
+		})
+	}
+	return report, nil
+}
+
+func replay(nasabah model.Nasabah, journal []model.Transaksi) []LedgerIssue {
+	var issues []LedgerIssue
+	saldo := 0.0
+	for _, t := range journal {
+		switch t.Jenis {
+		case model.JenisTabung, model.JenisTransferMasuk, model.JenisKoreksiKredit, model.JenisBunga, model.JenisSaldoAwal:
+			saldo += t.Nominal
+		case model.JenisTarik, model.JenisTransferKeluar, model.JenisKoreksiDebit, model.JenisBiaya, model.JenisPajak:
+			saldo -= t.Nominal
+		default:
+			issues = append(issues, LedgerIssue{
+				NoRekening:  nasabah.NoRekening,
+				TransaksiID: t.ID,
+				Problem:     fmt.Sprintf("jenis transaksi tidak dikenal: %q", t.Jenis),
+			})
+			saldo = t.SaldoAkhir
+			continue
+		}
+
+		if math.Abs(saldo-t.SaldoAkhir) > ledgerTolerance {
+			issues = append(issues, LedgerIssue{
+				NoRekening:  nasabah.NoRekening,
+				TransaksiID: t.ID,
+				Problem:     "saldo_akhir tidak sesuai jurnal",
+				Expected:    saldo,
+				Actual:      t.SaldoAkhir,
+			})
+			// Continue from the recorded value so one bad row is reported once.
+			saldo = t.SaldoAkhir
+		}
+	}
+
+	if math.Abs(saldo-nasabah.Saldo) > ledgerTolerance {
+		issues = append(issues, LedgerIssue{
+			NoRekening: nasabah.NoRekening,
+			Problem:    "saldo rekening tidak sesuai jurnal",
+			Expected:   saldo,
+			Actual:     nasabah.Saldo,
+		})
+	}
+	return issues
+}
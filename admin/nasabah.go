@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"gobanking/model"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NasabahResult struct {
+	ID         uint       `json:"id"`
+	Nama       string     `json:"nama"`
+	NIK        string     `json:"nik"`
+	NoHP       string     `json:"no_hp"`
+	Email      string     `json:"email"`
+	Bahasa     string     `json:"bahasa"`
+	NoRekening string     `json:"no_rekening"`
+	Saldo      float64    `json:"saldo"`
+	MataUang   string     `json:"mata_uang"`
+	FrozenAt   *time.Time `json:"frozen_at"`
+	// OverdraftAt is set while a reversal has left Saldo negative.
+	OverdraftAt *time.Time `json:"overdraft_at,omitempty"`
+	// DormantAt is set while the end-of-day run marks the account dormant.
+	DormantAt *time.Time `json:"dormant_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	Changed   bool       `json:"changed,omitempty"`
+}
+
+func nasabahResult(n model.Nasabah) NasabahResult {
+	return NasabahResult{
+		ID:          n.ID,
+		Nama:        n.Nama,
+		NIK:         n.NIK,
+		NoHP:        n.NoHP,
+		Email:       n.Email,
+		Bahasa:      n.Bahasa,
+		NoRekening:  n.NoRekening,
+		Saldo:       n.Saldo,
+		MataUang:    n.MataUang,
+		FrozenAt:    n.FrozenAt,
+		OverdraftAt: n.OverdraftAt,
+		DormantAt:   n.DormantAt,
+		CreatedAt:   n.CreatedAt,
+	}
+}
+
+// ShowNasabah looks a customer up by account number.
+func (s *Service) ShowNasabah(ctx context.Context, noRekening string) (NasabahResult, error) {
+	var nasabah model.Nasabah
+	err := s.db.WithContext(ctx).Where("no_rekening = ?", noRekening).First(&nasabah).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return NasabahResult{}, ErrAccountNotFound
+	}
+	if err != nil {
+		return NasabahResult{}, err
+	}
+	return nasabahResult(nasabah), nil
+}
+
+// SearchNasabah matches query exactly against NIK, NoHP and NoRekening, and
+// as a case-insensitive substring of the name or email.
+func (s *Service) SearchNasabah(ctx context.Context, query string, limit int) ([]NasabahResult, error) {
+	like := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query) + "%"
+
+	var found []model.Nasabah
+	err := s.db.WithContext(ctx).
+		Where("nik = ? OR no_hp = ? OR no_rekening = ? OR nama ILIKE ? OR email ILIKE ?", query, query, query, like, like).
+		Order("id").
+		Limit(limit).
+		Find(&found).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]NasabahResult, 0, len(found))
+	for _, n := range found {
+		results = append(results, nasabahResult(n))
+	}
+	return results, nil
+}
+
+// SetFrozen freezes or unfreezes an account. account.Service refuses
+// deposits and withdrawals on a frozen account.
+func (s *Service) SetFrozen(ctx context.Context, noRekening string, frozen bool) (NasabahResult, error) {
+	var result NasabahResult
+	err := s.mutate(ctx, func(tx *gorm.DB) error {
+		var nasabah model.Nasabah
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("no_rekening = ?", noRekening).First(&nasabah).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAccountNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if (nasabah.FrozenAt != nil) == frozen {
+			result = nasabahResult(nasabah)
+			return nil
+		}
+
+		var frozenAt *time.Time
+		if frozen {
+			now := time.Now()
+			frozenAt = &now
+		}
+		if err := tx.Model(&nasabah).Update("frozen_at", frozenAt).Error; err != nil {
+			return err
+		}
+		nasabah.FrozenAt = frozenAt
+		result = nasabahResult(nasabah)
+		result.Changed = true
+		return nil
+	})
+	return result, err
+}
@@ -1,24 +1,76 @@
 package config
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"gobanking/logging"
+	"io"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	Logger   *slog.Logger
+	// Env is APP_ENV. Logs are only left unmasked for "development" and
+	// "test"; see logging.PolicyFor.
+	Env       string
+	Server    ServerConfig
+	Log       LogConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Features  FeatureConfig
+	Webhook   WebhookConfig
+	Payroll   PayrollConfig
+	Schedule  ScheduleConfig
+	Reversal  ReversalConfig
+	EOD       EODConfig
+	Recon     ReconConfig
+	Notifier  NotifierConfig
+	OTP       OTPConfig
+	MFA       MFAConfig
+	Lockout   LockoutConfig
+	RateLimit RateLimitConfig
+	Metrics   MetricsConfig
+	GRPC      GRPCConfig
+	Tracing   TracingConfig
+	Health    HealthConfig
+	Logger    *slog.Logger
 }
 
 type ServerConfig struct {
-	Host string
-	Port int
+	Host         string
+	Port         int
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownTimeout bounds how long in-flight requests and background
+	// workers get to finish after SIGINT/SIGTERM.
+	ShutdownTimeout time.Duration
+	// DrainDelay keeps serving after /readyz starts failing so load
+	// balancers can take the instance out of rotation first.
+	DrainDelay time.Duration
+	// TrustedProxies lists the IPs or CIDR ranges of the reverse proxies
+	// whose X-Forwarded-For is believed. Empty means clients connect
+	// directly and forwarding headers are ignored.
+	TrustedProxies []string
+}
+
+type LogConfig struct {
+	// Level is "debug", "info", "warn" or "error".
+	Level string
+	// Format is "json" or "text".
+	Format string
+	// AuditPath, when set, receives every record unmasked.
+	AuditPath string
+}
+
+func (c LogConfig) level() slog.Level {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(c.Level))
+	return level
 }
 
 type DatabaseConfig struct {
@@ -27,13 +79,193 @@ type DatabaseConfig struct {
 	User     string
 	Password string
 	Name     string
+	SSLMode  string
+
+	ConnectTimeout time.Duration
+	// AutoMigrate applies pending migrations at startup. Disable it when
+	// migrations run as a separate `migrate up` deployment step.
+	AutoMigrate     bool
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// FeatureConfig switches optional parts of the API on or off.
+type FeatureConfig struct {
+	Swagger  bool
+	Webhooks bool
 }
 
 type JWTConfig struct {
 	Secret string
 }
 
-func Load() *Config {
+type NotifierConfig struct {
+	SMTPHost         string
+	SMTPPort         int
+	SMTPUser         string
+	SMTPPassword     string
+	SMTPFrom         string
+	SMSGatewayURL    string
+	SMSGatewayAPIKey string
+	QueueSize        int
+	Workers          int
+}
+
+type OTPConfig struct {
+	// TarikThreshold is the withdrawal amount above which an OTP is required.
+	TarikThreshold float64
+	TTL            time.Duration
+	MaxAttempts    int
+}
+
+type MFAConfig struct {
+	Issuer       string
+	ChallengeTTL time.Duration
+	// MaxAttempts is how many codes one MFA token may try.
+	MaxAttempts int
+	// RequiredRoles lists the user roles that must have 2FA enabled.
+	RequiredRoles []string
+}
+
+func (c MFAConfig) Required(role string) bool {
+	for _, r := range c.RequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type LockoutConfig struct {
+	// MaxFailures locks an email after this many failures within Window.
+	MaxFailures int
+	// MaxIPFailures locks a client IP; it is higher because IPs are shared.
+	MaxIPFailures int
+	Window        time.Duration
+	Duration      time.Duration
+	// BaseDelay is the wait imposed after the first failure, doubled for
+	// every further failure up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+type RateLimitConfig struct {
+	// Store is "memory" (per replica) or "postgres" (shared by replicas).
+	Store string
+	// Auth limits /login and /register per client IP.
+	Auth RateLimitPolicy
+	// User limits the protected routes per user_id.
+	User RateLimitPolicy
+	// Tarik is an additional, stricter per-user limit on /tarik.
+	Tarik RateLimitPolicy
+}
+
+type RateLimitPolicy struct {
+	Limit  int
+	Period time.Duration
+}
+
+type HealthConfig struct {
+	// Timeout bounds the dependency checks behind /readyz.
+	Timeout time.Duration
+}
+
+type MetricsConfig struct {
+	// Addr, when set, serves /metrics on a separate internal listener
+	// instead of the public API port.
+	Addr string
+}
+
+type GRPCConfig struct {
+	// Addr is the listener for the gRPC BankingService; empty disables it.
+	Addr string
+}
+
+type TracingConfig struct {
+	// Exporter is "none", "stdout", "file" or "otlp".
+	Exporter     string
+	FilePath     string
+	OTLPEndpoint string
+	OTLPInsecure bool
+	SampleRatio  float64
+	ServiceName  string
+}
+
+type WebhookConfig struct {
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// AllowPrivateTargets lets subscriptions point at loopback, private and
+	// link-local addresses. Only for local development and tests.
+	AllowPrivateTargets bool
+}
+
+type PayrollConfig struct {
+	// MaxItems caps the rows of one batch.
+	MaxItems int
+	// ChunkSize is how many rows the worker settles before updating the
+	// batch totals and giving other batches a turn.
+	ChunkSize    int
+	PollInterval time.Duration
+}
+
+type ScheduleConfig struct {
+	// TimeZone is the IANA zone monthly and cron schedules are evaluated in.
+	TimeZone string
+	// RetryDays is how many times an occurrence that failed for lack of
+	// funds is retried, RetryInterval apart, before it is given up.
+	RetryDays     int
+	RetryInterval time.Duration
+	PollInterval  time.Duration
+}
+
+type ReversalConfig struct {
+	// ApprovalThreshold is the IDR value above which a reversal waits for a
+	// second admin to approve it; 0 sends every reversal for approval.
+	ApprovalThreshold float64
+}
+
+type EODConfig struct {
+	// TimeZone is the IANA zone whose calendar date the business date may
+	// not run ahead of.
+	TimeZone string
+	// HolidayFile lists non-business days besides weekends, one date
+	// (2006-01-02) per line with an optional description after a comma.
+	// Empty means weekends only.
+	HolidayFile string
+	// InterestRate is the yearly interest on positive balances, as a
+	// fraction, accrued daily and credited at month end. TaxRate is the
+	// share of that interest withheld as tax.
+	InterestRate float64
+	TaxRate      float64
+	// MonthlyFee is the administration fee, in IDR, charged to IDR accounts
+	// at month end; 0 charges none.
+	MonthlyFee float64
+	// DormantDays without a deposit, withdrawal or outgoing transfer mark
+	// an account dormant.
+	DormantDays int
+}
+
+type ReconConfig struct {
+	// AmountTolerance is the largest difference, in the entry's currency,
+	// between a settlement entry and a deposit that still counts as the
+	// same amount.
+	AmountTolerance float64
+	// DateToleranceDays is how many days apart the settlement date and the
+	// deposit's business date may be when entries are paired without a
+	// reference.
+	DateToleranceDays int
+	// MaxEntries caps the entries of one settlement file.
+	MaxEntries int
+}
+
+// Load builds the configuration from flags, environment variables, Docker
+// secret files and an optional YAML file (see source for the precedence),
+// then validates it. Every problem found is reported in the returned error.
+func Load(args []string) (*Config, error) {
 	// Load environment variables from .env file
 	if err := godotenv.Load(); err != nil {
 		// If .env file doesn't exist, continue without it
@@ -41,39 +273,209 @@ func Load() *Config {
 		slog.Warn("No .env file found")
 	}
 
-	// Setup structured logging
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	}))
-	slog.SetDefault(logger)
-
 	// Parse command line arguments
-	host := flag.String("host", "0.0.0.0", "API server host")
-	port := flag.Int("port", 3000, "API server port")
-	flag.Parse()
+	flags := flag.NewFlagSet("gobanking", flag.ContinueOnError)
+	configPath := flags.String("config", os.Getenv("CONFIG_FILE"), "Path to a YAML config file")
+	flags.String("host", "0.0.0.0", "API server host")
+	flags.Int("port", 3000, "API server port")
+	flags.String("log-level", "info", "Log level: debug, info, warn or error")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
 
-	return &Config{
+	// Only flags given explicitly override the other layers
+	src := &source{flags: make(map[string]string)}
+	flagKeys := map[string]string{"host": "SERVER_HOST", "port": "SERVER_PORT", "log-level": "LOG_LEVEL"}
+	flags.Visit(func(f *flag.Flag) {
+		if key, ok := flagKeys[f.Name]; ok {
+			src.flags[key] = f.Value.String()
+		}
+	})
+
+	if *configPath != "" {
+		file, err := readFile(*configPath)
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca file konfigurasi: %w", err)
+		}
+		src.file = file
+	}
+
+	cfg := &Config{
+		Env: src.str("APP_ENV", ""),
 		Server: ServerConfig{
-			Host: *host,
-			Port: *port,
+			Host: src.str("SERVER_HOST", "0.0.0.0"),
+			Port: src.int("SERVER_PORT", 3000),
+
+			ReadTimeout:     src.duration("SERVER_READ_TIMEOUT", 15*time.Second),
+			WriteTimeout:    src.duration("SERVER_WRITE_TIMEOUT", 30*time.Second),
+			IdleTimeout:     src.duration("SERVER_IDLE_TIMEOUT", time.Minute),
+			TrustedProxies:  src.list("SERVER_TRUSTED_PROXIES", nil),
+			ShutdownTimeout: src.duration("SHUTDOWN_TIMEOUT", 30*time.Second),
+			DrainDelay:      src.duration("SHUTDOWN_DRAIN_DELAY", 0),
+		},
+		Log: LogConfig{
+			Level:     src.str("LOG_LEVEL", "info"),
+			Format:    src.str("LOG_FORMAT", "json"),
+			AuditPath: src.str("AUDIT_LOG_PATH", ""),
 		},
 		Database: DatabaseConfig{
-			Host:     os.Getenv("DB_HOST"),
-			Port:     os.Getenv("DB_PORT"),
-			User:     os.Getenv("DB_USER"),
-			Password: os.Getenv("DB_PASSWORD"),
-			Name:     os.Getenv("DB_NAME"),
+			Host:     src.str("DB_HOST", ""),
+			Port:     src.str("DB_PORT", "5432"),
+			User:     src.str("DB_USER", ""),
+			Password: src.str("DB_PASSWORD", ""),
+			Name:     src.str("DB_NAME", ""),
+			SSLMode:  src.str("DB_SSLMODE", "disable"),
+
+			ConnectTimeout:  src.duration("DB_CONNECT_TIMEOUT", time.Minute),
+			AutoMigrate:     src.bool("DB_AUTO_MIGRATE", true),
+			MaxOpenConns:    src.int("DB_POOL_MAX_OPEN", 25),
+			MaxIdleConns:    src.int("DB_POOL_MAX_IDLE", 10),
+			ConnMaxLifetime: src.duration("DB_POOL_CONN_MAX_LIFETIME", 30*time.Minute),
+			ConnMaxIdleTime: src.duration("DB_POOL_CONN_MAX_IDLE_TIME", 5*time.Minute),
 		},
 		JWT: JWTConfig{
-			Secret: os.Getenv("JWT_SECRET"),
+			Secret: src.str("JWT_SECRET", ""),
+		},
+		Features: FeatureConfig{
+			Swagger:  src.bool("FEATURE_SWAGGER", true),
+			Webhooks: src.bool("FEATURE_WEBHOOKS", true),
+		},
+		Webhook: WebhookConfig{
+			MaxAttempts:  src.int("WEBHOOK_MAX_ATTEMPTS", 8),
+			BaseBackoff:  src.duration("WEBHOOK_BASE_BACKOFF", 30*time.Second),
+			Timeout:      src.duration("WEBHOOK_TIMEOUT", 10*time.Second),
+			PollInterval: src.duration("WEBHOOK_POLL_INTERVAL", 5*time.Second),
+
+			AllowPrivateTargets: src.bool("WEBHOOK_ALLOW_PRIVATE_TARGETS", false),
+		},
+		Payroll: PayrollConfig{
+			MaxItems:     src.int("PAYROLL_MAX_ITEMS", 5000),
+			ChunkSize:    src.int("PAYROLL_CHUNK_SIZE", 100),
+			PollInterval: src.duration("PAYROLL_POLL_INTERVAL", 5*time.Second),
+		},
+		Schedule: ScheduleConfig{
+			TimeZone:      src.str("SCHEDULE_TIMEZONE", "Asia/Jakarta"),
+			RetryDays:     src.int("SCHEDULE_RETRY_DAYS", 3),
+			RetryInterval: src.duration("SCHEDULE_RETRY_INTERVAL", 24*time.Hour),
+			PollInterval:  src.duration("SCHEDULE_POLL_INTERVAL", time.Minute),
+		},
+		Reversal: ReversalConfig{
+			ApprovalThreshold: src.float("REVERSAL_APPROVAL_THRESHOLD", 10000000),
+		},
+		EOD: EODConfig{
+			TimeZone:     src.str("EOD_TIMEZONE", "Asia/Jakarta"),
+			HolidayFile:  src.str("EOD_HOLIDAY_FILE", ""),
+			InterestRate: src.float("EOD_INTEREST_RATE", 0.01),
+			TaxRate:      src.float("EOD_TAX_RATE", 0.2),
+			MonthlyFee:   src.float("EOD_MONTHLY_FEE", 10000),
+			DormantDays:  src.int("EOD_DORMANT_DAYS", 365),
+		},
+		Recon: ReconConfig{
+			AmountTolerance:   src.float("RECON_AMOUNT_TOLERANCE", 0),
+			DateToleranceDays: src.int("RECON_DATE_TOLERANCE_DAYS", 1),
+			MaxEntries:        src.int("RECON_MAX_ENTRIES", 50000),
+		},
+		Notifier: NotifierConfig{
+			SMTPHost:         src.str("SMTP_HOST", ""),
+			SMTPPort:         src.int("SMTP_PORT", 25),
+			SMTPUser:         src.str("SMTP_USER", ""),
+			SMTPPassword:     src.str("SMTP_PASSWORD", ""),
+			SMTPFrom:         src.str("SMTP_FROM", ""),
+			SMSGatewayURL:    src.str("SMS_GATEWAY_URL", ""),
+			SMSGatewayAPIKey: src.str("SMS_GATEWAY_API_KEY", ""),
+			QueueSize:        src.int("NOTIFIER_QUEUE_SIZE", 1000),
+			Workers:          src.int("NOTIFIER_WORKERS", 4),
+		},
+		OTP: OTPConfig{
+			TarikThreshold: src.float("OTP_TARIK_THRESHOLD", 10000000),
+			TTL:            src.duration("OTP_TTL", 5*time.Minute),
+			MaxAttempts:    src.int("OTP_MAX_ATTEMPTS", 3),
+		},
+		MFA: MFAConfig{
+			Issuer:        src.str("MFA_ISSUER", "gobanking"),
+			ChallengeTTL:  src.duration("MFA_CHALLENGE_TTL", 5*time.Minute),
+			MaxAttempts:   src.int("MFA_MAX_ATTEMPTS", 5),
+			RequiredRoles: src.list("MFA_REQUIRED_ROLES", []string{"staff", "admin"}),
+		},
+		Lockout: LockoutConfig{
+			MaxFailures:   src.int("LOCKOUT_MAX_FAILURES", 5),
+			MaxIPFailures: src.int("LOCKOUT_MAX_IP_FAILURES", 50),
+			Window:        src.duration("LOCKOUT_WINDOW", 15*time.Minute),
+			Duration:      src.duration("LOCKOUT_DURATION", 15*time.Minute),
+			BaseDelay:     src.duration("LOCKOUT_BASE_DELAY", time.Second),
+			MaxDelay:      src.duration("LOCKOUT_MAX_DELAY", 30*time.Second),
+		},
+		RateLimit: RateLimitConfig{
+			Store: src.str("RATE_LIMIT_STORE", "memory"),
+			Auth: RateLimitPolicy{
+				Limit:  src.int("RATE_LIMIT_AUTH_LIMIT", 10),
+				Period: src.duration("RATE_LIMIT_AUTH_PERIOD", time.Minute),
+			},
+			User: RateLimitPolicy{
+				Limit:  src.int("RATE_LIMIT_USER_LIMIT", 120),
+				Period: src.duration("RATE_LIMIT_USER_PERIOD", time.Minute),
+			},
+			Tarik: RateLimitPolicy{
+				Limit:  src.int("RATE_LIMIT_TARIK_LIMIT", 10),
+				Period: src.duration("RATE_LIMIT_TARIK_PERIOD", time.Minute),
+			},
+		},
+		Metrics: MetricsConfig{
+			Addr: src.str("METRICS_ADDR", ""),
+		},
+		GRPC: GRPCConfig{
+			Addr: src.str("GRPC_ADDR", ":9090"),
+		},
+		Health: HealthConfig{
+			Timeout: src.duration("HEALTH_TIMEOUT", 2*time.Second),
+		},
+		Tracing: TracingConfig{
+			Exporter:     src.str("TRACING_EXPORTER", "none"),
+			FilePath:     src.str("TRACING_FILE_PATH", "traces.json"),
+			OTLPEndpoint: src.str("TRACING_OTLP_ENDPOINT", ""),
+			OTLPInsecure: src.bool("TRACING_OTLP_INSECURE", false),
+			SampleRatio:  src.float("TRACING_SAMPLE_RATIO", 1),
+			ServiceName:  src.str("TRACING_SERVICE_NAME", "gobanking"),
 		},
-		Logger: logger,
 	}
+
+	if err := errors.Join(append(src.errs, cfg.validate()...)...); err != nil {
+		return nil, err
+	}
+
+	cfg.Logger = newLogger(cfg)
+	slog.SetDefault(cfg.Logger)
+	return cfg, nil
+}
+
+// newLogger sets up structured logging. Personal data is masked according
+// to the environment; only the audit sink, if configured, sees raw values.
+func newLogger(cfg *Config) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: cfg.Log.level()}
+	newHandler := func(w io.Writer) slog.Handler {
+		if cfg.Log.Format == "text" {
+			return slog.NewTextHandler(w, opts)
+		}
+		return slog.NewJSONHandler(w, opts)
+	}
+
+	var handler slog.Handler = logging.NewMaskingHandler(newHandler(os.Stdout), logging.PolicyFor(cfg.Env))
+
+	if cfg.Log.AuditPath != "" {
+		audit, err := os.OpenFile(cfg.Log.AuditPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			slog.Error("gagal membuka audit log", "path", cfg.Log.AuditPath, "error", err)
+		} else {
+			handler = logging.NewTeeHandler(handler, slog.NewJSONHandler(audit, opts))
+		}
+	}
+
+	return slog.New(handler)
 }
 
 func (c *DatabaseConfig) DSN() string {
 	return fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		c.Host, c.User, c.Password, c.Name, c.Port,
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		c.Host, c.User, c.Password, c.Name, c.Port, c.SSLMode,
 	)
 }
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// source resolves a key through the configuration layers. From highest to
+// lowest precedence: command-line flags, the environment variable KEY, a
+// file named by KEY_FILE (Docker secrets), the YAML config file, and
+// finally the built-in default.
+//
+// Keys are the environment variable names. The YAML file uses the same
+// names split into nested sections, so db.pool.max_open in the file is
+// DB_POOL_MAX_OPEN in the environment.
+type source struct {
+	flags map[string]string
+	file  map[string]string
+	errs  []error
+}
+
+func (s *source) errorf(key, format string, args ...any) {
+	s.errs = append(s.errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+}
+
+func (s *source) lookup(key string) (string, bool) {
+	if value, ok := s.flags[key]; ok {
+		return value, true
+	}
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value, true
+	}
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			s.errorf(key+"_FILE", "gagal membaca %s: %v", path, err)
+			return "", false
+		}
+		return strings.TrimRight(string(b), "\r\n"), true
+	}
+	if value, ok := s.file[key]; ok && value != "" {
+		return value, true
+	}
+	return "", false
+}
+
+func (s *source) str(key, fallback string) string {
+	if value, ok := s.lookup(key); ok {
+		return value
+	}
+	return fallback
+}
+
+func (s *source) list(key string, fallback []string) []string {
+	value, ok := s.lookup(key)
+	if !ok {
+		if _, set := os.LookupEnv(key); set {
+			// An explicitly empty variable clears the list.
+			return nil
+		}
+		return fallback
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func (s *source) int(key string, fallback int) int {
+	value, ok := s.lookup(key)
+	if !ok {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		s.errorf(key, "harus bilangan bulat, didapat %q", value)
+		return fallback
+	}
+	return n
+}
+
+func (s *source) float(key string, fallback float64) float64 {
+	value, ok := s.lookup(key)
+	if !ok {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		s.errorf(key, "harus angka, didapat %q", value)
+		return fallback
+	}
+	return f
+}
+
+func (s *source) bool(key string, fallback bool) bool {
+	value, ok := s.lookup(key)
+	if !ok {
+		return fallback
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		s.errorf(key, "harus true atau false, didapat %q", value)
+		return fallback
+	}
+	return b
+}
+
+func (s *source) duration(key string, fallback time.Duration) time.Duration {
+	value, ok := s.lookup(key)
+	if !ok {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		s.errorf(key, "harus durasi seperti 30s atau 5m, didapat %q", value)
+		return fallback
+	}
+	return d
+}
+
+// readFile loads a YAML config file and flattens it into environment-style
+// keys.
+func readFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	flat := make(map[string]string)
+	flatten("", doc, flat)
+	return flat, nil
+}
+
+func flatten(prefix string, node map[string]any, out map[string]string) {
+	keys := make([]string, 0, len(node))
+	for k := range node {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key := strings.ToUpper(k)
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		switch v := node[k].(type) {
+		case map[string]any:
+			flatten(key, v, out)
+		case []any:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			out[key] = strings.Join(items, ",")
+		case nil:
+		default:
+			out[key] = fmt.Sprint(v)
+		}
+	}
+}
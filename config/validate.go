@@ -0,0 +1,200 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"os"
+	"strings"
+	"time"
+	// Embedded so SCHEDULE_TIMEZONE resolves in images without zoneinfo.
+	_ "time/tzdata"
+)
+
+// minSecretLength is the shortest JWT_SECRET accepted; HS256 keys should be
+// at least as long as the hash output.
+const minSecretLength = 32
+
+// weakSecrets are placeholder values that must never reach a running server.
+var weakSecrets = []string{
+	"your-super-secret-key-change-this-in-production",
+	"secret",
+	"changeme",
+	"jwt-secret",
+}
+
+func (c *Config) validate() []error {
+	var errs []error
+	fail := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+	oneOf := func(key, value string, allowed ...string) {
+		for _, a := range allowed {
+			if value == a {
+				return
+			}
+		}
+		fail(key, "harus salah satu dari %s, didapat %q", strings.Join(allowed, ", "), value)
+	}
+	positive := func(key string, n int64) {
+		if n <= 0 {
+			fail(key, "harus lebih dari 0")
+		}
+	}
+	production := c.Env == "production"
+
+	// Secrets
+	switch secret := c.JWT.Secret; {
+	case secret == "":
+		fail("JWT_SECRET", "wajib diisi, buat dengan `openssl rand -base64 48`")
+	case isWeakSecret(secret):
+		fail("JWT_SECRET", "masih memakai nilai contoh, buat dengan `openssl rand -base64 48`")
+	case len(secret) < minSecretLength:
+		fail("JWT_SECRET", "minimal %d karakter, didapat %d", minSecretLength, len(secret))
+	}
+
+	// Server
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		fail("SERVER_PORT", "harus antara 1 dan 65535, didapat %d", c.Server.Port)
+	}
+	positive("SERVER_READ_TIMEOUT", int64(c.Server.ReadTimeout))
+	positive("SERVER_WRITE_TIMEOUT", int64(c.Server.WriteTimeout))
+	positive("SERVER_IDLE_TIMEOUT", int64(c.Server.IdleTimeout))
+	positive("SHUTDOWN_TIMEOUT", int64(c.Server.ShutdownTimeout))
+	if c.Server.DrainDelay < 0 {
+		fail("SHUTDOWN_DRAIN_DELAY", "tidak boleh negatif")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, err := netip.ParsePrefix(proxy); err == nil {
+			continue
+		}
+		if _, err := netip.ParseAddr(proxy); err != nil {
+			fail("SERVER_TRUSTED_PROXIES", "bukan IP atau CIDR: %q", proxy)
+		}
+	}
+
+	// Logging
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		fail("LOG_LEVEL", "harus salah satu dari debug, info, warn, error, didapat %q", c.Log.Level)
+	}
+	oneOf("LOG_FORMAT", c.Log.Format, "json", "text")
+
+	// Database
+	required := func(key, value string) {
+		if value == "" {
+			fail(key, "wajib diisi")
+		}
+	}
+	required("DB_HOST", c.Database.Host)
+	required("DB_USER", c.Database.User)
+	required("DB_NAME", c.Database.Name)
+	if production && c.Database.Password == "" {
+		fail("DB_PASSWORD", "wajib diisi di production")
+	}
+	oneOf("DB_SSLMODE", c.Database.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+	if production && !strings.HasPrefix(c.Database.SSLMode, "verify-") && c.Database.SSLMode != "require" {
+		fail("DB_SSLMODE", "harus require, verify-ca atau verify-full di production")
+	}
+	positive("DB_CONNECT_TIMEOUT", int64(c.Database.ConnectTimeout))
+	positive("DB_POOL_MAX_OPEN", int64(c.Database.MaxOpenConns))
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		fail("DB_POOL_MAX_IDLE", "harus antara 0 dan DB_POOL_MAX_OPEN (%d)", c.Database.MaxOpenConns)
+	}
+
+	// Workers and limits
+	positive("WEBHOOK_MAX_ATTEMPTS", int64(c.Webhook.MaxAttempts))
+	positive("WEBHOOK_BASE_BACKOFF", int64(c.Webhook.BaseBackoff))
+	positive("WEBHOOK_TIMEOUT", int64(c.Webhook.Timeout))
+	positive("WEBHOOK_POLL_INTERVAL", int64(c.Webhook.PollInterval))
+	if production && c.Webhook.AllowPrivateTargets {
+		fail("WEBHOOK_ALLOW_PRIVATE_TARGETS", "tidak boleh aktif di production")
+	}
+	positive("PAYROLL_MAX_ITEMS", int64(c.Payroll.MaxItems))
+	positive("PAYROLL_CHUNK_SIZE", int64(c.Payroll.ChunkSize))
+	positive("PAYROLL_POLL_INTERVAL", int64(c.Payroll.PollInterval))
+	if _, err := time.LoadLocation(c.Schedule.TimeZone); err != nil {
+		fail("SCHEDULE_TIMEZONE", "zona waktu tidak dikenal: %q", c.Schedule.TimeZone)
+	}
+	if c.Schedule.RetryDays < 0 {
+		fail("SCHEDULE_RETRY_DAYS", "tidak boleh negatif")
+	}
+	positive("SCHEDULE_RETRY_INTERVAL", int64(c.Schedule.RetryInterval))
+	positive("SCHEDULE_POLL_INTERVAL", int64(c.Schedule.PollInterval))
+	if c.Reversal.ApprovalThreshold < 0 {
+		fail("REVERSAL_APPROVAL_THRESHOLD", "tidak boleh negatif")
+	}
+	if _, err := time.LoadLocation(c.EOD.TimeZone); err != nil {
+		fail("EOD_TIMEZONE", "zona waktu tidak dikenal: %q", c.EOD.TimeZone)
+	}
+	if c.EOD.InterestRate < 0 || c.EOD.InterestRate > 1 {
+		fail("EOD_INTEREST_RATE", "harus antara 0 dan 1")
+	}
+	if c.EOD.TaxRate < 0 || c.EOD.TaxRate > 1 {
+		fail("EOD_TAX_RATE", "harus antara 0 dan 1")
+	}
+	if c.EOD.MonthlyFee < 0 {
+		fail("EOD_MONTHLY_FEE", "tidak boleh negatif")
+	}
+	if c.EOD.HolidayFile != "" {
+		// Parsed by eod.LoadCalendar when a run starts; a missing file is
+		// caught here rather than at the first cut-off.
+		if info, err := os.Stat(c.EOD.HolidayFile); err != nil {
+			fail("EOD_HOLIDAY_FILE", "tidak bisa dibaca: %v", err)
+		} else if info.IsDir() {
+			fail("EOD_HOLIDAY_FILE", "harus file, bukan direktori: %q", c.EOD.HolidayFile)
+		}
+	}
+	positive("EOD_DORMANT_DAYS", int64(c.EOD.DormantDays))
+	if c.Recon.AmountTolerance < 0 {
+		fail("RECON_AMOUNT_TOLERANCE", "tidak boleh negatif")
+	}
+	if c.Recon.DateToleranceDays < 0 {
+		fail("RECON_DATE_TOLERANCE_DAYS", "tidak boleh negatif")
+	}
+	positive("RECON_MAX_ENTRIES", int64(c.Recon.MaxEntries))
+	positive("NOTIFIER_QUEUE_SIZE", int64(c.Notifier.QueueSize))
+	positive("NOTIFIER_WORKERS", int64(c.Notifier.Workers))
+	positive("OTP_MAX_ATTEMPTS", int64(c.OTP.MaxAttempts))
+	positive("OTP_TTL", int64(c.OTP.TTL))
+	positive("MFA_CHALLENGE_TTL", int64(c.MFA.ChallengeTTL))
+	positive("MFA_MAX_ATTEMPTS", int64(c.MFA.MaxAttempts))
+	positive("LOCKOUT_MAX_FAILURES", int64(c.Lockout.MaxFailures))
+	positive("LOCKOUT_MAX_IP_FAILURES", int64(c.Lockout.MaxIPFailures))
+	positive("LOCKOUT_WINDOW", int64(c.Lockout.Window))
+	positive("LOCKOUT_DURATION", int64(c.Lockout.Duration))
+	positive("LOCKOUT_BASE_DELAY", int64(c.Lockout.BaseDelay))
+	positive("LOCKOUT_MAX_DELAY", int64(c.Lockout.MaxDelay))
+	if c.Lockout.BaseDelay > c.Lockout.MaxDelay {
+		fail("LOCKOUT_BASE_DELAY", "tidak boleh lebih dari LOCKOUT_MAX_DELAY (%s)", c.Lockout.MaxDelay)
+	}
+	oneOf("RATE_LIMIT_STORE", c.RateLimit.Store, "memory", "postgres")
+	policy := func(name string, p RateLimitPolicy) {
+		positive("RATE_LIMIT_"+name+"_LIMIT", int64(p.Limit))
+		positive("RATE_LIMIT_"+name+"_PERIOD", int64(p.Period))
+	}
+	policy("AUTH", c.RateLimit.Auth)
+	policy("USER", c.RateLimit.User)
+	policy("TARIK", c.RateLimit.Tarik)
+	positive("HEALTH_TIMEOUT", int64(c.Health.Timeout))
+
+	// Tracing
+	oneOf("TRACING_EXPORTER", c.Tracing.Exporter, "none", "stdout", "file", "otlp")
+	if c.Tracing.Exporter == "otlp" && c.Tracing.OTLPEndpoint == "" {
+		fail("TRACING_OTLP_ENDPOINT", "wajib diisi jika TRACING_EXPORTER=otlp")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("TRACING_SAMPLE_RATIO", "harus antara 0 dan 1")
+	}
+
+	return errs
+}
+
+func isWeakSecret(secret string) bool {
+	for _, weak := range weakSecrets {
+		if strings.EqualFold(secret, weak) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,12 +1,17 @@
 package database
 
 import (
+	"context"
 	"gobanking/config"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// Connect opens the database, retrying with exponential backoff until
+// cfg.Database.ConnectTimeout so the app survives a database that starts
+// slower than it does.
 func Connect(cfg *config.Config) (*gorm.DB, error) {
 	cfg.Logger.Info("menghubungkan database",
 		"host", cfg.Database.Host,
@@ -14,11 +19,44 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 		"database", cfg.Database.Name,
 	)
 
-	db, err := gorm.Open(postgres.Open(cfg.Database.DSN()), &gorm.Config{})
-	if err != nil {
-		cfg.Logger.Error("gagal menghubungkan database", "error", err)
-		return nil, err
+	deadline := time.Now().Add(cfg.Database.ConnectTimeout)
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		db, err := gorm.Open(postgres.Open(cfg.Database.DSN()), &gorm.Config{})
+		if err == nil {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return nil, err
+			}
+			sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+			sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+			sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+			sqlDB.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
+			return db, nil
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			cfg.Logger.Error("gagal menghubungkan database", "attempt", attempt, "error", err)
+			return nil, err
+		}
+
+		cfg.Logger.Warn("database belum tersedia, mencoba lagi",
+			"attempt", attempt,
+			"retry_in", backoff.String(),
+			"error", err,
+		)
+		time.Sleep(backoff)
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
 	}
+}
 
-	return db, nil
+// Ping checks that the database answers within ctx.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
 }
@@ -0,0 +1,82 @@
+// Package databasetest gives tests a freshly migrated Postgres database.
+// Tests that need one are skipped unless TEST_DATABASE_URL points at a
+// server the tests may create schemas on.
+package databasetest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"gobanking/database"
+	"os"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// EnvURL names the variable holding the Postgres connection string, in URL
+// or key=value form.
+const EnvURL = "TEST_DATABASE_URL"
+
+// Open returns a database on its own schema with every migration applied.
+// The schema is dropped when the test ends, so each call starts empty
+// except for the rows the migrations seed.
+func Open(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv(EnvURL)
+	if dsn == "" {
+		t.Skipf("%s tidak diisi, test Postgres dilewati", EnvURL)
+	}
+	config := &gorm.Config{Logger: logger.Discard}
+
+	admin, err := gorm.Open(postgres.Open(dsn), config)
+	if err != nil {
+		t.Fatalf("membuka %s: %v", EnvURL, err)
+	}
+	schema := "test_" + suffix(t)
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("membuat schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	db, err := gorm.Open(postgres.Open(withSearchPath(dsn, schema)), config)
+	if err != nil {
+		t.Fatalf("membuka schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if _, err := database.MigrateUp(context.Background(), db); err != nil {
+		t.Fatalf("migrasi: %v", err)
+	}
+	return db
+}
+
+// withSearchPath makes every connection of the pool use schema.
+func withSearchPath(dsn, schema string) string {
+	if !strings.Contains(dsn, "://") {
+		return dsn + " search_path=" + schema
+	}
+	if strings.Contains(dsn, "?") {
+		return dsn + "&search_path=" + schema
+	}
+	return dsn + "?search_path=" + schema
+}
+
+func suffix(t *testing.T) string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,261 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey is the pg_advisory_lock key held while migrating so that
+// only one replica applies migrations at a time.
+const migrationLockKey = 7_362_041_339
+
+var migrationName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is one versioned schema change with its rollback.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// SchemaMigration is a row of the schema_migrations table.
+type SchemaMigration struct {
+	Version   int    `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"not null"`
+	AppliedAt time.Time
+}
+
+// Migrations returns the embedded migrations ordered by version.
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		m := migrationName.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("nama file migrasi tidak valid: %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+		body, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != m[2] {
+			return nil, fmt.Errorf("versi migrasi %d dipakai dua nama: %s dan %s", version, migration.Name, m[2])
+		}
+		if m[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migrasi %04d_%s tidak punya file up", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// MigrateUp applies every pending migration, each in its own transaction.
+func MigrateUp(ctx context.Context, db *gorm.DB) ([]Migration, error) {
+	var applied []Migration
+	err := withMigrationLock(ctx, db, func(conn *gorm.DB) error {
+		migrations, done, err := load(conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(m.Up).Error; err != nil {
+					return err
+				}
+				return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migrasi %04d_%s gagal: %w", m.Version, m.Name, err)
+			}
+			applied = append(applied, m)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// MigrateDown rolls back the latest steps applied migrations.
+func MigrateDown(ctx context.Context, db *gorm.DB, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := withMigrationLock(ctx, db, func(conn *gorm.DB) error {
+		migrations, done, err := load(conn)
+		if err != nil {
+			return err
+		}
+		for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			m := migrations[i]
+			if _, ok := done[m.Version]; !ok {
+				continue
+			}
+			if m.Down == "" {
+				return fmt.Errorf("migrasi %04d_%s tidak bisa di-rollback: file down tidak ada", m.Version, m.Name)
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(m.Down).Error; err != nil {
+					return err
+				}
+				return tx.Delete(&SchemaMigration{}, m.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("rollback %04d_%s gagal: %w", m.Version, m.Name, err)
+			}
+			reverted = append(reverted, m)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists every known migration and when it was applied.
+func Status(ctx context.Context, db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, done, err := load(db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	status := make([]MigrationStatus, len(migrations))
+	for i, m := range migrations {
+		status[i].Migration = m
+		if row, ok := done[m.Version]; ok {
+			status[i].AppliedAt = &row.AppliedAt
+		}
+	}
+	return status, nil
+}
+
+// CheckMigrations reports an error unless the database is at the latest
+// embedded migration.
+func CheckMigrations(ctx context.Context, db *gorm.DB) error {
+	status, err := Status(ctx, db)
+	if err != nil {
+		return err
+	}
+	var pending []string
+	for _, s := range status {
+		if s.AppliedAt == nil {
+			pending = append(pending, fmt.Sprintf("%04d_%s", s.Version, s.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("migrasi belum dijalankan: %s", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// CreateMigration writes an empty up/down pair with the next version number
+// into dir and returns their paths.
+func CreateMigration(dir, name string) (string, string, error) {
+	name = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "_"))
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return "", "", errors.New("nama migrasi hanya boleh huruf kecil, angka dan _")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", err
+	}
+	next := 1
+	for _, entry := range entries {
+		if m := migrationName.FindStringSubmatch(entry.Name()); m != nil {
+			if v, _ := strconv.Atoi(m[1]); v >= next {
+				next = v + 1
+			}
+		}
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%04d_%s", next, name))
+	up, down := base+".up.sql", base+".down.sql"
+	if err := os.WriteFile(up, []byte("-- "+name+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(down, []byte("-- rollback "+name+"\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	return up, down, nil
+}
+
+// withMigrationLock runs fn on a single connection holding the migration
+// advisory lock. Replicas that start together wait here and then find
+// nothing left to apply.
+func withMigrationLock(ctx context.Context, db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	return db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey)
+
+		if err := conn.Exec(`CREATE TABLE IF NOT EXISTS "schema_migrations" (
+			"version" bigint PRIMARY KEY,
+			"name" text NOT NULL,
+			"applied_at" timestamptz NOT NULL DEFAULT now()
+		)`).Error; err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+func load(db *gorm.DB) ([]Migration, map[int]SchemaMigration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	done := make(map[int]SchemaMigration)
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return migrations, done, nil
+	}
+	var rows []SchemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, row := range rows {
+		done[row.Version] = row
+	}
+	return migrations, done, nil
+}
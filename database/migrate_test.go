@@ -0,0 +1,145 @@
+package database_test
+
+import (
+	"context"
+	"gobanking/database"
+	"gobanking/database/databasetest"
+	"gobanking/gl"
+	"gobanking/model"
+	"testing"
+	"time"
+)
+
+// TestOpeningBalanceBackfill rolls back to before 0012, books accounts whose
+// balance predates their journal and a conversion without its FX gain, and
+// checks that migrating up posts both and balances the trial balance.
+func TestOpeningBalanceBackfill(t *testing.T) {
+	ctx := context.Background()
+	db := databasetest.Open(t)
+	if _, err := database.MigrateDown(ctx, db, 2); err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+
+	now := time.Now()
+	tanggal := now.Truncate(24 * time.Hour)
+	idr := model.Nasabah{Nama: "Budi", NIK: "nik-1", NoHP: "081", NoRekening: "1001", Saldo: 500_000, MataUang: "IDR"}
+	usd := model.Nasabah{Nama: "Sari", NIK: "nik-2", NoHP: "082", NoRekening: "1002", Saldo: 90, MataUang: "USD"}
+	for _, n := range []*model.Nasabah{&idr, &usd} {
+		if err := db.Create(n).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	tabung := model.Transaksi{NasabahID: idr.ID, NoRekening: idr.NoRekening, Jenis: model.JenisTabung, Nominal: 200_000, SaldoAkhir: 500_000, MataUang: "IDR", TanggalBisnis: tanggal}
+	keluar := model.Transaksi{NasabahID: usd.ID, NoRekening: usd.NoRekening, Jenis: model.JenisTransferKeluar, Nominal: 10, SaldoAkhir: 90, MataUang: "USD", TanggalBisnis: tanggal}
+	masuk := model.Transaksi{NasabahID: idr.ID, NoRekening: idr.NoRekening, Jenis: model.JenisTransferMasuk, Nominal: 149_000, SaldoAkhir: 500_000, MataUang: "IDR", TanggalBisnis: tanggal}
+	for _, tr := range []*model.Transaksi{&tabung, &keluar, &masuk} {
+		if err := db.Create(tr).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	jurnal := []model.JurnalGL{
+		{TransaksiID: tabung.ID, Jenis: tabung.Jenis, AkunDebit: model.AkunKas, AkunKredit: model.AkunSimpananNasabah, Nominal: 200_000, MataUang: "IDR", Tanggal: now},
+		{TransaksiID: keluar.ID, Jenis: keluar.Jenis, AkunDebit: model.AkunSimpananNasabah, AkunKredit: model.AkunKliring, Nominal: 10, MataUang: "USD", Tanggal: now},
+		{TransaksiID: masuk.ID, Jenis: masuk.Jenis, AkunDebit: model.AkunKliring, AkunKredit: model.AkunSimpananNasabah, Nominal: 149_000, MataUang: "IDR", Tanggal: now},
+	}
+	if err := db.Create(&jurnal).Error; err != nil {
+		t.Fatal(err)
+	}
+	konversi := model.KonversiValas{
+		TransaksiDebitID: keluar.ID, TransaksiKreditID: masuk.ID,
+		MataUangAsal: "USD", NominalAsal: 10, MataUangTujuan: "IDR", NominalTujuan: 149_000,
+		Kurs: 14_900, LabaRugi: 1000,
+	}
+	if err := db.Create(&konversi).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := database.MigrateUp(ctx, db); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+
+	// 200000 deposited and 149000 received leave 151000 of the IDR 500000
+	// without a journal; the USD account lacks 100.
+	for _, tt := range []struct {
+		nasabah model.Nasabah
+		want    float64
+	}{{idr, 151_000}, {usd, 100}} {
+		var awal []model.Transaksi
+		db.Where("nasabah_id = ? AND jenis = ?", tt.nasabah.ID, model.JenisSaldoAwal).Find(&awal)
+		if len(awal) != 1 || awal[0].Nominal != tt.want || awal[0].MataUang != tt.nasabah.MataUang {
+			t.Fatalf("saldo_awal of %s = %+v, want one of %v", tt.nasabah.NoRekening, awal, tt.want)
+		}
+		var j model.JurnalGL
+		if err := db.Where("transaksi_id = ?", awal[0].ID).First(&j).Error; err != nil {
+			t.Fatalf("saldo_awal journal of %s: %v", tt.nasabah.NoRekening, err)
+		}
+		if j.AkunDebit != model.AkunEkuitasSaldoAwal || j.AkunKredit != model.AkunSimpananNasabah || j.Nominal != tt.want {
+			t.Fatalf("saldo_awal journal = %+v, want %v from equity to customer deposits", j, tt.want)
+		}
+	}
+
+	var laba model.JurnalGL
+	if err := db.Where("transaksi_id = ? AND jenis = ?", keluar.ID, model.JenisLabaKurs).First(&laba).Error; err != nil {
+		t.Fatalf("laba_kurs journal: %v", err)
+	}
+	if laba.AkunDebit != model.AkunKliring || laba.AkunKredit != model.AkunPendapatanKurs || laba.Nominal != 1000 || laba.MataUang != "IDR" {
+		t.Fatalf("laba_kurs journal = %+v, want 1000 IDR from clearing to FX income", laba)
+	}
+
+	nb, err := gl.NewService(db).TrialBalance(ctx)
+	if err != nil {
+		t.Fatalf("TrialBalance: %v", err)
+	}
+	for _, total := range nb.Total {
+		if total.LiabilitasNasabah != total.SaldoNasabah || total.Debit != total.Kredit {
+			t.Errorf("%s after backfill = %+v, want deposits equal to saldo", total.MataUang, total)
+		}
+	}
+}
+
+// TestBaselineAddsLaterColumns starts from the tables as the first
+// AutoMigrate created them, before email, bahasa, role and TOTP existed,
+// and checks that migrating up adds those columns with their defaults.
+func TestBaselineAddsLaterColumns(t *testing.T) {
+	ctx := context.Background()
+	db := databasetest.Open(t)
+	migrations, err := database.Migrations()
+	if err != nil {
+		t.Fatalf("Migrations: %v", err)
+	}
+	if _, err := database.MigrateDown(ctx, db, len(migrations)); err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+
+	for _, stmt := range []string{
+		`CREATE TABLE "nasabahs" ("id" bigserial PRIMARY KEY, "created_at" timestamptz, "updated_at" timestamptz, "deleted_at" timestamptz,
+			"nama" text NOT NULL, "nik" text NOT NULL UNIQUE, "no_hp" text NOT NULL UNIQUE, "no_rekening" text NOT NULL UNIQUE,
+			"saldo" decimal DEFAULT 0, CONSTRAINT "chk_nasabahs_saldo" CHECK (saldo >= 0))`,
+		`CREATE TABLE "users" ("id" bigserial PRIMARY KEY, "created_at" timestamptz, "updated_at" timestamptz, "deleted_at" timestamptz,
+			"email" text NOT NULL UNIQUE, "password" text NOT NULL)`,
+		`INSERT INTO "nasabahs" ("nama", "nik", "no_hp", "no_rekening", "saldo") VALUES ('Budi', 'nik-1', '081', '1001', 0)`,
+		`INSERT INTO "users" ("email", "password") VALUES ('budi@example.com', 'x')`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := database.MigrateUp(ctx, db); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	var nasabah model.Nasabah
+	if err := db.First(&nasabah).Error; err != nil {
+		t.Fatal(err)
+	}
+	if nasabah.Bahasa != "id" || nasabah.Email != "" {
+		t.Fatalf("nasabah = bahasa %q, email %q; want id and none", nasabah.Bahasa, nasabah.Email)
+	}
+	var user model.User
+	if err := db.First(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.Role != model.RoleUser || user.TOTPEnabled || user.TOTPSecret != "" {
+		t.Fatalf("user = role %q, totp %v; want user without TOTP", user.Role, user.TOTPEnabled)
+	}
+}
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS "rate_limit_buckets";
+DROP TABLE IF EXISTS "login_throttles";
+DROP TABLE IF EXISTS "backup_codes";
+DROP TABLE IF EXISTS "penerimas";
+DROP TABLE IF EXISTS "otp_challenges";
+DROP TABLE IF EXISTS "webhook_deliveries";
+DROP TABLE IF EXISTS "webhook_subscriptions";
+DROP TABLE IF EXISTS "transaksis";
+DROP TABLE IF EXISTS "users";
+DROP TABLE IF EXISTS "nasabahs";
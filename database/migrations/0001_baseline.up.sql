@@ -0,0 +1,167 @@
+-- Baseline: the schema previously created by gorm AutoMigrate. Every
+-- statement is idempotent so databases created by AutoMigrate adopt it
+-- without changes, and databases AutoMigrate created before a column
+-- existed get that column.
+
+CREATE TABLE IF NOT EXISTS "nasabahs" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "nama" text NOT NULL,
+    "nik" text NOT NULL UNIQUE,
+    "no_hp" text NOT NULL UNIQUE,
+    "email" text,
+    "bahasa" text NOT NULL DEFAULT 'id',
+    "no_rekening" text NOT NULL UNIQUE,
+    "saldo" decimal DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "chk_nasabahs_saldo" CHECK (saldo >= 0)
+);
+-- Added by AutoMigrate after the table was first created, so older
+-- databases may lack them.
+ALTER TABLE "nasabahs" ADD COLUMN IF NOT EXISTS "email" text;
+ALTER TABLE "nasabahs" ADD COLUMN IF NOT EXISTS "bahasa" text NOT NULL DEFAULT 'id';
+CREATE INDEX IF NOT EXISTS "idx_nasabahs_deleted_at" ON "nasabahs" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "email" text NOT NULL UNIQUE,
+    "password" text NOT NULL,
+    "role" text NOT NULL DEFAULT 'user',
+    "totp_secret" text,
+    "totp_enabled" boolean NOT NULL DEFAULT false,
+    PRIMARY KEY ("id")
+);
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "role" text NOT NULL DEFAULT 'user';
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "totp_secret" text;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "totp_enabled" boolean NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "transaksis" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "nasabah_id" bigint NOT NULL,
+    "no_rekening" text NOT NULL,
+    "jenis" text NOT NULL,
+    "nominal" decimal NOT NULL,
+    "saldo_akhir" decimal NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_transaksis_nasabah_id" ON "transaksis" ("nasabah_id");
+CREATE INDEX IF NOT EXISTS "idx_transaksis_no_rekening" ON "transaksis" ("no_rekening");
+CREATE INDEX IF NOT EXISTS "idx_transaksis_deleted_at" ON "transaksis" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "webhook_subscriptions" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" bigint NOT NULL,
+    "url" text NOT NULL,
+    "events" text NOT NULL,
+    "no_rekening" text,
+    "secret" text NOT NULL,
+    "aktif" boolean NOT NULL DEFAULT true,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_webhook_subscriptions_user_id" ON "webhook_subscriptions" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_webhook_subscriptions_no_rekening" ON "webhook_subscriptions" ("no_rekening");
+CREATE INDEX IF NOT EXISTS "idx_webhook_subscriptions_deleted_at" ON "webhook_subscriptions" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "webhook_deliveries" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "subscription_id" bigint NOT NULL,
+    "event" text NOT NULL,
+    "payload" text NOT NULL,
+    "status" text NOT NULL,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "next_attempt_at" timestamptz,
+    "last_status_code" bigint,
+    "last_error" text,
+    "delivered_at" timestamptz,
+    "replay_of" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_subscription_id" ON "webhook_deliveries" ("subscription_id");
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_status" ON "webhook_deliveries" ("status");
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_next_attempt_at" ON "webhook_deliveries" ("next_attempt_at");
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_deleted_at" ON "webhook_deliveries" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "otp_challenges" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" bigint NOT NULL,
+    "purpose" text NOT NULL,
+    "method" text NOT NULL,
+    "code_hash" text NOT NULL,
+    "target" text NOT NULL DEFAULT '',
+    "payload" text NOT NULL,
+    "status" text NOT NULL,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "max_attempts" bigint NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "verified_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_otp_challenges_user_id" ON "otp_challenges" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_otp_challenges_status" ON "otp_challenges" ("status");
+CREATE INDEX IF NOT EXISTS "idx_otp_challenges_deleted_at" ON "otp_challenges" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "penerimas" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "no_rekening" text NOT NULL,
+    "no_rekening_tujuan" text NOT NULL,
+    "alias" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_penerima_rekening_tujuan" ON "penerimas" ("no_rekening", "no_rekening_tujuan");
+CREATE INDEX IF NOT EXISTS "idx_penerimas_deleted_at" ON "penerimas" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "backup_codes" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" bigint NOT NULL,
+    "code_hash" text NOT NULL,
+    "used_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_backup_codes_user_id" ON "backup_codes" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_backup_codes_deleted_at" ON "backup_codes" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "login_throttles" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "key" text NOT NULL,
+    "failures" bigint NOT NULL DEFAULT 0,
+    "last_failure_at" timestamptz NOT NULL,
+    "locked_until" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_login_throttles_key" ON "login_throttles" ("key");
+CREATE INDEX IF NOT EXISTS "idx_login_throttles_locked_until" ON "login_throttles" ("locked_until");
+CREATE INDEX IF NOT EXISTS "idx_login_throttles_deleted_at" ON "login_throttles" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "rate_limit_buckets" (
+    "key" text,
+    "tokens" decimal NOT NULL,
+    "updated_at" timestamptz NOT NULL,
+    PRIMARY KEY ("key")
+);
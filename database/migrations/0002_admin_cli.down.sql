@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "admin_audit_logs";
+ALTER TABLE "nasabahs" DROP COLUMN IF EXISTS "frozen_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "disabled_at";
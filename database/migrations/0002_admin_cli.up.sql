@@ -0,0 +1,22 @@
+-- Operator admin CLI: user disabling, account freezing and an audit trail
+-- of every `gobanking admin` command.
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "disabled_at" timestamptz;
+ALTER TABLE "nasabahs" ADD COLUMN IF NOT EXISTS "frozen_at" timestamptz;
+
+CREATE TABLE IF NOT EXISTS "admin_audit_logs" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "operator" text NOT NULL,
+    "command" text NOT NULL,
+    "args" text NOT NULL,
+    "dry_run" boolean NOT NULL DEFAULT false,
+    "result" text NOT NULL,
+    "error" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_admin_audit_logs_operator" ON "admin_audit_logs" ("operator");
+CREATE INDEX IF NOT EXISTS "idx_admin_audit_logs_command" ON "admin_audit_logs" ("command");
+CREATE INDEX IF NOT EXISTS "idx_admin_audit_logs_deleted_at" ON "admin_audit_logs" ("deleted_at");
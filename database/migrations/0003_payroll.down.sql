@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "payroll_items";
+DROP TABLE IF EXISTS "payroll_batches";
+DROP INDEX IF EXISTS "idx_transaksis_no_rekening_referensi";
+ALTER TABLE "transaksis" DROP COLUMN IF EXISTS "no_rekening_lawan";
+ALTER TABLE "transaksis" DROP COLUMN IF EXISTS "referensi";
@@ -0,0 +1,51 @@
+-- Bulk payroll disbursement: transfers between accounts with an
+-- idempotency key per source account, and the batches that drive them.
+
+ALTER TABLE "transaksis" ADD COLUMN IF NOT EXISTS "referensi" text NOT NULL DEFAULT '';
+ALTER TABLE "transaksis" ADD COLUMN IF NOT EXISTS "no_rekening_lawan" text NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_transaksis_no_rekening_referensi" ON "transaksis" ("no_rekening","referensi") WHERE "referensi" <> '';
+
+CREATE TABLE IF NOT EXISTS "payroll_batches" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" bigint NOT NULL,
+    "no_rekening_sumber" text NOT NULL,
+    "keterangan" text,
+    "status" text NOT NULL,
+    "total_items" bigint NOT NULL DEFAULT 0,
+    "total_nominal" decimal NOT NULL DEFAULT 0,
+    "success_items" bigint NOT NULL DEFAULT 0,
+    "success_nominal" decimal NOT NULL DEFAULT 0,
+    "failed_items" bigint NOT NULL DEFAULT 0,
+    "failed_nominal" decimal NOT NULL DEFAULT 0,
+    "claimed_until" timestamptz,
+    "started_at" timestamptz,
+    "completed_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_payroll_batches_claimed_until" ON "payroll_batches" ("claimed_until");
+CREATE INDEX IF NOT EXISTS "idx_payroll_batches_status" ON "payroll_batches" ("status");
+CREATE INDEX IF NOT EXISTS "idx_payroll_batches_no_rekening_sumber" ON "payroll_batches" ("no_rekening_sumber");
+CREATE INDEX IF NOT EXISTS "idx_payroll_batches_user_id" ON "payroll_batches" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_payroll_batches_deleted_at" ON "payroll_batches" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "payroll_items" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "batch_id" bigint NOT NULL,
+    "baris" bigint NOT NULL,
+    "no_rekening" text NOT NULL,
+    "nama" text,
+    "nominal" decimal NOT NULL,
+    "status" text NOT NULL,
+    "error" text,
+    "transaksi_id" bigint,
+    "processed_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_payroll_items_batch_baris" ON "payroll_items" ("batch_id","baris");
+CREATE INDEX IF NOT EXISTS "idx_payroll_items_deleted_at" ON "payroll_items" ("deleted_at");
@@ -0,0 +1,45 @@
+-- Standing orders: scheduled and future-dated transfers, and the log of
+-- every execution attempt.
+
+CREATE TABLE IF NOT EXISTS "standing_orders" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "user_id" bigint NOT NULL,
+    "no_rekening" text NOT NULL,
+    "no_rekening_tujuan" text NOT NULL,
+    "nominal" decimal NOT NULL,
+    "keterangan" text,
+    "schedule" text NOT NULL,
+    "day_of_month" bigint NOT NULL DEFAULT 0,
+    "cron" text,
+    "start_at" timestamptz NOT NULL,
+    "end_at" timestamptz,
+    "status" text NOT NULL,
+    "next_run_at" timestamptz,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "next_attempt_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_standing_orders_no_rekening" ON "standing_orders" ("no_rekening");
+CREATE INDEX IF NOT EXISTS "idx_standing_orders_user_id" ON "standing_orders" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_standing_orders_deleted_at" ON "standing_orders" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_standing_orders_next_attempt_at" ON "standing_orders" ("next_attempt_at");
+CREATE INDEX IF NOT EXISTS "idx_standing_orders_status" ON "standing_orders" ("status");
+
+CREATE TABLE IF NOT EXISTS "standing_order_runs" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "standing_order_id" bigint NOT NULL,
+    "scheduled_at" timestamptz NOT NULL,
+    "attempt" bigint NOT NULL,
+    "status" text NOT NULL,
+    "error" text,
+    "transaksi_id" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_standing_order_runs_standing_order_id" ON "standing_order_runs" ("standing_order_id");
+CREATE INDEX IF NOT EXISTS "idx_standing_order_runs_deleted_at" ON "standing_order_runs" ("deleted_at");
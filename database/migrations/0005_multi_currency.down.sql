@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS "konversi_valas";
+DROP TABLE IF EXISTS "kurs";
+ALTER TABLE "standing_orders" DROP COLUMN IF EXISTS "mata_uang";
+ALTER TABLE "payroll_batches" DROP COLUMN IF EXISTS "mata_uang";
+ALTER TABLE "transaksis" DROP COLUMN IF EXISTS "mata_uang";
+ALTER TABLE "nasabahs" DROP COLUMN IF EXISTS "mata_uang";
@@ -0,0 +1,43 @@
+-- Multi-currency accounts: an ISO 4217 currency on accounts and amounts,
+-- the FX rate table and the record of every cross-currency conversion.
+-- Existing rows are IDR.
+
+ALTER TABLE "nasabahs" ADD COLUMN IF NOT EXISTS "mata_uang" varchar(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE "transaksis" ADD COLUMN IF NOT EXISTS "mata_uang" varchar(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE "payroll_batches" ADD COLUMN IF NOT EXISTS "mata_uang" varchar(3) NOT NULL DEFAULT 'IDR';
+ALTER TABLE "standing_orders" ADD COLUMN IF NOT EXISTS "mata_uang" varchar(3) NOT NULL DEFAULT 'IDR';
+
+CREATE TABLE IF NOT EXISTS "kurs" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "mata_uang" varchar(3) NOT NULL,
+    "beli" decimal NOT NULL,
+    "jual" decimal NOT NULL,
+    "berlaku_mulai" timestamptz NOT NULL,
+    "sumber" text NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_kurs_mata_uang_berlaku_mulai" ON "kurs" ("mata_uang","berlaku_mulai");
+CREATE INDEX IF NOT EXISTS "idx_kurs_deleted_at" ON "kurs" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "konversi_valas" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "transaksi_debit_id" bigint NOT NULL,
+    "transaksi_kredit_id" bigint NOT NULL,
+    "mata_uang_asal" varchar(3) NOT NULL,
+    "nominal_asal" decimal NOT NULL,
+    "mata_uang_tujuan" varchar(3) NOT NULL,
+    "nominal_tujuan" decimal NOT NULL,
+    "kurs_asal_id" bigint,
+    "kurs_tujuan_id" bigint,
+    "kurs" decimal NOT NULL,
+    "laba_rugi" decimal NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_konversi_valas_transaksi_debit_id" ON "konversi_valas" ("transaksi_debit_id");
+CREATE INDEX IF NOT EXISTS "idx_konversi_valas_deleted_at" ON "konversi_valas" ("deleted_at");
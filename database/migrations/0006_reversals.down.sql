@@ -0,0 +1,8 @@
+-- Fails while any account is still overdrawn: its negative saldo would
+-- violate the restored constraint.
+DROP TABLE IF EXISTS "reversals";
+DROP INDEX IF EXISTS "idx_transaksis_reversal_of_id";
+ALTER TABLE "transaksis" DROP COLUMN IF EXISTS "reversal_of_id";
+ALTER TABLE "nasabahs" DROP CONSTRAINT IF EXISTS "chk_nasabahs_saldo";
+ALTER TABLE "nasabahs" ADD CONSTRAINT "chk_nasabahs_saldo" CHECK (saldo >= 0);
+ALTER TABLE "nasabahs" DROP COLUMN IF EXISTS "overdraft_at";
@@ -0,0 +1,37 @@
+-- Transaction reversals: koreksi entries linked to the transaction they
+-- reverse, the reversal requests with their approval trail, and the
+-- overdraft flag that lets an approved reversal take a balance below zero.
+
+ALTER TABLE "nasabahs" ADD COLUMN IF NOT EXISTS "overdraft_at" timestamptz;
+ALTER TABLE "nasabahs" DROP CONSTRAINT IF EXISTS "chk_nasabahs_saldo";
+ALTER TABLE "nasabahs" ADD CONSTRAINT "chk_nasabahs_saldo" CHECK (saldo >= 0 OR overdraft_at IS NOT NULL);
+
+ALTER TABLE "transaksis" ADD COLUMN IF NOT EXISTS "reversal_of_id" bigint;
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_transaksis_reversal_of_id" ON "transaksis" ("reversal_of_id");
+
+CREATE TABLE IF NOT EXISTS "reversals" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "transaksi_id" bigint NOT NULL,
+    "no_rekening" text NOT NULL,
+    "jenis" text NOT NULL,
+    "nominal" decimal NOT NULL,
+    "mata_uang" varchar(3) NOT NULL DEFAULT 'IDR',
+    "alasan" text NOT NULL,
+    "allow_overdraft" boolean NOT NULL DEFAULT false,
+    "status" text NOT NULL,
+    "requested_by" bigint NOT NULL,
+    "decided_by" bigint,
+    "decided_at" timestamptz,
+    "koreksi_id" bigint,
+    "overdraft" boolean NOT NULL DEFAULT false,
+    "error" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_reversals_transaksi_id" ON "reversals" ("transaksi_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_reversals_pending" ON "reversals" ("transaksi_id") WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS "idx_reversals_no_rekening" ON "reversals" ("no_rekening");
+CREATE INDEX IF NOT EXISTS "idx_reversals_status" ON "reversals" ("status");
+CREATE INDEX IF NOT EXISTS "idx_reversals_deleted_at" ON "reversals" ("deleted_at");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "jurnal_gls";
+DROP TABLE IF EXISTS "aturan_postings";
+DROP TABLE IF EXISTS "akun_gls";
@@ -0,0 +1,80 @@
+-- General ledger: the chart of accounts, the posting rule of each
+-- transaction type and one balanced journal line per transaction. The
+-- default chart and rules are seeded and every existing transaction is
+-- posted, so the trial balance agrees with nasabahs.saldo from the start.
+
+CREATE TABLE IF NOT EXISTS "akun_gls" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "kode" text NOT NULL,
+    "nama" text NOT NULL,
+    "tipe" text NOT NULL,
+    "simpanan_nasabah" boolean NOT NULL DEFAULT false,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_akun_gls_kode" ON "akun_gls" ("kode");
+CREATE INDEX IF NOT EXISTS "idx_akun_gls_deleted_at" ON "akun_gls" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "aturan_postings" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "jenis" text NOT NULL,
+    "akun_debit" text NOT NULL,
+    "akun_kredit" text NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_aturan_postings_jenis" ON "aturan_postings" ("jenis");
+CREATE INDEX IF NOT EXISTS "idx_aturan_postings_deleted_at" ON "aturan_postings" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "jurnal_gls" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "transaksi_id" bigint NOT NULL,
+    "jenis" text NOT NULL,
+    "akun_debit" text NOT NULL,
+    "akun_kredit" text NOT NULL,
+    "nominal" decimal NOT NULL,
+    "mata_uang" varchar(3) NOT NULL DEFAULT 'IDR',
+    "tanggal" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_jurnal_gls_transaksi_id" ON "jurnal_gls" ("transaksi_id");
+CREATE INDEX IF NOT EXISTS "idx_jurnal_gls_akun_debit" ON "jurnal_gls" ("akun_debit");
+CREATE INDEX IF NOT EXISTS "idx_jurnal_gls_akun_kredit" ON "jurnal_gls" ("akun_kredit");
+CREATE INDEX IF NOT EXISTS "idx_jurnal_gls_tanggal" ON "jurnal_gls" ("tanggal");
+CREATE INDEX IF NOT EXISTS "idx_jurnal_gls_deleted_at" ON "jurnal_gls" ("deleted_at");
+
+-- Keep in sync with model.DefaultAturanPosting.
+INSERT INTO "akun_gls" ("created_at", "updated_at", "kode", "nama", "tipe", "simpanan_nasabah") VALUES
+    (now(), now(), '1100', 'Kas', 'asset', false),
+    (now(), now(), '1900', 'Kliring Antar Rekening', 'asset', false),
+    (now(), now(), '2100', 'Simpanan Nasabah', 'liability', true),
+    (now(), now(), '2200', 'Utang Pajak', 'liability', false),
+    (now(), now(), '4100', 'Pendapatan Biaya Administrasi', 'income', false),
+    (now(), now(), '5100', 'Beban Bunga', 'expense', false)
+ON CONFLICT ("kode") DO NOTHING;
+
+INSERT INTO "aturan_postings" ("created_at", "updated_at", "jenis", "akun_debit", "akun_kredit") VALUES
+    (now(), now(), 'tabung', '1100', '2100'),
+    (now(), now(), 'tarik', '2100', '1100'),
+    (now(), now(), 'transfer_keluar', '2100', '1900'),
+    (now(), now(), 'transfer_masuk', '1900', '2100'),
+    (now(), now(), 'koreksi_debit', '2100', '1100'),
+    (now(), now(), 'koreksi_kredit', '1100', '2100'),
+    (now(), now(), 'biaya', '2100', '4100'),
+    (now(), now(), 'bunga', '5100', '2100'),
+    (now(), now(), 'pajak', '2100', '2200')
+ON CONFLICT ("jenis") DO NOTHING;
+
+INSERT INTO "jurnal_gls" ("created_at", "updated_at", "transaksi_id", "jenis", "akun_debit", "akun_kredit", "nominal", "mata_uang", "tanggal")
+SELECT now(), now(), t."id", t."jenis", a."akun_debit", a."akun_kredit", t."nominal", t."mata_uang", t."created_at"
+FROM "transaksis" t
+JOIN "aturan_postings" a ON a."jenis" = t."jenis" AND a."deleted_at" IS NULL
+WHERE t."deleted_at" IS NULL
+ON CONFLICT ("transaksi_id") DO NOTHING;
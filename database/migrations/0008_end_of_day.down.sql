@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "rekening_korans";
+DROP TABLE IF EXISTS "saldo_harians";
+DROP TABLE IF EXISTS "akrual_bungas";
+DROP TABLE IF EXISTS "eod_langkahs";
+DROP TABLE IF EXISTS "eod_runs";
+ALTER TABLE "nasabahs" DROP COLUMN IF EXISTS "dormant_at";
+ALTER TABLE "transaksis" DROP COLUMN IF EXISTS "tanggal_bisnis";
+DROP TABLE IF EXISTS "tanggal_bisnis";
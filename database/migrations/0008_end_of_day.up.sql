@@ -0,0 +1,119 @@
+-- End of day: the business date, transactions booked on it, the runs that
+-- close it with their steps, and what they produce — interest accruals,
+-- daily balance snapshots and monthly statements.
+
+CREATE TABLE IF NOT EXISTS "tanggal_bisnis" (
+    "id" bigserial,
+    "tanggal" date NOT NULL,
+    "berikutnya" date NOT NULL,
+    "cut_off_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+-- The first business date is today in Jakarta, the next the following
+-- weekday; the holiday calendar takes over from the first rollover.
+INSERT INTO "tanggal_bisnis" ("id", "tanggal", "berikutnya", "updated_at")
+SELECT 1, today, today + CASE EXTRACT(ISODOW FROM today) WHEN 5 THEN 3 WHEN 6 THEN 2 ELSE 1 END, now()
+FROM (SELECT (now() AT TIME ZONE 'Asia/Jakarta')::date AS today) d
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE "transaksis" ADD COLUMN IF NOT EXISTS "tanggal_bisnis" date;
+UPDATE "transaksis" SET "tanggal_bisnis" = ("created_at" AT TIME ZONE 'Asia/Jakarta')::date WHERE "tanggal_bisnis" IS NULL;
+ALTER TABLE "transaksis" ALTER COLUMN "tanggal_bisnis" SET NOT NULL;
+CREATE INDEX IF NOT EXISTS "idx_transaksis_tanggal_bisnis" ON "transaksis" ("tanggal_bisnis");
+
+ALTER TABLE "nasabahs" ADD COLUMN IF NOT EXISTS "dormant_at" timestamptz;
+
+CREATE TABLE IF NOT EXISTS "eod_runs" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "tanggal_bisnis" date NOT NULL,
+    "status" text NOT NULL,
+    "langkah" text,
+    "error" text,
+    "started_by" bigint NOT NULL,
+    "lease_until" timestamptz,
+    "finished_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_eod_runs_deleted_at" ON "eod_runs" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_eod_runs_status" ON "eod_runs" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_eod_runs_tanggal_bisnis" ON "eod_runs" ("tanggal_bisnis");
+
+CREATE TABLE IF NOT EXISTS "eod_langkahs" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "run_id" bigint NOT NULL,
+    "langkah" text NOT NULL,
+    "status" text NOT NULL,
+    "jumlah" bigint NOT NULL DEFAULT 0,
+    "error" text,
+    "started_at" timestamptz NOT NULL,
+    "finished_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_eod_runs_steps" FOREIGN KEY ("run_id") REFERENCES "eod_runs"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_eod_langkahs_run_langkah" ON "eod_langkahs" ("run_id", "langkah");
+CREATE INDEX IF NOT EXISTS "idx_eod_langkahs_deleted_at" ON "eod_langkahs" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "akrual_bungas" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "nasabah_id" bigint NOT NULL,
+    "no_rekening" text NOT NULL,
+    "tanggal_bisnis" date NOT NULL,
+    "saldo" decimal NOT NULL,
+    "hari" bigint NOT NULL,
+    "bunga" decimal NOT NULL,
+    "mata_uang" varchar(3) NOT NULL DEFAULT 'IDR',
+    "transaksi_id" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_akrual_bungas_deleted_at" ON "akrual_bungas" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_akrual_bungas_transaksi_id" ON "akrual_bungas" ("transaksi_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_akrual_bungas_nasabah_tanggal" ON "akrual_bungas" ("nasabah_id", "tanggal_bisnis");
+
+CREATE TABLE IF NOT EXISTS "saldo_harians" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "nasabah_id" bigint NOT NULL,
+    "no_rekening" text NOT NULL,
+    "tanggal_bisnis" date NOT NULL,
+    "saldo" decimal NOT NULL,
+    "mata_uang" varchar(3) NOT NULL DEFAULT 'IDR',
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_saldo_harians_no_rekening" ON "saldo_harians" ("no_rekening");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_saldo_harians_nasabah_tanggal" ON "saldo_harians" ("nasabah_id", "tanggal_bisnis");
+CREATE INDEX IF NOT EXISTS "idx_saldo_harians_deleted_at" ON "saldo_harians" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "rekening_korans" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "nasabah_id" bigint NOT NULL,
+    "no_rekening" text NOT NULL,
+    "periode" text NOT NULL,
+    "tanggal_awal" date NOT NULL,
+    "tanggal_akhir" date NOT NULL,
+    "mata_uang" varchar(3) NOT NULL DEFAULT 'IDR',
+    "saldo_awal" decimal NOT NULL,
+    "total_kredit" decimal NOT NULL,
+    "total_debit" decimal NOT NULL,
+    "saldo_akhir" decimal NOT NULL,
+    "jumlah_transaksi" bigint NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_rekening_korans_no_rekening" ON "rekening_korans" ("no_rekening");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_rekening_korans_nasabah_periode" ON "rekening_korans" ("nasabah_id", "periode");
+CREATE INDEX IF NOT EXISTS "idx_rekening_korans_deleted_at" ON "rekening_korans" ("deleted_at");
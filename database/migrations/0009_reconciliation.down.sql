@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "rekonsiliasi_items";
+DROP TABLE IF EXISTS "rekonsiliasis";
+DROP INDEX IF EXISTS "idx_transaksis_tabung_referensi";
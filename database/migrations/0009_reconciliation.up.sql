@@ -0,0 +1,59 @@
+-- Reconciliation: imported settlement files of partners and the outcome of
+-- matching each entry against the tabung entries in our books.
+
+-- Settlement entries are looked up by reference across accounts.
+CREATE INDEX IF NOT EXISTS "idx_transaksis_tabung_referensi" ON "transaksis" ("referensi") WHERE "jenis" = 'tabung' AND "referensi" <> '';
+
+CREATE TABLE IF NOT EXISTS "rekonsiliasis" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "mitra" text NOT NULL,
+    "nama_file" text,
+    "format" text NOT NULL,
+    "checksum" text NOT NULL,
+    "imported_by" bigint NOT NULL,
+    "tanggal_awal" date NOT NULL,
+    "tanggal_akhir" date NOT NULL,
+    "total_entri" bigint NOT NULL DEFAULT 0,
+    "diabaikan" bigint NOT NULL DEFAULT 0,
+    "matched" bigint NOT NULL DEFAULT 0,
+    "missing_internal" bigint NOT NULL DEFAULT 0,
+    "missing_external" bigint NOT NULL DEFAULT 0,
+    "amount_mismatch" bigint NOT NULL DEFAULT 0,
+    "open" bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_rekonsiliasis_deleted_at" ON "rekonsiliasis" ("deleted_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_rekonsiliasis_checksum" ON "rekonsiliasis" ("checksum");
+CREATE INDEX IF NOT EXISTS "idx_rekonsiliasis_mitra" ON "rekonsiliasis" ("mitra");
+
+CREATE TABLE IF NOT EXISTS "rekonsiliasi_items" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "rekonsiliasi_id" bigint NOT NULL,
+    "status" text NOT NULL,
+    "baris" bigint NOT NULL DEFAULT 0,
+    "referensi" text NOT NULL DEFAULT '',
+    "no_rekening" text,
+    "mata_uang" varchar(3) NOT NULL DEFAULT 'IDR',
+    "nominal_eksternal" decimal NOT NULL DEFAULT 0,
+    "tanggal_eksternal" date,
+    "transaksi_id" bigint,
+    "nominal_internal" decimal NOT NULL DEFAULT 0,
+    "tanggal_internal" date,
+    "selisih" decimal NOT NULL DEFAULT 0,
+    "catatan" text,
+    "resolved_by" bigint,
+    "resolved_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_rekonsiliasi_items_deleted_at" ON "rekonsiliasi_items" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_rekonsiliasi_items_rekonsiliasi_id" ON "rekonsiliasi_items" ("rekonsiliasi_id");
+CREATE INDEX IF NOT EXISTS "idx_rekonsiliasi_items_status" ON "rekonsiliasi_items" ("status");
+CREATE INDEX IF NOT EXISTS "idx_rekonsiliasi_items_referensi" ON "rekonsiliasi_items" ("referensi");
+-- A deposit is paired with at most one settlement entry.
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_rekonsiliasi_items_transaksi" ON "rekonsiliasi_items" ("transaksi_id") WHERE status <> 'missing_external' AND deleted_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE "users" DROP COLUMN IF EXISTS "mfa_attempts";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "mfa_jti";
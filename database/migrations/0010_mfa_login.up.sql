@@ -0,0 +1,4 @@
+-- Single-use MFA tokens: the pending second login step of each user and
+-- the codes it has tried.
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "mfa_jti" text NOT NULL DEFAULT '';
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "mfa_attempts" bigint NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "idx_rate_limit_buckets_updated_at";
@@ -0,0 +1,2 @@
+-- Idle rate limit buckets are pruned by age.
+CREATE INDEX IF NOT EXISTS "idx_rate_limit_buckets_updated_at" ON "rate_limit_buckets" ("updated_at");
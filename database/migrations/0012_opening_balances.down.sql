@@ -0,0 +1,6 @@
+DELETE FROM "jurnal_gls" WHERE "jenis" IN ('saldo_awal', 'laba_kurs', 'rugi_kurs');
+DELETE FROM "transaksis" WHERE "jenis" = 'saldo_awal';
+DELETE FROM "aturan_postings" WHERE "jenis" IN ('saldo_awal', 'laba_kurs', 'rugi_kurs');
+DELETE FROM "akun_gls" WHERE "kode" IN ('3100', '4200', '5200');
+DROP INDEX IF EXISTS "idx_jurnal_gls_transaksi_jenis";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_jurnal_gls_transaksi_id" ON "jurnal_gls" ("transaksi_id");
@@ -0,0 +1,57 @@
+-- Opening balances and FX gains and losses in the general ledger. An
+-- account whose balance predates its journal gets one saldo_awal entry for
+-- the difference, booked against equity, so the ledger replay and the
+-- trial balance agree with nasabahs.saldo. Every currency conversion gets
+-- its gain or loss posted in IDR on its debit leg, which needs a journal
+-- line to be unique per transaction and jenis instead of per transaction.
+
+DROP INDEX IF EXISTS "idx_jurnal_gls_transaksi_id";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_jurnal_gls_transaksi_jenis" ON "jurnal_gls" ("transaksi_id","jenis");
+
+-- Keep in sync with model.DefaultAturanPosting.
+INSERT INTO "akun_gls" ("created_at", "updated_at", "kode", "nama", "tipe", "simpanan_nasabah") VALUES
+    (now(), now(), '3100', 'Ekuitas Saldo Awal', 'equity', false),
+    (now(), now(), '4200', 'Pendapatan Selisih Kurs', 'income', false),
+    (now(), now(), '5200', 'Beban Selisih Kurs', 'expense', false)
+ON CONFLICT ("kode") DO NOTHING;
+
+INSERT INTO "aturan_postings" ("created_at", "updated_at", "jenis", "akun_debit", "akun_kredit") VALUES
+    (now(), now(), 'saldo_awal', '3100', '2100'),
+    (now(), now(), 'laba_kurs', '1900', '4200'),
+    (now(), now(), 'rugi_kurs', '5200', '1900')
+ON CONFLICT ("jenis") DO NOTHING;
+
+-- The opening balance is dated when the account was opened; the ledger
+-- replay takes it before the account's other entries.
+INSERT INTO "transaksis" ("created_at", "updated_at", "nasabah_id", "no_rekening", "jenis", "nominal", "saldo_akhir", "mata_uang", "tanggal_bisnis")
+SELECT COALESCE(n."created_at", now()), now(), n."id", n."no_rekening", 'saldo_awal', d."selisih", d."selisih", n."mata_uang",
+    CAST(COALESCE(n."created_at", now()) AS date)
+FROM "nasabahs" n
+CROSS JOIN LATERAL (
+    SELECT n."saldo" - COALESCE(SUM(CASE
+        WHEN t."jenis" IN ('tabung', 'transfer_masuk', 'koreksi_kredit', 'bunga', 'saldo_awal') THEN t."nominal"
+        ELSE -t."nominal" END), 0) AS "selisih"
+    FROM "transaksis" t
+    WHERE t."nasabah_id" = n."id" AND t."deleted_at" IS NULL
+) d
+WHERE n."deleted_at" IS NULL
+    AND abs(d."selisih") >= 0.005
+    AND NOT EXISTS (
+        SELECT 1 FROM "transaksis" t WHERE t."nasabah_id" = n."id" AND t."jenis" = 'saldo_awal' AND t."deleted_at" IS NULL
+    );
+
+INSERT INTO "jurnal_gls" ("created_at", "updated_at", "transaksi_id", "jenis", "akun_debit", "akun_kredit", "nominal", "mata_uang", "tanggal")
+SELECT now(), now(), t."id", t."jenis", a."akun_debit", a."akun_kredit", t."nominal", t."mata_uang", t."created_at"
+FROM "transaksis" t
+JOIN "aturan_postings" a ON a."jenis" = t."jenis" AND a."deleted_at" IS NULL
+WHERE t."jenis" = 'saldo_awal' AND t."deleted_at" IS NULL
+ON CONFLICT ("transaksi_id", "jenis") DO NOTHING;
+
+INSERT INTO "jurnal_gls" ("created_at", "updated_at", "transaksi_id", "jenis", "akun_debit", "akun_kredit", "nominal", "mata_uang", "tanggal")
+SELECT now(), now(), k."transaksi_debit_id", a."jenis", a."akun_debit", a."akun_kredit", abs(k."laba_rugi"), 'IDR', t."created_at"
+FROM "konversi_valas" k
+JOIN "transaksis" t ON t."id" = k."transaksi_debit_id"
+JOIN "aturan_postings" a ON a."deleted_at" IS NULL
+    AND a."jenis" = CASE WHEN k."laba_rugi" > 0 THEN 'laba_kurs' ELSE 'rugi_kurs' END
+WHERE k."laba_rugi" <> 0 AND k."deleted_at" IS NULL
+ON CONFLICT ("transaksi_id", "jenis") DO NOTHING;
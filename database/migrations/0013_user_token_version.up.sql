@@ -0,0 +1,2 @@
+-- Tokens carry the user's token version; bumping it revokes them.
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "token_version" bigint NOT NULL DEFAULT 0;
@@ -24,14 +24,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/daftar": {
+        "/2fa/disable": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Register a new bank customer with their personal information",
+                "description": "Turn off 2FA with a current TOTP or backup code. Not allowed for roles that require 2FA by policy.",
                 "consumes": [
                     "application/json"
                 ],
@@ -39,25 +39,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "nasabah"
+                    "auth"
                 ],
-                "summary": "Register new customer",
+                "summary": "Disable 2FA",
                 "parameters": [
                     {
-                        "description": "Customer registration details",
+                        "description": "TOTP or backup code",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.DaftarRequest"
+                            "$ref": "#/definitions/model.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/2fa/enrol": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
                     }
                 ],
+                "description": "Generate a new TOTP secret and otpauth URI. 2FA is enabled once a code is confirmed at /2fa/verify.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start 2FA enrolment",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.RekeningResponse"
+                            "$ref": "#/definitions/model.TwoFactorEnrolResponse"
                         }
                     },
                     "400": {
@@ -75,9 +115,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
+        "/2fa/verify": {
             "post": {
-                "description": "Login with email and password",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confirm the authenticator with a TOTP code. Returns one-time backup codes, and a JWT when called with an enrolment token.",
                 "consumes": [
                     "application/json"
                 ],
@@ -87,15 +132,15 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Login user",
+                "summary": "Confirm 2FA enrolment",
                 "parameters": [
                     {
-                        "description": "User login details",
-                        "name": "user",
+                        "description": "TOTP code",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.LoginRequest"
+                            "$ref": "#/definitions/model.TwoFactorCodeRequest"
                         }
                     }
                 ],
@@ -103,7 +148,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.TokenResponse"
+                            "$ref": "#/definitions/model.TwoFactorEnabledResponse"
                         }
                     },
                     "400": {
@@ -111,43 +156,95 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/register": {
-            "post": {
-                "description": "Register a new user with email and password",
-                "consumes": [
-                    "application/json"
+        "/admin/eod": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "The current business date, the date a transaction booked now gets, and the latest end-of-day run. Admin only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "eod"
                 ],
-                "summary": "Register new user",
-                "parameters": [
-                    {
-                        "description": "User registration details",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
+                "summary": "Get business date",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.RegisterRequest"
+                            "$ref": "#/definitions/model.TanggalBisnisResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/admin/eod/run": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start the end-of-day run of the current business date in the background: cut-off, interest accrual, fees, dormancy, balance snapshots, statements and rollover to the next business date. A failed run is resumed from the step that failed. Poll the returned run for progress. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eod"
                 ],
+                "summary": "Run end of day",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/model.TokenResponse"
+                            "$ref": "#/definitions/model.EODRun"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -155,29 +252,65 @@ const docTemplate = `{
                 }
             }
         },
-        "/saldo/{no_rekening}": {
+        "/admin/eod/runs": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the current balance for a given account number",
-                "consumes": [
+                "description": "The latest 30 runs with their steps, newest first. Admin only.",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "eod"
+                ],
+                "summary": "List end-of-day runs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.EODRun"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/eod/runs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "nasabah"
+                    "eod"
                 ],
-                "summary": "Check balance",
+                "summary": "Get end-of-day run",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Account number",
-                        "name": "no_rekening",
+                        "type": "integer",
+                        "description": "Run ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -186,17 +319,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.SaldoResponse"
+                            "$ref": "#/definitions/model.EODRun"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
@@ -204,14 +343,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/tabung": {
+        "/admin/fx-rates": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deposit money into a customer's account",
+                "description": "Store a rate sheet, all rates or none. Each rate replaces the previous one of its currency from ` + "`" + `berlaku_mulai` + "`" + `, which defaults to now. Admin only; ` + "`" + `gobanking admin fx import` + "`" + ` loads the same from a CSV file.",
                 "consumes": [
                     "application/json"
                 ],
@@ -219,25 +358,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "nasabah"
+                    "admin"
                 ],
-                "summary": "Deposit money",
+                "summary": "Load FX rates",
                 "parameters": [
                     {
-                        "description": "Deposit details",
+                        "description": "Rates quoted in IDR",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.TransaksiRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.KursRequest"
+                            }
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.SaldoResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.KursResponse"
+                            }
                         }
                     },
                     "400": {
@@ -251,18 +396,68 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/tarik": {
+        "/admin/gl/accounts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The chart of accounts ordered by code. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gl"
+                ],
+                "summary": "List GL accounts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AkunGL"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Withdraw money from a customer's account",
+                "description": "Add an account to the chart. Mark liability accounts that hold customer deposits with ` + "`" + `simpanan_nasabah` + "`" + ` so the trial balance proves them against customer balances. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -270,25 +465,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "nasabah"
+                    "gl"
                 ],
-                "summary": "Withdraw money",
+                "summary": "Create GL account",
                 "parameters": [
                     {
-                        "description": "Withdrawal details",
+                        "description": "Account",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.TransaksiRequest"
+                            "$ref": "#/definitions/model.AkunGLRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/model.SaldoResponse"
+                            "$ref": "#/definitions/model.AkunGL"
                         }
                     },
                     "400": {
@@ -302,84 +497,3719 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.DaftarRequest": {
-            "type": "object",
-            "required": [
-                "nama",
-                "nik",
-                "no_hp"
-            ],
-            "properties": {
-                "nama": {
-                    "type": "string"
-                },
-                "nik": {
-                    "type": "string"
-                },
-                "no_hp": {
-                    "type": "string"
-                }
-            }
         },
-        "model.ErrorResponse": {
+        "/admin/gl/journal.csv": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Journal lines booked in [from, to) as CSV, each as a debit and a credit line. Both bounds are RFC 3339 and optional. Admin only.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "gl"
+                ],
+                "summary": "Download GL journal",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start, inclusive (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End, exclusive (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/gl/posting-rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The GL accounts each transaction type is debited and credited to. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gl"
+                ],
+                "summary": "List posting rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AturanPosting"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/gl/posting-rules/{jenis}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Post a transaction type to other GL accounts from now on; transactions already posted are not restated. Both accounts must exist in the chart. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gl"
+                ],
+                "summary": "Set posting rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction type, e.g. tabung",
+                        "name": "jenis",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Accounts",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AturanPostingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.AturanPosting"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/gl/trial-balance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Debit, credit and balance of every GL account per currency, with per-currency totals. A currency is ` + "`" + `seimbang` + "`" + ` when debits equal credits and its customer deposit accounts equal the sum of customer balances. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gl"
+                ],
+                "summary": "Trial balance",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.NeracaSaldoResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/gl/trial-balance.csv": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The trial balance as CSV, one line per account and currency followed by the currency totals. Admin only.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "gl"
+                ],
+                "summary": "Download trial balance",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/lockouts/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Clear failed-login lockout for an email and/or client IP. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Unlock login",
+                "parameters": [
+                    {
+                        "description": "Email and/or IP to unlock",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UnlockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/daftar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register a new bank customer with their personal information",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nasabah"
+                ],
+                "summary": "Register new customer",
+                "parameters": [
+                    {
+                        "description": "Customer registration details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.DaftarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RekeningResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fx-rates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The rate in effect now of every currency that has one, quoted in IDR. ` + "`" + `beli` + "`" + ` is what the bank pays for one unit and ` + "`" + `jual` + "`" + ` what it charges; cross-currency transfers buy the source currency at ` + "`" + `beli` + "`" + ` and sell the destination currency at ` + "`" + `jual` + "`" + `.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fx"
+                ],
+                "summary": "List FX rates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.KursResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Reports that the process is alive. Does not touch dependencies.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Login with email and password. Users with 2FA receive an MFA challenge token to complete at /login/mfa.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "User login details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TokenResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.MFAChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login/mfa": {
+            "post": {
+                "description": "Exchange the MFA challenge token from /login and a TOTP or backup code for a JWT",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Complete two-factor login",
+                "parameters": [
+                    {
+                        "description": "MFA token and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LoginMFARequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/otp/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Complete an OTP challenge. On success the original request is executed and its response returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "otp"
+                ],
+                "summary": "Verify OTP",
+                "parameters": [
+                    {
+                        "description": "Challenge and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.OTPVerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Credit many accounts from one source account. Every row is validated up front; invalid rows, including those crediting an account that is not a saved beneficiary of the source, are returned in ` + "`" + `rejected` + "`" + ` and marked failed without stopping the rest, which are transferred asynchronously. Amounts are in the source account's currency and converted for destinations held in another one. Batches whose total IDR value is above the OTP threshold, or cannot be valued for want of an FX rate, answer 202 with an OTP challenge and are submitted by POST /otp/verify.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Submit payroll batch",
+                "parameters": [
+                    {
+                        "description": "Source account and rows",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PayrollRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.OTPChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll/csv": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Same as POST /payroll, OTP step-up included, with the rows in a CSV file. The header row must name ` + "`" + `no_rekening` + "`" + ` and ` + "`" + `nominal` + "`" + ` and may name ` + "`" + `nama` + "`" + `; nominal uses a dot as decimal separator and no thousands separator.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Upload payroll batch as CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source account",
+                        "name": "no_rekening_sumber",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Description",
+                        "name": "keterangan",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.OTPChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Status and totals of a batch. ` + "`" + `success_nominal` + "`" + ` is what has been debited so far and ` + "`" + `journal_nominal` + "`" + ` is the same figure read back from the source account's ledger.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Get payroll batch",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.PayrollBatchResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payroll/{id}/result.csv": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "One CSV line per row with its status, failure reason and the source-side transaksi ID.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "payroll"
+                ],
+                "summary": "Download payroll results",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/penerima": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Save a beneficiary account. Always requires OTP; the response is an OTP challenge.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nasabah"
+                ],
+                "summary": "Add transfer beneficiary",
+                "parameters": [
+                    {
+                        "description": "Beneficiary details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PenerimaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.OTPChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/penerima/{no_rekening}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nasabah"
+                ],
+                "summary": "List transfer beneficiaries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account number",
+                        "name": "no_rekening",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Penerima"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Checks the database and that all migrations are applied. Returns 503 while any component is down or the server is draining.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reconciliations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The latest 100 imported settlement files with their counts, newest first. Staff and admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reconciliation"
+                ],
+                "summary": "List reconciliations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Rekonsiliasi"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Match a partner's settlement file against the deposits in our books. CSV files need a header naming ` + "`" + `referensi` + "`" + `, ` + "`" + `nominal` + "`" + ` and ` + "`" + `tanggal` + "`" + ` (YYYY-MM-DD) and may name ` + "`" + `no_rekening` + "`" + ` and ` + "`" + `mata_uang` + "`" + `. MT940 files contribute their credit ` + "`" + `:61:` + "`" + ` lines. Each entry is paired by reference first, then by amount and date within the configured tolerances, and every entry and expected deposit is classified as matched, missing_internal, missing_external or amount_mismatch. Staff and admin only.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reconciliation"
+                ],
+                "summary": "Import settlement file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Partner",
+                        "name": "mitra",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv or mt940; detected from the file when empty",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Settlement file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Rekonsiliasi"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reconciliations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reconciliation"
+                ],
+                "summary": "Get reconciliation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reconciliation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Rekonsiliasi"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reconciliations/{id}/exceptions.csv": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Every item that is not matched, as CSV, with both sides, the difference and the resolution if any. Staff and admin only.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "reconciliation"
+                ],
+                "summary": "Download exception report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reconciliation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reconciliations/{id}/items": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The result lines in file order, deposits missing from the file last. Staff and admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reconciliation"
+                ],
+                "summary": "List reconciliation items",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reconciliation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "matched, missing_internal, missing_external or amount_mismatch",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only unresolved exceptions",
+                        "name": "open",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.RekonsiliasiItem"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reconciliations/{id}/items/{item_id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Close an exception with a note. For a missing_internal entry, ` + "`" + `transaksi_id` + "`" + ` pairs it with a deposit found by hand; the deposit must be a tabung in the entry's currency not paired elsewhere, and its missing_external items are resolved too. Staff and admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reconciliation"
+                ],
+                "summary": "Resolve reconciliation exception",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reconciliation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Resolution",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ResolveRekonsiliasiRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RekonsiliasiItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Register a new user with email and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register new user",
+                "parameters": [
+                    {
+                        "description": "User registration details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rekening-koran/{no_rekening}/{periode}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The monthly statement of an account with the month's transactions by business date. Statements are generated by the end-of-day run of the month's last business date.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nasabah"
+                ],
+                "summary": "Get statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account number",
+                        "name": "no_rekening",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Month as YYYY-MM",
+                        "name": "periode",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.RekeningKoranResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reversals": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The latest 100 reversal requests, newest first. Staff and admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reversal"
+                ],
+                "summary": "List reversals",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, completed or rejected",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ReversalResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Undo a ` + "`" + `tabung` + "`" + ` or ` + "`" + `tarik` + "`" + ` by booking an equal and opposite ` + "`" + `koreksi_debit` + "`" + ` or ` + "`" + `koreksi_kredit` + "`" + ` entry linked to it by ` + "`" + `reversal_of_id` + "`" + `. A transaction is reversed at most once; transfers cannot be reversed. Reversals worth more than REVERSAL_APPROVAL_THRESHOLD in IDR, and any with ` + "`" + `allow_overdraft` + "`" + `, return 202 and wait for another admin's approval. Reversing a deposit that has already been spent is rejected unless ` + "`" + `allow_overdraft` + "`" + ` is set, in which case the balance goes negative and the account is flagged as overdrawn until it is paid back. Staff and admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reversal"
+                ],
+                "summary": "Request reversal",
+                "parameters": [
+                    {
+                        "description": "Transaction to reverse",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ReversalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReversalResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReversalResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reversals/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reversal"
+                ],
+                "summary": "Get reversal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reversal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReversalResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reversals/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Post a pending reversal. The approver must not be the requester. If the reversal can no longer be posted, e.g. the deposit has been spent and ` + "`" + `allow_overdraft` + "`" + ` is not set, the request is rejected and the error returned. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reversal"
+                ],
+                "summary": "Approve reversal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reversal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReversalResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reversals/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Close a pending reversal without posting it. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reversal"
+                ],
+                "summary": "Reject reversal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reversal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReversalDecisionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReversalResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/saldo/{no_rekening}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the current balance for a given account number",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nasabah"
+                ],
+                "summary": "Check balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account number",
+                        "name": "no_rekening",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SaldoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/standing-orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "standing-order"
+                ],
+                "summary": "List standing orders",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StandingOrderResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Schedule a transfer once at ` + "`" + `start_at` + "`" + `, monthly on ` + "`" + `day_of_month` + "`" + ` (at 00:00, on the last day of shorter months) or on a five-field ` + "`" + `cron` + "`" + ` rule whose minute and hour are single values. Times are evaluated in SCHEDULE_TIMEZONE. ` + "`" + `nominal` + "`" + ` is in the source account's currency. An occurrence that fails for lack of funds, a frozen account or a missing FX rate is retried SCHEDULE_RETRY_DAYS times, SCHEDULE_RETRY_INTERVAL apart (daily by default), before it is given up and the customer notified. The destination must be a saved beneficiary of the source account and is checked again before every run. Orders whose ` + "`" + `nominal` + "`" + ` has an IDR value above the OTP threshold, or cannot be valued for want of an FX rate, answer 202 with an OTP challenge and are created by POST /otp/verify.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "standing-order"
+                ],
+                "summary": "Create standing order",
+                "parameters": [
+                    {
+                        "description": "Transfer and schedule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.StandingOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.StandingOrderResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.OTPChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/standing-orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "standing-order"
+                ],
+                "summary": "Get standing order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Standing order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StandingOrderResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the transfer and schedule of an active order. The next run is recomputed and pending retries are dropped. The same beneficiary rule and OTP step-up as POST /standing-orders apply.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "standing-order"
+                ],
+                "summary": "Update standing order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Standing order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transfer and schedule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.StandingOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StandingOrderResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.OTPChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop an active order. Its run history is kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "standing-order"
+                ],
+                "summary": "Cancel standing order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Standing order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.StandingOrderResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/standing-orders/{id}/runs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Execution attempts of an order, newest first. A run with status ` + "`" + `retry` + "`" + ` is followed by another attempt at the same ` + "`" + `scheduled_at` + "`" + `.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "standing-order"
+                ],
+                "summary": "List standing order runs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Standing order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.StandingOrderRunResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tabung": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deposit money into a customer's account. An optional ` + "`" + `referensi` + "`" + `, e.g. a partner's settlement reference, is matched by reconciliation and may be used once per account.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nasabah"
+                ],
+                "summary": "Deposit money",
+                "parameters": [
+                    {
+                        "description": "Deposit details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TabungRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SaldoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tarik": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraw money from a customer's account, in the account's currency. Amounts whose IDR value is above the OTP threshold, or cannot be valued for want of an FX rate, answer 202 with an OTP challenge and are executed by POST /otp/verify.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nasabah"
+                ],
+                "summary": "Withdraw money",
+                "parameters": [
+                    {
+                        "description": "Withdrawal details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TransaksiRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SaldoResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.OTPChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ubah-no-hp": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the customer's NoHP. Always requires OTP, sent to the current number.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "nasabah"
+                ],
+                "summary": "Change phone number",
+                "parameters": [
+                    {
+                        "description": "Account and new phone number",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UbahNoHPRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.OTPChallengeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "List webhook subscriptions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WebhookResponse"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribe a URL to account events. The signing secret is only returned once. Leaving no_rekening empty subscribes to every account and is admin only. The URL must not point at a loopback, private or link-local address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Create webhook subscription",
+                "parameters": [
+                    {
+                        "description": "Subscription details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Get webhook subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Update webhook subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Delete webhook subscription",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delivery log for a subscription, newest first. Filter by status to find dead-lettered deliveries.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, retrying, success or dead",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.WebhookDeliveryResponse"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{delivery_id}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue a new delivery with the same payload, e.g. to recover a dead-lettered event.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Replay webhook delivery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookDeliveryResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.AkunGL": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kode": {
+                    "type": "string"
+                },
+                "nama": {
+                    "type": "string"
+                },
+                "simpanan_nasabah": {
+                    "description": "SimpananNasabah marks the liability accounts holding customer\ndeposits; the trial balance proves them against nasabahs.saldo.",
+                    "type": "boolean"
+                },
+                "tipe": {
+                    "description": "Tipe is asset, liability, equity, income or expense. Asset and\nexpense accounts carry a debit balance, the others a credit balance.",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AkunGLRequest": {
+            "type": "object",
+            "required": [
+                "kode",
+                "nama",
+                "tipe"
+            ],
+            "properties": {
+                "kode": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "nama": {
+                    "type": "string"
+                },
+                "simpanan_nasabah": {
+                    "type": "boolean"
+                },
+                "tipe": {
+                    "type": "string",
+                    "enum": [
+                        "asset",
+                        "liability",
+                        "equity",
+                        "income",
+                        "expense"
+                    ]
+                }
+            }
+        },
+        "model.AturanPosting": {
+            "type": "object",
+            "properties": {
+                "akun_debit": {
+                    "type": "string"
+                },
+                "akun_kredit": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "jenis": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AturanPostingRequest": {
+            "type": "object",
+            "required": [
+                "akun_debit",
+                "akun_kredit"
+            ],
+            "properties": {
+                "akun_debit": {
+                    "type": "string"
+                },
+                "akun_kredit": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ComponentStatus": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DaftarRequest": {
+            "type": "object",
+            "required": [
+                "nama",
+                "nik",
+                "no_hp"
+            ],
+            "properties": {
+                "bahasa": {
+                    "type": "string",
+                    "enum": [
+                        "id",
+                        "en"
+                    ]
+                },
+                "email": {
+                    "type": "string"
+                },
+                "mata_uang": {
+                    "description": "MataUang is the ISO 4217 account currency, IDR when empty.",
+                    "type": "string"
+                },
+                "nama": {
+                    "type": "string"
+                },
+                "nik": {
+                    "type": "string"
+                },
+                "no_hp": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EODLangkah": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "jumlah": {
+                    "type": "integer"
+                },
+                "langkah": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EODRun": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "langkah": {
+                    "description": "Langkah is the step running or, after a failure, the one that failed.",
+                    "type": "string"
+                },
+                "started_by": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.EODLangkah"
+                    }
+                },
+                "tanggal_bisnis": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "remark": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.ComponentStatus"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.KursRequest": {
+            "type": "object",
+            "required": [
+                "beli",
+                "jual",
+                "mata_uang"
+            ],
+            "properties": {
+                "beli": {
+                    "type": "number"
+                },
+                "berlaku_mulai": {
+                    "description": "BerlakuMulai defaults to now.",
+                    "type": "string"
+                },
+                "jual": {
+                    "type": "number"
+                },
+                "mata_uang": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.KursResponse": {
+            "type": "object",
+            "properties": {
+                "basis": {
+                    "description": "Basis is the currency the rates are quoted in, always IDR.",
+                    "type": "string"
+                },
+                "beli": {
+                    "type": "number"
+                },
+                "berlaku_mulai": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "jual": {
+                    "type": "number"
+                },
+                "mata_uang": {
+                    "type": "string"
+                },
+                "sumber": {
+                    "type": "string"
+                },
+                "tengah": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.LoginMFARequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "model.MFAChallengeResponse": {
+            "type": "object",
+            "properties": {
+                "enrolment_required": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.NeracaSaldoBaris": {
+            "type": "object",
+            "properties": {
+                "debit": {
+                    "type": "number"
+                },
+                "kode": {
+                    "type": "string"
+                },
+                "kredit": {
+                    "type": "number"
+                },
+                "mata_uang": {
+                    "type": "string"
+                },
+                "nama": {
+                    "type": "string"
+                },
+                "saldo": {
+                    "type": "number"
+                },
+                "tipe": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.NeracaSaldoResponse": {
+            "type": "object",
+            "properties": {
+                "baris": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.NeracaSaldoBaris"
+                    }
+                },
+                "per": {
+                    "type": "string"
+                },
+                "seimbang": {
+                    "description": "Seimbang is true when every currency balances.",
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.NeracaSaldoTotal"
+                    }
+                }
+            }
+        },
+        "model.NeracaSaldoTotal": {
+            "type": "object",
+            "properties": {
+                "debit": {
+                    "type": "number"
+                },
+                "kredit": {
+                    "type": "number"
+                },
+                "liabilitas_nasabah": {
+                    "description": "LiabilitasNasabah is the balance of the SimpananNasabah accounts and\nSaldoNasabah the sum of nasabahs.saldo.",
+                    "type": "number"
+                },
+                "mata_uang": {
+                    "type": "string"
+                },
+                "saldo_nasabah": {
+                    "type": "number"
+                },
+                "seimbang": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.OTPChallengeResponse": {
+            "type": "object",
+            "properties": {
+                "challenge_id": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "remark": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.OTPVerifyRequest": {
+            "type": "object",
+            "required": [
+                "challenge_id",
+                "code"
+            ],
+            "properties": {
+                "challenge_id": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PayrollBatchResponse": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "failed_items": {
+                    "type": "integer"
+                },
+                "failed_nominal": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "journal_nominal": {
+                    "description": "JournalNominal is what the ledger shows debited from the source for\nthis batch; it matches SuccessNominal once the batch completes.",
+                    "type": "number"
+                },
+                "keterangan": {
+                    "type": "string"
+                },
+                "mata_uang": {
+                    "type": "string"
+                },
+                "no_rekening_sumber": {
+                    "type": "string"
+                },
+                "rejected": {
+                    "description": "Rejected lists the rows that failed validation; only returned when\nthe batch is created.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PayrollItemResponse"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "success_items": {
+                    "type": "integer"
+                },
+                "success_nominal": {
+                    "type": "number"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "total_nominal": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.PayrollItemRequest": {
+            "type": "object",
+            "properties": {
+                "nama": {
+                    "type": "string"
+                },
+                "no_rekening": {
+                    "type": "string"
+                },
+                "nominal": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.PayrollItemResponse": {
+            "type": "object",
+            "properties": {
+                "baris": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "mata_uang": {
+                    "type": "string"
+                },
+                "nama": {
+                    "type": "string"
+                },
+                "no_rekening": {
+                    "type": "string"
+                },
+                "nominal": {
+                    "type": "number"
+                },
+                "processed_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaksi_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PayrollRequest": {
+            "type": "object",
+            "required": [
+                "items",
+                "no_rekening_sumber"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.PayrollItemRequest"
+                    }
+                },
+                "keterangan": {
+                    "type": "string"
+                },
+                "no_rekening_sumber": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Penerima": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "no_rekening": {
+                    "type": "string"
+                },
+                "no_rekening_tujuan": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PenerimaRequest": {
+            "type": "object",
+            "required": [
+                "no_rekening",
+                "no_rekening_tujuan"
+            ],
+            "properties": {
+                "alias": {
+                    "type": "string"
+                },
+                "no_rekening": {
+                    "type": "string"
+                },
+                "no_rekening_tujuan": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "model.RekeningKoranResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "jumlah_transaksi": {
+                    "type": "integer"
+                },
+                "mata_uang": {
+                    "type": "string"
+                },
+                "no_rekening": {
+                    "type": "string"
+                },
+                "periode": {
+                    "description": "Periode is the month, as 2006-01.",
+                    "type": "string"
+                },
+                "saldo_akhir": {
+                    "type": "number"
+                },
+                "saldo_awal": {
+                    "type": "number"
+                },
+                "tanggal_akhir": {
+                    "type": "string"
+                },
+                "tanggal_awal": {
+                    "type": "string"
+                },
+                "total_debit": {
+                    "type": "number"
+                },
+                "total_kredit": {
+                    "type": "number"
+                },
+                "transaksi": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Transaksi"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RekeningResponse": {
+            "type": "object",
+            "properties": {
+                "mata_uang": {
+                    "type": "string"
+                },
+                "no_rekening": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Rekonsiliasi": {
+            "type": "object",
+            "properties": {
+                "amount_mismatch": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "diabaikan": {
+                    "type": "integer"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imported_by": {
+                    "type": "integer"
+                },
+                "matched": {
+                    "type": "integer"
+                },
+                "missing_external": {
+                    "type": "integer"
+                },
+                "missing_internal": {
+                    "type": "integer"
+                },
+                "mitra": {
+                    "type": "string"
+                },
+                "nama_file": {
+                    "type": "string"
+                },
+                "open": {
+                    "description": "Open is the number of exceptions not yet resolved.",
+                    "type": "integer"
+                },
+                "tanggal_akhir": {
+                    "type": "string"
+                },
+                "tanggal_awal": {
+                    "description": "TanggalAwal and TanggalAkhir bound the entries' dates; deposits\nbooked in that range are expected in the file.",
+                    "type": "string"
+                },
+                "total_entri": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RekonsiliasiItem": {
+            "type": "object",
+            "properties": {
+                "baris": {
+                    "description": "Baris is the entry's line in the file; 0 for missing_external.",
+                    "type": "integer"
+                },
+                "catatan": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "mata_uang": {
+                    "type": "string"
+                },
+                "no_rekening": {
+                    "type": "string"
+                },
+                "nominal_eksternal": {
+                    "type": "number"
+                },
+                "nominal_internal": {
+                    "type": "number"
+                },
+                "referensi": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "resolved_by": {
+                    "type": "integer"
+                },
+                "selisih": {
+                    "description": "Selisih is NominalEksternal - NominalInternal.",
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tanggal_eksternal": {
+                    "type": "string"
+                },
+                "tanggal_internal": {
+                    "type": "string"
+                },
+                "transaksi_id": {
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ResolveRekonsiliasiRequest": {
+            "type": "object",
+            "required": [
+                "catatan"
+            ],
+            "properties": {
+                "catatan": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "transaksi_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ReversalDecisionRequest": {
+            "type": "object",
+            "properties": {
+                "alasan": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "model.ReversalRequest": {
+            "type": "object",
+            "required": [
+                "alasan",
+                "transaksi_id"
+            ],
+            "properties": {
+                "alasan": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "allow_overdraft": {
+                    "type": "boolean"
+                },
+                "transaksi_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ReversalResponse": {
+            "type": "object",
+            "properties": {
+                "alasan": {
+                    "type": "string"
+                },
+                "allow_overdraft": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "jenis": {
+                    "type": "string"
+                },
+                "koreksi_id": {
+                    "type": "integer"
+                },
+                "mata_uang": {
+                    "type": "string"
+                },
+                "no_rekening": {
+                    "type": "string"
+                },
+                "nominal": {
+                    "type": "number"
+                },
+                "overdraft": {
+                    "type": "boolean"
+                },
+                "requested_by": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaksi_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SaldoResponse": {
             "type": "object",
             "properties": {
-                "remark": {
+                "dormant": {
+                    "description": "Dormant is true while the account is marked dormant.",
+                    "type": "boolean"
+                },
+                "mata_uang": {
                     "type": "string"
+                },
+                "overdraft": {
+                    "description": "Overdraft is true while a reversal has left the balance negative.",
+                    "type": "boolean"
+                },
+                "saldo": {
+                    "type": "number"
                 }
             }
         },
-        "model.LoginRequest": {
+        "model.StandingOrderRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "no_rekening",
+                "no_rekening_tujuan",
+                "nominal",
+                "schedule"
             ],
             "properties": {
-                "email": {
+                "cron": {
+                    "description": "Cron has five fields: minute hour day-of-month month day-of-week. The\nminute and hour must be single values, so it runs at most daily.",
                     "type": "string"
                 },
-                "password": {
+                "day_of_month": {
+                    "type": "integer"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "keterangan": {
+                    "type": "string"
+                },
+                "no_rekening": {
+                    "type": "string"
+                },
+                "no_rekening_tujuan": {
+                    "type": "string"
+                },
+                "nominal": {
+                    "type": "number"
+                },
+                "schedule": {
+                    "description": "Schedule is \"once\", \"monthly\" or \"cron\".",
                     "type": "string",
-                    "minLength": 6
+                    "enum": [
+                        "once",
+                        "monthly",
+                        "cron"
+                    ]
+                },
+                "start_at": {
+                    "description": "StartAt is the execution time of a one-off order and the earliest\noccurrence of a recurring one (default now).",
+                    "type": "string"
                 }
             }
         },
-        "model.RegisterRequest": {
+        "model.StandingOrderResponse": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "cron": {
+                    "type": "string"
+                },
+                "day_of_month": {
+                    "type": "integer"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "keterangan": {
+                    "type": "string"
+                },
+                "mata_uang": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "no_rekening": {
+                    "type": "string"
+                },
+                "no_rekening_tujuan": {
+                    "type": "string"
+                },
+                "nominal": {
+                    "type": "number"
+                },
+                "schedule": {
+                    "type": "string"
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "model.RekeningResponse": {
+        "model.StandingOrderRunResponse": {
             "type": "object",
             "properties": {
-                "no_rekening": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
                     "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "scheduled_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "transaksi_id": {
+                    "type": "integer"
                 }
             }
         },
-        "model.SaldoResponse": {
+        "model.TabungRequest": {
             "type": "object",
+            "required": [
+                "no_rekening",
+                "nominal"
+            ],
             "properties": {
-                "saldo": {
+                "no_rekening": {
+                    "type": "string"
+                },
+                "nominal": {
                     "type": "number"
+                },
+                "referensi": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "model.TanggalBisnisResponse": {
+            "type": "object",
+            "properties": {
+                "berikutnya": {
+                    "type": "string"
+                },
+                "cut_off_at": {
+                    "type": "string"
+                },
+                "last_run": {
+                    "description": "LastRun is the latest end-of-day run, if any.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.EODRun"
+                        }
+                    ]
+                },
+                "posting": {
+                    "description": "Posting is the date a transaction booked now gets.",
+                    "type": "string"
+                },
+                "tanggal": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
@@ -391,6 +4221,55 @@ const docTemplate = `{
                 }
             }
         },
+        "model.Transaksi": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "jenis": {
+                    "type": "string"
+                },
+                "mata_uang": {
+                    "description": "MataUang is the account's currency; Nominal and SaldoAkhir are in it.",
+                    "type": "string"
+                },
+                "no_rekening": {
+                    "type": "string"
+                },
+                "no_rekening_lawan": {
+                    "description": "NoRekeningLawan is the other side of a transfer.",
+                    "type": "string"
+                },
+                "nominal": {
+                    "type": "number"
+                },
+                "referensi": {
+                    "description": "Referensi is the caller's idempotency key, unique per account when\nset.",
+                    "type": "string"
+                },
+                "reversal_of_id": {
+                    "description": "ReversalOfID links a koreksi entry to the Transaksi it reverses. It is\nunique, so a transaction can be reversed only once.",
+                    "type": "integer"
+                },
+                "saldo_akhir": {
+                    "type": "number"
+                },
+                "tanggal_bisnis": {
+                    "description": "TanggalBisnis is the business date the entry belongs to: the current\none, or the next once today's cut-off has passed.",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
         "model.TransaksiRequest": {
             "type": "object",
             "required": [
@@ -405,6 +4284,157 @@ const docTemplate = `{
                     "type": "number"
                 }
             }
+        },
+        "model.TwoFactorCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TwoFactorEnabledResponse": {
+            "type": "object",
+            "properties": {
+                "backup_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.TwoFactorEnrolResponse": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.UbahNoHPRequest": {
+            "type": "object",
+            "required": [
+                "no_hp",
+                "no_rekening"
+            ],
+            "properties": {
+                "no_hp": {
+                    "type": "string"
+                },
+                "no_rekening": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.UnlockRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "replay_of": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "aktif": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "no_rekening": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "aktif": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "no_rekening": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,85 @@
+package eod
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCalendar = errors.New("file hari libur tidak valid")
+
+// Calendar knows the non-business days: weekends and the listed holidays.
+type Calendar struct {
+	libur map[time.Time]string
+}
+
+// LoadCalendar reads a holiday file in the format of ParseCalendar. An
+// empty path gives a calendar of weekends only.
+func LoadCalendar(path string) (Calendar, error) {
+	if path == "" {
+		return Calendar{}, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+	defer file.Close()
+	return ParseCalendar(file)
+}
+
+// ParseCalendar reads one holiday per line as 2006-01-02, optionally
+// followed by a comma and a description. Blank lines and lines starting
+// with # are skipped.
+func ParseCalendar(r io.Reader) (Calendar, error) {
+	cal := Calendar{libur: make(map[time.Time]string)}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if line == 1 {
+			// Spreadsheet exports often start with a byte order mark.
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		tanggal, keterangan, _ := strings.Cut(text, ",")
+		d, err := time.Parse(time.DateOnly, strings.TrimSpace(tanggal))
+		if err != nil {
+			return Calendar{}, fmt.Errorf("%w: baris %d: tanggal harus 2006-01-02", ErrInvalidCalendar, line)
+		}
+		cal.libur[d] = strings.TrimSpace(keterangan)
+	}
+	if err := scanner.Err(); err != nil {
+		return Calendar{}, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+	return cal, nil
+}
+
+// IsBusinessDay reports whether d is neither a weekend nor a holiday.
+func (c Calendar) IsBusinessDay(d time.Time) bool {
+	d = date(d)
+	if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+		return false
+	}
+	_, libur := c.libur[d]
+	return !libur
+}
+
+// Next returns the first business day after d.
+func (c Calendar) Next(d time.Time) time.Time {
+	next := date(d).AddDate(0, 0, 1)
+	for !c.IsBusinessDay(next) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// date strips the time of day, keeping t's calendar date as midnight UTC,
+// the way Postgres date columns are read back.
+func date(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
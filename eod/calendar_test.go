@@ -0,0 +1,102 @@
+package eod_test
+
+import (
+	"errors"
+	"gobanking/eod"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	d, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// libur is a holiday file as exported from a spreadsheet, with a byte order
+// mark: the Monday after Independence Day 2025 and a Friday in 2026.
+const libur = "\ufeff# hari libur nasional\n\n2025-08-18, Cuti bersama HUT RI\n2026-03-20\n"
+
+func TestParseCalendar(t *testing.T) {
+	cal, err := eod.ParseCalendar(strings.NewReader(libur))
+	if err != nil {
+		t.Fatalf("ParseCalendar: %v", err)
+	}
+	for _, tt := range []struct {
+		tanggal string
+		want    bool
+	}{
+		{"2025-08-15", true},  // Friday
+		{"2025-08-16", false}, // Saturday
+		{"2025-08-17", false}, // Sunday
+		{"2025-08-18", false}, // listed with a description
+		{"2025-08-19", true},
+		{"2026-03-20", false}, // listed without one
+	} {
+		if got := cal.IsBusinessDay(day(tt.tanggal)); got != tt.want {
+			t.Errorf("IsBusinessDay(%s) = %v, want %v", tt.tanggal, got, tt.want)
+		}
+	}
+	// The time of day and zone do not matter, only the calendar date.
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	if cal.IsBusinessDay(time.Date(2025, 8, 18, 23, 30, 0, 0, jakarta)) {
+		t.Error("IsBusinessDay(2025-08-18 23:30 WIB) = true, want false")
+	}
+}
+
+func TestParseCalendarRejectsBadDates(t *testing.T) {
+	for _, src := range []string{"2025-13-01\n", "2025-08-18\n18/08/2025, HUT RI\n"} {
+		if _, err := eod.ParseCalendar(strings.NewReader(src)); !errors.Is(err, eod.ErrInvalidCalendar) {
+			t.Errorf("ParseCalendar(%q): err = %v, want ErrInvalidCalendar", src, err)
+		}
+	}
+}
+
+func TestCalendarNext(t *testing.T) {
+	cal, err := eod.ParseCalendar(strings.NewReader(libur))
+	if err != nil {
+		t.Fatalf("ParseCalendar: %v", err)
+	}
+	for _, tt := range []struct {
+		tanggal, want string
+	}{
+		{"2025-08-13", "2025-08-14"},
+		{"2025-08-15", "2025-08-19"}, // over the weekend and the holiday
+		{"2025-08-16", "2025-08-19"}, // from a non-business day
+		{"2026-03-19", "2026-03-23"}, // Friday holiday, then the weekend
+		{"2025-12-31", "2026-01-01"}, // across the year; not a listed holiday
+	} {
+		if got := cal.Next(day(tt.tanggal)); !got.Equal(day(tt.want)) {
+			t.Errorf("Next(%s) = %s, want %s", tt.tanggal, got.Format(time.DateOnly), tt.want)
+		}
+	}
+}
+
+func TestLoadCalendar(t *testing.T) {
+	cal, err := eod.LoadCalendar("")
+	if err != nil {
+		t.Fatalf("LoadCalendar(\"\"): %v", err)
+	}
+	if got := cal.Next(day("2025-08-15")); !got.Equal(day("2025-08-18")) {
+		t.Errorf("weekends-only Next(2025-08-15) = %s, want 2025-08-18", got.Format(time.DateOnly))
+	}
+
+	path := filepath.Join(t.TempDir(), "libur.txt")
+	if err := os.WriteFile(path, []byte(libur), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if cal, err = eod.LoadCalendar(path); err != nil {
+		t.Fatalf("LoadCalendar(%s): %v", path, err)
+	}
+	if cal.IsBusinessDay(day("2025-08-18")) {
+		t.Error("IsBusinessDay(2025-08-18) = true from the file, want false")
+	}
+	if _, err := eod.LoadCalendar(filepath.Join(t.TempDir(), "tidak-ada.txt")); !errors.Is(err, eod.ErrInvalidCalendar) {
+		t.Errorf("LoadCalendar of a missing file: err = %v, want ErrInvalidCalendar", err)
+	}
+}
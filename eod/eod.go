@@ -0,0 +1,290 @@
+// Package eod runs the end-of-day batch that closes a business date: cut-off,
+// interest accrual, fee charging, dormancy marking, balance snapshots,
+// statements and the rollover to the next business date. Each step is
+// recorded and safe to repeat, so a failed run is resumed from the step
+// that failed.
+package eod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/metrics"
+	"gobanking/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// runLease is how long a run stays claimed without a sign of life; the
+// runner renews it between steps and while posting.
+const runLease = 10 * time.Minute
+
+var (
+	ErrRunNotFound       = errors.New("EOD tidak ditemukan")
+	ErrRunning           = errors.New("EOD sedang berjalan")
+	ErrTooEarly          = errors.New("tanggal bisnis belum berakhir")
+	ErrStatementNotFound = errors.New("rekening koran tidak ditemukan")
+)
+
+type Service struct {
+	db       *gorm.DB
+	cfg      *config.Config
+	accounts *account.Service
+}
+
+func NewService(db *gorm.DB, cfg *config.Config, accounts *account.Service) *Service {
+	return &Service{db: db, cfg: cfg, accounts: accounts}
+}
+
+func stepOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
+// TanggalBisnis returns the business date with the latest run.
+func (s *Service) TanggalBisnis(ctx context.Context) (model.TanggalBisnisResponse, error) {
+	var resp model.TanggalBisnisResponse
+	if err := s.db.WithContext(ctx).First(&resp.TanggalBisnis).Error; err != nil {
+		return resp, err
+	}
+	resp.Posting = resp.TanggalBisnis.Posting()
+
+	var last model.EODRun
+	err := s.db.WithContext(ctx).Preload("Steps", stepOrder).Order("tanggal_bisnis DESC").First(&last).Error
+	if err == nil {
+		resp.LastRun = &last
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// Runs returns the latest 30 runs, newest first.
+func (s *Service) Runs(ctx context.Context) ([]model.EODRun, error) {
+	var runs []model.EODRun
+	err := s.db.WithContext(ctx).Preload("Steps", stepOrder).Order("tanggal_bisnis DESC").Limit(30).Find(&runs).Error
+	return runs, err
+}
+
+func (s *Service) Run(ctx context.Context, id uint) (model.EODRun, error) {
+	var run model.EODRun
+	err := s.db.WithContext(ctx).Preload("Steps", stepOrder).First(&run, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return run, ErrRunNotFound
+	}
+	return run, err
+}
+
+// Start claims the run of the current business date for userID, creating
+// it or resuming a failed one, and returns it for Execute. The business
+// date may not be later than today in EOD_TIMEZONE, so starting twice in
+// one evening does not close tomorrow as well. A run another runner holds
+// is ErrRunning.
+func (s *Service) Start(ctx context.Context, userID uint) (model.EODRun, error) {
+	if _, err := LoadCalendar(s.cfg.EOD.HolidayFile); err != nil {
+		return model.EODRun{}, err
+	}
+
+	var tanggal model.TanggalBisnis
+	if err := s.db.WithContext(ctx).First(&tanggal).Error; err != nil {
+		return model.EODRun{}, err
+	}
+	loc, err := time.LoadLocation(s.cfg.EOD.TimeZone)
+	if err != nil {
+		return model.EODRun{}, err
+	}
+	if tanggal.Tanggal.After(date(time.Now().In(loc))) {
+		return model.EODRun{}, fmt.Errorf("%w: %s", ErrTooEarly, tanggal.Tanggal.Format(time.DateOnly))
+	}
+
+	now := time.Now()
+	lease := now.Add(runLease)
+	run := model.EODRun{
+		TanggalBisnis: tanggal.Tanggal,
+		Status:        model.EODRunning,
+		StartedBy:     userID,
+		LeaseUntil:    &lease,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("tanggal_bisnis = ?", tanggal.Tanggal).First(&run).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(&run).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.EODRun{}).
+			Where("id = ? AND (status = ? OR (status = ? AND lease_until < ?))", run.ID, model.EODFailed, model.EODRunning, now).
+			Updates(map[string]any{
+				"status":      model.EODRunning,
+				"started_by":  userID,
+				"lease_until": lease,
+				"error":       "",
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRunning
+		}
+		run.Status, run.StartedBy, run.LeaseUntil, run.Error = model.EODRunning, userID, &lease, ""
+		return nil
+	})
+	if err != nil {
+		return model.EODRun{}, err
+	}
+
+	s.cfg.Logger.Info("EOD dimulai",
+		"run_id", run.ID,
+		"tanggal_bisnis", run.TanggalBisnis.Format(time.DateOnly),
+		"started_by", userID,
+	)
+	return run, nil
+}
+
+// Execute runs the steps of a claimed run in order, skipping those already
+// completed. On the first failure the run is marked failed at that step
+// and the error returned; Start then resumes it from there.
+func (s *Service) Execute(ctx context.Context, run model.EODRun) (model.EODRun, error) {
+	cal, err := LoadCalendar(s.cfg.EOD.HolidayFile)
+	if err != nil {
+		return s.fail(ctx, run, "", err)
+	}
+	var done []model.EODLangkah
+	if err := s.db.WithContext(ctx).Where("run_id = ? AND status = ?", run.ID, model.EODCompleted).Find(&done).Error; err != nil {
+		return s.fail(ctx, run, "", err)
+	}
+	completed := make(map[string]bool, len(done))
+	for _, l := range done {
+		completed[l.Langkah] = true
+	}
+
+	st := &state{run: run, tanggal: date(run.TanggalBisnis), cal: cal}
+	st.berikutnya = cal.Next(st.tanggal)
+	steps := s.steps()
+	for _, name := range model.LangkahEOD {
+		if completed[name] {
+			continue
+		}
+		if err := s.renew(ctx, &st.run, name); err != nil {
+			return st.run, err
+		}
+
+		started := time.Now()
+		langkah := model.EODLangkah{RunID: run.ID, Langkah: name}
+		err := s.db.WithContext(ctx).Where(langkah).
+			Assign(map[string]any{"status": model.EODRunning, "started_at": started, "error": ""}).
+			FirstOrCreate(&langkah).Error
+		if err != nil {
+			return s.fail(ctx, st.run, name, err)
+		}
+
+		jumlah, err := steps[name](ctx, st)
+		finished := time.Now()
+		if err != nil {
+			metrics.EODStepDuration.WithLabelValues(name, model.EODFailed).Observe(finished.Sub(started).Seconds())
+			s.db.WithContext(ctx).Model(&langkah).Updates(map[string]any{
+				"status":      model.EODFailed,
+				"error":       err.Error(),
+				"finished_at": finished,
+			})
+			return s.fail(ctx, st.run, name, err)
+		}
+		metrics.EODStepDuration.WithLabelValues(name, model.EODCompleted).Observe(finished.Sub(started).Seconds())
+		err = s.db.WithContext(ctx).Model(&langkah).Updates(map[string]any{
+			"status":      model.EODCompleted,
+			"jumlah":      jumlah,
+			"finished_at": finished,
+		}).Error
+		if err != nil {
+			return s.fail(ctx, st.run, name, err)
+		}
+		s.cfg.Logger.Info("langkah EOD selesai",
+			"run_id", run.ID,
+			"tanggal_bisnis", st.tanggal.Format(time.DateOnly),
+			"langkah", name,
+			"jumlah", jumlah,
+			"durasi", finished.Sub(started).String(),
+		)
+	}
+
+	// The rollover step has already completed the run in its transaction;
+	// this covers a run whose steps were all done before it was resumed.
+	now := time.Now()
+	err = s.db.WithContext(ctx).Model(&model.EODRun{}).
+		Where("id = ? AND status = ?", run.ID, model.EODRunning).
+		Updates(map[string]any{"status": model.EODCompleted, "langkah": "", "lease_until": nil, "finished_at": now}).Error
+	if err != nil {
+		return st.run, err
+	}
+	s.cfg.Logger.Info("EOD selesai",
+		"run_id", run.ID,
+		"tanggal_bisnis", st.tanggal.Format(time.DateOnly),
+		"tanggal_bisnis_baru", st.berikutnya.Format(time.DateOnly),
+	)
+	return s.Run(ctx, run.ID)
+}
+
+// renew extends the lease of a running run and records the step it is on.
+func (s *Service) renew(ctx context.Context, run *model.EODRun, langkah string) error {
+	lease := time.Now().Add(runLease)
+	result := s.db.WithContext(ctx).Model(&model.EODRun{}).
+		Where("id = ? AND status = ?", run.ID, model.EODRunning).
+		Updates(map[string]any{"langkah": langkah, "lease_until": lease})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRunning
+	}
+	run.Langkah, run.LeaseUntil = langkah, &lease
+	return nil
+}
+
+// fail marks the run failed at langkah and returns cause.
+func (s *Service) fail(ctx context.Context, run model.EODRun, langkah string, cause error) (model.EODRun, error) {
+	s.cfg.Logger.Error("EOD gagal",
+		"run_id", run.ID,
+		"tanggal_bisnis", run.TanggalBisnis.Format(time.DateOnly),
+		"langkah", langkah,
+		"error", cause,
+	)
+	run.Status, run.Langkah, run.Error, run.LeaseUntil = model.EODFailed, langkah, cause.Error(), nil
+	// The failure is recorded even if ctx was cancelled mid-step.
+	err := s.db.WithContext(context.WithoutCancel(ctx)).Model(&model.EODRun{}).
+		Where("id = ? AND status = ?", run.ID, model.EODRunning).
+		Updates(map[string]any{
+			"status":      model.EODFailed,
+			"langkah":     langkah,
+			"error":       cause.Error(),
+			"lease_until": nil,
+		}).Error
+	if err != nil {
+		s.cfg.Logger.Error("gagal mencatat EOD gagal", "run_id", run.ID, "error", err)
+	}
+	return run, cause
+}
+
+// Statement returns the statement of noRekening for periode (2006-01) with
+// its transactions.
+func (s *Service) Statement(ctx context.Context, noRekening, periode string) (model.RekeningKoranResponse, error) {
+	var resp model.RekeningKoranResponse
+	err := s.db.WithContext(ctx).
+		Where("no_rekening = ? AND periode = ?", noRekening, periode).
+		First(&resp.RekeningKoran).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return resp, ErrStatementNotFound
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	err = s.db.WithContext(ctx).
+		Where("nasabah_id = ? AND tanggal_bisnis BETWEEN ? AND ?", resp.NasabahID, resp.TanggalAwal, resp.TanggalAkhir).
+		Order("tanggal_bisnis, id").
+		Find(&resp.Transaksi).Error
+	return resp, err
+}
@@ -0,0 +1,275 @@
+package eod_test
+
+import (
+	"context"
+	"errors"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/database/databasetest"
+	"gobanking/eod"
+	"gobanking/model"
+	"gobanking/notifier"
+	"gobanking/repository"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const admin uint = 1
+
+type fixture struct {
+	db       *gorm.DB
+	accounts *account.Service
+	service  *eod.Service
+}
+
+// newFixture sets the business date to Friday 30 January 2026, the last
+// business day of the month; with Monday 2 February a holiday the next one
+// is Tuesday 3 February, four days of interest later.
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	db := databasetest.Open(t)
+	holidays := filepath.Join(t.TempDir(), "libur.txt")
+	if err := os.WriteFile(holidays, []byte("2026-02-02\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		Logger: logger,
+		EOD: config.EODConfig{
+			TimeZone:     "Asia/Jakarta",
+			HolidayFile:  holidays,
+			InterestRate: 0.0365,
+			TaxRate:      0.2,
+			MonthlyFee:   5000,
+			DormantDays:  180,
+		},
+	}
+	err := db.Model(&model.TanggalBisnis{}).Where("id = ?", 1).
+		Updates(map[string]any{"tanggal": day("2026-01-30"), "berikutnya": day("2026-02-03"), "cut_off_at": nil}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts := account.NewService(repository.NewGormStore(db, nil), notifier.LogNotifier{Logger: logger})
+	return fixture{db: db, accounts: accounts, service: eod.NewService(db, cfg, accounts)}
+}
+
+func (f fixture) openAccount(t *testing.T, suffix string, saldo float64) model.Nasabah {
+	t.Helper()
+	ctx := context.Background()
+	n, err := f.accounts.OpenAccount(ctx, account.OpenAccountInput{Nama: "Nasabah " + suffix, NIK: "nik-" + suffix, NoHP: "08" + suffix})
+	if err != nil {
+		t.Fatalf("OpenAccount: %v", err)
+	}
+	if n, err = f.accounts.Deposit(ctx, n.NoRekening, saldo, ""); err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	return n
+}
+
+func (f fixture) count(t *testing.T, value any, query string, args ...any) int64 {
+	t.Helper()
+	var n int64
+	if err := f.db.Model(value).Where(query, args...).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// TestRunClosesMonthEnd runs a month-end date through and checks the steps
+// ran in order and that the date moved past the weekend and the holiday.
+func TestRunClosesMonthEnd(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	n := f.openAccount(t, "1", 1_000_000)
+
+	run, err := f.service.Start(ctx, admin)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if run, err = f.service.Execute(ctx, run); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if run.Status != model.EODCompleted || run.Langkah != "" || run.FinishedAt == nil {
+		t.Fatalf("run = %+v, want completed", run)
+	}
+	if len(run.Steps) != len(model.LangkahEOD) {
+		t.Fatalf("steps = %d, want %d", len(run.Steps), len(model.LangkahEOD))
+	}
+	for i, l := range run.Steps {
+		if l.Langkah != model.LangkahEOD[i] || l.Status != model.EODCompleted {
+			t.Errorf("step %d = %s %s, want %s completed", i, l.Langkah, l.Status, model.LangkahEOD[i])
+		}
+		if i > 0 && l.StartedAt.Before(*run.Steps[i-1].FinishedAt) {
+			t.Errorf("%s started before %s finished", l.Langkah, run.Steps[i-1].Langkah)
+		}
+	}
+
+	resp, err := f.service.TanggalBisnis(ctx)
+	if err != nil {
+		t.Fatalf("TanggalBisnis: %v", err)
+	}
+	if !resp.Tanggal.Equal(day("2026-02-03")) || !resp.Berikutnya.Equal(day("2026-02-04")) || resp.CutOffAt != nil {
+		t.Fatalf("tanggal bisnis = %+v, want 2026-02-03 then 2026-02-04 and no cut-off", resp.TanggalBisnis)
+	}
+
+	// Four days at 3.65% a year on 1000000 is 400, less 20% tax, less the
+	// 5000 fee.
+	var bunga model.AkrualBunga
+	if err := f.db.Where("nasabah_id = ?", n.ID).First(&bunga).Error; err != nil {
+		t.Fatalf("akrual: %v", err)
+	}
+	if bunga.Hari != 4 || math.Abs(bunga.Bunga-400) > 1e-6 || bunga.TransaksiID == nil {
+		t.Fatalf("akrual = %+v, want 400 over 4 days, credited", bunga)
+	}
+	if saldo, _ := f.accounts.Balance(ctx, n.NoRekening); saldo != 1_000_000+400-80-5000 {
+		t.Fatalf("saldo = %v, want %v", saldo, 1_000_000+400-80-5000)
+	}
+	statement, err := f.service.Statement(ctx, n.NoRekening, "2026-01")
+	if err != nil {
+		t.Fatalf("Statement: %v", err)
+	}
+	if statement.SaldoAwal != 0 || statement.SaldoAkhir != 1_000_000+400-80-5000 || len(statement.Transaksi) != 4 {
+		t.Fatalf("statement = %+v, want 0 to the closing balance over 4 entries", statement.RekeningKoran)
+	}
+}
+
+// failOn makes every statement executed through db whose SQL contains
+// table fail while *fail is set. inspect, if not nil, runs first.
+func failOn(t *testing.T, db *gorm.DB, table string, fail *bool, inspect func()) {
+	t.Helper()
+	err := db.Callback().Raw().Before("gorm:raw").Register("test:gagal_"+table, func(tx *gorm.DB) {
+		if !*fail || !strings.Contains(tx.Statement.SQL.String(), table) {
+			return
+		}
+		if inspect != nil {
+			inspect()
+		}
+		tx.AddError(errors.New("disk penuh"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestResumeAfterFailedStep fails the statements step, then resumes the run
+// after losing the records of three completed steps, and checks that
+// nothing is credited, charged or accrued twice.
+func TestResumeAfterFailedStep(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	n := f.openAccount(t, "1", 1_000_000)
+
+	fail := true
+	var during model.EODRun
+	failOn(t, f.db, "rekening_korans", &fail, func() {
+		f.db.Session(&gorm.Session{NewDB: true}).First(&during)
+	})
+
+	run, err := f.service.Start(ctx, admin)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	started := *run.LeaseUntil
+	if run, err = f.service.Execute(ctx, run); err == nil {
+		t.Fatal("Execute succeeded while statements fail")
+	}
+	if run.Status != model.EODFailed || run.Langkah != model.LangkahRekeningKoran || run.LeaseUntil != nil {
+		t.Fatalf("run = %s at %q, want failed at %s and the lease released", run.Status, run.Langkah, model.LangkahRekeningKoran)
+	}
+	// The lease was renewed on the way to the failing step.
+	if during.Langkah != model.LangkahRekeningKoran || during.LeaseUntil == nil || !during.LeaseUntil.After(started) {
+		t.Fatalf("run during %s = %q, lease %v; want it renewed past %v", model.LangkahRekeningKoran, during.Langkah, during.LeaseUntil, started)
+	}
+	resp, _ := f.service.TanggalBisnis(ctx)
+	if !resp.Tanggal.Equal(day("2026-01-30")) || resp.CutOffAt == nil {
+		t.Fatalf("tanggal bisnis = %+v, want still 2026-01-30, cut off", resp.TanggalBisnis)
+	}
+	saldo, _ := f.accounts.Balance(ctx, n.NoRekening)
+
+	// A crash between posting and recording the step makes it run again.
+	err = f.db.Unscoped().Where("run_id = ? AND langkah IN ?", run.ID,
+		[]string{model.LangkahBunga, model.LangkahBiaya, model.LangkahSnapshotSaldo}).Delete(&model.EODLangkah{}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.db.Model(&model.AkrualBunga{}).Where("nasabah_id = ?", n.ID).Update("transaksi_id", nil)
+
+	fail = false
+	resumed, err := f.service.Start(ctx, admin)
+	if err != nil {
+		t.Fatalf("Start after failure: %v", err)
+	}
+	if resumed.ID != run.ID {
+		t.Fatalf("Start = run %d, want %d resumed", resumed.ID, run.ID)
+	}
+	if run, err = f.service.Execute(ctx, resumed); err != nil {
+		t.Fatalf("Execute after failure: %v", err)
+	}
+	if run.Status != model.EODCompleted {
+		t.Fatalf("run = %s, want completed", run.Status)
+	}
+
+	if got, _ := f.accounts.Balance(ctx, n.NoRekening); got != saldo {
+		t.Fatalf("saldo after resume = %v, want %v unchanged", got, saldo)
+	}
+	for _, jenis := range []string{model.JenisBunga, model.JenisPajak, model.JenisBiaya} {
+		if got := f.count(t, &model.Transaksi{}, "nasabah_id = ? AND jenis = ?", n.ID, jenis); got != 1 {
+			t.Errorf("%s entries = %d, want 1", jenis, got)
+		}
+	}
+	var bunga model.Transaksi
+	f.db.Where("nasabah_id = ? AND jenis = ?", n.ID, model.JenisBunga).First(&bunga)
+	if bunga.Referensi != "EOD-BUNGA-202601" {
+		t.Errorf("bunga referensi = %q, want EOD-BUNGA-202601", bunga.Referensi)
+	}
+	if got := f.count(t, &model.AkrualBunga{}, "nasabah_id = ?", n.ID); got != 1 {
+		t.Errorf("accruals = %d, want 1", got)
+	}
+	if got := f.count(t, &model.SaldoHarian{}, "nasabah_id = ?", n.ID); got != 1 {
+		t.Errorf("snapshots = %d, want 1", got)
+	}
+	if got := f.count(t, &model.RekeningKoran{}, "nasabah_id = ?", n.ID); got != 1 {
+		t.Errorf("statements = %d, want 1", got)
+	}
+}
+
+func TestStartHonoursLease(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	run, err := f.service.Start(ctx, admin)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := f.service.Start(ctx, admin+1); !errors.Is(err, eod.ErrRunning) {
+		t.Fatalf("Start while leased: err = %v, want ErrRunning", err)
+	}
+
+	// The first runner died: its lease ran out.
+	f.db.Model(&model.EODRun{}).Where("id = ?", run.ID).Update("lease_until", time.Now().Add(-time.Second))
+	taken, err := f.service.Start(ctx, admin+1)
+	if err != nil {
+		t.Fatalf("Start after the lease expired: %v", err)
+	}
+	if taken.ID != run.ID || taken.StartedBy != admin+1 || !taken.LeaseUntil.After(time.Now()) {
+		t.Fatalf("run = %+v, want %d taken over by %d with a fresh lease", taken, run.ID, admin+1)
+	}
+}
+
+func TestStartRefusesFutureDate(t *testing.T) {
+	f := newFixture(t)
+	besok := time.Now().AddDate(0, 0, 2)
+	f.db.Model(&model.TanggalBisnis{}).Where("id = ?", 1).Update("tanggal", besok)
+
+	if _, err := f.service.Start(context.Background(), admin); !errors.Is(err, eod.ErrTooEarly) {
+		t.Fatalf("Start for %s: err = %v, want ErrTooEarly", besok.Format(time.DateOnly), err)
+	}
+}
@@ -0,0 +1,394 @@
+package eod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gobanking/account"
+	"gobanking/fx"
+	"gobanking/model"
+	"gobanking/repository"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// renewEvery is how many accounts a posting step handles between lease
+// renewals.
+const renewEvery = 200
+
+// state is what the steps of one run share.
+type state struct {
+	run        model.EODRun
+	tanggal    time.Time
+	berikutnya time.Time
+	cal        Calendar
+	cutOff     *time.Time
+}
+
+// monthEnd reports whether the business date is the month's last, so
+// interest is credited, fees charged and statements generated.
+func (st *state) monthEnd() bool {
+	return st.berikutnya.Month() != st.tanggal.Month()
+}
+
+func (st *state) periode() string {
+	return st.tanggal.Format("2006-01")
+}
+
+// step runs one end-of-day step and returns the number of accounts or rows
+// it touched. Every step must be safe to run again after a failure.
+type step func(ctx context.Context, st *state) (int, error)
+
+func (s *Service) steps() map[string]step {
+	return map[string]step{
+		model.LangkahCutOff:        s.cutOff,
+		model.LangkahBunga:         s.accrue,
+		model.LangkahBiaya:         s.chargeFees,
+		model.LangkahDormansi:      s.markDormant,
+		model.LangkahSnapshotSaldo: s.snapshot,
+		model.LangkahRekeningKoran: s.statements,
+		model.LangkahRollover:      s.rollover,
+	}
+}
+
+// saldoAt is the balance of nasabahs n at the end of business date ?,
+// the current balance less whatever was booked on later dates.
+const saldoAt = `n.saldo - COALESCE((
+	SELECT SUM(CASE WHEN t.jenis IN ? THEN t.nominal ELSE -t.nominal END)
+	FROM transaksis t
+	WHERE t.nasabah_id = n.id AND t.tanggal_bisnis > ? AND t.deleted_at IS NULL
+), 0)`
+
+// cutOff closes the business date: transactions booked from now on get
+// the next business date. The exclusive calendar lock waits for bookings
+// that read the old date to commit, so none lands on it afterwards.
+func (s *Service) cutOff(ctx context.Context, st *state) (int, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", repository.CalendarLockKey).Error; err != nil {
+			return err
+		}
+		var tanggal model.TanggalBisnis
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tanggal).Error; err != nil {
+			return err
+		}
+		if !date(tanggal.Tanggal).Equal(st.tanggal) {
+			return fmt.Errorf("tanggal bisnis %s, EOD untuk %s",
+				tanggal.Tanggal.Format(time.DateOnly), st.tanggal.Format(time.DateOnly))
+		}
+		if tanggal.CutOffAt == nil {
+			now := time.Now()
+			tanggal.CutOffAt = &now
+		}
+		tanggal.Berikutnya = st.berikutnya
+		st.cutOff = tanggal.CutOffAt
+		return tx.Model(&tanggal).Updates(map[string]any{
+			"berikutnya": tanggal.Berikutnya,
+			"cut_off_at": tanggal.CutOffAt,
+		}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	err = s.db.WithContext(ctx).Model(&model.Transaksi{}).Where("tanggal_bisnis = ?", st.tanggal).Count(&n).Error
+	return int(n), err
+}
+
+// cutOffAt returns when the business date was cut off. Accounts opened
+// after that are left to the next date's run.
+func (s *Service) cutOffAt(ctx context.Context, st *state) (time.Time, error) {
+	if st.cutOff == nil {
+		var tanggal model.TanggalBisnis
+		if err := s.db.WithContext(ctx).First(&tanggal).Error; err != nil {
+			return time.Time{}, err
+		}
+		if tanggal.CutOffAt == nil {
+			return time.Time{}, errors.New("tanggal bisnis belum di-cut-off")
+		}
+		st.cutOff = tanggal.CutOffAt
+	}
+	return *st.cutOff, nil
+}
+
+// accrue accrues a day's interest on every positive balance for each
+// calendar day until the next business date, so weekends and holidays
+// earn interest too. At month end the month's accruals are credited per
+// account and the withholding tax debited.
+func (s *Service) accrue(ctx context.Context, st *state) (int, error) {
+	cutOff, err := s.cutOffAt(ctx, st)
+	if err != nil {
+		return 0, err
+	}
+	hari := int(st.berikutnya.Sub(st.tanggal).Hours() / 24)
+	faktor := s.cfg.EOD.InterestRate * float64(hari) / 365
+
+	result := s.db.WithContext(ctx).Exec(`
+		INSERT INTO akrual_bungas (created_at, updated_at, nasabah_id, no_rekening, tanggal_bisnis, saldo, hari, bunga, mata_uang)
+		SELECT ?, ?, id, no_rekening, ?, saldo_d, ?, saldo_d * ?, mata_uang
+		FROM (
+			SELECT n.id, n.no_rekening, n.mata_uang, `+saldoAt+` AS saldo_d
+			FROM nasabahs n
+			WHERE n.deleted_at IS NULL AND n.created_at <= ?
+		) s
+		WHERE saldo_d > 0
+		ON CONFLICT DO NOTHING`,
+		time.Now(), time.Now(), st.tanggal, hari, faktor,
+		model.JenisKredit, st.tanggal, cutOff,
+	)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	jumlah := int(result.RowsAffected)
+	if !st.monthEnd() {
+		return jumlah, nil
+	}
+
+	var totals []struct {
+		NasabahID  uint
+		NoRekening string
+		MataUang   string
+		Bunga      float64
+	}
+	err = s.db.WithContext(ctx).Model(&model.AkrualBunga{}).
+		Select("nasabah_id, no_rekening, mata_uang, SUM(bunga) AS bunga").
+		Where("transaksi_id IS NULL AND tanggal_bisnis <= ?", st.tanggal).
+		Group("nasabah_id, no_rekening, mata_uang").
+		Order("nasabah_id").
+		Scan(&totals).Error
+	if err != nil {
+		return jumlah, err
+	}
+
+	for i, total := range totals {
+		if i > 0 && i%renewEvery == 0 {
+			if err := s.renew(ctx, &st.run, model.LangkahBunga); err != nil {
+				return jumlah, err
+			}
+		}
+		bunga := fx.Round(total.Bunga, total.MataUang)
+		if bunga <= 0 {
+			// Carried into next month's credit.
+			continue
+		}
+
+		// Interest and tax are posted before the accruals are marked, so a
+		// failure in between is repeated idempotently by reference.
+		kredit, err := s.post(ctx, account.PostingInput{
+			NoRekening:    total.NoRekening,
+			Jenis:         model.JenisBunga,
+			Nominal:       bunga,
+			Referensi:     "EOD-BUNGA-" + st.tanggal.Format("200601"),
+			TanggalBisnis: st.tanggal,
+		})
+		if err != nil {
+			return jumlah, fmt.Errorf("%s: %w", total.NoRekening, err)
+		}
+		if pajak := fx.Round(bunga*s.cfg.EOD.TaxRate, total.MataUang); pajak > 0 {
+			_, err := s.post(ctx, account.PostingInput{
+				NoRekening:    total.NoRekening,
+				Jenis:         model.JenisPajak,
+				Nominal:       pajak,
+				Referensi:     "EOD-PAJAK-" + st.tanggal.Format("200601"),
+				TanggalBisnis: st.tanggal,
+			})
+			if errors.Is(err, account.ErrInsufficientFunds) {
+				// Only possible if the interest was spent since; the tax is
+				// settled manually.
+				s.cfg.Logger.Warn("pajak bunga tidak terpotong", "no_rekening", total.NoRekening, "nominal", pajak)
+			} else if err != nil {
+				return jumlah, fmt.Errorf("%s: %w", total.NoRekening, err)
+			}
+		}
+
+		err = s.db.WithContext(ctx).Model(&model.AkrualBunga{}).
+			Where("nasabah_id = ? AND transaksi_id IS NULL AND tanggal_bisnis <= ?", total.NasabahID, st.tanggal).
+			Update("transaksi_id", kredit.ID).Error
+		if err != nil {
+			return jumlah, err
+		}
+	}
+	return jumlah, nil
+}
+
+// chargeFees debits the monthly administration fee from rupiah accounts at
+// month end. An account that cannot cover it is skipped and logged.
+func (s *Service) chargeFees(ctx context.Context, st *state) (int, error) {
+	if !st.monthEnd() || s.cfg.EOD.MonthlyFee == 0 {
+		return 0, nil
+	}
+	cutOff, err := s.cutOffAt(ctx, st)
+	if err != nil {
+		return 0, err
+	}
+
+	var rekening []string
+	err = s.db.WithContext(ctx).Model(&model.Nasabah{}).
+		Where("mata_uang = ? AND created_at <= ?", "IDR", cutOff).
+		Order("id").
+		Pluck("no_rekening", &rekening).Error
+	if err != nil {
+		return 0, err
+	}
+
+	jumlah := 0
+	for i, noRekening := range rekening {
+		if i > 0 && i%renewEvery == 0 {
+			if err := s.renew(ctx, &st.run, model.LangkahBiaya); err != nil {
+				return jumlah, err
+			}
+		}
+		_, err := s.post(ctx, account.PostingInput{
+			NoRekening:    noRekening,
+			Jenis:         model.JenisBiaya,
+			Nominal:       s.cfg.EOD.MonthlyFee,
+			Referensi:     "EOD-BIAYA-" + st.tanggal.Format("200601"),
+			TanggalBisnis: st.tanggal,
+		})
+		if errors.Is(err, account.ErrInsufficientFunds) {
+			s.cfg.Logger.Warn("biaya admin tidak terpotong", "no_rekening", noRekening, "nominal", s.cfg.EOD.MonthlyFee)
+			continue
+		}
+		if err != nil {
+			return jumlah, fmt.Errorf("%s: %w", noRekening, err)
+		}
+		jumlah++
+	}
+	return jumlah, nil
+}
+
+// post books in, treating a reference already used as done so a resumed
+// step does not charge twice.
+func (s *Service) post(ctx context.Context, in account.PostingInput) (model.Transaksi, error) {
+	transaksi, err := s.accounts.Post(ctx, in)
+	if errors.Is(err, account.ErrDuplicateReference) {
+		return transaksi, nil
+	}
+	return transaksi, err
+}
+
+// markDormant marks accounts without a customer-initiated transaction in
+// EOD_DORMANT_DAYS as dormant, and clears the mark on accounts active
+// again since. Interest, fees and incoming transfers do not count as
+// activity.
+func (s *Service) markDormant(ctx context.Context, st *state) (int, error) {
+	aktif := []string{model.JenisTabung, model.JenisTarik, model.JenisTransferKeluar}
+	batas := st.tanggal.AddDate(0, 0, -s.cfg.EOD.DormantDays)
+
+	var jumlah int64
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`
+			UPDATE nasabahs SET dormant_at = ?
+			WHERE dormant_at IS NULL AND deleted_at IS NULL AND created_at < ?
+			AND NOT EXISTS (
+				SELECT 1 FROM transaksis t
+				WHERE t.nasabah_id = nasabahs.id AND t.jenis IN ? AND t.tanggal_bisnis > ? AND t.deleted_at IS NULL
+			)`,
+			time.Now(), batas, aktif, batas,
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+		jumlah = result.RowsAffected
+
+		return tx.Exec(`
+			UPDATE nasabahs SET dormant_at = NULL
+			WHERE dormant_at IS NOT NULL
+			AND EXISTS (
+				SELECT 1 FROM transaksis t
+				WHERE t.nasabah_id = nasabahs.id AND t.jenis IN ? AND t.created_at > nasabahs.dormant_at AND t.deleted_at IS NULL
+			)`,
+			aktif,
+		).Error
+	})
+	return int(jumlah), err
+}
+
+// snapshot records every account's balance at the end of the business
+// date.
+func (s *Service) snapshot(ctx context.Context, st *state) (int, error) {
+	cutOff, err := s.cutOffAt(ctx, st)
+	if err != nil {
+		return 0, err
+	}
+	result := s.db.WithContext(ctx).Exec(`
+		INSERT INTO saldo_harians (created_at, updated_at, nasabah_id, no_rekening, tanggal_bisnis, saldo, mata_uang)
+		SELECT ?, ?, n.id, n.no_rekening, ?, `+saldoAt+`, n.mata_uang
+		FROM nasabahs n
+		WHERE n.deleted_at IS NULL AND n.created_at <= ?
+		ON CONFLICT DO NOTHING`,
+		time.Now(), time.Now(), st.tanggal, model.JenisKredit, st.tanggal, cutOff,
+	)
+	return int(result.RowsAffected), result.Error
+}
+
+// statements generates the month's statements from the closing snapshot
+// and the month's transactions. The opening balance is derived, so it
+// matches the previous statement's closing balance by construction.
+func (s *Service) statements(ctx context.Context, st *state) (int, error) {
+	if !st.monthEnd() {
+		return 0, nil
+	}
+	awal := time.Date(st.tanggal.Year(), st.tanggal.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	result := s.db.WithContext(ctx).Exec(`
+		INSERT INTO rekening_korans (created_at, updated_at, nasabah_id, no_rekening, periode, tanggal_awal, tanggal_akhir,
+			mata_uang, saldo_awal, total_kredit, total_debit, saldo_akhir, jumlah_transaksi)
+		SELECT ?, ?, h.nasabah_id, h.no_rekening, ?, ?, ?,
+			h.mata_uang, h.saldo - COALESCE(m.kredit, 0) + COALESCE(m.debit, 0),
+			COALESCE(m.kredit, 0), COALESCE(m.debit, 0), h.saldo, COALESCE(m.jumlah, 0)
+		FROM saldo_harians h
+		LEFT JOIN (
+			SELECT nasabah_id,
+				SUM(CASE WHEN jenis IN ? THEN nominal ELSE 0 END) AS kredit,
+				SUM(CASE WHEN jenis IN ? THEN 0 ELSE nominal END) AS debit,
+				COUNT(*) AS jumlah
+			FROM transaksis
+			WHERE tanggal_bisnis BETWEEN ? AND ? AND deleted_at IS NULL
+			GROUP BY nasabah_id
+		) m ON m.nasabah_id = h.nasabah_id
+		WHERE h.tanggal_bisnis = ? AND h.deleted_at IS NULL
+		ON CONFLICT DO NOTHING`,
+		time.Now(), time.Now(), st.periode(), awal, st.tanggal,
+		model.JenisKredit, model.JenisKredit, awal, st.tanggal, st.tanggal,
+	)
+	return int(result.RowsAffected), result.Error
+}
+
+// rollover moves the business date to the next business day and completes
+// the run in the same transaction, so a crash leaves either both or
+// neither.
+func (s *Service) rollover(ctx context.Context, st *state) (int, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var tanggal model.TanggalBisnis
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tanggal).Error; err != nil {
+			return err
+		}
+		if !date(tanggal.Tanggal).Equal(st.tanggal) {
+			return fmt.Errorf("tanggal bisnis %s, EOD untuk %s",
+				tanggal.Tanggal.Format(time.DateOnly), st.tanggal.Format(time.DateOnly))
+		}
+		err := tx.Model(&tanggal).Updates(map[string]any{
+			"tanggal":    st.berikutnya,
+			"berikutnya": st.cal.Next(st.berikutnya),
+			"cut_off_at": nil,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		err = tx.Model(&model.EODLangkah{}).
+			Where("run_id = ? AND langkah = ?", st.run.ID, model.LangkahRollover).
+			Updates(map[string]any{"status": model.EODCompleted, "jumlah": 1, "finished_at": now}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.EODRun{}).
+			Where("id = ?", st.run.ID).
+			Updates(map[string]any{"status": model.EODCompleted, "langkah": "", "lease_until": nil, "finished_at": now}).Error
+	})
+	return 1, err
+}
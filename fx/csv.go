@@ -0,0 +1,86 @@
+package fx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gobanking/model"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCSV = errors.New("file kurs tidak valid")
+
+// ParseCSV reads rates from a file with a header row naming mata_uang, beli
+// and jual, plus an optional berlaku_mulai in RFC 3339 that defaults to
+// now. Unlike payroll uploads the file is all or nothing: the first bad row
+// fails it, so a half-loaded rate sheet never goes live.
+func ParseCSV(r io.Reader, now time.Time) ([]model.Kurs, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheet exports often start with a byte order mark.
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"mata_uang", "beli", "jual"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: kolom %s tidak ada", ErrInvalidCSV, required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rates []model.Kurs
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+
+		k := model.Kurs{
+			MataUang:     strings.ToUpper(field(record, "mata_uang")),
+			BerlakuMulai: now,
+			Sumber:       model.KursSumberCSV,
+		}
+		if k.Beli, err = strconv.ParseFloat(field(record, "beli"), 64); err != nil {
+			return nil, fmt.Errorf("%w: baris %d: beli tidak valid", ErrInvalidCSV, line)
+		}
+		if k.Jual, err = strconv.ParseFloat(field(record, "jual"), 64); err != nil {
+			return nil, fmt.Errorf("%w: baris %d: jual tidak valid", ErrInvalidCSV, line)
+		}
+		if v := field(record, "berlaku_mulai"); v != "" {
+			if k.BerlakuMulai, err = time.Parse(time.RFC3339, v); err != nil {
+				return nil, fmt.Errorf("%w: baris %d: berlaku_mulai harus RFC 3339", ErrInvalidCSV, line)
+			}
+		}
+		if err := ValidateRate(k); err != nil {
+			return nil, fmt.Errorf("%w: baris %d: %v", ErrInvalidCSV, line, err)
+		}
+		rates = append(rates, k)
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%w: tidak ada baris kurs", ErrInvalidCSV)
+	}
+	return rates, nil
+}
@@ -0,0 +1,135 @@
+// Package fx prices conversions between account currencies. Every rate is
+// quoted in IDR, so a conversion between two foreign currencies crosses
+// through IDR: the bank buys the source currency at its Beli rate and sells
+// the destination currency at its Jual rate.
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"gobanking/model"
+	"math"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	ErrRateNotFound = errors.New("kurs tidak tersedia")
+	ErrInvalidRate  = errors.New("kurs tidak valid")
+)
+
+var validate = validator.New()
+
+// RateFunc returns the rate in effect for a foreign currency, or
+// ErrRateNotFound.
+type RateFunc func(mataUang string) (model.Kurs, error)
+
+// Quote is a priced conversion of NominalAsal into NominalTujuan.
+type Quote struct {
+	MataUangAsal   string
+	NominalAsal    float64
+	MataUangTujuan string
+	NominalTujuan  float64
+	// KursAsal and KursTujuan are the rates used, nil on the IDR side.
+	KursAsal   *model.Kurs
+	KursTujuan *model.Kurs
+	// Kurs is the effective rate in units of MataUangTujuan per
+	// MataUangAsal.
+	Kurs float64
+	// LabaRugi is the bank's gain (positive) or loss in IDR at mid rates.
+	LabaRugi float64
+}
+
+// Convert prices nominal in asal as an amount of tujuan, rounded to the
+// minor unit of tujuan. rate is only called for currencies other than IDR.
+func Convert(nominal float64, asal, tujuan string, rate RateFunc) (Quote, error) {
+	q := Quote{MataUangAsal: asal, NominalAsal: nominal, MataUangTujuan: tujuan}
+
+	beli, tengahAsal := 1.0, 1.0
+	if asal != model.MataUangIDR {
+		k, err := rate(asal)
+		if err != nil {
+			return Quote{}, err
+		}
+		q.KursAsal, beli, tengahAsal = &k, k.Beli, k.Tengah()
+	}
+	jual, tengahTujuan := 1.0, 1.0
+	if tujuan != model.MataUangIDR {
+		k, err := rate(tujuan)
+		if err != nil {
+			return Quote{}, err
+		}
+		q.KursTujuan, jual, tengahTujuan = &k, k.Jual, k.Tengah()
+	}
+
+	q.Kurs = beli / jual
+	q.NominalTujuan = Round(nominal*q.Kurs, tujuan)
+	q.LabaRugi = Round(nominal*tengahAsal-q.NominalTujuan*tengahTujuan, model.MataUangIDR)
+	return q, nil
+}
+
+// Equivalent values nominal in IDR at the mid rate, e.g. to apply an IDR
+// limit to a foreign currency amount.
+func Equivalent(nominal float64, mataUang string, rate RateFunc) (float64, error) {
+	if mataUang == model.MataUangIDR {
+		return nominal, nil
+	}
+	k, err := rate(mataUang)
+	if err != nil {
+		return 0, err
+	}
+	return nominal * k.Tengah(), nil
+}
+
+// Round rounds v to the minor unit of mataUang (ISO 4217 exponent).
+func Round(v float64, mataUang string) float64 {
+	scale := math.Pow10(exponent(mataUang))
+	return math.Round(v*scale) / scale
+}
+
+func exponent(mataUang string) int {
+	switch mataUang {
+	case "BIF", "CLP", "DJF", "GNF", "ISK", "JPY", "KMF", "KRW", "PYG",
+		"RWF", "UGX", "UYI", "VND", "VUV", "XAF", "XOF", "XPF":
+		return 0
+	case "BHD", "IQD", "JOD", "KWD", "LYD", "OMR", "TND":
+		return 3
+	}
+	return 2
+}
+
+// ValidCurrency reports whether code is an ISO 4217 currency code.
+func ValidCurrency(code string) bool {
+	return validate.Var(code, "required,iso4217") == nil
+}
+
+// ValidateRate checks a rate before it is stored.
+func ValidateRate(k model.Kurs) error {
+	switch {
+	case !ValidCurrency(k.MataUang):
+		return fmt.Errorf("%w: mata uang %q bukan kode ISO 4217", ErrInvalidRate, k.MataUang)
+	case k.MataUang == model.MataUangIDR:
+		return fmt.Errorf("%w: IDR adalah mata uang dasar", ErrInvalidRate)
+	case !(k.Beli > 0) || math.IsInf(k.Beli, 0):
+		return fmt.Errorf("%w: beli %s harus lebih dari 0", ErrInvalidRate, k.MataUang)
+	case !(k.Jual >= k.Beli) || math.IsInf(k.Jual, 0):
+		return fmt.Errorf("%w: jual %s tidak boleh di bawah beli", ErrInvalidRate, k.MataUang)
+	case k.BerlakuMulai.IsZero():
+		return fmt.Errorf("%w: berlaku_mulai %s harus diisi", ErrInvalidRate, k.MataUang)
+	}
+	return nil
+}
+
+// Response converts a rate for API output.
+func Response(k model.Kurs) model.KursResponse {
+	return model.KursResponse{
+		ID:           k.ID,
+		MataUang:     k.MataUang,
+		Beli:         k.Beli,
+		Jual:         k.Jual,
+		Tengah:       k.Tengah(),
+		BerlakuMulai: k.BerlakuMulai,
+		Sumber:       k.Sumber,
+		Basis:        model.MataUangIDR,
+	}
+}
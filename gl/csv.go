@@ -0,0 +1,44 @@
+package gl
+
+import (
+	"encoding/csv"
+	"gobanking/model"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteTrialBalanceCSV writes one line per account and currency followed by
+// one total line per currency.
+func WriteTrialBalanceCSV(w io.Writer, tb model.NeracaSaldoResponse) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"kode", "nama", "tipe", "mata_uang", "debit", "kredit", "saldo"})
+	for _, b := range tb.Baris {
+		writer.Write([]string{b.Kode, b.Nama, b.Tipe, b.MataUang, amount(b.Debit), amount(b.Kredit), amount(b.Saldo)})
+	}
+	for _, t := range tb.Total {
+		writer.Write([]string{"", "TOTAL", "", t.MataUang, amount(t.Debit), amount(t.Kredit), amount(t.Debit - t.Kredit)})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJournalCSV writes each journal line as its debit and credit legs so
+// the file can be loaded into an accounting system as is.
+func WriteJournalCSV(w io.Writer, jurnal []model.JurnalGL) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"jurnal_id", "transaksi_id", "tanggal", "jenis", "kode_akun", "mata_uang", "debit", "kredit"})
+	for _, j := range jurnal {
+		id := strconv.FormatUint(uint64(j.ID), 10)
+		transaksiID := strconv.FormatUint(uint64(j.TransaksiID), 10)
+		tanggal := j.Tanggal.Format(time.RFC3339)
+		writer.Write([]string{id, transaksiID, tanggal, j.Jenis, j.AkunDebit, j.MataUang, amount(j.Nominal), amount(0)})
+		writer.Write([]string{id, transaksiID, tanggal, j.Jenis, j.AkunKredit, j.MataUang, amount(0), amount(j.Nominal)})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func amount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
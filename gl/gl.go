@@ -0,0 +1,221 @@
+// Package gl is the bank's general ledger. account.Service posts every
+// Transaksi as one balanced JurnalGL line using the AturanPosting of its
+// Jenis; this package maintains the chart of accounts and the rules, and
+// reports the trial balance, which also proves the customer deposit
+// accounts against the sum of nasabahs.saldo.
+package gl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gobanking/model"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// tolerance absorbs float rounding when comparing sums.
+const tolerance = 0.005
+
+var (
+	ErrAkunNotFound = errors.New("akun GL tidak ditemukan")
+	ErrAkunExists   = errors.New("kode akun GL sudah ada")
+	ErrJenisUnknown = errors.New("jenis transaksi tidak dikenal")
+)
+
+type Service struct {
+	db *gorm.DB
+}
+
+func NewService(db *gorm.DB) *Service {
+	return &Service{db: db}
+}
+
+// Accounts returns the chart of accounts ordered by Kode.
+func (s *Service) Accounts(ctx context.Context) ([]model.AkunGL, error) {
+	var accounts []model.AkunGL
+	err := s.db.WithContext(ctx).Order("kode").Find(&accounts).Error
+	return accounts, err
+}
+
+func (s *Service) CreateAccount(ctx context.Context, req model.AkunGLRequest) (model.AkunGL, error) {
+	akun := model.AkunGL{
+		Kode:            req.Kode,
+		Nama:            req.Nama,
+		Tipe:            req.Tipe,
+		SimpananNasabah: req.SimpananNasabah,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&model.AkunGL{}).Where("kode = ?", req.Kode).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrAkunExists
+		}
+		return tx.Create(&akun).Error
+	})
+	return akun, err
+}
+
+// Rules returns the posting rules ordered by Jenis.
+func (s *Service) Rules(ctx context.Context) ([]model.AturanPosting, error) {
+	var rules []model.AturanPosting
+	err := s.db.WithContext(ctx).Order("jenis").Find(&rules).Error
+	return rules, err
+}
+
+// SetRule points jenis at new debit and credit accounts. Transactions
+// already posted keep their journal lines; only later ones use the new
+// rule.
+func (s *Service) SetRule(ctx context.Context, jenis string, req model.AturanPostingRequest) (model.AturanPosting, error) {
+	known := false
+	for _, a := range model.DefaultAturanPosting {
+		known = known || a.Jenis == jenis
+	}
+	if !known {
+		return model.AturanPosting{}, fmt.Errorf("%w: %s", ErrJenisUnknown, jenis)
+	}
+
+	var aturan model.AturanPosting
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, kode := range []string{req.AkunDebit, req.AkunKredit} {
+			var found int64
+			if err := tx.Model(&model.AkunGL{}).Where("kode = ?", kode).Count(&found).Error; err != nil {
+				return err
+			}
+			if found == 0 {
+				return fmt.Errorf("%w: %s", ErrAkunNotFound, kode)
+			}
+		}
+
+		err := tx.Where("jenis = ?", jenis).First(&aturan).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			aturan = model.AturanPosting{Jenis: jenis, AkunDebit: req.AkunDebit, AkunKredit: req.AkunKredit}
+			return tx.Create(&aturan).Error
+		}
+		if err != nil {
+			return err
+		}
+		aturan.AkunDebit, aturan.AkunKredit = req.AkunDebit, req.AkunKredit
+		return tx.Save(&aturan).Error
+	})
+	return aturan, err
+}
+
+// TrialBalance sums the journal per account and currency. It reads one
+// snapshot, so postings committed while it runs cannot unbalance it.
+func (s *Service) TrialBalance(ctx context.Context) (model.NeracaSaldoResponse, error) {
+	resp := model.NeracaSaldoResponse{
+		Per:      time.Now(),
+		Baris:    []model.NeracaSaldoBaris{},
+		Total:    []model.NeracaSaldoTotal{},
+		Seimbang: true,
+	}
+
+	var (
+		accounts []model.AkunGL
+		sums     []struct {
+			Kode     string
+			MataUang string
+			Debit    float64
+			Kredit   float64
+		}
+		saldo []struct {
+			MataUang string
+			Saldo    float64
+		}
+	)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Find(&accounts).Error; err != nil {
+			return err
+		}
+		err := tx.Raw(`
+			SELECT kode, mata_uang, SUM(debit) AS debit, SUM(kredit) AS kredit FROM (
+				SELECT akun_debit AS kode, mata_uang, nominal AS debit, 0 AS kredit FROM jurnal_gls WHERE deleted_at IS NULL
+				UNION ALL
+				SELECT akun_kredit AS kode, mata_uang, 0 AS debit, nominal AS kredit FROM jurnal_gls WHERE deleted_at IS NULL
+			) j
+			GROUP BY kode, mata_uang
+			ORDER BY kode, mata_uang`).Scan(&sums).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.Nasabah{}).
+			Select("mata_uang, SUM(saldo) AS saldo").
+			Group("mata_uang").
+			Scan(&saldo).Error
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return resp, err
+	}
+
+	chart := make(map[string]model.AkunGL, len(accounts))
+	for _, a := range accounts {
+		chart[a.Kode] = a
+	}
+	totals := make(map[string]*model.NeracaSaldoTotal)
+	total := func(mataUang string) *model.NeracaSaldoTotal {
+		t, ok := totals[mataUang]
+		if !ok {
+			t = &model.NeracaSaldoTotal{MataUang: mataUang}
+			totals[mataUang] = t
+		}
+		return t
+	}
+
+	for _, sum := range sums {
+		akun := chart[sum.Kode]
+		baris := model.NeracaSaldoBaris{
+			Kode:     sum.Kode,
+			Nama:     akun.Nama,
+			Tipe:     akun.Tipe,
+			MataUang: sum.MataUang,
+			Debit:    sum.Debit,
+			Kredit:   sum.Kredit,
+			Saldo:    sum.Debit - sum.Kredit,
+		}
+		if akun.Tipe == model.TipeLiability || akun.Tipe == model.TipeEquity || akun.Tipe == model.TipeIncome {
+			baris.Saldo = -baris.Saldo
+		}
+		resp.Baris = append(resp.Baris, baris)
+
+		t := total(sum.MataUang)
+		t.Debit += sum.Debit
+		t.Kredit += sum.Kredit
+		if akun.SimpananNasabah {
+			t.LiabilitasNasabah += sum.Kredit - sum.Debit
+		}
+	}
+	for _, n := range saldo {
+		total(n.MataUang).SaldoNasabah += n.Saldo
+	}
+
+	for _, t := range totals {
+		t.Seimbang = math.Abs(t.Debit-t.Kredit) < tolerance &&
+			math.Abs(t.LiabilitasNasabah-t.SaldoNasabah) < tolerance
+		resp.Seimbang = resp.Seimbang && t.Seimbang
+		resp.Total = append(resp.Total, *t)
+	}
+	sort.Slice(resp.Total, func(i, j int) bool { return resp.Total[i].MataUang < resp.Total[j].MataUang })
+	return resp, nil
+}
+
+// Journal returns the journal lines booked in [from, to), oldest first. A
+// zero from or to leaves that end open.
+func (s *Service) Journal(ctx context.Context, from, to time.Time) ([]model.JurnalGL, error) {
+	query := s.db.WithContext(ctx).Order("tanggal, id")
+	if !from.IsZero() {
+		query = query.Where("tanggal >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("tanggal < ?", to)
+	}
+	var jurnal []model.JurnalGL
+	err := query.Find(&jurnal).Error
+	return jurnal, err
+}
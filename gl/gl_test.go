@@ -0,0 +1,138 @@
+package gl_test
+
+import (
+	"context"
+	"gobanking/account"
+	"gobanking/database/databasetest"
+	"gobanking/gl"
+	"gobanking/model"
+	"gobanking/notifier"
+	"gobanking/repository"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// TestDefaultPostingRules checks that every rule moves money between two
+// accounts, and that a customer movement credits customer deposits exactly
+// when it raises the balance, so the deposit account tracks nasabahs.saldo.
+func TestDefaultPostingRules(t *testing.T) {
+	kredit := map[string]bool{}
+	for _, jenis := range model.JenisKredit {
+		kredit[jenis] = true
+	}
+	rules := map[string]model.AturanPosting{}
+	for _, a := range model.DefaultAturanPosting {
+		if _, dup := rules[a.Jenis]; dup {
+			t.Fatalf("two rules for %s", a.Jenis)
+		}
+		rules[a.Jenis] = a
+		if a.AkunDebit == "" || a.AkunKredit == "" || a.AkunDebit == a.AkunKredit {
+			t.Errorf("%s: debit %q, kredit %q; want two different accounts", a.Jenis, a.AkunDebit, a.AkunKredit)
+		}
+	}
+
+	for _, jenis := range []string{
+		model.JenisTabung, model.JenisTarik, model.JenisTransferKeluar, model.JenisTransferMasuk,
+		model.JenisKoreksiDebit, model.JenisKoreksiKredit, model.JenisBiaya, model.JenisBunga,
+		model.JenisPajak, model.JenisSaldoAwal,
+	} {
+		a, ok := rules[jenis]
+		switch {
+		case !ok:
+			t.Errorf("no rule for %s", jenis)
+		case kredit[jenis] && a.AkunKredit != model.AkunSimpananNasabah:
+			t.Errorf("%s credits %s, want customer deposits", jenis, a.AkunKredit)
+		case !kredit[jenis] && a.AkunDebit != model.AkunSimpananNasabah:
+			t.Errorf("%s debits %s, want customer deposits", jenis, a.AkunDebit)
+		}
+	}
+	// FX results settle the clearing account and never touch a customer.
+	for _, jenis := range []string{model.JenisLabaKurs, model.JenisRugiKurs} {
+		a, ok := rules[jenis]
+		if !ok {
+			t.Errorf("no rule for %s", jenis)
+			continue
+		}
+		if a.AkunDebit != model.AkunKliring && a.AkunKredit != model.AkunKliring {
+			t.Errorf("%s = %s/%s, want one side on clearing", jenis, a.AkunDebit, a.AkunKredit)
+		}
+		if a.AkunDebit == model.AkunSimpananNasabah || a.AkunKredit == model.AkunSimpananNasabah {
+			t.Errorf("%s posts to customer deposits", jenis)
+		}
+	}
+}
+
+// TestTrialBalanceBalances books a deposit, withdrawal, transfer, both FX
+// directions and a reversal, and checks the migrated chart and rules keep
+// every currency in balance.
+func TestTrialBalanceBalances(t *testing.T) {
+	ctx := context.Background()
+	db := databasetest.Open(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := account.NewService(repository.NewGormStore(db, nil), notifier.LogNotifier{Logger: logger})
+	err := s.SetRates(ctx, []model.Kurs{{
+		MataUang: "USD", Beli: 14900, Jual: 15100, BerlakuMulai: time.Now().Add(-time.Minute), Sumber: "api",
+	}})
+	if err != nil {
+		t.Fatalf("SetRates: %v", err)
+	}
+
+	open := func(suffix, mataUang string, saldo float64) model.Nasabah {
+		t.Helper()
+		n, err := s.OpenAccount(ctx, account.OpenAccountInput{Nama: "Nasabah " + suffix, NIK: "nik-" + suffix, NoHP: "08" + suffix, MataUang: mataUang})
+		if err != nil {
+			t.Fatalf("OpenAccount(%s): %v", suffix, err)
+		}
+		if saldo > 0 {
+			if n, err = s.Deposit(ctx, n.NoRekening, saldo, ""); err != nil {
+				t.Fatalf("Deposit(%s): %v", suffix, err)
+			}
+		}
+		return n
+	}
+	a := open("1", "IDR", 1_000_000)
+	b := open("2", "IDR", 0)
+	c := open("3", "USD", 100)
+
+	if _, err := s.Withdraw(ctx, a.NoRekening, 150_000); err != nil {
+		t.Fatalf("Withdraw: %v", err)
+	}
+	for _, in := range []account.TransferInput{
+		{Sumber: a.NoRekening, Tujuan: b.NoRekening, Nominal: 200_000},
+		{Sumber: c.NoRekening, Tujuan: a.NoRekening, Nominal: 10},
+		{Sumber: b.NoRekening, Tujuan: c.NoRekening, Nominal: 151_000},
+	} {
+		if _, err := s.Transfer(ctx, in); err != nil {
+			t.Fatalf("Transfer(%+v): %v", in, err)
+		}
+	}
+	if _, err := s.Deposit(ctx, b.NoRekening, 50_000, ""); err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	var tabung model.Transaksi
+	if err := db.Where("no_rekening = ? AND jenis = ?", b.NoRekening, model.JenisTabung).Last(&tabung).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Reverse(ctx, account.ReverseInput{TransaksiID: tabung.ID}); err != nil {
+		t.Fatalf("Reverse: %v", err)
+	}
+
+	nb, err := gl.NewService(db).TrialBalance(ctx)
+	if err != nil {
+		t.Fatalf("TrialBalance: %v", err)
+	}
+	if !nb.Seimbang || len(nb.Total) != 2 {
+		t.Fatalf("trial balance = %+v, want IDR and USD in balance", nb.Total)
+	}
+	want := map[string]float64{"IDR": 1_000_000 - 150_000 + 149_000 - 151_000, "USD": 90 + 10}
+	for _, total := range nb.Total {
+		if !total.Seimbang || total.Debit != total.Kredit {
+			t.Errorf("%s: debit %v, kredit %v; want equal", total.MataUang, total.Debit, total.Kredit)
+		}
+		if total.LiabilitasNasabah != want[total.MataUang] || total.SaldoNasabah != want[total.MataUang] {
+			t.Errorf("%s: deposits %v, saldo %v; want %v", total.MataUang, total.LiabilitasNasabah, total.SaldoNasabah, want[total.MataUang])
+		}
+	}
+}
@@ -0,0 +1,863 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.0
+// 	protoc        (unknown)
+// source: banking/v1/banking.proto
+
+package bankingpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OpenAccountRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Nama  string                 `protobuf:"bytes,1,opt,name=nama,proto3" json:"nama,omitempty"`
+	Nik   string                 `protobuf:"bytes,2,opt,name=nik,proto3" json:"nik,omitempty"`
+	NoHp  string                 `protobuf:"bytes,3,opt,name=no_hp,json=noHp,proto3" json:"no_hp,omitempty"`
+	Email string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	// bahasa selects the notification language, "id" or "en".
+	Bahasa string `protobuf:"bytes,5,opt,name=bahasa,proto3" json:"bahasa,omitempty"`
+	// mata_uang is the ISO 4217 account currency, "IDR" when empty.
+	MataUang      string `protobuf:"bytes,6,opt,name=mata_uang,json=mataUang,proto3" json:"mata_uang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenAccountRequest) Reset() {
+	*x = OpenAccountRequest{}
+	mi := &file_banking_v1_banking_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenAccountRequest) ProtoMessage() {}
+
+func (x *OpenAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_banking_v1_banking_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenAccountRequest.ProtoReflect.Descriptor instead.
+func (*OpenAccountRequest) Descriptor() ([]byte, []int) {
+	return file_banking_v1_banking_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OpenAccountRequest) GetNama() string {
+	if x != nil {
+		return x.Nama
+	}
+	return ""
+}
+
+func (x *OpenAccountRequest) GetNik() string {
+	if x != nil {
+		return x.Nik
+	}
+	return ""
+}
+
+func (x *OpenAccountRequest) GetNoHp() string {
+	if x != nil {
+		return x.NoHp
+	}
+	return ""
+}
+
+func (x *OpenAccountRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *OpenAccountRequest) GetBahasa() string {
+	if x != nil {
+		return x.Bahasa
+	}
+	return ""
+}
+
+func (x *OpenAccountRequest) GetMataUang() string {
+	if x != nil {
+		return x.MataUang
+	}
+	return ""
+}
+
+type OpenAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoRekening    string                 `protobuf:"bytes,1,opt,name=no_rekening,json=noRekening,proto3" json:"no_rekening,omitempty"`
+	MataUang      string                 `protobuf:"bytes,2,opt,name=mata_uang,json=mataUang,proto3" json:"mata_uang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenAccountResponse) Reset() {
+	*x = OpenAccountResponse{}
+	mi := &file_banking_v1_banking_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenAccountResponse) ProtoMessage() {}
+
+func (x *OpenAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_banking_v1_banking_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenAccountResponse.ProtoReflect.Descriptor instead.
+func (*OpenAccountResponse) Descriptor() ([]byte, []int) {
+	return file_banking_v1_banking_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OpenAccountResponse) GetNoRekening() string {
+	if x != nil {
+		return x.NoRekening
+	}
+	return ""
+}
+
+func (x *OpenAccountResponse) GetMataUang() string {
+	if x != nil {
+		return x.MataUang
+	}
+	return ""
+}
+
+type DepositRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	NoRekening string                 `protobuf:"bytes,1,opt,name=no_rekening,json=noRekening,proto3" json:"no_rekening,omitempty"`
+	Nominal    float64                `protobuf:"fixed64,2,opt,name=nominal,proto3" json:"nominal,omitempty"`
+	// referensi is the caller's reference for the deposit, unique per account
+	// when set; reconciliation matches settlement files on it.
+	Referensi     string `protobuf:"bytes,3,opt,name=referensi,proto3" json:"referensi,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepositRequest) Reset() {
+	*x = DepositRequest{}
+	mi := &file_banking_v1_banking_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepositRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepositRequest) ProtoMessage() {}
+
+func (x *DepositRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_banking_v1_banking_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepositRequest.ProtoReflect.Descriptor instead.
+func (*DepositRequest) Descriptor() ([]byte, []int) {
+	return file_banking_v1_banking_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DepositRequest) GetNoRekening() string {
+	if x != nil {
+		return x.NoRekening
+	}
+	return ""
+}
+
+func (x *DepositRequest) GetNominal() float64 {
+	if x != nil {
+		return x.Nominal
+	}
+	return 0
+}
+
+func (x *DepositRequest) GetReferensi() string {
+	if x != nil {
+		return x.Referensi
+	}
+	return ""
+}
+
+type DepositResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Saldo float64                `protobuf:"fixed64,1,opt,name=saldo,proto3" json:"saldo,omitempty"`
+	// mata_uang is the ISO 4217 currency of saldo.
+	MataUang      string `protobuf:"bytes,2,opt,name=mata_uang,json=mataUang,proto3" json:"mata_uang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DepositResponse) Reset() {
+	*x = DepositResponse{}
+	mi := &file_banking_v1_banking_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DepositResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DepositResponse) ProtoMessage() {}
+
+func (x *DepositResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_banking_v1_banking_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DepositResponse.ProtoReflect.Descriptor instead.
+func (*DepositResponse) Descriptor() ([]byte, []int) {
+	return file_banking_v1_banking_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DepositResponse) GetSaldo() float64 {
+	if x != nil {
+		return x.Saldo
+	}
+	return 0
+}
+
+func (x *DepositResponse) GetMataUang() string {
+	if x != nil {
+		return x.MataUang
+	}
+	return ""
+}
+
+type WithdrawRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoRekening    string                 `protobuf:"bytes,1,opt,name=no_rekening,json=noRekening,proto3" json:"no_rekening,omitempty"`
+	Nominal       float64                `protobuf:"fixed64,2,opt,name=nominal,proto3" json:"nominal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WithdrawRequest) Reset() {
+	*x = WithdrawRequest{}
+	mi := &file_banking_v1_banking_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WithdrawRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithdrawRequest) ProtoMessage() {}
+
+func (x *WithdrawRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_banking_v1_banking_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithdrawRequest.ProtoReflect.Descriptor instead.
+func (*WithdrawRequest) Descriptor() ([]byte, []int) {
+	return file_banking_v1_banking_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WithdrawRequest) GetNoRekening() string {
+	if x != nil {
+		return x.NoRekening
+	}
+	return ""
+}
+
+func (x *WithdrawRequest) GetNominal() float64 {
+	if x != nil {
+		return x.Nominal
+	}
+	return 0
+}
+
+type WithdrawResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Saldo float64                `protobuf:"fixed64,1,opt,name=saldo,proto3" json:"saldo,omitempty"`
+	// mata_uang is the ISO 4217 currency of saldo.
+	MataUang      string `protobuf:"bytes,2,opt,name=mata_uang,json=mataUang,proto3" json:"mata_uang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WithdrawResponse) Reset() {
+	*x = WithdrawResponse{}
+	mi := &file_banking_v1_banking_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WithdrawResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WithdrawResponse) ProtoMessage() {}
+
+func (x *WithdrawResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_banking_v1_banking_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WithdrawResponse.ProtoReflect.Descriptor instead.
+func (*WithdrawResponse) Descriptor() ([]byte, []int) {
+	return file_banking_v1_banking_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WithdrawResponse) GetSaldo() float64 {
+	if x != nil {
+		return x.Saldo
+	}
+	return 0
+}
+
+func (x *WithdrawResponse) GetMataUang() string {
+	if x != nil {
+		return x.MataUang
+	}
+	return ""
+}
+
+type GetBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoRekening    string                 `protobuf:"bytes,1,opt,name=no_rekening,json=noRekening,proto3" json:"no_rekening,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceRequest) Reset() {
+	*x = GetBalanceRequest{}
+	mi := &file_banking_v1_banking_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceRequest) ProtoMessage() {}
+
+func (x *GetBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_banking_v1_banking_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceRequest.ProtoReflect.Descriptor instead.
+func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_banking_v1_banking_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetBalanceRequest) GetNoRekening() string {
+	if x != nil {
+		return x.NoRekening
+	}
+	return ""
+}
+
+type GetBalanceResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Saldo float64                `protobuf:"fixed64,1,opt,name=saldo,proto3" json:"saldo,omitempty"`
+	// mata_uang is the ISO 4217 currency of saldo.
+	MataUang      string `protobuf:"bytes,2,opt,name=mata_uang,json=mataUang,proto3" json:"mata_uang,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceResponse) Reset() {
+	*x = GetBalanceResponse{}
+	mi := &file_banking_v1_banking_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceResponse) ProtoMessage() {}
+
+func (x *GetBalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_banking_v1_banking_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceResponse.ProtoReflect.Descriptor instead.
+func (*GetBalanceResponse) Descriptor() ([]byte, []int) {
+	return file_banking_v1_banking_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetBalanceResponse) GetSaldo() float64 {
+	if x != nil {
+		return x.Saldo
+	}
+	return 0
+}
+
+func (x *GetBalanceResponse) GetMataUang() string {
+	if x != nil {
+		return x.MataUang
+	}
+	return ""
+}
+
+type StreamTransactionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoRekening    string                 `protobuf:"bytes,1,opt,name=no_rekening,json=noRekening,proto3" json:"no_rekening,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTransactionsRequest) Reset() {
+	*x = StreamTransactionsRequest{}
+	mi := &file_banking_v1_banking_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTransactionsRequest) ProtoMessage() {}
+
+func (x *StreamTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_banking_v1_banking_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*StreamTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_banking_v1_banking_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StreamTransactionsRequest) GetNoRekening() string {
+	if x != nil {
+		return x.NoRekening
+	}
+	return ""
+}
+
+type StreamTransactionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transaction   *Transaction           `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTransactionsResponse) Reset() {
+	*x = StreamTransactionsResponse{}
+	mi := &file_banking_v1_banking_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTransactionsResponse) ProtoMessage() {}
+
+func (x *StreamTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_banking_v1_banking_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*StreamTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_banking_v1_banking_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StreamTransactionsResponse) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type Transaction struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	NoRekening string                 `protobuf:"bytes,2,opt,name=no_rekening,json=noRekening,proto3" json:"no_rekening,omitempty"`
+	// jenis is "tabung", "tarik", "transfer_keluar", "transfer_masuk",
+	// "koreksi_debit", "koreksi_kredit", "bunga", "biaya", "pajak" or
+	// "saldo_awal".
+	Jenis      string                 `protobuf:"bytes,3,opt,name=jenis,proto3" json:"jenis,omitempty"`
+	Nominal    float64                `protobuf:"fixed64,4,opt,name=nominal,proto3" json:"nominal,omitempty"`
+	SaldoAkhir float64                `protobuf:"fixed64,5,opt,name=saldo_akhir,json=saldoAkhir,proto3" json:"saldo_akhir,omitempty"`
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// mata_uang is the ISO 4217 currency of nominal and saldo_akhir.
+	MataUang string `protobuf:"bytes,7,opt,name=mata_uang,json=mataUang,proto3" json:"mata_uang,omitempty"`
+	// reversal_of_id is the transaction a koreksi entry reverses, 0 otherwise.
+	ReversalOfId uint64 `protobuf:"varint,8,opt,name=reversal_of_id,json=reversalOfId,proto3" json:"reversal_of_id,omitempty"`
+	// tanggal_bisnis is the business date the transaction was booked on, as
+	// YYYY-MM-DD.
+	TanggalBisnis string `protobuf:"bytes,9,opt,name=tanggal_bisnis,json=tanggalBisnis,proto3" json:"tanggal_bisnis,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_banking_v1_banking_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_banking_v1_banking_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_banking_v1_banking_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Transaction) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Transaction) GetNoRekening() string {
+	if x != nil {
+		return x.NoRekening
+	}
+	return ""
+}
+
+func (x *Transaction) GetJenis() string {
+	if x != nil {
+		return x.Jenis
+	}
+	return ""
+}
+
+func (x *Transaction) GetNominal() float64 {
+	if x != nil {
+		return x.Nominal
+	}
+	return 0
+}
+
+func (x *Transaction) GetSaldoAkhir() float64 {
+	if x != nil {
+		return x.SaldoAkhir
+	}
+	return 0
+}
+
+func (x *Transaction) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Transaction) GetMataUang() string {
+	if x != nil {
+		return x.MataUang
+	}
+	return ""
+}
+
+func (x *Transaction) GetReversalOfId() uint64 {
+	if x != nil {
+		return x.ReversalOfId
+	}
+	return 0
+}
+
+func (x *Transaction) GetTanggalBisnis() string {
+	if x != nil {
+		return x.TanggalBisnis
+	}
+	return ""
+}
+
+var File_banking_v1_banking_proto protoreflect.FileDescriptor
+
+var file_banking_v1_banking_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x62, 0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x61, 0x6e,
+	0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x62, 0x61, 0x6e, 0x6b,
+	0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9a, 0x01, 0x0a, 0x12, 0x4f, 0x70, 0x65, 0x6e,
+	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x61, 0x12, 0x10, 0x0a, 0x03, 0x6e, 0x69, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6e, 0x69, 0x6b, 0x12, 0x13, 0x0a, 0x05, 0x6e, 0x6f, 0x5f, 0x68, 0x70, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x6f, 0x48, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61,
+	0x69, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12,
+	0x16, 0x0a, 0x06, 0x62, 0x61, 0x68, 0x61, 0x73, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x62, 0x61, 0x68, 0x61, 0x73, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x74, 0x61, 0x5f,
+	0x75, 0x61, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x61, 0x74, 0x61,
+	0x55, 0x61, 0x6e, 0x67, 0x22, 0x53, 0x0a, 0x13, 0x4f, 0x70, 0x65, 0x6e, 0x41, 0x63, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6e,
+	0x6f, 0x5f, 0x72, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x6e, 0x6f, 0x52, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x1b, 0x0a, 0x09,
+	0x6d, 0x61, 0x74, 0x61, 0x5f, 0x75, 0x61, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x6d, 0x61, 0x74, 0x61, 0x55, 0x61, 0x6e, 0x67, 0x22, 0x69, 0x0a, 0x0e, 0x44, 0x65, 0x70,
+	0x6f, 0x73, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6e,
+	0x6f, 0x5f, 0x72, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x6e, 0x6f, 0x52, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07,
+	0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x6e,
+	0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x73, 0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x66, 0x65, 0x72,
+	0x65, 0x6e, 0x73, 0x69, 0x22, 0x44, 0x0a, 0x0f, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x61, 0x6c, 0x64, 0x6f,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x61, 0x6c, 0x64, 0x6f, 0x12, 0x1b, 0x0a,
+	0x09, 0x6d, 0x61, 0x74, 0x61, 0x5f, 0x75, 0x61, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x6d, 0x61, 0x74, 0x61, 0x55, 0x61, 0x6e, 0x67, 0x22, 0x4c, 0x0a, 0x0f, 0x57, 0x69,
+	0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a,
+	0x0b, 0x6e, 0x6f, 0x5f, 0x72, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x6e, 0x6f, 0x52, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x18,
+	0x0a, 0x07, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x07, 0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x22, 0x45, 0x0a, 0x10, 0x57, 0x69, 0x74, 0x68,
+	0x64, 0x72, 0x61, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x73, 0x61, 0x6c, 0x64, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x61, 0x6c,
+	0x64, 0x6f, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x74, 0x61, 0x5f, 0x75, 0x61, 0x6e, 0x67, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x61, 0x74, 0x61, 0x55, 0x61, 0x6e, 0x67, 0x22,
+	0x34, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x6f, 0x5f, 0x72, 0x65, 0x6b, 0x65, 0x6e,
+	0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x6f, 0x52, 0x65, 0x6b,
+	0x65, 0x6e, 0x69, 0x6e, 0x67, 0x22, 0x47, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x61, 0x6c, 0x64, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x61, 0x6c, 0x64,
+	0x6f, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x74, 0x61, 0x5f, 0x75, 0x61, 0x6e, 0x67, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x61, 0x74, 0x61, 0x55, 0x61, 0x6e, 0x67, 0x22, 0x3c,
+	0x0a, 0x19, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6e,
+	0x6f, 0x5f, 0x72, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x6e, 0x6f, 0x52, 0x65, 0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x22, 0x57, 0x0a, 0x1a,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x0b, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xb4, 0x02, 0x0a, 0x0b, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x6f, 0x5f, 0x72, 0x65, 0x6b, 0x65,
+	0x6e, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x6f, 0x52, 0x65,
+	0x6b, 0x65, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x6a, 0x65, 0x6e, 0x69, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x65, 0x6e, 0x69, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x6e, 0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x6e,
+	0x6f, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x61, 0x6c, 0x64, 0x6f, 0x5f,
+	0x61, 0x6b, 0x68, 0x69, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x73, 0x61, 0x6c,
+	0x64, 0x6f, 0x41, 0x6b, 0x68, 0x69, 0x72, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x41, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x74, 0x61, 0x5f, 0x75, 0x61, 0x6e, 0x67, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x61, 0x74, 0x61, 0x55, 0x61, 0x6e, 0x67, 0x12,
+	0x24, 0x0a, 0x0e, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x61, 0x6c, 0x5f, 0x6f, 0x66, 0x5f, 0x69,
+	0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x72, 0x65, 0x76, 0x65, 0x72, 0x73, 0x61,
+	0x6c, 0x4f, 0x66, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x61, 0x6e, 0x67, 0x67, 0x61, 0x6c,
+	0x5f, 0x62, 0x69, 0x73, 0x6e, 0x69, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74,
+	0x61, 0x6e, 0x67, 0x67, 0x61, 0x6c, 0x42, 0x69, 0x73, 0x6e, 0x69, 0x73, 0x32, 0x9f, 0x03, 0x0a,
+	0x0e, 0x42, 0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x4e, 0x0a, 0x0b, 0x4f, 0x70, 0x65, 0x6e, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1e,
+	0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x65, 0x6e,
+	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x65, 0x6e,
+	0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x42, 0x0a, 0x07, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x12, 0x1a, 0x2e, 0x62, 0x61, 0x6e,
+	0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67,
+	0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x08, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72, 0x61, 0x77, 0x12,
+	0x1b, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x69, 0x74,
+	0x68, 0x64, 0x72, 0x61, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x62,
+	0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x69, 0x74, 0x68, 0x64, 0x72,
+	0x61, 0x77, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4b, 0x0a, 0x0a, 0x47, 0x65,
+	0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1d, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x69,
+	0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x69, 0x6e,
+	0x67, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x65, 0x0a, 0x12, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x25, 0x2e,
+	0x62, 0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x62, 0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x1d,
+	0x5a, 0x1b, 0x67, 0x6f, 0x62, 0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67, 0x2f, 0x67, 0x72, 0x70, 0x63,
+	0x61, 0x70, 0x69, 0x2f, 0x62, 0x61, 0x6e, 0x6b, 0x69, 0x6e, 0x67, 0x70, 0x62, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_banking_v1_banking_proto_rawDescOnce sync.Once
+	file_banking_v1_banking_proto_rawDescData = file_banking_v1_banking_proto_rawDesc
+)
+
+func file_banking_v1_banking_proto_rawDescGZIP() []byte {
+	file_banking_v1_banking_proto_rawDescOnce.Do(func() {
+		file_banking_v1_banking_proto_rawDescData = protoimpl.X.CompressGZIP(file_banking_v1_banking_proto_rawDescData)
+	})
+	return file_banking_v1_banking_proto_rawDescData
+}
+
+var file_banking_v1_banking_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_banking_v1_banking_proto_goTypes = []any{
+	(*OpenAccountRequest)(nil),         // 0: banking.v1.OpenAccountRequest
+	(*OpenAccountResponse)(nil),        // 1: banking.v1.OpenAccountResponse
+	(*DepositRequest)(nil),             // 2: banking.v1.DepositRequest
+	(*DepositResponse)(nil),            // 3: banking.v1.DepositResponse
+	(*WithdrawRequest)(nil),            // 4: banking.v1.WithdrawRequest
+	(*WithdrawResponse)(nil),           // 5: banking.v1.WithdrawResponse
+	(*GetBalanceRequest)(nil),          // 6: banking.v1.GetBalanceRequest
+	(*GetBalanceResponse)(nil),         // 7: banking.v1.GetBalanceResponse
+	(*StreamTransactionsRequest)(nil),  // 8: banking.v1.StreamTransactionsRequest
+	(*StreamTransactionsResponse)(nil), // 9: banking.v1.StreamTransactionsResponse
+	(*Transaction)(nil),                // 10: banking.v1.Transaction
+	(*timestamppb.Timestamp)(nil),      // 11: google.protobuf.Timestamp
+}
+var file_banking_v1_banking_proto_depIdxs = []int32{
+	10, // 0: banking.v1.StreamTransactionsResponse.transaction:type_name -> banking.v1.Transaction
+	11, // 1: banking.v1.Transaction.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: banking.v1.BankingService.OpenAccount:input_type -> banking.v1.OpenAccountRequest
+	2,  // 3: banking.v1.BankingService.Deposit:input_type -> banking.v1.DepositRequest
+	4,  // 4: banking.v1.BankingService.Withdraw:input_type -> banking.v1.WithdrawRequest
+	6,  // 5: banking.v1.BankingService.GetBalance:input_type -> banking.v1.GetBalanceRequest
+	8,  // 6: banking.v1.BankingService.StreamTransactions:input_type -> banking.v1.StreamTransactionsRequest
+	1,  // 7: banking.v1.BankingService.OpenAccount:output_type -> banking.v1.OpenAccountResponse
+	3,  // 8: banking.v1.BankingService.Deposit:output_type -> banking.v1.DepositResponse
+	5,  // 9: banking.v1.BankingService.Withdraw:output_type -> banking.v1.WithdrawResponse
+	7,  // 10: banking.v1.BankingService.GetBalance:output_type -> banking.v1.GetBalanceResponse
+	9,  // 11: banking.v1.BankingService.StreamTransactions:output_type -> banking.v1.StreamTransactionsResponse
+	7,  // [7:12] is the sub-list for method output_type
+	2,  // [2:7] is the sub-list for method input_type
+	2,  // [2:2] is the sub-list for extension type_name
+	2,  // [2:2] is the sub-list for extension extendee
+	0,  // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_banking_v1_banking_proto_init() }
+func file_banking_v1_banking_proto_init() {
+	if File_banking_v1_banking_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_banking_v1_banking_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_banking_v1_banking_proto_goTypes,
+		DependencyIndexes: file_banking_v1_banking_proto_depIdxs,
+		MessageInfos:      file_banking_v1_banking_proto_msgTypes,
+	}.Build()
+	File_banking_v1_banking_proto = out.File
+	file_banking_v1_banking_proto_rawDesc = nil
+	file_banking_v1_banking_proto_goTypes = nil
+	file_banking_v1_banking_proto_depIdxs = nil
+}
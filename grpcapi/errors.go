@@ -0,0 +1,46 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"gobanking/account"
+	"gobanking/logging"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus maps the account domain errors onto gRPC status codes. The
+// messages match the REST remarks; anything unexpected is logged and
+// reported as INTERNAL without details.
+func toStatus(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, account.ErrAccountNotFound):
+		return status.Error(codes.NotFound, "No Rekening tidak ditemukan")
+	case errors.Is(err, account.ErrBeneficiaryNotFound):
+		return status.Error(codes.NotFound, "No Rekening tujuan tidak ditemukan")
+	case errors.Is(err, account.ErrInsufficientFunds):
+		return status.Error(codes.FailedPrecondition, "Saldo tidak mencukupi")
+	case errors.Is(err, account.ErrAccountFrozen):
+		return status.Error(codes.FailedPrecondition, "Rekening dibekukan")
+	case errors.Is(err, account.ErrInvalidAmount):
+		return status.Error(codes.InvalidArgument, "Nominal harus lebih dari 0")
+	case errors.Is(err, account.ErrRateNotFound):
+		return status.Error(codes.FailedPrecondition, "Kurs tidak tersedia")
+	case errors.Is(err, account.ErrDuplicateAccount):
+		return status.Error(codes.AlreadyExists, "NIK atau No Handphone sudah terdaftar")
+	case errors.Is(err, account.ErrBeneficiaryExists):
+		return status.Error(codes.AlreadyExists, "Penerima sudah terdaftar")
+	case errors.Is(err, account.ErrPhoneInUse):
+		return status.Error(codes.AlreadyExists, "No Handphone sudah terdaftar")
+	case errors.Is(err, account.ErrDuplicateReference):
+		return status.Error(codes.AlreadyExists, "Referensi sudah dipakai")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	logging.FromContext(ctx).Error("gagal memproses rpc", "error", err)
+	return status.Error(codes.Internal, "Internal server error")
+}
@@ -0,0 +1,178 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"gobanking/config"
+	"gobanking/logging"
+	"gobanking/middleware"
+	"gobanking/repository"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type (
+	claimsKey struct{}
+	callKey   struct{}
+)
+
+// call lets the auth interceptor report the user back to the access log,
+// which runs outside it.
+type call struct {
+	userID any
+}
+
+// claimsFrom returns the token claims stored by the auth interceptor.
+func claimsFrom(ctx context.Context) jwt.MapClaims {
+	claims, _ := ctx.Value(claimsKey{}).(jwt.MapClaims)
+	return claims
+}
+
+// accessLogUnary mirrors middleware.AccessLog: it stores a logger carrying
+// the request ID in the context, turns panics into INTERNAL and writes one
+// log line per call.
+func accessLogUnary(cfg *config.Config) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		ctx, done := startCall(ctx, cfg, info.FullMethod)
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, r)
+			}
+			done(err)
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func accessLogStream(cfg *config.Config) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx, done := startCall(ss.Context(), cfg, info.FullMethod)
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, r)
+			}
+			done(err)
+		}()
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func startCall(ctx context.Context, cfg *config.Config, method string) (context.Context, func(error)) {
+	start := time.Now()
+	requestID := middleware.RequestID(firstMetadata(ctx, strings.ToLower(middleware.HeaderRequestID)))
+	grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(middleware.HeaderRequestID), requestID))
+
+	logger := cfg.Logger.With("request_id", requestID)
+	info := &call{}
+	ctx = context.WithValue(logging.WithLogger(ctx, logger), callKey{}, info)
+	return ctx, func(err error) {
+		code := status.Code(err)
+		level := slog.LevelInfo
+		switch code {
+		case codes.OK:
+		case codes.Internal, codes.Unknown, codes.DataLoss:
+			level = slog.LevelError
+		default:
+			level = slog.LevelWarn
+		}
+
+		attrs := []any{
+			"method", method,
+			"code", code.String(),
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+		}
+		if info.userID != nil {
+			attrs = append(attrs, "user_id", info.userID)
+		}
+		if err != nil {
+			attrs = append(attrs, "error", err)
+		}
+		logger.Log(ctx, level, "rpc selesai", attrs...)
+	}
+}
+
+func recovered(ctx context.Context, r any) error {
+	logging.FromContext(ctx).Error("panic pada handler gRPC", "panic", r)
+	return status.Error(codes.Internal, "Internal server error")
+}
+
+// authUnary and authStream accept the same bearer tokens as
+// middleware.AuthMiddleware, read from the "authorization" metadata, and
+// refuse revoked ones the same way.
+func authUnary(cfg *config.Config, users repository.UserRepository) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, cfg, users)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authStream(cfg *config.Config, users repository.UserRepository) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), cfg, users)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, cfg *config.Config, users repository.UserRepository) (context.Context, error) {
+	logger := logging.FromContext(ctx)
+
+	authHeader := firstMetadata(ctx, "authorization")
+	if authHeader == "" {
+		return ctx, status.Error(codes.Unauthenticated, "Missing authorization header")
+	}
+
+	claims, err := middleware.ParseToken(cfg, strings.TrimPrefix(authHeader, "Bearer "))
+	if err != nil {
+		logger.Warn("invalid token", "error", err)
+		return ctx, status.Error(codes.Unauthenticated, "Invalid token")
+	}
+	if err := middleware.CheckRevoked(ctx, users, claims); err != nil {
+		if !errors.Is(err, middleware.ErrTokenRevoked) {
+			logger.Error("gagal memeriksa token", "error", err)
+			return ctx, status.Error(codes.Internal, "Internal server error")
+		}
+		logger.Warn("invalid token", "user_id", claims["user_id"], "error", err)
+		return ctx, status.Error(codes.Unauthenticated, "Invalid token")
+	}
+
+	if info, ok := ctx.Value(callKey{}).(*call); ok {
+		info.userID = claims["user_id"]
+	}
+	ctx = context.WithValue(ctx, claimsKey{}, claims)
+	return logging.WithLogger(ctx, logger.With("user_id", claims["user_id"])), nil
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// serverStream replaces the context of a stream so interceptors can pass
+// values to the handler.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,99 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"gobanking/config"
+	"gobanking/grpcapi/bankingpb"
+	"gobanking/logging"
+	"gobanking/ratelimit"
+	"math"
+	"net"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// rateLimiter applies the REST rate limits to gRPC calls. It takes from the
+// same store under the same policy names and keys as middleware.RateLimit,
+// so a user's REST and gRPC calls share one bucket: every call counts
+// against "user", and Withdraw also against "tarik" like POST /tarik.
+type rateLimiter struct {
+	store  ratelimit.Store
+	user   ratelimit.Policy
+	byCall map[string][]ratelimit.Policy
+}
+
+func newRateLimiter(cfg *config.Config, store ratelimit.Store) *rateLimiter {
+	policy := func(name string, p config.RateLimitPolicy) ratelimit.Policy {
+		return ratelimit.Policy{Name: name, Limit: p.Limit, Period: p.Period}
+	}
+	return &rateLimiter{
+		store: store,
+		user:  policy("user", cfg.RateLimit.User),
+		byCall: map[string][]ratelimit.Policy{
+			bankingpb.BankingService_Withdraw_FullMethodName: {policy("tarik", cfg.RateLimit.Tarik)},
+		},
+	}
+}
+
+// rateLimitUnary and rateLimitStream must run after the auth interceptors,
+// whose claims give the user key.
+func rateLimitUnary(l *rateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.take(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// rateLimitStream counts opening a stream as one call.
+func rateLimitStream(l *rateLimiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.take(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// take spends one token of every policy of method, returning
+// RESOURCE_EXHAUSTED with a retry-after header when one is empty. Store
+// errors fail open.
+func (l *rateLimiter) take(ctx context.Context, method string) error {
+	key := rateLimitKey(ctx)
+	for _, policy := range append([]ratelimit.Policy{l.user}, l.byCall[method]...) {
+		k := policy.Name + ":" + key
+		res, err := l.store.Take(ctx, k, policy)
+		if err != nil {
+			logging.FromContext(ctx).Error("gagal memeriksa rate limit", "policy", policy.Name, "error", err)
+			continue
+		}
+		if !res.Allowed {
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds())))))
+			logging.FromContext(ctx).Warn("rate limit terlampaui", "policy", policy.Name, "key", k)
+			return status.Error(codes.ResourceExhausted, "Terlalu banyak request, coba lagi nanti")
+		}
+	}
+	return nil
+}
+
+// rateLimitKey mirrors middleware.ByUser: the token's user_id, or the peer
+// IP for service tokens.
+func rateLimitKey(ctx context.Context) string {
+	if id := claimsFrom(ctx)["user_id"]; id != nil {
+		return fmt.Sprintf("user:%v", id)
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return "ip:" + host
+		}
+		return "ip:" + p.Addr.String()
+	}
+	return "ip:"
+}
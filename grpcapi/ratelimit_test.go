@@ -0,0 +1,114 @@
+package grpcapi_test
+
+import (
+	"context"
+	"fmt"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/grpcapi"
+	"gobanking/grpcapi/bankingpb"
+	"gobanking/model"
+	"gobanking/notifier"
+	"gobanking/ratelimit"
+	"gobanking/repository"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// TestWithdrawSharesRESTRateLimits checks that Withdraw draws on the same
+// per-user "tarik" bucket as POST /tarik, and that other calls only count
+// against "user".
+func TestWithdrawSharesRESTRateLimits(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		Logger: logger,
+		JWT:    config.JWTConfig{Secret: "rahasia-test"},
+		OTP:    config.OTPConfig{TarikThreshold: 10_000_000},
+		RateLimit: config.RateLimitConfig{
+			User:  config.RateLimitPolicy{Limit: 5, Period: time.Hour},
+			Tarik: config.RateLimitPolicy{Limit: 2, Period: time.Hour},
+		},
+	}
+	store := repository.NewMemoryStore()
+	accounts := account.NewService(store, notifier.LogNotifier{Logger: logger})
+	ctx := context.Background()
+	user := &model.User{Email: "budi@example.com", Password: "x"}
+	if err := store.Users().Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	n, err := accounts.OpenAccount(ctx, account.OpenAccountInput{Nama: "Budi", NIK: "nik-1", NoHP: "081"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := accounts.Deposit(ctx, n.NoRekening, 100000, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	limiter := ratelimit.NewMemoryStore()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpcapi.NewServer(cfg, accounts, store.Users(), limiter)
+	go srv.Serve(lis)
+	defer srv.Shutdown(ctx)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := bankingpb.NewBankingServiceClient(conn)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": user.ID,
+		"role":    model.RoleUser,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(cfg.JWT.Secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+
+	// A withdrawal over REST spends the first "tarik" token.
+	tarik := ratelimit.Policy{Name: "tarik", Limit: 2, Period: time.Hour}
+	if _, err := limiter.Take(ctx, fmt.Sprintf("tarik:user:%d", user.ID), tarik); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Withdraw(ctx, &bankingpb.WithdrawRequest{NoRekening: n.NoRekening, Nominal: 1000}); err != nil {
+		t.Fatalf("first gRPC Withdraw: %v", err)
+	}
+	var header metadata.MD
+	_, err = client.Withdraw(ctx, &bankingpb.WithdrawRequest{NoRekening: n.NoRekening, Nominal: 1000}, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Withdraw over the tarik limit: %v, want RESOURCE_EXHAUSTED", err)
+	}
+	if len(header.Get("retry-after")) == 0 {
+		t.Fatal("no retry-after header on RESOURCE_EXHAUSTED")
+	}
+
+	// Both Withdraw calls spent a "user" token, the refused one included;
+	// three are left for GetBalance.
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetBalance(ctx, &bankingpb.GetBalanceRequest{NoRekening: n.NoRekening}); err != nil {
+			t.Fatalf("GetBalance %d: %v", i+1, err)
+		}
+	}
+	if _, err := client.GetBalance(ctx, &bankingpb.GetBalanceRequest{NoRekening: n.NoRekening}); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("GetBalance over the user limit: %v, want RESOURCE_EXHAUSTED", err)
+	}
+	if saldo, _ := accounts.Balance(context.Background(), n.NoRekening); saldo != 99000 {
+		t.Fatalf("saldo = %v, want 99000 after one withdrawal", saldo)
+	}
+}
@@ -0,0 +1,207 @@
+// Package grpcapi serves the BankingService defined in
+// proto/banking/v1/banking.proto. It calls the same account.Service as the
+// REST handlers, so both transports apply identical rules.
+package grpcapi
+
+import (
+	"context"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/grpcapi/bankingpb"
+	"gobanking/model"
+	"gobanking/ratelimit"
+	"gobanking/repository"
+	"net"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server is the gRPC listener. Shutdown ends open transaction streams and
+// waits for unary calls, like http.Server.Shutdown.
+type Server struct {
+	grpc     *grpc.Server
+	stopping chan struct{}
+}
+
+// NewServer builds the service. limiter should be the store the REST routes
+// use, so both transports draw on the same rate limit buckets.
+func NewServer(cfg *config.Config, accounts *account.Service, users repository.UserRepository, limiter ratelimit.Store) *Server {
+	s := &Server{stopping: make(chan struct{})}
+	limits := newRateLimiter(cfg, limiter)
+	s.grpc = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(accessLogUnary(cfg), authUnary(cfg, users), rateLimitUnary(limits)),
+		grpc.ChainStreamInterceptor(accessLogStream(cfg), authStream(cfg, users), rateLimitStream(limits)),
+	)
+	bankingpb.RegisterBankingServiceServer(s.grpc, &bankingService{
+		cfg:      cfg,
+		accounts: accounts,
+		validate: validator.New(),
+		stopping: s.stopping,
+	})
+	return s
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// Shutdown stops accepting calls and waits for running ones until ctx is
+// done, after which they are cut off.
+func (s *Server) Shutdown(ctx context.Context) error {
+	close(s.stopping)
+
+	done := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		return ctx.Err()
+	}
+}
+
+type bankingService struct {
+	bankingpb.UnimplementedBankingServiceServer
+
+	cfg      *config.Config
+	accounts *account.Service
+	validate *validator.Validate
+	stopping <-chan struct{}
+}
+
+func (s *bankingService) OpenAccount(ctx context.Context, req *bankingpb.OpenAccountRequest) (*bankingpb.OpenAccountResponse, error) {
+	in := model.DaftarRequest{
+		Nama:     req.GetNama(),
+		NIK:      req.GetNik(),
+		NoHP:     req.GetNoHp(),
+		Email:    req.GetEmail(),
+		Bahasa:   req.GetBahasa(),
+		MataUang: req.GetMataUang(),
+	}
+	if err := s.validate.Struct(in); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Semua field harus diisi")
+	}
+
+	nasabah, err := s.accounts.OpenAccount(ctx, account.OpenAccountInput(in))
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &bankingpb.OpenAccountResponse{NoRekening: nasabah.NoRekening, MataUang: nasabah.MataUang}, nil
+}
+
+func (s *bankingService) Deposit(ctx context.Context, req *bankingpb.DepositRequest) (*bankingpb.DepositResponse, error) {
+	if err := s.validateTransaksi(req.GetNoRekening(), req.GetNominal()); err != nil {
+		return nil, err
+	}
+
+	nasabah, err := s.accounts.Deposit(ctx, req.GetNoRekening(), req.GetNominal(), req.GetReferensi())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &bankingpb.DepositResponse{Saldo: nasabah.Saldo, MataUang: nasabah.MataUang}, nil
+}
+
+func (s *bankingService) Withdraw(ctx context.Context, req *bankingpb.WithdrawRequest) (*bankingpb.WithdrawResponse, error) {
+	if err := s.validateTransaksi(req.GetNoRekening(), req.GetNominal()); err != nil {
+		return nil, err
+	}
+
+	// The OTP challenge is interactive and only offered over REST.
+	nasabah, err := s.accounts.Account(ctx, req.GetNoRekening())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	above, err := s.accounts.ExceedsIDR(ctx, nasabah.MataUang, req.GetNominal(), s.cfg.OTP.TarikThreshold)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	if above {
+		return nil, status.Error(codes.FailedPrecondition, "Penarikan di atas batas OTP harus melalui POST /tarik")
+	}
+
+	nasabah, err = s.accounts.Withdraw(ctx, req.GetNoRekening(), req.GetNominal())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &bankingpb.WithdrawResponse{Saldo: nasabah.Saldo, MataUang: nasabah.MataUang}, nil
+}
+
+func (s *bankingService) GetBalance(ctx context.Context, req *bankingpb.GetBalanceRequest) (*bankingpb.GetBalanceResponse, error) {
+	if req.GetNoRekening() == "" {
+		return nil, status.Error(codes.InvalidArgument, "No Rekening harus diisi")
+	}
+
+	nasabah, err := s.accounts.Account(ctx, req.GetNoRekening())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &bankingpb.GetBalanceResponse{Saldo: nasabah.Saldo, MataUang: nasabah.MataUang}, nil
+}
+
+func (s *bankingService) StreamTransactions(req *bankingpb.StreamTransactionsRequest, stream bankingpb.BankingService_StreamTransactionsServer) error {
+	ctx := stream.Context()
+
+	if req.GetNoRekening() == "" {
+		if role, _ := claimsFrom(ctx)["role"].(string); role != model.RoleAdmin {
+			return status.Error(codes.PermissionDenied, "Akses ditolak")
+		}
+	} else if _, err := s.accounts.Account(ctx, req.GetNoRekening()); err != nil {
+		return toStatus(ctx, err)
+	}
+
+	events, cancel := s.accounts.Subscribe(req.GetNoRekening())
+	defer cancel()
+
+	// Headers tell the client the subscription is live.
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return toStatus(ctx, ctx.Err())
+		case <-s.stopping:
+			return status.Error(codes.Unavailable, "Server sedang berhenti")
+		case transaksi, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "Stream tertinggal, sebagian transaksi terlewat")
+			}
+			msg := &bankingpb.Transaction{
+				Id:            uint64(transaksi.ID),
+				NoRekening:    transaksi.NoRekening,
+				Jenis:         transaksi.Jenis,
+				Nominal:       transaksi.Nominal,
+				SaldoAkhir:    transaksi.SaldoAkhir,
+				CreatedAt:     timestamppb.New(transaksi.CreatedAt),
+				MataUang:      transaksi.MataUang,
+				TanggalBisnis: transaksi.TanggalBisnis.Format(time.DateOnly),
+			}
+			if transaksi.ReversalOfID != nil {
+				msg.ReversalOfId = uint64(*transaksi.ReversalOfID)
+			}
+			err := stream.Send(&bankingpb.StreamTransactionsResponse{Transaction: msg})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// validateTransaksi applies the rules of model.TransaksiRequest.
+func (s *bankingService) validateTransaksi(noRekening string, nominal float64) error {
+	if err := s.validate.Struct(model.TransaksiRequest{NoRekening: noRekening, Nominal: nominal}); err != nil {
+		return status.Error(codes.InvalidArgument, "Semua field harus diisi")
+	}
+	return nil
+}
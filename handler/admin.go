@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"gobanking/config"
+	"gobanking/lockout"
+	"gobanking/model"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type AdminHandler struct {
+	cfg      *config.Config
+	guard    *lockout.Guard
+	validate *validator.Validate
+}
+
+func NewAdminHandler(cfg *config.Config, guard *lockout.Guard) *AdminHandler {
+	return &AdminHandler{
+		cfg:      cfg,
+		guard:    guard,
+		validate: validator.New(),
+	}
+}
+
+// @Summary Unlock login
+// @Description Clear failed-login lockout for an email and/or client IP. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.UnlockRequest true "Email and/or IP to unlock"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/lockouts/unlock [post]
+func (h *AdminHandler) Unlock(c echo.Context) error {
+	var req model.UnlockRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+
+	if err := h.validate.Struct(req); err != nil || (req.Email == "" && req.IP == "") {
+		logger(c).Warn("gagal validasi", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Email atau IP harus diisi dengan benar"})
+	}
+
+	cleared, err := h.guard.Unlock(req.Email, req.IP)
+	if err != nil {
+		logger(c).Error("gagal membuka lockout", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("lockout dibuka oleh admin",
+		"email", req.Email,
+		"ip", req.IP,
+		"cleared", cleared,
+		"admin_id", currentUserID(c),
+	)
+	return c.NoContent(http.StatusNoContent)
+}
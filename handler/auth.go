@@ -1,26 +1,48 @@
 package handler
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"gobanking/config"
+	"gobanking/lockout"
+	"gobanking/metrics"
+	"gobanking/middleware"
 	"gobanking/model"
+	"gobanking/repository"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"golang.org/x/crypto/bcrypt"
-	"gorm.io/gorm"
 )
 
 type AuthHandler struct {
-	db       *gorm.DB
+	users    repository.UserRepository
 	cfg      *config.Config
+	validate *validator.Validate
+	guard    *lockout.Guard
+	// dummyHash is compared against when the email is unknown so that both
+	// paths spend the same bcrypt time and accounts cannot be enumerated.
+	dummyHash []byte
 }
 
-func NewAuthHandler(db *gorm.DB, cfg *config.Config) *AuthHandler {
+func NewAuthHandler(users repository.UserRepository, cfg *config.Config, guard *lockout.Guard) *AuthHandler {
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte("gobanking-dummy-password"), bcrypt.DefaultCost)
+	if err != nil {
+		panic(fmt.Sprintf("gagal membuat dummy hash: %v", err))
+	}
+
 	return &AuthHandler{
-		db:  db,
-		cfg: cfg,
+		users:     users,
+		cfg:       cfg,
+		validate:  validator.New(),
+		guard:     guard,
+		dummyHash: dummyHash,
 	}
 }
 
@@ -36,92 +58,319 @@ func NewAuthHandler(db *gorm.DB, cfg *config.Config) *AuthHandler {
 func (h *AuthHandler) Register(c echo.Context) error {
 	var req model.RegisterRequest
 	if err := c.Bind(&req); err != nil {
-		h.cfg.Logger.Warn("format request salah", "error", err)
+		logger(c).Warn("format request salah", "error", err)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
 	}
 
 	// Check if email already exists
-	var existingUser model.User
-	result := h.db.Where("email = ?", req.Email).First(&existingUser)
-	if result.RowsAffected > 0 {
-		h.cfg.Logger.Info("gagal register: email sudah terdaftar", "email", req.Email)
+	if _, err := h.users.FindByEmail(c.Request().Context(), req.Email); err == nil {
+		logger(c).Info("gagal register: email sudah terdaftar", "email", req.Email)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Email sudah terdaftar"})
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		h.cfg.Logger.Error("gagal hash password", "error", err)
+		logger(c).Error("gagal hash password", "error", err)
 		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
 	}
 
 	user := model.User{
 		Email:    req.Email,
 		Password: string(hashedPassword),
+		Role:     model.RoleUser,
 	}
 
-	if err := h.db.Create(&user).Error; err != nil {
-		h.cfg.Logger.Error("gagal mendaftarkan user", "error", err)
+	err = h.users.Create(c.Request().Context(), &user)
+	if errors.Is(err, repository.ErrDuplicate) {
+		logger(c).Info("gagal register: email sudah terdaftar", "email", req.Email)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Email sudah terdaftar"})
+	}
+	if err != nil {
+		logger(c).Error("gagal mendaftarkan user", "error", err)
 		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
 	}
 
-	// Generate JWT token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": user.ID,
-		"email":   user.Email,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
-	})
-
-	tokenString, err := token.SignedString([]byte(h.cfg.JWT.Secret))
+	tokenString, err := h.issueToken(user)
 	if err != nil {
-		h.cfg.Logger.Error("gagal generate token", "error", err)
+		logger(c).Error("gagal generate token", "error", err)
 		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
 	}
 
-	h.cfg.Logger.Info("user berhasil didaftarkan", "email", user.Email)
+	logger(c).Info("user berhasil didaftarkan", "email", user.Email)
 	return c.JSON(http.StatusCreated, model.TokenResponse{Token: tokenString})
 }
 
 // @Summary Login user
-// @Description Login with email and password
+// @Description Login with email and password. Users with 2FA receive an MFA challenge token to complete at /login/mfa.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param user body model.LoginRequest true "User login details"
 // @Success 200 {object} model.TokenResponse
+// @Success 202 {object} model.MFAChallengeResponse
 // @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
 // @Router /login [post]
 func (h *AuthHandler) Login(c echo.Context) error {
 	var req model.LoginRequest
 	if err := c.Bind(&req); err != nil {
-		h.cfg.Logger.Warn("format request salah", "error", err)
+		logger(c).Warn("format request salah", "error", err)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
 	}
 
-	var user model.User
-	if err := h.db.Where("email = ?", req.Email).First(&user).Error; err != nil {
-		h.cfg.Logger.Info("gagal login: email tidak ditemukan", "email", req.Email)
-		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Email atau password salah"})
+	ip := c.RealIP()
+	wait, err := h.guard.Check(req.Email, ip)
+	if err != nil {
+		logger(c).Error("gagal memeriksa lockout", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	if wait > 0 {
+		metrics.LoginFailuresTotal.WithLabelValues("throttled").Inc()
+		logger(c).Info("gagal login: terlalu banyak percobaan", "email", req.Email, "ip", ip)
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+		return c.JSON(http.StatusTooManyRequests, model.ErrorResponse{Remark: "Terlalu banyak percobaan login, coba lagi nanti"})
+	}
+
+	user, err := h.users.FindByEmail(c.Request().Context(), req.Email)
+	if err != nil {
+		bcrypt.CompareHashAndPassword(h.dummyHash, []byte(req.Password))
+		logger(c).Info("gagal login: email tidak ditemukan", "email", req.Email)
+		metrics.LoginFailuresTotal.WithLabelValues("unknown_email").Inc()
+		return h.loginFailed(c, req.Email, ip, nil)
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		h.cfg.Logger.Info("gagal login: password salah", "email", req.Email)
-		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Email atau password salah"})
+		logger(c).Info("gagal login: password salah", "email", req.Email)
+		metrics.LoginFailuresTotal.WithLabelValues("wrong_password").Inc()
+		return h.loginFailed(c, req.Email, ip, &user)
+	}
+
+	if user.DisabledAt != nil {
+		logger(c).Info("gagal login: user dinonaktifkan", "email", req.Email)
+		metrics.LoginFailuresTotal.WithLabelValues("disabled").Inc()
+		return c.JSON(http.StatusForbidden, model.ErrorResponse{Remark: "Akun dinonaktifkan"})
+	}
+
+	// With 2FA enabled, or required by policy, the password only earns a
+	// short-lived challenge token for the second step. The failure counter
+	// is only cleared once that step is done too, so logging in again does
+	// not buy fresh code guesses.
+	if user.TOTPEnabled || h.cfg.MFA.Required(user.Role) {
+		return h.mfaChallenge(c, user)
+	}
+
+	if err := h.guard.Success(req.Email); err != nil {
+		logger(c).Error("gagal mereset lockout", "error", err)
+	}
+
+	tokenString, err := h.issueToken(user)
+	if err != nil {
+		logger(c).Error("gagal generate token", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("user berhasil login", "email", user.Email)
+	return c.JSON(http.StatusOK, model.TokenResponse{Token: tokenString})
+}
+
+// @Summary Complete two-factor login
+// @Description Exchange the MFA challenge token from /login and a TOTP or backup code for a JWT
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.LoginMFARequest true "MFA token and code"
+// @Success 200 {object} model.TokenResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
+// @Router /login/mfa [post]
+func (h *AuthHandler) LoginMFA(c echo.Context) error {
+	var req model.LoginMFARequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		logger(c).Warn("gagal validasi", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Semua field harus diisi"})
+	}
+
+	ctx := c.Request().Context()
+	claims, err := h.parseToken(req.MFAToken, model.TokenPurposeMFA)
+	if err == nil {
+		// A password reset or disable between the two steps bumps the
+		// user's token version and so voids the MFA token too.
+		err = middleware.CheckRevoked(ctx, h.users, claims)
+	}
+	if err != nil {
+		logger(c).Info("gagal login 2FA: token tidak valid", "error", err)
+		return c.JSON(http.StatusUnauthorized, model.ErrorResponse{Remark: "Token MFA tidak valid"})
+	}
+	userID := uint(claims["user_id"].(float64))
+	jti, _ := claims["jti"].(string)
+
+	user, err := h.users.FindByID(ctx, userID)
+	if err != nil || !user.TOTPEnabled || user.DisabledAt != nil {
+		return c.JSON(http.StatusUnauthorized, model.ErrorResponse{Remark: "Token MFA tidak valid"})
+	}
+
+	ip := c.RealIP()
+	wait, err := h.guard.Check(user.Email, ip)
+	if err != nil {
+		logger(c).Error("gagal memeriksa lockout", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	if wait > 0 {
+		metrics.LoginFailuresTotal.WithLabelValues("throttled").Inc()
+		logger(c).Info("gagal login 2FA: terlalu banyak percobaan", "email", user.Email, "ip", ip)
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+		return c.JSON(http.StatusTooManyRequests, model.ErrorResponse{Remark: "Terlalu banyak percobaan login, coba lagi nanti"})
 	}
 
-	// Generate JWT token
+	// The attempt is spent before the code is checked, so parallel guesses
+	// cannot exceed the limit.
+	reserved, err := h.users.ReserveMFAAttempt(ctx, user.ID, jti, h.cfg.MFA.MaxAttempts)
+	if err != nil {
+		logger(c).Error("gagal mencatat percobaan 2FA", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	if !reserved {
+		logger(c).Info("gagal login 2FA: token sudah dipakai atau percobaan habis", "email", user.Email)
+		return c.JSON(http.StatusUnauthorized, model.ErrorResponse{Remark: "Token MFA tidak valid"})
+	}
+
+	ok, err := h.checkSecondFactor(ctx, user, req.Code)
+	if err != nil {
+		logger(c).Error("gagal memeriksa kode 2FA", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	if !ok {
+		metrics.LoginFailuresTotal.WithLabelValues("wrong_mfa_code").Inc()
+		logger(c).Info("gagal login 2FA: kode salah", "email", user.Email)
+		if err := h.guard.Failure(ctx, user.Email, ip, &user); err != nil {
+			logger(c).Error("gagal mencatat percobaan login", "error", err)
+		}
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Kode 2FA salah"})
+	}
+
+	finished, err := h.users.FinishMFALogin(ctx, user.ID, jti)
+	if err != nil {
+		logger(c).Error("gagal menyelesaikan login 2FA", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	if !finished {
+		return c.JSON(http.StatusUnauthorized, model.ErrorResponse{Remark: "Token MFA tidak valid"})
+	}
+	if err := h.guard.Success(user.Email); err != nil {
+		logger(c).Error("gagal mereset lockout", "error", err)
+	}
+
+	tokenString, err := h.issueToken(user)
+	if err != nil {
+		logger(c).Error("gagal generate token", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("user berhasil login dengan 2FA", "email", user.Email)
+	return c.JSON(http.StatusOK, model.TokenResponse{Token: tokenString})
+}
+
+func (h *AuthHandler) loginFailed(c echo.Context, email, ip string, user *model.User) error {
+	if err := h.guard.Failure(c.Request().Context(), email, ip, user); err != nil {
+		logger(c).Error("gagal mencatat percobaan login", "error", err)
+	}
+	return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Email atau password salah"})
+}
+
+func (h *AuthHandler) mfaChallenge(c echo.Context, user model.User) error {
+	purpose := model.TokenPurposeMFA
+	if !user.TOTPEnabled {
+		purpose = model.TokenPurposeMFAEnrol
+	}
+
+	claims := jwt.MapClaims{
+		"user_id": user.ID,
+		"email":   user.Email,
+		"role":    user.Role,
+		"purpose": purpose,
+		"ver":     user.TokenVersion,
+	}
+	// An MFA token completes one login; the enrolment token is used by
+	// several /2fa calls and stays valid until it expires.
+	if purpose == model.TokenPurposeMFA {
+		jti, err := newTokenID()
+		if err == nil {
+			err = h.users.StartMFALogin(c.Request().Context(), user.ID, jti)
+		}
+		if err != nil {
+			logger(c).Error("gagal memulai login 2FA", "error", err)
+			return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+		}
+		claims["jti"] = jti
+	}
+
+	expiresAt := time.Now().Add(h.cfg.MFA.ChallengeTTL)
+	claims["exp"] = expiresAt.Unix()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	tokenString, err := token.SignedString([]byte(h.cfg.JWT.Secret))
+	if err != nil {
+		logger(c).Error("gagal generate token MFA", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("login menunggu 2FA", "email", user.Email, "purpose", purpose)
+	return c.JSON(http.StatusAccepted, model.MFAChallengeResponse{
+		MFARequired:       true,
+		EnrolmentRequired: purpose == model.TokenPurposeMFAEnrol,
+		MFAToken:          tokenString,
+		ExpiresAt:         expiresAt,
+	})
+}
+
+func (h *AuthHandler) issueToken(user model.User) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": user.ID,
 		"email":   user.Email,
+		"role":    user.Role,
+		"ver":     user.TokenVersion,
 		"exp":     time.Now().Add(time.Hour * 24).Unix(),
 	})
+	return token.SignedString([]byte(h.cfg.JWT.Secret))
+}
 
-	tokenString, err := token.SignedString([]byte(h.cfg.JWT.Secret))
+// parseToken validates a purpose-bound token issued to a user and returns
+// its claims.
+func (h *AuthHandler) parseToken(tokenString, purpose string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(h.cfg.JWT.Secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 	if err != nil {
-		h.cfg.Logger.Error("gagal generate token", "error", err)
-		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+		return nil, err
 	}
 
-	h.cfg.Logger.Info("user berhasil login", "email", user.Email)
-	return c.JSON(http.StatusOK, model.TokenResponse{Token: tokenString})
-}
\ No newline at end of file
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("token tidak valid")
+	}
+	if p, _ := claims["purpose"].(string); p != purpose {
+		return nil, fmt.Errorf("purpose token %q, diharapkan %q", p, purpose)
+	}
+
+	if _, ok := claims["user_id"].(float64); !ok {
+		return nil, errors.New("user_id tidak ada di token")
+	}
+	return claims, nil
+}
+
+// newTokenID returns a random jti.
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"gobanking/logging"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+)
+
+// currentUserID returns the user_id claim set by middleware.AuthMiddleware.
+func currentUserID(c echo.Context) uint {
+	switch id := c.Get("user_id").(type) {
+	case float64:
+		return uint(id)
+	case uint:
+		return id
+	default:
+		return 0
+	}
+}
+
+// currentRole returns the role claim set by middleware.AuthMiddleware.
+func currentRole(c echo.Context) string {
+	role, _ := c.Get("role").(string)
+	return role
+}
+
+// logger returns the request-scoped logger, which carries the request ID
+// and, once authenticated, the user ID.
+func logger(c echo.Context) *slog.Logger {
+	return logging.FromContext(c.Request().Context())
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"gobanking/eod"
+	"gobanking/model"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// EODHandler lets admins run the end-of-day batch and customers read the
+// statements it generates.
+type EODHandler struct {
+	eod *eod.Service
+}
+
+func NewEODHandler(service *eod.Service) *EODHandler {
+	return &EODHandler{eod: service}
+}
+
+// @Summary Get business date
+// @Description The current business date, the date a transaction booked now gets, and the latest end-of-day run. Admin only.
+// @Tags eod
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.TanggalBisnisResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/eod [get]
+func (h *EODHandler) TanggalBisnis(c echo.Context) error {
+	resp, err := h.eod.TanggalBisnis(c.Request().Context())
+	if err != nil {
+		logger(c).Error("gagal mengambil tanggal bisnis", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Run end of day
+// @Description Start the end-of-day run of the current business date in the background: cut-off, interest accrual, fees, dormancy, balance snapshots, statements and rollover to the next business date. A failed run is resumed from the step that failed. Poll the returned run for progress. Admin only.
+// @Tags eod
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} model.EODRun
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 422 {object} model.ErrorResponse
+// @Router /admin/eod/run [post]
+func (h *EODHandler) Run(c echo.Context) error {
+	run, err := h.eod.Start(c.Request().Context(), currentUserID(c))
+	switch {
+	case errors.Is(err, eod.ErrRunning):
+		return c.JSON(http.StatusConflict, model.ErrorResponse{Remark: "EOD sedang berjalan"})
+	case errors.Is(err, eod.ErrTooEarly):
+		return c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Remark: "Tanggal bisnis belum berakhir"})
+	case errors.Is(err, eod.ErrInvalidCalendar):
+		logger(c).Error("kalender libur tidak valid", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Kalender libur tidak valid"})
+	case err != nil:
+		logger(c).Error("gagal memulai EOD", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	// The run outlives the request; failures are recorded on the run.
+	go h.eod.Execute(context.WithoutCancel(c.Request().Context()), run)
+	return c.JSON(http.StatusAccepted, run)
+}
+
+// @Summary List end-of-day runs
+// @Description The latest 30 runs with their steps, newest first. Admin only.
+// @Tags eod
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.EODRun
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/eod/runs [get]
+func (h *EODHandler) Runs(c echo.Context) error {
+	runs, err := h.eod.Runs(c.Request().Context())
+	if err != nil {
+		logger(c).Error("gagal mengambil EOD", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return c.JSON(http.StatusOK, runs)
+}
+
+// @Summary Get end-of-day run
+// @Tags eod
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Run ID"
+// @Success 200 {object} model.EODRun
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /admin/eod/runs/{id} [get]
+func (h *EODHandler) Get(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "EOD tidak ditemukan"})
+	}
+
+	run, err := h.eod.Run(c.Request().Context(), uint(id))
+	if errors.Is(err, eod.ErrRunNotFound) {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "EOD tidak ditemukan"})
+	}
+	if err != nil {
+		logger(c).Error("gagal mengambil EOD", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return c.JSON(http.StatusOK, run)
+}
+
+// @Summary Get statement
+// @Description The monthly statement of an account with the month's transactions by business date. Statements are generated by the end-of-day run of the month's last business date.
+// @Tags nasabah
+// @Produce json
+// @Security BearerAuth
+// @Param no_rekening path string true "Account number"
+// @Param periode path string true "Month as YYYY-MM"
+// @Success 200 {object} model.RekeningKoranResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /rekening-koran/{no_rekening}/{periode} [get]
+func (h *EODHandler) Statement(c echo.Context) error {
+	periode := c.Param("periode")
+	if _, err := time.Parse("2006-01", periode); err != nil {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Periode harus berformat YYYY-MM"})
+	}
+
+	resp, err := h.eod.Statement(c.Request().Context(), c.Param("no_rekening"), periode)
+	if errors.Is(err, eod.ErrStatementNotFound) {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Rekening koran tidak ditemukan"})
+	}
+	if err != nil {
+		logger(c).Error("gagal mengambil rekening koran", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"gobanking/account"
+	"gobanking/fx"
+	"gobanking/model"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// FXHandler publishes the exchange rates account.Service converts with.
+type FXHandler struct {
+	accounts *account.Service
+	validate *validator.Validate
+}
+
+func NewFXHandler(accounts *account.Service) *FXHandler {
+	return &FXHandler{
+		accounts: accounts,
+		validate: validator.New(),
+	}
+}
+
+// @Summary List FX rates
+// @Description The rate in effect now of every currency that has one, quoted in IDR. `beli` is what the bank pays for one unit and `jual` what it charges; cross-currency transfers buy the source currency at `beli` and sell the destination currency at `jual`.
+// @Tags fx
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.KursResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /fx-rates [get]
+func (h *FXHandler) List(c echo.Context) error {
+	rates, err := h.accounts.Rates(c.Request().Context())
+	if err != nil {
+		logger(c).Error("gagal mengambil kurs", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	resp := make([]model.KursResponse, len(rates))
+	for i, k := range rates {
+		resp[i] = fx.Response(k)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Load FX rates
+// @Description Store a rate sheet, all rates or none. Each rate replaces the previous one of its currency from `berlaku_mulai`, which defaults to now. Admin only; `gobanking admin fx import` loads the same from a CSV file.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body []model.KursRequest true "Rates quoted in IDR"
+// @Success 201 {array} model.KursResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /admin/fx-rates [post]
+func (h *FXHandler) Load(c echo.Context) error {
+	var req []model.KursRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+	if len(req) == 0 {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Kurs harus diisi"})
+	}
+
+	now := time.Now()
+	rates := make([]model.Kurs, len(req))
+	for i, r := range req {
+		r.MataUang = strings.ToUpper(r.MataUang)
+		if err := h.validate.Struct(r); err != nil || r.MataUang == model.MataUangIDR {
+			logger(c).Warn("gagal validasi", "error", err, "mata_uang", r.MataUang)
+			return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Mata uang, beli dan jual harus diisi dengan benar"})
+		}
+		rates[i] = model.Kurs{MataUang: r.MataUang, Beli: r.Beli, Jual: r.Jual, BerlakuMulai: now, Sumber: model.KursSumberAPI}
+		if r.BerlakuMulai != nil {
+			rates[i].BerlakuMulai = *r.BerlakuMulai
+		}
+	}
+
+	err := h.accounts.SetRates(c.Request().Context(), rates)
+	if errors.Is(err, fx.ErrInvalidRate) {
+		logger(c).Warn("kurs ditolak", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: err.Error()})
+	}
+	if errors.Is(err, account.ErrRateExists) {
+		return c.JSON(http.StatusConflict, model.ErrorResponse{Remark: err.Error()})
+	}
+	if err != nil {
+		logger(c).Error("gagal menyimpan kurs", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	resp := make([]model.KursResponse, len(rates))
+	for i, k := range rates {
+		resp[i] = fx.Response(k)
+	}
+	logger(c).Info("kurs dimuat oleh admin", "jumlah", len(rates), "admin_id", currentUserID(c))
+	return c.JSON(http.StatusCreated, resp)
+}
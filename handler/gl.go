@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gobanking/gl"
+	"gobanking/model"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// GLHandler exposes the chart of accounts, the posting rules and the trial
+// balance to admins.
+type GLHandler struct {
+	gl       *gl.Service
+	validate *validator.Validate
+}
+
+func NewGLHandler(service *gl.Service) *GLHandler {
+	return &GLHandler{
+		gl:       service,
+		validate: validator.New(),
+	}
+}
+
+// @Summary List GL accounts
+// @Description The chart of accounts ordered by code. Admin only.
+// @Tags gl
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.AkunGL
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/gl/accounts [get]
+func (h *GLHandler) Accounts(c echo.Context) error {
+	accounts, err := h.gl.Accounts(c.Request().Context())
+	if err != nil {
+		logger(c).Error("gagal mengambil akun GL", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return c.JSON(http.StatusOK, accounts)
+}
+
+// @Summary Create GL account
+// @Description Add an account to the chart. Mark liability accounts that hold customer deposits with `simpanan_nasabah` so the trial balance proves them against customer balances. Admin only.
+// @Tags gl
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.AkunGLRequest true "Account"
+// @Success 201 {object} model.AkunGL
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /admin/gl/accounts [post]
+func (h *GLHandler) CreateAccount(c echo.Context) error {
+	var req model.AkunGLRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		logger(c).Warn("gagal validasi", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Kode, nama dan tipe (asset, liability, equity, income, expense) harus diisi"})
+	}
+
+	akun, err := h.gl.CreateAccount(c.Request().Context(), req)
+	if errors.Is(err, gl.ErrAkunExists) {
+		return c.JSON(http.StatusConflict, model.ErrorResponse{Remark: "Kode akun GL sudah ada"})
+	}
+	if err != nil {
+		logger(c).Error("gagal membuat akun GL", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("akun GL dibuat", "kode", akun.Kode, "tipe", akun.Tipe)
+	return c.JSON(http.StatusCreated, akun)
+}
+
+// @Summary List posting rules
+// @Description The GL accounts each transaction type is debited and credited to. Admin only.
+// @Tags gl
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.AturanPosting
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/gl/posting-rules [get]
+func (h *GLHandler) Rules(c echo.Context) error {
+	rules, err := h.gl.Rules(c.Request().Context())
+	if err != nil {
+		logger(c).Error("gagal mengambil aturan posting", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return c.JSON(http.StatusOK, rules)
+}
+
+// @Summary Set posting rule
+// @Description Post a transaction type to other GL accounts from now on; transactions already posted are not restated. Both accounts must exist in the chart. Admin only.
+// @Tags gl
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param jenis path string true "Transaction type, e.g. tabung"
+// @Param request body model.AturanPostingRequest true "Accounts"
+// @Success 200 {object} model.AturanPosting
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 422 {object} model.ErrorResponse
+// @Router /admin/gl/posting-rules/{jenis} [put]
+func (h *GLHandler) SetRule(c echo.Context) error {
+	var req model.AturanPostingRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		logger(c).Warn("gagal validasi", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Akun debit dan kredit harus diisi dan berbeda"})
+	}
+
+	aturan, err := h.gl.SetRule(c.Request().Context(), c.Param("jenis"), req)
+	switch {
+	case errors.Is(err, gl.ErrJenisUnknown):
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Jenis transaksi tidak dikenal"})
+	case errors.Is(err, gl.ErrAkunNotFound):
+		return c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Remark: err.Error()})
+	case err != nil:
+		logger(c).Error("gagal mengubah aturan posting", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("aturan posting diubah",
+		"jenis", aturan.Jenis,
+		"akun_debit", aturan.AkunDebit,
+		"akun_kredit", aturan.AkunKredit,
+	)
+	return c.JSON(http.StatusOK, aturan)
+}
+
+// @Summary Trial balance
+// @Description Debit, credit and balance of every GL account per currency, with per-currency totals. A currency is `seimbang` when debits equal credits and its customer deposit accounts equal the sum of customer balances. Admin only.
+// @Tags gl
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.NeracaSaldoResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/gl/trial-balance [get]
+func (h *GLHandler) TrialBalance(c echo.Context) error {
+	tb, err := h.trialBalance(c)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return c.JSON(http.StatusOK, tb)
+}
+
+// @Summary Download trial balance
+// @Description The trial balance as CSV, one line per account and currency followed by the currency totals. Admin only.
+// @Tags gl
+// @Produce text/csv
+// @Security BearerAuth
+// @Success 200 {file} file
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/gl/trial-balance.csv [get]
+func (h *GLHandler) TrialBalanceCSV(c echo.Context) error {
+	tb, err := h.trialBalance(c)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	var buf bytes.Buffer
+	if err := gl.WriteTrialBalanceCSV(&buf, tb); err != nil {
+		logger(c).Error("gagal menyusun neraca saldo", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	filename := fmt.Sprintf("neraca-saldo-%s.csv", tb.Per.Format("20060102-150405"))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// @Summary Download GL journal
+// @Description Journal lines booked in [from, to) as CSV, each as a debit and a credit line. Both bounds are RFC 3339 and optional. Admin only.
+// @Tags gl
+// @Produce text/csv
+// @Security BearerAuth
+// @Param from query string false "Start, inclusive (RFC 3339)"
+// @Param to query string false "End, exclusive (RFC 3339)"
+// @Success 200 {file} file
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /admin/gl/journal.csv [get]
+func (h *GLHandler) JournalCSV(c echo.Context) error {
+	var from, to time.Time
+	for _, bound := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		v := c.QueryParam(bound.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "from dan to harus RFC 3339"})
+		}
+		*bound.dst = t
+	}
+
+	jurnal, err := h.gl.Journal(c.Request().Context(), from, to)
+	if err != nil {
+		logger(c).Error("gagal mengambil jurnal GL", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	var buf bytes.Buffer
+	if err := gl.WriteJournalCSV(&buf, jurnal); err != nil {
+		logger(c).Error("gagal menyusun jurnal GL", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="jurnal-gl.csv"`)
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// trialBalance builds the trial balance and logs currencies that do not
+// balance, which means a posting went missing or a balance was changed
+// outside account.Service.
+func (h *GLHandler) trialBalance(c echo.Context) (model.NeracaSaldoResponse, error) {
+	tb, err := h.gl.TrialBalance(c.Request().Context())
+	if err != nil {
+		logger(c).Error("gagal menyusun neraca saldo", "error", err)
+		return tb, err
+	}
+	for _, t := range tb.Total {
+		if !t.Seimbang {
+			logger(c).Warn("neraca saldo tidak seimbang",
+				"mata_uang", t.MataUang,
+				"debit", t.Debit,
+				"kredit", t.Kredit,
+				"liabilitas_nasabah", t.LiabilitasNasabah,
+				"saldo_nasabah", t.SaldoNasabah,
+			)
+		}
+	}
+	return tb, nil
+}
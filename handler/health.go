@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"gobanking/config"
+	"gobanking/database"
+	"gobanking/model"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type HealthHandler struct {
+	db       *gorm.DB
+	cfg      *config.Config
+	draining atomic.Bool
+}
+
+func NewHealthHandler(db *gorm.DB, cfg *config.Config) *HealthHandler {
+	return &HealthHandler{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// Drain makes readiness fail so load balancers stop routing new traffic
+// while in-flight requests finish.
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
+}
+
+// @Summary Liveness probe
+// @Description Reports that the process is alive. Does not touch dependencies.
+// @Tags health
+// @Produce json
+// @Success 200 {object} model.HealthResponse
+// @Router /healthz [get]
+func (h *HealthHandler) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, model.HealthResponse{Status: model.HealthOK})
+}
+
+// @Summary Readiness probe
+// @Description Checks the database and that all migrations are applied. Returns 503 while any component is down or the server is draining.
+// @Tags health
+// @Produce json
+// @Success 200 {object} model.HealthResponse
+// @Failure 503 {object} model.HealthResponse
+// @Router /readyz [get]
+func (h *HealthHandler) Ready(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), h.cfg.Health.Timeout)
+	defer cancel()
+
+	resp := model.HealthResponse{
+		Status: model.HealthOK,
+		Components: map[string]model.ComponentStatus{
+			"database":   check(ctx, func(ctx context.Context) error { return database.Ping(ctx, h.db) }),
+			"migrations": check(ctx, func(ctx context.Context) error { return database.CheckMigrations(ctx, h.db) }),
+		},
+	}
+
+	for name, component := range resp.Components {
+		if component.Status != model.HealthUp {
+			logger(c).Warn("komponen tidak siap", "component", name, "error", component.Error)
+			resp.Status = model.HealthUnavailable
+		}
+	}
+	if h.draining.Load() {
+		resp.Status = model.HealthDraining
+	}
+
+	if resp.Status != model.HealthOK {
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func check(ctx context.Context, fn func(context.Context) error) model.ComponentStatus {
+	start := time.Now()
+	err := fn(ctx)
+	status := model.ComponentStatus{
+		Status:    model.HealthUp,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		status.Status = model.HealthDown
+		status.Error = err.Error()
+	}
+	return status
+}
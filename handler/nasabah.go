@@ -1,33 +1,40 @@
 package handler
 
 import (
-	"fmt"
+	"encoding/json"
+	"errors"
+	"gobanking/account"
 	"gobanking/config"
 	"gobanking/model"
-	"math/rand"
+	"gobanking/otp"
 	"net/http"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm"
 )
 
+// NasabahHandler maps the customer endpoints onto account.Service; the
+// business rules live there.
 type NasabahHandler struct {
-	db       *gorm.DB
+	accounts *account.Service
 	cfg      *config.Config
 	validate *validator.Validate
+	otp      *OTPHandler
 }
 
-func NewNasabahHandler(db *gorm.DB, cfg *config.Config) *NasabahHandler {
-	return &NasabahHandler{
-		db:       db,
+func NewNasabahHandler(accounts *account.Service, cfg *config.Config, otpHandler *OTPHandler) *NasabahHandler {
+	h := &NasabahHandler{
+		accounts: accounts,
 		cfg:      cfg,
 		validate: validator.New(),
+		otp:      otpHandler,
 	}
-}
 
-func generateNoRekening() string {
-	return fmt.Sprintf("%010d", rand.Intn(9000000000)+1000000000)
+	otpHandler.handle(otp.PurposeTarik, h.executeTarik)
+	otpHandler.handle(otp.PurposeTambahPenerima, h.executeTambahPenerima)
+	otpHandler.handle(otp.PurposeUbahNoHP, h.executeUbahNoHP)
+
+	return h
 }
 
 // @Summary Register new customer
@@ -44,145 +51,194 @@ func generateNoRekening() string {
 func (h *NasabahHandler) Daftar(c echo.Context) error {
 	var req model.DaftarRequest
 	if err := c.Bind(&req); err != nil {
-		h.cfg.Logger.Warn("format request salah", "error", err)
+		logger(c).Warn("format request salah", "error", err)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
 	}
 
 	if err := h.validate.Struct(req); err != nil {
-		h.cfg.Logger.Warn("gagal validasi", "error", err)
+		logger(c).Warn("gagal validasi", "error", err)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Semua field harus diisi"})
 	}
 
-	var existingNasabah model.Nasabah
-	result := h.db.Where("nik = ? OR no_hp = ?", req.NIK, req.NoHP).First(&existingNasabah)
-	if result.RowsAffected > 0 {
-		h.cfg.Logger.Info("gagal daftar: duplikat NIK atau No Handphone",
+	nasabah, err := h.accounts.OpenAccount(c.Request().Context(), account.OpenAccountInput{
+		Nama:     req.Nama,
+		NIK:      req.NIK,
+		NoHP:     req.NoHP,
+		Email:    req.Email,
+		Bahasa:   req.Bahasa,
+		MataUang: req.MataUang,
+	})
+	if errors.Is(err, account.ErrDuplicateAccount) {
+		logger(c).Info("gagal daftar: duplikat NIK atau No Handphone",
 			"nik", req.NIK,
 			"no_hp", req.NoHP,
 		)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "NIK atau No Handphone sudah terdaftar"})
 	}
-
-	nasabah := model.Nasabah{
-		Nama:       req.Nama,
-		NIK:        req.NIK,
-		NoHP:       req.NoHP,
-		NoRekening: generateNoRekening(),
-		Saldo:      0,
-	}
-
-	if err := h.db.Create(&nasabah).Error; err != nil {
-		h.cfg.Logger.Error("gagal mendaftarkan nasabah", "error", err)
+	if err != nil {
+		logger(c).Error("gagal mendaftarkan nasabah", "error", err)
 		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
 	}
 
-	h.cfg.Logger.Info("nasabah berhasil didaftarkan",
+	logger(c).Info("nasabah berhasil didaftarkan",
 		"name", nasabah.Nama,
 		"no_rekening", nasabah.NoRekening,
+		"mata_uang", nasabah.MataUang,
 	)
 
-	return c.JSON(http.StatusOK, model.RekeningResponse{NoRekening: nasabah.NoRekening})
+	return c.JSON(http.StatusOK, model.RekeningResponse{NoRekening: nasabah.NoRekening, MataUang: nasabah.MataUang})
 }
 
 // @Summary Deposit money
-// @Description Deposit money into a customer's account
+// @Description Deposit money into a customer's account. An optional `referensi`, e.g. a partner's settlement reference, is matched by reconciliation and may be used once per account.
 // @Tags nasabah
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body model.TransaksiRequest true "Deposit details"
+// @Param request body model.TabungRequest true "Deposit details"
 // @Success 200 {object} model.SaldoResponse
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
 // @Router /tabung [post]
 func (h *NasabahHandler) Tabung(c echo.Context) error {
-	var req model.TransaksiRequest
+	var req model.TabungRequest
 	if err := c.Bind(&req); err != nil {
-		h.cfg.Logger.Warn("format request salah", "error", err)
+		logger(c).Warn("format request salah", "error", err)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
 	}
 
 	if err := h.validate.Struct(req); err != nil {
-		h.cfg.Logger.Warn("gagal validasi", "error", err)
+		logger(c).Warn("gagal validasi", "error", err)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Semua field harus diisi"})
 	}
 
-	var nasabah model.Nasabah
-	if err := h.db.Where("no_rekening = ?", req.NoRekening).First(&nasabah).Error; err != nil {
-		h.cfg.Logger.Info("gagal tabungan: rekening tidak ditemukan",
+	nasabah, err := h.accounts.Deposit(c.Request().Context(), req.NoRekening, req.Nominal, req.Referensi)
+	if errors.Is(err, account.ErrAccountNotFound) {
+		logger(c).Info("gagal tabungan: rekening tidak ditemukan",
 			"no_rekening", req.NoRekening,
 		)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No Rekening tidak ditemukan"})
 	}
-
-	nasabah.Saldo += req.Nominal
-	if err := h.db.Save(&nasabah).Error; err != nil {
-		h.cfg.Logger.Error("gagal memperbarui saldo", "error", err)
+	if errors.Is(err, account.ErrInvalidAmount) {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Nominal harus lebih dari 0"})
+	}
+	if errors.Is(err, account.ErrAccountFrozen) {
+		logger(c).Info("gagal mutasi: rekening dibekukan", "no_rekening", req.NoRekening)
+		return c.JSON(http.StatusForbidden, model.ErrorResponse{Remark: "Rekening dibekukan"})
+	}
+	if errors.Is(err, account.ErrDuplicateReference) {
+		logger(c).Info("gagal tabungan: referensi sudah dipakai", "no_rekening", req.NoRekening)
+		return c.JSON(http.StatusConflict, model.ErrorResponse{Remark: "Referensi sudah dipakai"})
+	}
+	if err != nil {
+		logger(c).Error("gagal memperbarui saldo", "error", err)
 		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
 	}
 
-	h.cfg.Logger.Info("tabungan berhasil",
+	logger(c).Info("tabungan berhasil",
 		"no_rekening", nasabah.NoRekening,
 		"nominal", req.Nominal,
 		"saldo_baru", nasabah.Saldo,
 	)
 
-	return c.JSON(http.StatusOK, model.SaldoResponse{Saldo: nasabah.Saldo})
+	return c.JSON(http.StatusOK, saldoResponse(nasabah))
 }
 
 // @Summary Withdraw money
-// @Description Withdraw money from a customer's account
+// @Description Withdraw money from a customer's account, in the account's currency. Amounts whose IDR value is above the OTP threshold, or cannot be valued for want of an FX rate, answer 202 with an OTP challenge and are executed by POST /otp/verify.
 // @Tags nasabah
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body model.TransaksiRequest true "Withdrawal details"
 // @Success 200 {object} model.SaldoResponse
+// @Success 202 {object} model.OTPChallengeResponse
 // @Failure 400 {object} model.ErrorResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
 // @Router /tarik [post]
 func (h *NasabahHandler) Tarik(c echo.Context) error {
 	var req model.TransaksiRequest
 	if err := c.Bind(&req); err != nil {
-		h.cfg.Logger.Warn("format request salah", "error", err)
+		logger(c).Warn("format request salah", "error", err)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
 	}
 
 	if err := h.validate.Struct(req); err != nil {
-		h.cfg.Logger.Warn("gagal validasi", "error", err)
+		logger(c).Warn("gagal validasi", "error", err)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Semua field harus diisi"})
 	}
 
-	var nasabah model.Nasabah
-	if err := h.db.Where("no_rekening = ?", req.NoRekening).First(&nasabah).Error; err != nil {
-		h.cfg.Logger.Info("gagal penarikan: rekening tidak ditemukan",
+	nasabah, err := h.accounts.Account(c.Request().Context(), req.NoRekening)
+	if errors.Is(err, account.ErrAccountNotFound) {
+		logger(c).Info("gagal penarikan: rekening tidak ditemukan",
 			"no_rekening", req.NoRekening,
 		)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No Rekening tidak ditemukan"})
 	}
+	if err != nil {
+		logger(c).Error("gagal mengambil nasabah", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	above, err := h.accounts.ExceedsIDR(c.Request().Context(), nasabah.MataUang, req.Nominal, h.cfg.OTP.TarikThreshold)
+	if err != nil {
+		logger(c).Error("gagal menilai nominal dalam IDR", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	if above {
+		return h.otp.challenge(c, otp.PurposeTarik, req, recipient(nasabah))
+	}
+
+	return h.tarik(c, req)
+}
+
+func (h *NasabahHandler) executeTarik(c echo.Context, payload []byte) error {
+	var req model.TransaksiRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		logger(c).Error("payload OTP tidak valid", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return h.tarik(c, req)
+}
 
-	if nasabah.Saldo < req.Nominal {
-		h.cfg.Logger.Info("gagal penarikan: saldo tidak mencukupi",
+func (h *NasabahHandler) tarik(c echo.Context, req model.TransaksiRequest) error {
+	nasabah, err := h.accounts.Withdraw(c.Request().Context(), req.NoRekening, req.Nominal)
+	if errors.Is(err, account.ErrAccountNotFound) {
+		logger(c).Info("gagal penarikan: rekening tidak ditemukan",
+			"no_rekening", req.NoRekening,
+		)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No Rekening tidak ditemukan"})
+	}
+	if errors.Is(err, account.ErrInvalidAmount) {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Nominal harus lebih dari 0"})
+	}
+	if errors.Is(err, account.ErrAccountFrozen) {
+		logger(c).Info("gagal mutasi: rekening dibekukan", "no_rekening", req.NoRekening)
+		return c.JSON(http.StatusForbidden, model.ErrorResponse{Remark: "Rekening dibekukan"})
+	}
+	if errors.Is(err, account.ErrInsufficientFunds) {
+		logger(c).Info("gagal penarikan: saldo tidak mencukupi",
 			"no_rekening", nasabah.NoRekening,
 			"saldo", nasabah.Saldo,
 			"nominal_ditarik", req.Nominal,
 		)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Saldo tidak mencukupi"})
 	}
-
-	nasabah.Saldo -= req.Nominal
-	if err := h.db.Save(&nasabah).Error; err != nil {
-		h.cfg.Logger.Error("gagal memperbarui saldo", "error", err)
+	if err != nil {
+		logger(c).Error("gagal memperbarui saldo", "error", err)
 		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
 	}
 
-	h.cfg.Logger.Info("penarikan berhasil",
+	logger(c).Info("penarikan berhasil",
 		"no_rekening", nasabah.NoRekening,
 		"nominal", req.Nominal,
 		"saldo_baru", nasabah.Saldo,
 	)
 
-	return c.JSON(http.StatusOK, model.SaldoResponse{Saldo: nasabah.Saldo})
+	return c.JSON(http.StatusOK, saldoResponse(nasabah))
 }
 
 // @Summary Check balance
@@ -199,18 +255,201 @@ func (h *NasabahHandler) Tarik(c echo.Context) error {
 func (h *NasabahHandler) Saldo(c echo.Context) error {
 	noRekening := c.Param("no_rekening")
 
-	var nasabah model.Nasabah
-	if err := h.db.Where("no_rekening = ?", noRekening).First(&nasabah).Error; err != nil {
-		h.cfg.Logger.Info("gagal pengecekan saldo: rekening tidak ditemukan",
+	nasabah, err := h.accounts.Account(c.Request().Context(), noRekening)
+	if errors.Is(err, account.ErrAccountNotFound) {
+		logger(c).Info("gagal pengecekan saldo: rekening tidak ditemukan",
 			"no_rekening", noRekening,
 		)
 		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No Rekening tidak ditemukan"})
 	}
+	if err != nil {
+		logger(c).Error("gagal mengambil saldo", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
 
-	h.cfg.Logger.Info("pengecekan saldo berhasil",
-		"no_rekening", nasabah.NoRekening,
+	logger(c).Info("pengecekan saldo berhasil",
+		"no_rekening", noRekening,
 		"saldo", nasabah.Saldo,
+		"mata_uang", nasabah.MataUang,
+	)
+
+	return c.JSON(http.StatusOK, saldoResponse(nasabah))
+}
+
+// @Summary Add transfer beneficiary
+// @Description Save a beneficiary account. Always requires OTP; the response is an OTP challenge.
+// @Tags nasabah
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.PenerimaRequest true "Beneficiary details"
+// @Success 202 {object} model.OTPChallengeResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /penerima [post]
+func (h *NasabahHandler) TambahPenerima(c echo.Context) error {
+	var req model.PenerimaRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		logger(c).Warn("gagal validasi", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Rekening asal dan tujuan harus diisi dan berbeda"})
+	}
+
+	err := h.accounts.CheckBeneficiary(c.Request().Context(), req.NoRekening, req.NoRekeningTujuan)
+	if errors.Is(err, account.ErrAccountNotFound) {
+		logger(c).Info("gagal tambah penerima: rekening tidak ditemukan", "no_rekening", req.NoRekening)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No Rekening tidak ditemukan"})
+	}
+	if errors.Is(err, account.ErrBeneficiaryNotFound) {
+		logger(c).Info("gagal tambah penerima: rekening tujuan tidak ditemukan", "no_rekening_tujuan", req.NoRekeningTujuan)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No Rekening tujuan tidak ditemukan"})
+	}
+	if err != nil {
+		logger(c).Error("gagal memeriksa rekening", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	nasabah, err := h.accounts.Account(c.Request().Context(), req.NoRekening)
+	if err != nil {
+		logger(c).Error("gagal mengambil nasabah", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	return h.otp.challenge(c, otp.PurposeTambahPenerima, req, recipient(nasabah))
+}
+
+func (h *NasabahHandler) executeTambahPenerima(c echo.Context, payload []byte) error {
+	var req model.PenerimaRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		logger(c).Error("payload OTP tidak valid", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	// Either account may have been closed while the OTP was outstanding.
+	penerima, err := h.accounts.AddBeneficiary(c.Request().Context(), req.NoRekening, req.NoRekeningTujuan, req.Alias)
+	if errors.Is(err, account.ErrBeneficiaryExists) {
+		logger(c).Info("gagal tambah penerima: sudah terdaftar", "no_rekening", req.NoRekening)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Penerima sudah terdaftar"})
+	}
+	if errors.Is(err, account.ErrAccountNotFound) {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No Rekening tidak ditemukan"})
+	}
+	if errors.Is(err, account.ErrBeneficiaryNotFound) {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No Rekening tujuan tidak ditemukan"})
+	}
+	if err != nil {
+		logger(c).Error("gagal menambah penerima", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("penerima berhasil ditambahkan",
+		"no_rekening", penerima.NoRekening,
+		"no_rekening_tujuan", penerima.NoRekeningTujuan,
 	)
+	return c.JSON(http.StatusCreated, penerima)
+}
+
+// @Summary List transfer beneficiaries
+// @Tags nasabah
+// @Produce json
+// @Security BearerAuth
+// @Param no_rekening path string true "Account number"
+// @Success 200 {array} model.Penerima
+// @Failure 401 {object} model.ErrorResponse
+// @Router /penerima/{no_rekening} [get]
+func (h *NasabahHandler) DaftarPenerima(c echo.Context) error {
+	penerima, err := h.accounts.Beneficiaries(c.Request().Context(), c.Param("no_rekening"))
+	if err != nil {
+		logger(c).Error("gagal mengambil penerima", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return c.JSON(http.StatusOK, penerima)
+}
+
+// @Summary Change phone number
+// @Description Change the customer's NoHP. Always requires OTP, sent to the current number.
+// @Tags nasabah
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.UbahNoHPRequest true "Account and new phone number"
+// @Success 202 {object} model.OTPChallengeResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /ubah-no-hp [post]
+func (h *NasabahHandler) UbahNoHP(c echo.Context) error {
+	var req model.UbahNoHPRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		logger(c).Warn("gagal validasi", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Semua field harus diisi"})
+	}
+
+	nasabah, err := h.accounts.Account(c.Request().Context(), req.NoRekening)
+	if errors.Is(err, account.ErrAccountNotFound) {
+		logger(c).Info("gagal ubah no hp: rekening tidak ditemukan", "no_rekening", req.NoRekening)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No Rekening tidak ditemukan"})
+	}
+	if err != nil {
+		logger(c).Error("gagal mengambil nasabah", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	err = h.accounts.CheckPhoneAvailable(c.Request().Context(), req.NoRekening, req.NoHP)
+	if errors.Is(err, account.ErrPhoneInUse) {
+		logger(c).Info("gagal ubah no hp: nomor sudah terdaftar", "no_rekening", req.NoRekening)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No Handphone sudah terdaftar"})
+	}
+	if err != nil {
+		logger(c).Error("gagal memeriksa no hp", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	return h.otp.challenge(c, otp.PurposeUbahNoHP, req, recipient(nasabah))
+}
+
+func (h *NasabahHandler) executeUbahNoHP(c echo.Context, payload []byte) error {
+	var req model.UbahNoHPRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		logger(c).Error("payload OTP tidak valid", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
 
-	return c.JSON(http.StatusOK, model.SaldoResponse{Saldo: nasabah.Saldo})
+	// The number may have been taken while the OTP was outstanding.
+	err := h.accounts.ChangePhone(c.Request().Context(), req.NoRekening, req.NoHP)
+	if errors.Is(err, account.ErrPhoneInUse) {
+		logger(c).Info("gagal ubah no hp: nomor sudah terdaftar", "no_rekening", req.NoRekening)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No Handphone sudah terdaftar"})
+	}
+	if errors.Is(err, account.ErrAccountNotFound) {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No Rekening tidak ditemukan"})
+	}
+	if err != nil {
+		logger(c).Error("gagal mengubah no hp", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("no hp berhasil diubah", "no_rekening", req.NoRekening)
+	return c.JSON(http.StatusOK, model.RekeningResponse{NoRekening: req.NoRekening})
+}
+
+func recipient(nasabah model.Nasabah) otp.Recipient {
+	return otp.Recipient{NoHP: nasabah.NoHP, Bahasa: nasabah.Bahasa}
+}
+
+func saldoResponse(nasabah model.Nasabah) model.SaldoResponse {
+	return model.SaldoResponse{
+		Saldo:     nasabah.Saldo,
+		MataUang:  nasabah.MataUang,
+		Overdraft: nasabah.OverdraftAt != nil,
+		Dormant:   nasabah.DormantAt != nil,
+	}
 }
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"gobanking/config"
+	"gobanking/model"
+	"gobanking/otp"
+	"gobanking/tracing"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// stepUpAction runs an operation that was held back until its OTP challenge
+// was verified. payload is the JSON stored when the challenge was created.
+type stepUpAction func(c echo.Context, payload []byte) error
+
+type OTPHandler struct {
+	cfg      *config.Config
+	svc      *otp.Service
+	validate *validator.Validate
+	actions  map[string]stepUpAction
+}
+
+func NewOTPHandler(cfg *config.Config, svc *otp.Service) *OTPHandler {
+	return &OTPHandler{
+		cfg:      cfg,
+		svc:      svc,
+		validate: validator.New(),
+		actions:  make(map[string]stepUpAction),
+	}
+}
+
+func (h *OTPHandler) handle(purpose string, action stepUpAction) {
+	h.actions[purpose] = action
+}
+
+// challenge parks the request behind an OTP and answers 202 with the
+// challenge the client has to complete at POST /otp/verify.
+func (h *OTPHandler) challenge(c echo.Context, purpose string, payload any, to otp.Recipient) error {
+	ctx, span := tracing.Tracer().Start(c.Request().Context(), "otp.challenge")
+	defer span.End()
+
+	ch, err := h.svc.Challenge(ctx, currentUserID(c), purpose, payload, to)
+	if err != nil {
+		logger(c).Error("gagal membuat challenge OTP", "purpose", purpose, "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("verifikasi OTP diperlukan",
+		"purpose", purpose,
+		"challenge_id", ch.ID,
+		"method", ch.Method,
+	)
+	return c.JSON(http.StatusAccepted, model.OTPChallengeResponse{
+		Remark:      "Verifikasi OTP diperlukan",
+		ChallengeID: ch.ID,
+		Method:      ch.Method,
+		Target:      ch.Target,
+		ExpiresAt:   ch.ExpiresAt,
+	})
+}
+
+// @Summary Verify OTP
+// @Description Complete an OTP challenge. On success the original request is executed and its response returned.
+// @Tags otp
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.OTPVerifyRequest true "Challenge and code"
+// @Success 200 {object} object
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 429 {object} model.ErrorResponse
+// @Router /otp/verify [post]
+func (h *OTPHandler) Verify(c echo.Context) error {
+	var req model.OTPVerifyRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		logger(c).Warn("gagal validasi", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Semua field harus diisi"})
+	}
+
+	ch, err := h.svc.Verify(c.Request().Context(), currentUserID(c), req.ChallengeID, req.Code)
+	switch {
+	case errors.Is(err, otp.ErrChallengeNotFound):
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Challenge OTP tidak ditemukan"})
+	case errors.Is(err, otp.ErrChallengeExpired), errors.Is(err, otp.ErrChallengeUsed):
+		logger(c).Info("gagal verifikasi OTP", "challenge_id", req.ChallengeID, "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "OTP sudah tidak berlaku"})
+	case errors.Is(err, otp.ErrTooManyAttempts):
+		logger(c).Warn("gagal verifikasi OTP: batas percobaan tercapai", "challenge_id", req.ChallengeID)
+		return c.JSON(http.StatusTooManyRequests, model.ErrorResponse{Remark: "Percobaan OTP melebihi batas"})
+	case errors.Is(err, otp.ErrInvalidCode):
+		logger(c).Info("gagal verifikasi OTP: kode salah",
+			"challenge_id", req.ChallengeID,
+			"attempts", ch.Attempts,
+		)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Kode OTP salah"})
+	case err != nil:
+		logger(c).Error("gagal verifikasi OTP", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	action, ok := h.actions[ch.Purpose]
+	if !ok {
+		logger(c).Error("aksi OTP tidak terdaftar", "purpose", ch.Purpose)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("OTP terverifikasi", "challenge_id", ch.ID, "purpose", ch.Purpose)
+	return action(c, []byte(ch.Payload))
+}
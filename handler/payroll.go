@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/model"
+	"gobanking/otp"
+	"gobanking/payroll"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// PayrollHandler accepts bulk disbursement batches; payroll.Processor
+// settles them in the background.
+type PayrollHandler struct {
+	payroll  *payroll.Service
+	accounts *account.Service
+	cfg      *config.Config
+	validate *validator.Validate
+	otp      *OTPHandler
+}
+
+func NewPayrollHandler(service *payroll.Service, accounts *account.Service, cfg *config.Config, otpHandler *OTPHandler) *PayrollHandler {
+	h := &PayrollHandler{
+		payroll:  service,
+		accounts: accounts,
+		cfg:      cfg,
+		validate: validator.New(),
+		otp:      otpHandler,
+	}
+
+	otpHandler.handle(otp.PurposePayroll, h.executeSubmit)
+
+	return h
+}
+
+// payrollStepUp is the batch held back behind an OTP challenge.
+type payrollStepUp struct {
+	NoRekeningSumber string        `json:"no_rekening_sumber"`
+	Keterangan       string        `json:"keterangan"`
+	Rows             []payroll.Row `json:"rows"`
+}
+
+// @Summary Submit payroll batch
+// @Description Credit many accounts from one source account. Every row is validated up front; invalid rows, including those crediting an account that is not a saved beneficiary of the source, are returned in `rejected` and marked failed without stopping the rest, which are transferred asynchronously. Amounts are in the source account's currency and converted for destinations held in another one. Batches whose total IDR value is above the OTP threshold, or cannot be valued for want of an FX rate, answer 202 with an OTP challenge and are submitted by POST /otp/verify.
+// @Tags payroll
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.PayrollRequest true "Source account and rows"
+// @Success 202 {object} model.PayrollBatchResponse
+// @Success 202 {object} model.OTPChallengeResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /payroll [post]
+func (h *PayrollHandler) Create(c echo.Context) error {
+	var req model.PayrollRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		logger(c).Warn("gagal validasi", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No rekening sumber dan items harus diisi"})
+	}
+
+	rows := make([]payroll.Row, len(req.Items))
+	for i, item := range req.Items {
+		rows[i] = payroll.Row{
+			Baris:      i + 1,
+			NoRekening: item.NoRekening,
+			Nama:       item.Nama,
+			Nominal:    item.Nominal,
+		}
+	}
+	return h.submit(c, req.NoRekeningSumber, req.Keterangan, rows)
+}
+
+// @Summary Upload payroll batch as CSV
+// @Description Same as POST /payroll, OTP step-up included, with the rows in a CSV file. The header row must name `no_rekening` and `nominal` and may name `nama`; nominal uses a dot as decimal separator and no thousands separator.
+// @Tags payroll
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param no_rekening_sumber formData string true "Source account"
+// @Param keterangan formData string false "Description"
+// @Param file formData file true "CSV file"
+// @Success 202 {object} model.PayrollBatchResponse
+// @Success 202 {object} model.OTPChallengeResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /payroll/csv [post]
+func (h *PayrollHandler) CreateCSV(c echo.Context) error {
+	sumber := c.FormValue("no_rekening_sumber")
+	if sumber == "" {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No rekening sumber harus diisi"})
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		logger(c).Warn("file payroll tidak ada", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "File CSV harus diunggah"})
+	}
+	file, err := header.Open()
+	if err != nil {
+		logger(c).Error("gagal membuka file payroll", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	defer file.Close()
+
+	rows, err := payroll.ParseCSV(file, h.cfg.Payroll.MaxItems)
+	if err != nil {
+		logger(c).Warn("file payroll ditolak", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: payrollRemark(err)})
+	}
+	return h.submit(c, sumber, c.FormValue("keterangan"), rows)
+}
+
+// submit asks for an OTP sent to the source account's holder when the batch
+// is worth more than a withdrawal may be without one.
+func (h *PayrollHandler) submit(c echo.Context, sumber, keterangan string, rows []payroll.Row) error {
+	nasabah, err := h.accounts.Account(c.Request().Context(), sumber)
+	if errors.Is(err, account.ErrAccountNotFound) {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "No Rekening sumber tidak ditemukan"})
+	}
+	if err != nil {
+		logger(c).Error("gagal mengambil nasabah", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	above, err := h.accounts.ExceedsIDR(c.Request().Context(), nasabah.MataUang, payroll.Total(rows), h.cfg.OTP.TarikThreshold)
+	if err != nil {
+		logger(c).Error("gagal menilai nominal dalam IDR", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	if above {
+		return h.otp.challenge(c, otp.PurposePayroll, payrollStepUp{
+			NoRekeningSumber: sumber,
+			Keterangan:       keterangan,
+			Rows:             rows,
+		}, recipient(nasabah))
+	}
+
+	return h.store(c, sumber, keterangan, rows)
+}
+
+func (h *PayrollHandler) executeSubmit(c echo.Context, payload []byte) error {
+	var req payrollStepUp
+	if err := json.Unmarshal(payload, &req); err != nil {
+		logger(c).Error("payload OTP tidak valid", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return h.store(c, req.NoRekeningSumber, req.Keterangan, req.Rows)
+}
+
+func (h *PayrollHandler) store(c echo.Context, sumber, keterangan string, rows []payroll.Row) error {
+	batch, rejected, err := h.payroll.Submit(c.Request().Context(), currentUserID(c), sumber, keterangan, rows)
+	switch {
+	case errors.Is(err, account.ErrAccountNotFound):
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "No Rekening sumber tidak ditemukan"})
+	case errors.Is(err, account.ErrAccountFrozen):
+		return c.JSON(http.StatusForbidden, model.ErrorResponse{Remark: "Rekening sumber dibekukan"})
+	case errors.Is(err, payroll.ErrEmptyBatch), errors.Is(err, payroll.ErrTooManyRows):
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: payrollRemark(err)})
+	case err != nil:
+		logger(c).Error("gagal menyimpan batch payroll", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("batch payroll diterima",
+		"batch_id", batch.ID,
+		"no_rekening_sumber", sumber,
+		"total_items", batch.TotalItems,
+		"total_nominal", batch.TotalNominal,
+		"rejected", len(rejected),
+	)
+
+	resp := payrollBatchResponse(batch, 0)
+	resp.Rejected = make([]model.PayrollItemResponse, len(rejected))
+	for i, item := range rejected {
+		resp.Rejected[i] = payrollItemResponse(item, batch.MataUang)
+	}
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+// @Summary Get payroll batch
+// @Description Status and totals of a batch. `success_nominal` is what has been debited so far and `journal_nominal` is the same figure read back from the source account's ledger.
+// @Tags payroll
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Batch ID"
+// @Success 200 {object} model.PayrollBatchResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /payroll/{id} [get]
+func (h *PayrollHandler) Get(c echo.Context) error {
+	batch, err := h.findBatch(c)
+	if errors.Is(err, payroll.ErrBatchNotFound) {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Batch payroll tidak ditemukan"})
+	}
+	if err != nil {
+		logger(c).Error("gagal mengambil batch payroll", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	journal, err := h.payroll.JournalNominal(c.Request().Context(), batch)
+	if err != nil {
+		logger(c).Error("gagal menghitung jurnal payroll", "batch_id", batch.ID, "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return c.JSON(http.StatusOK, payrollBatchResponse(batch, journal))
+}
+
+// @Summary Download payroll results
+// @Description One CSV line per row with its status, failure reason and the source-side transaksi ID.
+// @Tags payroll
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path int true "Batch ID"
+// @Success 200 {file} file
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /payroll/{id}/result.csv [get]
+func (h *PayrollHandler) Result(c echo.Context) error {
+	batch, err := h.findBatch(c)
+	if errors.Is(err, payroll.ErrBatchNotFound) {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Batch payroll tidak ditemukan"})
+	}
+	if err != nil {
+		logger(c).Error("gagal mengambil batch payroll", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	items, err := h.payroll.Items(c.Request().Context(), batch.ID)
+	if err != nil {
+		logger(c).Error("gagal mengambil baris payroll", "batch_id", batch.ID, "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	var buf bytes.Buffer
+	if err := payroll.WriteResultCSV(&buf, batch.MataUang, items); err != nil {
+		logger(c).Error("gagal menyusun hasil payroll", "batch_id", batch.ID, "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="payroll-%d.csv"`, batch.ID))
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// findBatch loads the batch in the path if the caller owns it.
+func (h *PayrollHandler) findBatch(c echo.Context) (model.PayrollBatch, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return model.PayrollBatch{}, payroll.ErrBatchNotFound
+	}
+	return h.payroll.Batch(c.Request().Context(), currentUserID(c), uint(id))
+}
+
+func payrollRemark(err error) string {
+	switch {
+	case errors.Is(err, payroll.ErrEmptyBatch):
+		return "Batch payroll tidak berisi baris"
+	case errors.Is(err, payroll.ErrTooManyRows):
+		return "Jumlah baris melebihi batas"
+	}
+	// ErrInvalidCSV carries the parser detail, which is safe to show.
+	return err.Error()
+}
+
+func payrollBatchResponse(batch model.PayrollBatch, journal float64) model.PayrollBatchResponse {
+	return model.PayrollBatchResponse{
+		ID:               batch.ID,
+		NoRekeningSumber: batch.NoRekeningSumber,
+		Keterangan:       batch.Keterangan,
+		Status:           batch.Status,
+		TotalItems:       batch.TotalItems,
+		TotalNominal:     batch.TotalNominal,
+		MataUang:         batch.MataUang,
+		SuccessItems:     batch.SuccessItems,
+		SuccessNominal:   batch.SuccessNominal,
+		FailedItems:      batch.FailedItems,
+		FailedNominal:    batch.FailedNominal,
+		JournalNominal:   journal,
+		CreatedAt:        batch.CreatedAt,
+		StartedAt:        batch.StartedAt,
+		CompletedAt:      batch.CompletedAt,
+	}
+}
+
+// payrollItemResponse converts a row of a batch whose amounts are in
+// mataUang.
+func payrollItemResponse(item model.PayrollItem, mataUang string) model.PayrollItemResponse {
+	return model.PayrollItemResponse{
+		Baris:       item.Baris,
+		NoRekening:  item.NoRekening,
+		Nama:        item.Nama,
+		Nominal:     item.Nominal,
+		MataUang:    mataUang,
+		Status:      item.Status,
+		Error:       item.Error,
+		TransaksiID: item.TransaksiID,
+		ProcessedAt: item.ProcessedAt,
+	}
+}
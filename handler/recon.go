@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gobanking/model"
+	"gobanking/recon"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// ReconHandler lets operations staff import partners' settlement files and
+// work through the exceptions.
+type ReconHandler struct {
+	recon    *recon.Service
+	validate *validator.Validate
+}
+
+func NewReconHandler(service *recon.Service) *ReconHandler {
+	return &ReconHandler{
+		recon:    service,
+		validate: validator.New(),
+	}
+}
+
+// @Summary Import settlement file
+// @Description Match a partner's settlement file against the deposits in our books. CSV files need a header naming `referensi`, `nominal` and `tanggal` (YYYY-MM-DD) and may name `no_rekening` and `mata_uang`. MT940 files contribute their credit `:61:` lines. Each entry is paired by reference first, then by amount and date within the configured tolerances, and every entry and expected deposit is classified as matched, missing_internal, missing_external or amount_mismatch. Staff and admin only.
+// @Tags reconciliation
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param mitra formData string true "Partner"
+// @Param format formData string false "csv or mt940; detected from the file when empty"
+// @Param file formData file true "Settlement file"
+// @Success 201 {object} model.Rekonsiliasi
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /reconciliations [post]
+func (h *ReconHandler) Import(c echo.Context) error {
+	mitra := c.FormValue("mitra")
+	if mitra == "" {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Mitra harus diisi"})
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		logger(c).Warn("file settlement tidak ada", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "File settlement harus diunggah"})
+	}
+	file, err := header.Open()
+	if err != nil {
+		logger(c).Error("gagal membuka file settlement", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		logger(c).Error("gagal membaca file settlement", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	rec, err := h.recon.Import(c.Request().Context(), currentUserID(c), recon.ImportInput{
+		Mitra:    mitra,
+		NamaFile: header.Filename,
+		Format:   c.FormValue("format"),
+		Data:     data,
+	})
+	switch {
+	case errors.Is(err, recon.ErrInvalidFile), errors.Is(err, recon.ErrEmptyFile),
+		errors.Is(err, recon.ErrTooManyEntries), errors.Is(err, recon.ErrUnknownFormat):
+		logger(c).Warn("file settlement ditolak", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: err.Error()})
+	case errors.Is(err, recon.ErrDuplicateFile):
+		return c.JSON(http.StatusConflict, model.ErrorResponse{Remark: "File settlement sudah pernah diimpor"})
+	case err != nil:
+		logger(c).Error("gagal mengimpor file settlement", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return c.JSON(http.StatusCreated, rec)
+}
+
+// @Summary List reconciliations
+// @Description The latest 100 imported settlement files with their counts, newest first. Staff and admin only.
+// @Tags reconciliation
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.Rekonsiliasi
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /reconciliations [get]
+func (h *ReconHandler) List(c echo.Context) error {
+	recs, err := h.recon.List(c.Request().Context())
+	if err != nil {
+		logger(c).Error("gagal mengambil rekonsiliasi", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return c.JSON(http.StatusOK, recs)
+}
+
+// @Summary Get reconciliation
+// @Tags reconciliation
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reconciliation ID"
+// @Success 200 {object} model.Rekonsiliasi
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /reconciliations/{id} [get]
+func (h *ReconHandler) Get(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Rekonsiliasi tidak ditemukan"})
+	}
+	rec, err := h.recon.Get(c.Request().Context(), uint(id))
+	if err != nil {
+		return h.fail(c, err, "gagal mengambil rekonsiliasi")
+	}
+	return c.JSON(http.StatusOK, rec)
+}
+
+// @Summary List reconciliation items
+// @Description The result lines in file order, deposits missing from the file last. Staff and admin only.
+// @Tags reconciliation
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reconciliation ID"
+// @Param status query string false "matched, missing_internal, missing_external or amount_mismatch"
+// @Param open query bool false "Only unresolved exceptions"
+// @Success 200 {array} model.RekonsiliasiItem
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /reconciliations/{id}/items [get]
+func (h *ReconHandler) Items(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Rekonsiliasi tidak ditemukan"})
+	}
+	open, _ := strconv.ParseBool(c.QueryParam("open"))
+	items, err := h.recon.Items(c.Request().Context(), uint(id), c.QueryParam("status"), open)
+	if err != nil {
+		return h.fail(c, err, "gagal mengambil item rekonsiliasi")
+	}
+	return c.JSON(http.StatusOK, items)
+}
+
+// @Summary Download exception report
+// @Description Every item that is not matched, as CSV, with both sides, the difference and the resolution if any. Staff and admin only.
+// @Tags reconciliation
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path int true "Reconciliation ID"
+// @Success 200 {file} file
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /reconciliations/{id}/exceptions.csv [get]
+func (h *ReconHandler) Exceptions(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Rekonsiliasi tidak ditemukan"})
+	}
+	items, err := h.recon.Exceptions(c.Request().Context(), uint(id))
+	if err != nil {
+		return h.fail(c, err, "gagal mengambil pengecualian rekonsiliasi")
+	}
+
+	var buf bytes.Buffer
+	if err := recon.WriteExceptionsCSV(&buf, items); err != nil {
+		logger(c).Error("gagal menyusun laporan pengecualian", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	filename := fmt.Sprintf("rekonsiliasi-%d-pengecualian.csv", id)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// @Summary Resolve reconciliation exception
+// @Description Close an exception with a note. For a missing_internal entry, `transaksi_id` pairs it with a deposit found by hand; the deposit must be a tabung in the entry's currency not paired elsewhere, and its missing_external items are resolved too. Staff and admin only.
+// @Tags reconciliation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reconciliation ID"
+// @Param item_id path int true "Item ID"
+// @Param request body model.ResolveRekonsiliasiRequest true "Resolution"
+// @Success 200 {object} model.RekonsiliasiItem
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /reconciliations/{id}/items/{item_id}/resolve [post]
+func (h *ReconHandler) Resolve(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Item rekonsiliasi tidak ditemukan"})
+	}
+	itemID, err := strconv.ParseUint(c.Param("item_id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Item rekonsiliasi tidak ditemukan"})
+	}
+
+	var req model.ResolveRekonsiliasiRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		logger(c).Warn("gagal validasi", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Catatan harus diisi, maksimal 500 karakter"})
+	}
+
+	item, err := h.recon.Resolve(c.Request().Context(), currentUserID(c), uint(id), uint(itemID), req)
+	if err != nil {
+		return h.fail(c, err, "gagal menyelesaikan item rekonsiliasi")
+	}
+	return c.JSON(http.StatusOK, item)
+}
+
+func (h *ReconHandler) fail(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, recon.ErrNotFound):
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Rekonsiliasi tidak ditemukan"})
+	case errors.Is(err, recon.ErrItemNotFound):
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Item rekonsiliasi tidak ditemukan"})
+	case errors.Is(err, recon.ErrTransaksiNotFound):
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Transaksi tabung tidak ditemukan dalam mata uang yang sama"})
+	case errors.Is(err, recon.ErrNotLinkable):
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Hanya item missing_internal yang dapat dipasangkan dengan transaksi"})
+	case errors.Is(err, recon.ErrNotException):
+		return c.JSON(http.StatusConflict, model.ErrorResponse{Remark: "Item sudah cocok"})
+	case errors.Is(err, recon.ErrAlreadyResolved):
+		return c.JSON(http.StatusConflict, model.ErrorResponse{Remark: "Item sudah diselesaikan"})
+	case errors.Is(err, recon.ErrTransaksiClaimed):
+		return c.JSON(http.StatusConflict, model.ErrorResponse{Remark: "Transaksi sudah dipasangkan dengan entri settlement lain"})
+	}
+	logger(c).Error(msg, "error", err)
+	return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+}
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"errors"
+	"gobanking/account"
+	"gobanking/model"
+	"gobanking/reversal"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// ReversalHandler lets bank staff undo a posted tabung or tarik; large
+// reversals wait for an admin's approval.
+type ReversalHandler struct {
+	reversals *reversal.Service
+	validate  *validator.Validate
+}
+
+func NewReversalHandler(service *reversal.Service) *ReversalHandler {
+	return &ReversalHandler{
+		reversals: service,
+		validate:  validator.New(),
+	}
+}
+
+// @Summary Request reversal
+// @Description Undo a `tabung` or `tarik` by booking an equal and opposite `koreksi_debit` or `koreksi_kredit` entry linked to it by `reversal_of_id`. A transaction is reversed at most once; transfers cannot be reversed. Reversals worth more than REVERSAL_APPROVAL_THRESHOLD in IDR, and any with `allow_overdraft`, return 202 and wait for another admin's approval. Reversing a deposit that has already been spent is rejected unless `allow_overdraft` is set, in which case the balance goes negative and the account is flagged as overdrawn until it is paid back. Staff and admin only.
+// @Tags reversal
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ReversalRequest true "Transaction to reverse"
+// @Success 201 {object} model.ReversalResponse
+// @Success 202 {object} model.ReversalResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 422 {object} model.ErrorResponse
+// @Router /reversals [post]
+func (h *ReversalHandler) Create(c echo.Context) error {
+	var req model.ReversalRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		logger(c).Warn("gagal validasi", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Transaksi ID dan alasan harus diisi"})
+	}
+
+	rev, err := h.reversals.Request(c.Request().Context(), currentUserID(c), req)
+	if err != nil {
+		return h.fail(c, err, "gagal membuat koreksi")
+	}
+
+	logger(c).Info("koreksi diajukan",
+		"reversal_id", rev.ID,
+		"transaksi_id", rev.TransaksiID,
+		"no_rekening", rev.NoRekening,
+		"status", rev.Status,
+	)
+	if rev.Status == model.ReversalPending {
+		return c.JSON(http.StatusAccepted, reversalResponse(rev))
+	}
+	return c.JSON(http.StatusCreated, reversalResponse(rev))
+}
+
+// @Summary List reversals
+// @Description The latest 100 reversal requests, newest first. Staff and admin only.
+// @Tags reversal
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending, completed or rejected"
+// @Success 200 {array} model.ReversalResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /reversals [get]
+func (h *ReversalHandler) List(c echo.Context) error {
+	reversals, err := h.reversals.List(c.Request().Context(), c.QueryParam("status"))
+	if err != nil {
+		logger(c).Error("gagal mengambil koreksi", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	resp := make([]model.ReversalResponse, len(reversals))
+	for i, rev := range reversals {
+		resp[i] = reversalResponse(rev)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Get reversal
+// @Tags reversal
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reversal ID"
+// @Success 200 {object} model.ReversalResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /reversals/{id} [get]
+func (h *ReversalHandler) Get(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Permintaan koreksi tidak ditemukan"})
+	}
+
+	rev, err := h.reversals.Get(c.Request().Context(), uint(id))
+	if err != nil {
+		return h.fail(c, err, "gagal mengambil koreksi")
+	}
+	return c.JSON(http.StatusOK, reversalResponse(rev))
+}
+
+// @Summary Approve reversal
+// @Description Post a pending reversal. The approver must not be the requester. If the reversal can no longer be posted, e.g. the deposit has been spent and `allow_overdraft` is not set, the request is rejected and the error returned. Admin only.
+// @Tags reversal
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reversal ID"
+// @Success 200 {object} model.ReversalResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Failure 422 {object} model.ErrorResponse
+// @Router /reversals/{id}/approve [post]
+func (h *ReversalHandler) Approve(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Permintaan koreksi tidak ditemukan"})
+	}
+
+	rev, err := h.reversals.Approve(c.Request().Context(), currentUserID(c), uint(id))
+	if err != nil {
+		return h.fail(c, err, "gagal menyetujui koreksi")
+	}
+
+	logger(c).Info("koreksi disetujui",
+		"reversal_id", rev.ID,
+		"transaksi_id", rev.TransaksiID,
+		"koreksi_id", rev.KoreksiID,
+		"overdraft", rev.Overdraft,
+	)
+	return c.JSON(http.StatusOK, reversalResponse(rev))
+}
+
+// @Summary Reject reversal
+// @Description Close a pending reversal without posting it. Admin only.
+// @Tags reversal
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reversal ID"
+// @Param request body model.ReversalDecisionRequest false "Reason"
+// @Success 200 {object} model.ReversalResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /reversals/{id}/reject [post]
+func (h *ReversalHandler) Reject(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Permintaan koreksi tidak ditemukan"})
+	}
+
+	var req model.ReversalDecisionRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+	if err := h.validate.Struct(req); err != nil {
+		logger(c).Warn("gagal validasi", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Alasan terlalu panjang"})
+	}
+
+	rev, err := h.reversals.Reject(c.Request().Context(), currentUserID(c), uint(id), req.Alasan)
+	if err != nil {
+		return h.fail(c, err, "gagal menolak koreksi")
+	}
+
+	logger(c).Info("koreksi ditolak", "reversal_id", rev.ID, "transaksi_id", rev.TransaksiID)
+	return c.JSON(http.StatusOK, reversalResponse(rev))
+}
+
+// fail maps a reversal.Service error to a response; msg is logged for
+// unexpected errors.
+func (h *ReversalHandler) fail(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, reversal.ErrReversalNotFound):
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Permintaan koreksi tidak ditemukan"})
+	case errors.Is(err, account.ErrTransaksiNotFound):
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Transaksi tidak ditemukan"})
+	case errors.Is(err, account.ErrAccountNotFound):
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "No Rekening tidak ditemukan"})
+	case errors.Is(err, account.ErrNotReversible):
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Hanya transaksi tabung dan tarik yang dapat dikoreksi"})
+	case errors.Is(err, account.ErrAlreadyReversed):
+		return c.JSON(http.StatusConflict, model.ErrorResponse{Remark: "Transaksi sudah dikoreksi"})
+	case errors.Is(err, reversal.ErrPendingExists):
+		return c.JSON(http.StatusConflict, model.ErrorResponse{Remark: "Transaksi sudah memiliki permintaan koreksi yang menunggu persetujuan"})
+	case errors.Is(err, reversal.ErrNotPending):
+		return c.JSON(http.StatusConflict, model.ErrorResponse{Remark: "Permintaan koreksi sudah diputuskan"})
+	case errors.Is(err, reversal.ErrSelfApproval):
+		return c.JSON(http.StatusForbidden, model.ErrorResponse{Remark: "Permintaan koreksi harus disetujui oleh admin lain"})
+	case errors.Is(err, account.ErrInsufficientFunds):
+		return c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Remark: "Saldo tidak mencukupi untuk koreksi; ajukan ulang dengan allow_overdraft"})
+	}
+	logger(c).Error(msg, "error", err)
+	return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+}
+
+func reversalResponse(rev model.Reversal) model.ReversalResponse {
+	return model.ReversalResponse{
+		ID:             rev.ID,
+		TransaksiID:    rev.TransaksiID,
+		NoRekening:     rev.NoRekening,
+		Jenis:          rev.Jenis,
+		Nominal:        rev.Nominal,
+		MataUang:       rev.MataUang,
+		Alasan:         rev.Alasan,
+		AllowOverdraft: rev.AllowOverdraft,
+		Status:         rev.Status,
+		RequestedBy:    rev.RequestedBy,
+		DecidedBy:      rev.DecidedBy,
+		DecidedAt:      rev.DecidedAt,
+		KoreksiID:      rev.KoreksiID,
+		Overdraft:      rev.Overdraft,
+		Error:          rev.Error,
+		CreatedAt:      rev.CreatedAt,
+	}
+}
@@ -0,0 +1,354 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/model"
+	"gobanking/otp"
+	"gobanking/schedule"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// StandingOrderHandler manages scheduled transfers; schedule.Executor
+// carries them out in the background.
+type StandingOrderHandler struct {
+	orders   *schedule.Service
+	accounts *account.Service
+	cfg      *config.Config
+	validate *validator.Validate
+	otp      *OTPHandler
+}
+
+func NewStandingOrderHandler(service *schedule.Service, accounts *account.Service, cfg *config.Config, otpHandler *OTPHandler) *StandingOrderHandler {
+	h := &StandingOrderHandler{
+		orders:   service,
+		accounts: accounts,
+		cfg:      cfg,
+		validate: validator.New(),
+		otp:      otpHandler,
+	}
+
+	otpHandler.handle(otp.PurposeBuatStanding, h.executeCreate)
+	otpHandler.handle(otp.PurposeUbahStanding, h.executeUpdate)
+
+	return h
+}
+
+// standingOrderUpdate is an update held back behind an OTP challenge.
+type standingOrderUpdate struct {
+	ID      uint                       `json:"id"`
+	Request model.StandingOrderRequest `json:"request"`
+}
+
+// @Summary Create standing order
+// @Description Schedule a transfer once at `start_at`, monthly on `day_of_month` (at 00:00, on the last day of shorter months) or on a five-field `cron` rule whose minute and hour are single values. Times are evaluated in SCHEDULE_TIMEZONE. `nominal` is in the source account's currency. An occurrence that fails for lack of funds, a frozen account or a missing FX rate is retried SCHEDULE_RETRY_DAYS times, SCHEDULE_RETRY_INTERVAL apart (daily by default), before it is given up and the customer notified. The destination must be a saved beneficiary of the source account and is checked again before every run. Orders whose `nominal` has an IDR value above the OTP threshold, or cannot be valued for want of an FX rate, answer 202 with an OTP challenge and are created by POST /otp/verify.
+// @Tags standing-order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.StandingOrderRequest true "Transfer and schedule"
+// @Success 201 {object} model.StandingOrderResponse
+// @Success 202 {object} model.OTPChallengeResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /standing-orders [post]
+func (h *StandingOrderHandler) Create(c echo.Context) error {
+	req, errResp := h.bindRequest(c)
+	if errResp != nil {
+		return c.JSON(http.StatusBadRequest, errResp)
+	}
+
+	if err := h.orders.Check(c.Request().Context(), req); err != nil {
+		return h.fail(c, err, "gagal memeriksa standing order")
+	}
+	if held, err := h.stepUp(c, req, otp.PurposeBuatStanding, req); held {
+		return err
+	}
+	return h.create(c, req)
+}
+
+func (h *StandingOrderHandler) executeCreate(c echo.Context, payload []byte) error {
+	var req model.StandingOrderRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		logger(c).Error("payload OTP tidak valid", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return h.create(c, req)
+}
+
+func (h *StandingOrderHandler) create(c echo.Context, req model.StandingOrderRequest) error {
+	order, err := h.orders.Create(c.Request().Context(), currentUserID(c), req)
+	if err != nil {
+		return h.fail(c, err, "gagal membuat standing order")
+	}
+
+	logger(c).Info("standing order dibuat",
+		"standing_order_id", order.ID,
+		"no_rekening", order.NoRekening,
+		"schedule", order.Schedule,
+		"next_run_at", order.NextRunAt,
+	)
+	return c.JSON(http.StatusCreated, standingOrderResponse(order))
+}
+
+// @Summary List standing orders
+// @Tags standing-order
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.StandingOrderResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /standing-orders [get]
+func (h *StandingOrderHandler) List(c echo.Context) error {
+	orders, err := h.orders.List(c.Request().Context(), currentUserID(c))
+	if err != nil {
+		logger(c).Error("gagal mengambil standing order", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	resp := make([]model.StandingOrderResponse, len(orders))
+	for i, order := range orders {
+		resp[i] = standingOrderResponse(order)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Get standing order
+// @Tags standing-order
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Standing order ID"
+// @Success 200 {object} model.StandingOrderResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /standing-orders/{id} [get]
+func (h *StandingOrderHandler) Get(c echo.Context) error {
+	order, err := h.findOrder(c)
+	if err != nil {
+		return h.fail(c, err, "gagal mengambil standing order")
+	}
+	return c.JSON(http.StatusOK, standingOrderResponse(order))
+}
+
+// @Summary Update standing order
+// @Description Replace the transfer and schedule of an active order. The next run is recomputed and pending retries are dropped. The same beneficiary rule and OTP step-up as POST /standing-orders apply.
+// @Tags standing-order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Standing order ID"
+// @Param request body model.StandingOrderRequest true "Transfer and schedule"
+// @Success 200 {object} model.StandingOrderResponse
+// @Success 202 {object} model.OTPChallengeResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /standing-orders/{id} [put]
+func (h *StandingOrderHandler) Update(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Standing order tidak ditemukan"})
+	}
+
+	req, errResp := h.bindRequest(c)
+	if errResp != nil {
+		return c.JSON(http.StatusBadRequest, errResp)
+	}
+
+	order, err := h.orders.Order(c.Request().Context(), currentUserID(c), uint(id))
+	if err != nil {
+		return h.fail(c, err, "gagal mengambil standing order")
+	}
+	if order.Status != model.StandingOrderActive {
+		return h.fail(c, schedule.ErrOrderClosed, "")
+	}
+	if err := h.orders.Check(c.Request().Context(), req); err != nil {
+		return h.fail(c, err, "gagal memeriksa standing order")
+	}
+	if held, err := h.stepUp(c, req, otp.PurposeUbahStanding, standingOrderUpdate{ID: order.ID, Request: req}); held {
+		return err
+	}
+	return h.update(c, order.ID, req)
+}
+
+func (h *StandingOrderHandler) executeUpdate(c echo.Context, payload []byte) error {
+	var req standingOrderUpdate
+	if err := json.Unmarshal(payload, &req); err != nil {
+		logger(c).Error("payload OTP tidak valid", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	return h.update(c, req.ID, req.Request)
+}
+
+func (h *StandingOrderHandler) update(c echo.Context, id uint, req model.StandingOrderRequest) error {
+	order, err := h.orders.Update(c.Request().Context(), currentUserID(c), id, req)
+	if err != nil {
+		return h.fail(c, err, "gagal memperbarui standing order")
+	}
+
+	logger(c).Info("standing order diperbarui",
+		"standing_order_id", order.ID,
+		"next_run_at", order.NextRunAt,
+	)
+	return c.JSON(http.StatusOK, standingOrderResponse(order))
+}
+
+// @Summary Cancel standing order
+// @Description Stop an active order. Its run history is kept.
+// @Tags standing-order
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Standing order ID"
+// @Success 200 {object} model.StandingOrderResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /standing-orders/{id} [delete]
+func (h *StandingOrderHandler) Cancel(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Standing order tidak ditemukan"})
+	}
+
+	order, err := h.orders.Cancel(c.Request().Context(), currentUserID(c), uint(id))
+	if err != nil {
+		return h.fail(c, err, "gagal membatalkan standing order")
+	}
+
+	logger(c).Info("standing order dibatalkan", "standing_order_id", order.ID)
+	return c.JSON(http.StatusOK, standingOrderResponse(order))
+}
+
+// @Summary List standing order runs
+// @Description Execution attempts of an order, newest first. A run with status `retry` is followed by another attempt at the same `scheduled_at`.
+// @Tags standing-order
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Standing order ID"
+// @Success 200 {array} model.StandingOrderRunResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /standing-orders/{id}/runs [get]
+func (h *StandingOrderHandler) Runs(c echo.Context) error {
+	order, err := h.findOrder(c)
+	if err != nil {
+		return h.fail(c, err, "gagal mengambil standing order")
+	}
+
+	runs, err := h.orders.Runs(c.Request().Context(), order.ID)
+	if err != nil {
+		logger(c).Error("gagal mengambil riwayat standing order", "standing_order_id", order.ID, "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	resp := make([]model.StandingOrderRunResponse, len(runs))
+	for i, run := range runs {
+		resp[i] = model.StandingOrderRunResponse{
+			ID:          run.ID,
+			ScheduledAt: run.ScheduledAt,
+			Attempt:     run.Attempt,
+			Status:      run.Status,
+			Error:       run.Error,
+			TransaksiID: run.TransaksiID,
+			CreatedAt:   run.CreatedAt,
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *StandingOrderHandler) bindRequest(c echo.Context) (model.StandingOrderRequest, *model.ErrorResponse) {
+	var req model.StandingOrderRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return req, &model.ErrorResponse{Remark: "Format request salah"}
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		logger(c).Warn("gagal validasi", "error", err)
+		return req, &model.ErrorResponse{Remark: "No rekening, no rekening tujuan, nominal dan schedule harus diisi dengan benar"}
+	}
+	return req, nil
+}
+
+// stepUp answers with an OTP challenge, sent to the source account's holder,
+// when the order's nominal is worth more than a withdrawal may be without
+// one. held reports whether a response has been written.
+func (h *StandingOrderHandler) stepUp(c echo.Context, req model.StandingOrderRequest, purpose string, payload any) (held bool, err error) {
+	nasabah, err := h.accounts.Account(c.Request().Context(), req.NoRekening)
+	if err != nil {
+		return true, h.fail(c, err, "gagal mengambil nasabah")
+	}
+
+	above, err := h.accounts.ExceedsIDR(c.Request().Context(), nasabah.MataUang, req.Nominal, h.cfg.OTP.TarikThreshold)
+	if err != nil {
+		logger(c).Error("gagal menilai nominal dalam IDR", "error", err)
+		return true, c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	if !above {
+		return false, nil
+	}
+	return true, h.otp.challenge(c, purpose, payload, recipient(nasabah))
+}
+
+// findOrder loads the order in the path if the caller owns it.
+func (h *StandingOrderHandler) findOrder(c echo.Context) (model.StandingOrder, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return model.StandingOrder{}, schedule.ErrOrderNotFound
+	}
+	return h.orders.Order(c.Request().Context(), currentUserID(c), uint(id))
+}
+
+// fail maps a schedule.Service error to a response; msg is logged for
+// unexpected errors.
+func (h *StandingOrderHandler) fail(c echo.Context, err error, msg string) error {
+	switch {
+	case errors.Is(err, schedule.ErrOrderNotFound):
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Standing order tidak ditemukan"})
+	case errors.Is(err, schedule.ErrOrderClosed):
+		return c.JSON(http.StatusConflict, model.ErrorResponse{Remark: "Standing order sudah tidak aktif"})
+	case errors.Is(err, schedule.ErrInvalidSchedule):
+		// The wrapped detail names the offending field and is safe to show.
+		logger(c).Warn("jadwal ditolak", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: err.Error()})
+	case errors.Is(err, account.ErrAccountNotFound):
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "No Rekening tidak ditemukan"})
+	case errors.Is(err, account.ErrBeneficiaryNotFound):
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "No Rekening tujuan tidak ditemukan"})
+	case errors.Is(err, account.ErrNotBeneficiary):
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "No Rekening tujuan belum terdaftar sebagai penerima"})
+	case errors.Is(err, account.ErrAccountFrozen):
+		return c.JSON(http.StatusForbidden, model.ErrorResponse{Remark: "Rekening dibekukan"})
+	}
+	logger(c).Error(msg, "error", err)
+	return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+}
+
+func standingOrderResponse(order model.StandingOrder) model.StandingOrderResponse {
+	return model.StandingOrderResponse{
+		ID:               order.ID,
+		NoRekening:       order.NoRekening,
+		NoRekeningTujuan: order.NoRekeningTujuan,
+		Nominal:          order.Nominal,
+		MataUang:         order.MataUang,
+		Keterangan:       order.Keterangan,
+		Schedule:         order.Schedule,
+		DayOfMonth:       order.DayOfMonth,
+		Cron:             order.Cron,
+		StartAt:          order.StartAt,
+		EndAt:            order.EndAt,
+		Status:           order.Status,
+		NextRunAt:        order.NextRunAt,
+		Attempts:         order.Attempts,
+		CreatedAt:        order.CreatedAt,
+	}
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"gobanking/model"
+	"gobanking/otp"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const backupCodeCount = 10
+
+// @Summary Start 2FA enrolment
+// @Description Generate a new TOTP secret and otpauth URI. 2FA is enabled once a code is confirmed at /2fa/verify.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.TwoFactorEnrolResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /2fa/enrol [post]
+func (h *AuthHandler) EnrolTwoFactor(c echo.Context) error {
+	user, err := h.users.FindByID(c.Request().Context(), currentUserID(c))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, model.ErrorResponse{Remark: "Invalid token"})
+	}
+
+	if user.TOTPEnabled {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "2FA sudah aktif"})
+	}
+
+	secret, err := otp.NewSecret()
+	if err != nil {
+		logger(c).Error("gagal membuat secret TOTP", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	if err := h.users.SetTOTPSecret(c.Request().Context(), user.ID, secret); err != nil {
+		logger(c).Error("gagal menyimpan secret TOTP", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("enrolmen 2FA dimulai", "email", user.Email)
+	return c.JSON(http.StatusOK, model.TwoFactorEnrolResponse{
+		Secret:     secret,
+		OtpauthURI: otp.URI(h.cfg.MFA.Issuer, user.Email, secret),
+	})
+}
+
+// @Summary Confirm 2FA enrolment
+// @Description Confirm the authenticator with a TOTP code. Returns one-time backup codes, and a JWT when called with an enrolment token.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.TwoFactorCodeRequest true "TOTP code"
+// @Success 200 {object} model.TwoFactorEnabledResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /2fa/verify [post]
+func (h *AuthHandler) VerifyTwoFactor(c echo.Context) error {
+	var req model.TwoFactorCodeRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+
+	user, err := h.users.FindByID(c.Request().Context(), currentUserID(c))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, model.ErrorResponse{Remark: "Invalid token"})
+	}
+
+	if user.TOTPEnabled {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "2FA sudah aktif"})
+	}
+	if user.TOTPSecret == "" {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Enrolmen 2FA belum dimulai"})
+	}
+	if !otp.ValidateTOTP(user.TOTPSecret, req.Code, time.Now()) {
+		logger(c).Info("gagal aktivasi 2FA: kode salah", "email", user.Email)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Kode 2FA salah"})
+	}
+
+	codes, hashes, err := generateBackupCodes()
+	if err != nil {
+		logger(c).Error("gagal membuat backup code", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	if err := h.users.EnableTOTP(c.Request().Context(), user.ID, hashes); err != nil {
+		logger(c).Error("gagal mengaktifkan 2FA", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	resp := model.TwoFactorEnabledResponse{BackupCodes: codes}
+	if c.Get("token_purpose") == model.TokenPurposeMFAEnrol {
+		resp.Token, err = h.issueToken(user)
+		if err != nil {
+			logger(c).Error("gagal generate token", "error", err)
+			return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+		}
+	}
+
+	logger(c).Info("2FA diaktifkan", "email", user.Email)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Disable 2FA
+// @Description Turn off 2FA with a current TOTP or backup code. Not allowed for roles that require 2FA by policy.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.TwoFactorCodeRequest true "TOTP or backup code"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /2fa/disable [post]
+func (h *AuthHandler) DisableTwoFactor(c echo.Context) error {
+	var req model.TwoFactorCodeRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Format request salah"})
+	}
+
+	user, err := h.users.FindByID(c.Request().Context(), currentUserID(c))
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, model.ErrorResponse{Remark: "Invalid token"})
+	}
+
+	if h.cfg.MFA.Required(user.Role) {
+		return c.JSON(http.StatusForbidden, model.ErrorResponse{Remark: "2FA wajib untuk role ini"})
+	}
+	if !user.TOTPEnabled {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "2FA belum aktif"})
+	}
+
+	ok, err := h.checkSecondFactor(c.Request().Context(), user, req.Code)
+	if err != nil {
+		logger(c).Error("gagal memeriksa kode 2FA", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+	if !ok {
+		return c.JSON(http.StatusBadRequest, model.ErrorResponse{Remark: "Kode 2FA salah"})
+	}
+
+	if err := h.users.DisableTOTP(c.Request().Context(), user.ID); err != nil {
+		logger(c).Error("gagal menonaktifkan 2FA", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("2FA dinonaktifkan", "email", user.Email)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// checkSecondFactor accepts a current TOTP code or an unused backup code,
+// consuming the backup code on success.
+func (h *AuthHandler) checkSecondFactor(ctx context.Context, user model.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if otp.ValidateTOTP(user.TOTPSecret, code, time.Now()) {
+		return true, nil
+	}
+
+	backups, err := h.users.UnusedBackupCodes(ctx, user.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, backup := range backups {
+		if bcrypt.CompareHashAndPassword([]byte(backup.CodeHash), []byte(strings.ToLower(code))) != nil {
+			continue
+		}
+		// A concurrent login may have consumed it first.
+		return h.users.UseBackupCode(ctx, backup.ID)
+	}
+	return false, nil
+}
+
+// generateBackupCodes returns codes in xxxxx-xxxxx form and their bcrypt
+// hashes.
+func generateBackupCodes() ([]string, []string, error) {
+	codes := make([]string, backupCodeCount)
+	hashes := make([]string, backupCodeCount)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := hex.EncodeToString(b)
+		codes[i] = raw[:5] + "-" + raw[5:]
+
+		hash, err := bcrypt.GenerateFromPassword([]byte(codes[i]), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, nil, err
+		}
+		hashes[i] = string(hash)
+	}
+	return codes, hashes, nil
+}
@@ -0,0 +1,320 @@
+package handler
+
+import (
+	"errors"
+	"gobanking/config"
+	"gobanking/model"
+	"gobanking/webhook"
+	"net/http"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type WebhookHandler struct {
+	db         *gorm.DB
+	cfg        *config.Config
+	dispatcher *webhook.Dispatcher
+	validate   *validator.Validate
+}
+
+func NewWebhookHandler(db *gorm.DB, cfg *config.Config, dispatcher *webhook.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{
+		db:         db,
+		cfg:        cfg,
+		dispatcher: dispatcher,
+		validate:   validator.New(),
+	}
+}
+
+// @Summary Create webhook subscription
+// @Description Subscribe a URL to account events. The signing secret is only returned once. Leaving no_rekening empty subscribes to every account and is admin only. The URL must not point at a loopback, private or link-local address.
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.WebhookRequest true "Subscription details"
+// @Success 201 {object} model.WebhookResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /webhooks [post]
+func (h *WebhookHandler) Create(c echo.Context) error {
+	req, status, errResp := h.bindRequest(c)
+	if errResp != nil {
+		return c.JSON(status, errResp)
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		logger(c).Error("gagal membuat secret webhook", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	sub := model.WebhookSubscription{
+		UserID:     currentUserID(c),
+		URL:        req.URL,
+		Events:     webhook.JoinEvents(req.Events),
+		NoRekening: req.NoRekening,
+		Secret:     secret,
+		Aktif:      req.Aktif == nil || *req.Aktif,
+	}
+	if err := h.db.Create(&sub).Error; err != nil {
+		logger(c).Error("gagal membuat langganan webhook", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("langganan webhook dibuat",
+		"subscription_id", sub.ID,
+		"events", sub.Events,
+	)
+
+	resp := webhookResponse(sub)
+	resp.Secret = sub.Secret
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// @Summary List webhook subscriptions
+// @Tags webhook
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} model.WebhookResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /webhooks [get]
+func (h *WebhookHandler) List(c echo.Context) error {
+	var subs []model.WebhookSubscription
+	if err := h.db.Where("user_id = ?", currentUserID(c)).Order("id").Find(&subs).Error; err != nil {
+		logger(c).Error("gagal mengambil langganan webhook", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	resp := make([]model.WebhookResponse, len(subs))
+	for i, sub := range subs {
+		resp[i] = webhookResponse(sub)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Get webhook subscription
+// @Tags webhook
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} model.WebhookResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) Get(c echo.Context) error {
+	sub, ok := h.findSubscription(c)
+	if !ok {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Webhook tidak ditemukan"})
+	}
+	return c.JSON(http.StatusOK, webhookResponse(sub))
+}
+
+// @Summary Update webhook subscription
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Param request body model.WebhookRequest true "Subscription details"
+// @Success 200 {object} model.WebhookResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) Update(c echo.Context) error {
+	sub, ok := h.findSubscription(c)
+	if !ok {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Webhook tidak ditemukan"})
+	}
+
+	req, status, errResp := h.bindRequest(c)
+	if errResp != nil {
+		return c.JSON(status, errResp)
+	}
+
+	sub.URL = req.URL
+	sub.Events = webhook.JoinEvents(req.Events)
+	sub.NoRekening = req.NoRekening
+	if req.Aktif != nil {
+		sub.Aktif = *req.Aktif
+	}
+	if err := h.db.Save(&sub).Error; err != nil {
+		logger(c).Error("gagal memperbarui langganan webhook", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("langganan webhook diperbarui", "subscription_id", sub.ID)
+	return c.JSON(http.StatusOK, webhookResponse(sub))
+}
+
+// @Summary Delete webhook subscription
+// @Tags webhook
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Success 204
+// @Failure 404 {object} model.ErrorResponse
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c echo.Context) error {
+	sub, ok := h.findSubscription(c)
+	if !ok {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Webhook tidak ditemukan"})
+	}
+
+	if err := h.db.Delete(&sub).Error; err != nil {
+		logger(c).Error("gagal menghapus langganan webhook", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("langganan webhook dihapus", "subscription_id", sub.ID)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary List webhook deliveries
+// @Description Delivery log for a subscription, newest first. Filter by status to find dead-lettered deliveries.
+// @Tags webhook
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Param status query string false "pending, retrying, success or dead"
+// @Success 200 {array} model.WebhookDeliveryResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) Deliveries(c echo.Context) error {
+	sub, ok := h.findSubscription(c)
+	if !ok {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Webhook tidak ditemukan"})
+	}
+
+	query := h.db.Where("subscription_id = ?", sub.ID)
+	if status := c.QueryParam("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var deliveries []model.WebhookDelivery
+	if err := query.Order("id DESC").Limit(100).Find(&deliveries).Error; err != nil {
+		logger(c).Error("gagal mengambil log webhook", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	resp := make([]model.WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		resp[i] = webhookDeliveryResponse(delivery)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Replay webhook delivery
+// @Description Queue a new delivery with the same payload, e.g. to recover a dead-lettered event.
+// @Tags webhook
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 202 {object} model.WebhookDeliveryResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /webhooks/{id}/deliveries/{delivery_id}/replay [post]
+func (h *WebhookHandler) Replay(c echo.Context) error {
+	sub, ok := h.findSubscription(c)
+	if !ok {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Webhook tidak ditemukan"})
+	}
+
+	var original model.WebhookDelivery
+	err := h.db.Where("id = ? AND subscription_id = ?", c.Param("delivery_id"), sub.ID).First(&original).Error
+	if err != nil {
+		return c.JSON(http.StatusNotFound, model.ErrorResponse{Remark: "Delivery tidak ditemukan"})
+	}
+
+	delivery, err := h.dispatcher.Replay(original)
+	if err != nil {
+		logger(c).Error("gagal replay webhook", "error", err)
+		return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+	}
+
+	logger(c).Info("webhook di-replay",
+		"subscription_id", sub.ID,
+		"delivery_id", original.ID,
+		"replay_id", delivery.ID,
+	)
+	return c.JSON(http.StatusAccepted, webhookDeliveryResponse(delivery))
+}
+
+func (h *WebhookHandler) bindRequest(c echo.Context) (model.WebhookRequest, int, *model.ErrorResponse) {
+	var req model.WebhookRequest
+	if err := c.Bind(&req); err != nil {
+		logger(c).Warn("format request salah", "error", err)
+		return req, http.StatusBadRequest, &model.ErrorResponse{Remark: "Format request salah"}
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		logger(c).Warn("gagal validasi", "error", err)
+		return req, http.StatusBadRequest, &model.ErrorResponse{Remark: "URL dan events harus diisi dengan benar"}
+	}
+
+	for _, event := range req.Events {
+		if !webhook.ValidEvent(event) {
+			logger(c).Warn("event webhook tidak dikenal", "event", event)
+			return req, http.StatusBadRequest, &model.ErrorResponse{Remark: "Event tidak dikenal: " + event}
+		}
+	}
+
+	// Events carry balances, so only admins may follow every account.
+	if req.NoRekening == "" && currentRole(c) != model.RoleAdmin {
+		logger(c).Warn("langganan webhook semua rekening ditolak")
+		return req, http.StatusForbidden, &model.ErrorResponse{Remark: "Hanya admin yang dapat berlangganan semua rekening"}
+	}
+
+	if !h.cfg.Webhook.AllowPrivateTargets {
+		err := webhook.CheckTarget(c.Request().Context(), req.URL)
+		if errors.Is(err, webhook.ErrTargetNotAllowed) {
+			logger(c).Warn("URL webhook ditolak", "error", err)
+			return req, http.StatusBadRequest, &model.ErrorResponse{Remark: "URL webhook tidak boleh mengarah ke alamat internal"}
+		}
+		if err != nil {
+			logger(c).Warn("URL webhook ditolak", "error", err)
+			return req, http.StatusBadRequest, &model.ErrorResponse{Remark: "Host URL webhook tidak dapat di-resolve"}
+		}
+	}
+
+	return req, http.StatusOK, nil
+}
+
+func (h *WebhookHandler) findSubscription(c echo.Context) (model.WebhookSubscription, bool) {
+	var sub model.WebhookSubscription
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return sub, false
+	}
+	err = h.db.Where("id = ? AND user_id = ?", id, currentUserID(c)).First(&sub).Error
+	return sub, err == nil
+}
+
+func webhookResponse(sub model.WebhookSubscription) model.WebhookResponse {
+	return model.WebhookResponse{
+		ID:         sub.ID,
+		URL:        sub.URL,
+		Events:     webhook.SplitEvents(sub.Events),
+		NoRekening: sub.NoRekening,
+		Aktif:      sub.Aktif,
+		CreatedAt:  sub.CreatedAt,
+	}
+}
+
+func webhookDeliveryResponse(delivery model.WebhookDelivery) model.WebhookDeliveryResponse {
+	return model.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		Event:          delivery.Event,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		ReplayOf:       delivery.ReplayOf,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
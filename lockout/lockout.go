@@ -0,0 +1,184 @@
+// Package lockout tracks failed logins per email and per client IP,
+// imposing progressive delays and temporary lockouts.
+package lockout
+
+import (
+	"context"
+	"errors"
+	"gobanking/config"
+	"gobanking/logging"
+	"gobanking/model"
+	"gobanking/notifier"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Guard struct {
+	db       *gorm.DB
+	cfg      *config.Config
+	notifier notifier.Notifier
+}
+
+func NewGuard(db *gorm.DB, cfg *config.Config, n notifier.Notifier) *Guard {
+	return &Guard{
+		db:       db,
+		cfg:      cfg,
+		notifier: n,
+	}
+}
+
+func EmailKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check returns how long the caller must wait before another attempt for
+// email from ip is allowed. Zero means the attempt may proceed.
+func (g *Guard) Check(email, ip string) (time.Duration, error) {
+	var throttles []model.LoginThrottle
+	err := g.db.Where("key IN ?", []string{EmailKey(email), IPKey(ip)}).Find(&throttles).Error
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var wait time.Duration
+	for _, t := range throttles {
+		if d := g.waitFor(t, now); d > wait {
+			wait = d
+		}
+	}
+	return wait, nil
+}
+
+// Failure records a failed attempt against both keys. user is nil when the
+// email is unknown; a lockout of a real account is reported to its owner.
+func (g *Guard) Failure(ctx context.Context, email, ip string, user *model.User) error {
+	emailThrottle, err := g.record(EmailKey(email), g.cfg.Lockout.MaxFailures)
+	if err != nil {
+		return err
+	}
+	if _, err := g.record(IPKey(ip), g.cfg.Lockout.MaxIPFailures); err != nil {
+		return err
+	}
+
+	if user != nil && emailThrottle.Failures == g.cfg.Lockout.MaxFailures && emailThrottle.LockedUntil != nil {
+		logging.FromContext(ctx).Warn("akun dikunci sementara",
+			"email", user.Email,
+			"failures", emailThrottle.Failures,
+			"locked_until", *emailThrottle.LockedUntil,
+		)
+		g.notifyLockout(ctx, *user, emailThrottle)
+	}
+	return nil
+}
+
+// Success clears the email counter. The IP counter is left to expire so a
+// valid login cannot be used to reset an attacker's IP budget.
+func (g *Guard) Success(email string) error {
+	return g.db.Unscoped().Where("key = ?", EmailKey(email)).Delete(&model.LoginThrottle{}).Error
+}
+
+// Unlock removes throttling state for the given email and/or IP and
+// returns the number of cleared entries.
+func (g *Guard) Unlock(email, ip string) (int64, error) {
+	var keys []string
+	if email != "" {
+		keys = append(keys, EmailKey(email))
+	}
+	if ip != "" {
+		keys = append(keys, IPKey(ip))
+	}
+	if len(keys) == 0 {
+		return 0, errors.New("email atau ip harus diisi")
+	}
+
+	result := g.db.Unscoped().Where("key IN ?", keys).Delete(&model.LoginThrottle{})
+	return result.RowsAffected, result.Error
+}
+
+func (g *Guard) record(key string, maxFailures int) (model.LoginThrottle, error) {
+	var t model.LoginThrottle
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&t).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			t = model.LoginThrottle{Key: key}
+		} else if err != nil {
+			return err
+		}
+
+		// Failures outside the window no longer count.
+		if t.ID != 0 && now.Sub(t.LastFailureAt) > g.cfg.Lockout.Window && !g.locked(t, now) {
+			t.Failures = 0
+			t.LockedUntil = nil
+		}
+
+		t.Failures++
+		t.LastFailureAt = now
+		if t.Failures >= maxFailures && !g.locked(t, now) {
+			until := now.Add(g.cfg.Lockout.Duration)
+			t.LockedUntil = &until
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"failures", "last_failure_at", "locked_until", "updated_at"}),
+		}).Save(&t).Error
+	})
+	return t, err
+}
+
+func (g *Guard) waitFor(t model.LoginThrottle, now time.Time) time.Duration {
+	if g.locked(t, now) {
+		return t.LockedUntil.Sub(now)
+	}
+	if t.Failures == 0 || now.Sub(t.LastFailureAt) > g.cfg.Lockout.Window {
+		return 0
+	}
+
+	if d := t.LastFailureAt.Add(g.delay(t.Failures)).Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
+
+func (g *Guard) locked(t model.LoginThrottle, now time.Time) bool {
+	return t.LockedUntil != nil && t.LockedUntil.After(now)
+}
+
+// delay doubles BaseDelay for every failure after the first.
+func (g *Guard) delay(failures int) time.Duration {
+	d := g.cfg.Lockout.BaseDelay
+	for i := 1; i < failures; i++ {
+		d *= 2
+		if d >= g.cfg.Lockout.MaxDelay {
+			return g.cfg.Lockout.MaxDelay
+		}
+	}
+	return d
+}
+
+func (g *Guard) notifyLockout(ctx context.Context, user model.User, t model.LoginThrottle) {
+	subject, body, err := notifier.Render(notifier.TemplateLockout, notifier.LangID, map[string]any{
+		"Email":     user.Email,
+		"Percobaan": t.Failures,
+		"Hingga":    t.LockedUntil.Format("02-01-2006 15:04 MST"),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("gagal menyusun notifikasi lockout", "error", err)
+		return
+	}
+	g.notifier.Send(ctx, notifier.Message{
+		Channel: notifier.ChannelEmail,
+		To:      user.Email,
+		Subject: subject,
+		Body:    body,
+	})
+}
@@ -0,0 +1,184 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Mode says how an attribute value is hidden.
+type Mode int
+
+const (
+	// ModeClear logs the value unchanged.
+	ModeClear Mode = iota
+	// ModeRedact replaces the value entirely.
+	ModeRedact
+	// ModeTail keeps only the last four characters.
+	ModeTail
+	// ModeInitial keeps only the first character, for names.
+	ModeInitial
+	// ModeEmail keeps the first character of the local part and the domain.
+	ModeEmail
+	// ModeContact masks an email address like ModeEmail and anything else,
+	// such as a phone number, like ModeTail.
+	ModeContact
+)
+
+const redacted = "[REDACTED]"
+
+// Policy maps attribute keys to masking modes. When MaskEmails is set,
+// email addresses inside any other string value are masked as well.
+type Policy struct {
+	Keys       map[string]Mode
+	MaskEmails bool
+}
+
+// PolicyFor returns the masking policy for an environment. Personal data
+// is masked as required by UU PDP unless env is exactly "development" or
+// "test", whose logs stay clear for debugging against synthetic data. An
+// unset or unknown env gets the production policy.
+func PolicyFor(env string) Policy {
+	switch env {
+	case "development", "test":
+		return Policy{}
+	case "staging":
+		return Policy{Keys: personalKeys(), MaskEmails: true}
+	default:
+		keys := personalKeys()
+		keys["saldo"] = ModeRedact
+		keys["saldo_baru"] = ModeRedact
+		keys["saldo_akhir"] = ModeRedact
+		return Policy{Keys: keys, MaskEmails: true}
+	}
+}
+
+// personalKeys are the attributes that identify a customer. "to" is the
+// phone number or email address a notification is sent to.
+func personalKeys() map[string]Mode {
+	return map[string]Mode{
+		"nik":                ModeRedact,
+		"no_hp":              ModeTail,
+		"to":                 ModeContact,
+		"no_rekening":        ModeTail,
+		"no_rekening_sumber": ModeTail,
+		"no_rekening_tujuan": ModeTail,
+		"email":              ModeEmail,
+		"nama":               ModeInitial,
+		"name":               ModeInitial,
+	}
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// MaskingHandler hides sensitive attributes before passing records on.
+type MaskingHandler struct {
+	next   slog.Handler
+	policy Policy
+}
+
+func NewMaskingHandler(next slog.Handler, policy Policy) *MaskingHandler {
+	return &MaskingHandler{next: next, policy: policy}
+}
+
+func (h *MaskingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *MaskingHandler) Handle(ctx context.Context, r slog.Record) error {
+	masked := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		masked.AddAttrs(h.mask(a))
+		return true
+	})
+	return h.next.Handle(ctx, masked)
+}
+
+func (h *MaskingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	masked := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		masked[i] = h.mask(a)
+	}
+	return &MaskingHandler{next: h.next.WithAttrs(masked), policy: h.policy}
+}
+
+func (h *MaskingHandler) WithGroup(name string) slog.Handler {
+	return &MaskingHandler{next: h.next.WithGroup(name), policy: h.policy}
+}
+
+func (h *MaskingHandler) mask(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		masked := make([]any, len(group))
+		for i, ga := range group {
+			masked[i] = h.mask(ga)
+		}
+		return slog.Group(a.Key, masked...)
+	}
+
+	if mode, ok := h.policy.Keys[a.Key]; ok && mode != ModeClear {
+		return slog.String(a.Key, apply(mode, a.Value))
+	}
+
+	if !h.policy.MaskEmails {
+		return a
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, maskEmails(a.Value.String()))
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, maskEmails(err.Error()))
+		}
+	}
+	return a
+}
+
+func apply(mode Mode, v slog.Value) string {
+	s := v.String()
+	switch mode {
+	case ModeTail:
+		return MaskTail(s)
+	case ModeInitial:
+		if s == "" {
+			return s
+		}
+		return string([]rune(s)[:1]) + "***"
+	case ModeEmail:
+		return MaskEmail(s)
+	case ModeContact:
+		if strings.Contains(s, "@") {
+			return MaskEmail(s)
+		}
+		return MaskTail(s)
+	default:
+		return redacted
+	}
+}
+
+// MaskTail hides all but the last four characters.
+func MaskTail(s string) string {
+	if len(s) <= 4 {
+		return strings.Repeat("*", len(s))
+	}
+	return strings.Repeat("*", len(s)-4) + s[len(s)-4:]
+}
+
+// MaskEmail turns "budi@example.com" into "b***@example.com".
+func MaskEmail(s string) string {
+	local, domain, ok := strings.Cut(s, "@")
+	if !ok || local == "" {
+		return redacted
+	}
+	return local[:1] + "***@" + domain
+}
+
+func maskEmails(s string) string {
+	if !strings.Contains(s, "@") {
+		return s
+	}
+	return emailPattern.ReplaceAllStringFunc(s, MaskEmail)
+}
@@ -0,0 +1,62 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"gobanking/logging"
+	"gobanking/notifier"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestPolicyForMasksUnlessDevelopmentOrTest(t *testing.T) {
+	for _, tt := range []struct {
+		env   string
+		clear bool
+	}{
+		{"development", true},
+		{"test", true},
+		{"", false},
+		{"dev", false},
+		{"local", false},
+		{"Development", false},
+		{"staging", false},
+		{"production", false},
+	} {
+		t.Run(tt.env, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(logging.NewMaskingHandler(slog.NewTextHandler(&buf, nil), logging.PolicyFor(tt.env)))
+			logger.Info("nasabah", "nik", "3171234567890001", "no_hp", "081234567890", "email", "budi@example.com")
+
+			out := buf.String()
+			leaked := strings.Contains(out, "3171234567890001") ||
+				strings.Contains(out, "081234567890") ||
+				strings.Contains(out, "budi@example.com")
+			if leaked != tt.clear {
+				t.Fatalf("APP_ENV=%q logged %s, want clear = %v", tt.env, out, tt.clear)
+			}
+		})
+	}
+}
+
+// TestLogNotifierRecipientMasked checks the "to" attribute LogNotifier
+// writes, for both the SMS and the email channel.
+func TestLogNotifierRecipientMasked(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(logging.NewMaskingHandler(slog.NewTextHandler(&buf, nil), logging.PolicyFor("")))
+	n := notifier.LogNotifier{Logger: logger}
+
+	n.Send(context.Background(), notifier.Message{Channel: notifier.ChannelSMS, To: "081234567890", Subject: "Setoran diterima"})
+	n.Send(context.Background(), notifier.Message{Channel: notifier.ChannelEmail, To: "budi@example.com", Subject: "Setoran diterima"})
+
+	out := buf.String()
+	for _, want := range []string{"to=********7890", "to=b***@example.com"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log does not contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "081234567890") || strings.Contains(out, "budi@") {
+		t.Fatalf("recipient logged in clear:\n%s", out)
+	}
+}
@@ -1,15 +1,34 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"gobanking/account"
 	"gobanking/config"
 	"gobanking/database"
 	_ "gobanking/docs"
-	"gobanking/model"
+	"gobanking/grpcapi"
+	"gobanking/handler"
+	"gobanking/metrics"
+	"gobanking/notifier"
+	"gobanking/payroll"
+	"gobanking/repository"
 	"gobanking/router"
+	"gobanking/schedule"
+	"gobanking/tracing"
+	"gobanking/webhook"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	echoSwagger "github.com/swaggo/echo-swagger"
+	"gorm.io/gorm"
 )
 
 // @title Banking API
@@ -33,8 +52,19 @@ import (
 // @name Authorization
 // @description JWT Bearer authentication
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		os.Exit(runAdmin(os.Args[2:]))
+	}
+
 	// Load configuration
-	cfg := config.Load()
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "konfigurasi tidak valid:\n%v\n", err)
+		os.Exit(2)
+	}
 
 	// Connect to database
 	db, err := database.Connect(cfg)
@@ -42,23 +72,213 @@ func main() {
 		panic("gagal menghubungkan database")
 	}
 
-	// Auto migrate the schema
-	if err := db.AutoMigrate(&model.Nasabah{}, &model.User{}); err != nil {
-		cfg.Logger.Error("gagal migrasi database", "error", err)
-		panic("gagal migrasi database")
+	// Setup tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		cfg.Logger.Error("gagal menyiapkan tracing", "error", err)
+		panic("gagal menyiapkan tracing")
+	}
+
+	// Instrument database queries and the connection pool
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		cfg.Logger.Error("gagal memasang plugin metrics", "error", err)
+	}
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		cfg.Logger.Error("gagal memasang plugin tracing", "error", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.RegisterDBStats(sqlDB)
+	}
+
+	// Apply pending schema migrations
+	if cfg.Database.AutoMigrate {
+		applied, err := database.MigrateUp(context.Background(), db)
+		if err != nil {
+			cfg.Logger.Error("gagal migrasi database", "error", err)
+			panic("gagal migrasi database")
+		}
+		for _, m := range applied {
+			cfg.Logger.Info("migrasi diterapkan", "version", m.Version, "name", m.Name)
+		}
+	}
+
+	// Background workers stop when workers is cancelled during shutdown
+	workers, stopWorkers := context.WithCancel(context.Background())
+	var background sync.WaitGroup
+
+	// Deliver webhook events in the background
+	dispatcher := webhook.NewDispatcher(db, cfg)
+	if cfg.Features.Webhooks {
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			dispatcher.Run(workers)
+		}()
 	}
 
+	// Send customer notifications without blocking transactions
+	notifications := notifier.NewAsync(notifier.New(cfg), cfg.Logger, cfg.Notifier.QueueSize)
+	notifications.Start(cfg.Notifier.Workers)
+
 	// Echo instance
 	e := echo.New()
+	e.HideBanner = true
+
+	e.Server.ReadTimeout = cfg.Server.ReadTimeout
+	e.Server.WriteTimeout = cfg.Server.WriteTimeout
+	e.Server.IdleTimeout = cfg.Server.IdleTimeout
 
 	// Swagger documentation
-	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	if cfg.Features.Swagger {
+		e.GET("/swagger/*", echoSwagger.WrapHandler)
+	}
+
+	// Prometheus metrics, on an internal port when METRICS_ADDR is set
+	var metricsServer *http.Server
+	if cfg.Metrics.Addr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer = &http.Server{Addr: cfg.Metrics.Addr, Handler: mux}
+		go func() {
+			cfg.Logger.Info("memulai server metrics", "alamat", cfg.Metrics.Addr)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				cfg.Logger.Error("server metrics berhenti", "error", err)
+			}
+		}()
+	} else {
+		e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+	}
+
+	// Business logic shared by REST and gRPC; outbox events become webhook
+	// deliveries
+	store := repository.NewGormStore(db, dispatcher.Enqueue)
+	accounts := account.NewService(store, notifications)
+
+	// Settle payroll batches in the background
+	payrollProcessor := payroll.NewProcessor(db, cfg, accounts)
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		payrollProcessor.Run(workers)
+	}()
+
+	// Execute due standing orders in the background
+	executor := schedule.NewExecutor(db, cfg, accounts)
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		executor.Run(workers)
+	}()
 
 	// Setup routes
-	router.Setup(e, db, cfg)
+	health := handler.NewHealthHandler(db, cfg)
+	// REST and gRPC draw on the same rate limit buckets
+	limiter := router.RateLimitStore(db, cfg)
+	router.Setup(e, db, cfg, store, accounts, dispatcher, notifications, health, limiter)
+
+	// gRPC BankingService on its own port
+	var grpcServer *grpcapi.Server
+	if cfg.GRPC.Addr != "" {
+		lis, err := net.Listen("tcp", cfg.GRPC.Addr)
+		if err != nil {
+			cfg.Logger.Error("gagal membuka port gRPC", "alamat", cfg.GRPC.Addr, "error", err)
+			panic("gagal membuka port gRPC")
+		}
+		grpcServer = grpcapi.NewServer(cfg, accounts, store.Users(), limiter)
+		go func() {
+			cfg.Logger.Info("memulai server gRPC", "alamat", cfg.GRPC.Addr)
+			if err := grpcServer.Serve(lis); err != nil {
+				cfg.Logger.Error("server gRPC berhenti", "error", err)
+			}
+		}()
+	}
 
 	// Start server
 	serverAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
-	cfg.Logger.Info("memulai server", "alamat", serverAddr)
-	e.Logger.Fatal(e.Start(serverAddr))
+	serverErr := make(chan error, 1)
+	go func() {
+		cfg.Logger.Info("memulai server", "alamat", serverAddr)
+		if err := e.Start(serverAddr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	// Wait for SIGINT/SIGTERM or a listener failure
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	select {
+	case <-signals.Done():
+		cfg.Logger.Info("sinyal berhenti diterima, memulai graceful shutdown")
+	case err := <-serverErr:
+		cfg.Logger.Error("server berhenti", "error", err)
+	}
+
+	// Fail readiness first so load balancers stop sending new requests
+	health.Drain()
+	if cfg.Server.DrainDelay > 0 {
+		cfg.Logger.Info("menunggu load balancer", "delay", cfg.Server.DrainDelay.String())
+		time.Sleep(cfg.Server.DrainDelay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	shutdown(ctx, cfg, e, grpcServer, metricsServer, stopWorkers, &background, notifications, db, shutdownTracing)
+}
+
+// shutdown stops the HTTP and gRPC servers, waiting for in-flight handlers, then the
+// background workers, and finally flushes traces and closes the DB pool.
+func shutdown(
+	ctx context.Context,
+	cfg *config.Config,
+	e *echo.Echo,
+	grpcServer *grpcapi.Server,
+	metricsServer *http.Server,
+	stopWorkers context.CancelFunc,
+	background *sync.WaitGroup,
+	notifications *notifier.Async,
+	db *gorm.DB,
+	shutdownTracing func(context.Context) error,
+) {
+	if err := e.Shutdown(ctx); err != nil {
+		cfg.Logger.Error("request belum selesai saat batas waktu shutdown", "error", err)
+	} else {
+		cfg.Logger.Info("semua request selesai")
+	}
+
+	if grpcServer != nil {
+		if err := grpcServer.Shutdown(ctx); err != nil {
+			cfg.Logger.Error("rpc belum selesai saat batas waktu shutdown", "error", err)
+		}
+	}
+
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(ctx); err != nil {
+			cfg.Logger.Error("gagal menghentikan server metrics", "error", err)
+		}
+	}
+
+	stopWorkers()
+	done := make(chan struct{})
+	go func() {
+		background.Wait()
+		notifications.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+		cfg.Logger.Info("worker latar belakang berhenti")
+	case <-ctx.Done():
+		cfg.Logger.Error("worker latar belakang belum berhenti saat batas waktu shutdown")
+	}
+
+	if err := shutdownTracing(ctx); err != nil {
+		cfg.Logger.Error("gagal flush tracing", "error", err)
+	}
+
+	if sqlDB, err := db.DB(); err == nil {
+		if err := sqlDB.Close(); err != nil {
+			cfg.Logger.Error("gagal menutup koneksi database", "error", err)
+		}
+	}
+	cfg.Logger.Info("server berhenti")
 }
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"gobanking/config"
+	"gobanking/notifier"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// TestShutdownWaitsForInFlightRequest starts the HTTP server, holds one
+// request inside its handler and checks that shutdown neither cuts it off
+// nor returns before it has been answered, while refusing new connections.
+func TestShutdownWaitsForInFlightRequest(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{Logger: logger}
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	e := echo.New()
+	e.HideBanner, e.HidePort = true, true
+	e.GET("/lambat", func(c echo.Context) error {
+		close(entered)
+		<-release
+		return c.String(http.StatusOK, "selesai")
+	})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Listener = lis
+	go func() {
+		if err := e.Start(""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			t.Errorf("server: %v", err)
+		}
+	}()
+	url := "http://" + lis.Addr().String()
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	answered := make(chan result, 1)
+	go func() {
+		resp, err := http.Get(url + "/lambat")
+		if err != nil {
+			answered <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		answered <- result{status: resp.StatusCode, body: string(body), err: err}
+	}()
+	select {
+	case <-entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request never reached the handler")
+	}
+
+	// The pool is opened lazily, so no database is needed to close it.
+	db, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1 dbname=test"), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	workers, stopWorkers := context.WithCancel(context.Background())
+	var background sync.WaitGroup
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		<-workers.Done()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		shutdown(ctx, cfg, e, nil, nil, stopWorkers, &background,
+			notifier.NewAsync(notifier.LogNotifier{Logger: logger}, logger, 1),
+			db, func(context.Context) error { return nil })
+		close(stopped)
+	}()
+
+	// New connections are refused once shutdown has closed the listener.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		conn, err := net.DialTimeout("tcp", lis.Addr().String(), 100*time.Millisecond)
+		if err != nil {
+			break
+		}
+		conn.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("listener still accepting connections during shutdown")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case <-stopped:
+		t.Fatal("shutdown returned while a request was in flight")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case r := <-answered:
+		if r.err != nil || r.status != http.StatusOK || r.body != "selesai" {
+			t.Fatalf("in-flight request = %d %q, %v; want 200 selesai", r.status, r.body, r.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("in-flight request was not answered")
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown did not return after the request finished")
+	}
+	if workers.Err() == nil {
+		t.Fatal("background workers were not stopped")
+	}
+}
@@ -0,0 +1,115 @@
+// Package metrics exposes Prometheus metrics for HTTP traffic, database
+// queries and business events.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "gobanking"
+
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "gorm statement latency by operation and table.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation", "table"})
+
+	TransactionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transactions_total",
+		Help:      "Committed transactions by type (tabung, tarik, transfer_keluar, transfer_masuk).",
+	}, []string{"jenis"})
+
+	TransactionAmountTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transaction_amount_total",
+		Help:      "Sum of committed transaction amounts by type, in the account currency.",
+	}, []string{"jenis", "mata_uang"})
+
+	InsufficientBalanceTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "insufficient_balance_rejections_total",
+		Help:      "Withdrawals and transfers rejected for insufficient balance.",
+	})
+
+	LoginFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_failures_total",
+		Help:      "Failed logins by reason.",
+	}, []string{"reason"})
+
+	PayrollItemsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "payroll_items_total",
+		Help:      "Settled payroll rows by status (success, failed).",
+	}, []string{"status"})
+
+	StandingOrderRunsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "standing_order_runs_total",
+		Help:      "Standing order execution attempts by status (success, retry, failed).",
+	}, []string{"status"})
+
+	ReversalsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reversals_total",
+		Help:      "Transaction reversal requests by outcome (pending, completed, rejected).",
+	}, []string{"status"})
+
+	EODStepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "eod_step_duration_seconds",
+		Help:      "End-of-day step duration by step and outcome (completed, failed).",
+		Buckets:   []float64{.1, .5, 1, 5, 15, 60, 300, 900},
+	}, []string{"langkah", "status"})
+
+	ReconItemsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconciliation_items_total",
+		Help:      "Reconciliation results by status (matched, missing_internal, missing_external, amount_mismatch).",
+	}, []string{"status"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		DBQueryDuration,
+		TransactionsTotal,
+		TransactionAmountTotal,
+		InsufficientBalanceTotal,
+		LoginFailuresTotal,
+		PayrollItemsTotal,
+		StandingOrderRunsTotal,
+		ReversalsTotal,
+		EODStepDuration,
+		ReconItemsTotal,
+	)
+}
+
+// RegisterDBStats exports connection pool statistics of db.
+func RegisterDBStats(db *sql.DB) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// Handler serves the registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
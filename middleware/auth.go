@@ -1,8 +1,13 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"gobanking/config"
+	"gobanking/logging"
 	"gobanking/model"
+	"gobanking/repository"
 	"net/http"
 	"strings"
 
@@ -10,34 +15,138 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func AuthMiddleware(cfg *config.Config) echo.MiddlewareFunc {
+// ErrTokenRevoked refuses a token whose user was disabled, deleted or had
+// their password reset after it was issued.
+var ErrTokenRevoked = errors.New("token sudah dicabut")
+
+func AuthMiddleware(cfg *config.Config, users repository.UserRepository) echo.MiddlewareFunc {
+	return authMiddleware(cfg, users)
+}
+
+// EnrolmentAuthMiddleware also accepts the enrolment token /login hands to
+// users who must set up 2FA before they may receive a regular token.
+func EnrolmentAuthMiddleware(cfg *config.Config, users repository.UserRepository) echo.MiddlewareFunc {
+	return authMiddleware(cfg, users, model.TokenPurposeMFAEnrol)
+}
+
+// authMiddleware validates the bearer token and checks on every request
+// that it has not been revoked. Tokens carrying a purpose claim are only
+// accepted when that purpose is listed in allowed.
+func authMiddleware(cfg *config.Config, users repository.UserRepository, allowed ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			logger := logging.FromContext(c.Request().Context())
 			authHeader := c.Request().Header.Get("Authorization")
 			if authHeader == "" {
 				return c.JSON(http.StatusUnauthorized, model.ErrorResponse{Remark: "Missing authorization header"})
 			}
 
 			tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-				return []byte(cfg.JWT.Secret), nil
-			})
-
-			if err != nil || !token.Valid {
-				cfg.Logger.Warn("invalid token", "error", err)
+			claims, err := ParseToken(cfg, tokenString, allowed...)
+			if err != nil {
+				logger.Warn("invalid token", "error", err)
 				return c.JSON(http.StatusUnauthorized, model.ErrorResponse{Remark: "Invalid token"})
 			}
-
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				cfg.Logger.Warn("invalid token claims")
+			if err := CheckRevoked(c.Request().Context(), users, claims); err != nil {
+				if !errors.Is(err, ErrTokenRevoked) {
+					logger.Error("gagal memeriksa token", "error", err)
+					return c.JSON(http.StatusInternalServerError, model.ErrorResponse{Remark: "Internal server error"})
+				}
+				logger.Warn("invalid token", "user_id", claims["user_id"], "error", err)
 				return c.JSON(http.StatusUnauthorized, model.ErrorResponse{Remark: "Invalid token"})
 			}
+			purpose, _ := claims["purpose"].(string)
 
 			c.Set("user_id", claims["user_id"])
 			c.Set("email", claims["email"])
+			c.Set("role", claims["role"])
+			c.Set("token_purpose", purpose)
+
+			// Every later log line of this request names the user.
+			ctx := logging.WithLogger(c.Request().Context(), logger.With("user_id", claims["user_id"]))
+			c.SetRequest(c.Request().WithContext(ctx))
 
 			return next(c)
 		}
 	}
 }
+
+// ParseToken validates an access token signed with JWT_SECRET and returns
+// its claims. Tokens carrying a purpose claim are only accepted when that
+// purpose is listed in allowed. The gRPC interceptors share it.
+func ParseToken(cfg *config.Config, tokenString string, allowed ...string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(cfg.JWT.Secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("token tidak valid")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("claims token tidak valid")
+	}
+
+	purpose, _ := claims["purpose"].(string)
+	if purpose != "" && !contains(allowed, purpose) {
+		return nil, fmt.Errorf("token dengan purpose %q tidak berlaku di sini", purpose)
+	}
+	return claims, nil
+}
+
+// CheckRevoked returns ErrTokenRevoked when the user a token was issued to
+// no longer exists, is disabled or has moved past the token's "ver" claim.
+// Tokens without one count as version 0. Service tokens from `jwt mint`
+// name no user and are only bounded by their expiry. The gRPC interceptors
+// share it.
+func CheckRevoked(ctx context.Context, users repository.UserRepository, claims jwt.MapClaims) error {
+	id, ok := claims["user_id"].(float64)
+	if !ok {
+		if service, _ := claims["service"].(bool); service {
+			return nil
+		}
+		return fmt.Errorf("%w: user_id tidak ada di token", ErrTokenRevoked)
+	}
+
+	user, err := users.FindByID(ctx, uint(id))
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: user tidak ditemukan", ErrTokenRevoked)
+	}
+	if err != nil {
+		return err
+	}
+	if user.DisabledAt != nil {
+		return fmt.Errorf("%w: user dinonaktifkan", ErrTokenRevoked)
+	}
+	version, _ := claims["ver"].(float64)
+	if int(version) != user.TokenVersion {
+		return fmt.Errorf("%w: versi token %d, sekarang %d", ErrTokenRevoked, int(version), user.TokenVersion)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRole only lets users with one of roles through. It must run after
+// AuthMiddleware.
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			role, _ := c.Get("role").(string)
+			if !contains(roles, role) {
+				return c.JSON(http.StatusForbidden, model.ErrorResponse{Remark: "Akses ditolak"})
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package middleware_test
+
+import (
+	"context"
+	"gobanking/config"
+	"gobanking/middleware"
+	"gobanking/model"
+	"gobanking/repository"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+func TestAuthMiddlewareRefusesRevokedTokens(t *testing.T) {
+	cfg := &config.Config{
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		JWT:    config.JWTConfig{Secret: "rahasia-test"},
+	}
+	store := repository.NewMemoryStore()
+	now := time.Now()
+	users := map[string]*model.User{
+		"aktif":    {Email: "aktif@example.com", Password: "x"},
+		"reset":    {Email: "reset@example.com", Password: "x", TokenVersion: 1},
+		"nonaktif": {Email: "nonaktif@example.com", Password: "x", DisabledAt: &now},
+	}
+	for _, u := range users {
+		if err := store.Users().Create(context.Background(), u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sign := func(claims jwt.MapClaims) string {
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.JWT.Secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	userToken := func(u *model.User, ver int) string {
+		return sign(jwt.MapClaims{"user_id": u.ID, "email": u.Email, "role": model.RoleUser, "ver": ver})
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"current version", userToken(users["aktif"], 0), http.StatusOK},
+		{"token without ver counts as 0", sign(jwt.MapClaims{"user_id": users["aktif"].ID, "role": model.RoleUser}), http.StatusOK},
+		{"issued before password reset", userToken(users["reset"], 0), http.StatusUnauthorized},
+		{"issued after password reset", userToken(users["reset"], 1), http.StatusOK},
+		{"disabled user", userToken(users["nonaktif"], 0), http.StatusUnauthorized},
+		{"unknown user", sign(jwt.MapClaims{"user_id": 999, "role": model.RoleUser}), http.StatusUnauthorized},
+		{"service token", sign(jwt.MapClaims{"sub": "payroll", "role": model.RoleStaff, "service": true}), http.StatusOK},
+		{"token without user or service", sign(jwt.MapClaims{"role": model.RoleAdmin}), http.StatusUnauthorized},
+	}
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, middleware.AuthMiddleware(cfg, store.Users()))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
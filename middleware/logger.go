@@ -1,13 +1,109 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"gobanking/config"
+	"gobanking/logging"
+	"log/slog"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const HeaderRequestID = "X-Request-ID"
+
 func SetupMiddleware(e *echo.Echo, cfg *config.Config) {
-	e.Use(middleware.Logger())
+	e.Use(Metrics())
+	e.Use(Tracing())
+	e.Use(AccessLog(cfg))
 	e.Use(middleware.Recover())
 }
+
+// AccessLog assigns every request an ID, or keeps the caller's
+// X-Request-ID, stores a logger carrying it in the request context and
+// writes one access log line when the request completes.
+func AccessLog(cfg *config.Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+
+			requestID := RequestID(req.Header.Get(HeaderRequestID))
+			c.Set("request_id", requestID)
+			c.Response().Header().Set(HeaderRequestID, requestID)
+
+			logger := cfg.Logger.With("request_id", requestID)
+			if sc := trace.SpanContextFromContext(req.Context()); sc.IsValid() {
+				logger = logger.With("trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
+			}
+			c.SetRequest(req.WithContext(logging.WithLogger(req.Context(), logger)))
+
+			err := next(c)
+			if err != nil {
+				// Let Echo write the error response so the logged status is
+				// the one the client sees.
+				c.Error(err)
+			}
+
+			res := c.Response()
+			level := slog.LevelInfo
+			switch {
+			case res.Status >= 500:
+				level = slog.LevelError
+			case res.Status >= 400:
+				level = slog.LevelWarn
+			}
+
+			attrs := []any{
+				"method", req.Method,
+				"route", c.Path(),
+				"path", req.URL.Path,
+				"status", res.Status,
+				"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+				"bytes_out", res.Size,
+				"ip", c.RealIP(),
+			}
+			if userID := c.Get("user_id"); userID != nil {
+				attrs = append(attrs, "user_id", userID)
+			}
+			if err != nil {
+				attrs = append(attrs, "error", err)
+			}
+
+			logger.Log(req.Context(), level, "request selesai", attrs...)
+			return nil
+		}
+	}
+}
+
+// RequestID keeps a caller-supplied ID when it is safe to log and echo back,
+// and otherwise returns a new random one.
+func RequestID(id string) string {
+	if validRequestID(id) {
+		return id
+	}
+	return newRequestID()
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID accepts caller-supplied IDs that are safe to log and echo
+// back: at most 128 printable ASCII characters.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
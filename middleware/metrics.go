@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"gobanking/metrics"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Metrics observes request latency per route template and status. Requests
+// that match no route are grouped under "unmatched" to bound cardinality.
+func Metrics() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			metrics.HTTPRequestDuration.
+				WithLabelValues(c.Request().Method, route, strconv.Itoa(c.Response().Status)).
+				Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
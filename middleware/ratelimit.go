@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"fmt"
+	"gobanking/logging"
+	"gobanking/model"
+	"gobanking/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimitKey derives the bucket key for a request.
+type RateLimitKey func(c echo.Context) string
+
+func ByIP(c echo.Context) string {
+	return "ip:" + c.RealIP()
+}
+
+// ByUser keys on the user_id set by AuthMiddleware, falling back to the
+// client IP for unauthenticated requests.
+func ByUser(c echo.Context) string {
+	if id := c.Get("user_id"); id != nil {
+		return fmt.Sprintf("user:%v", id)
+	}
+	return ByIP(c)
+}
+
+// RateLimit rejects requests over policy with 429 and reports the bucket
+// state in RateLimit-* headers. Store errors fail open.
+func RateLimit(store ratelimit.Store, policy ratelimit.Policy, key RateLimitKey) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			k := policy.Name + ":" + key(c)
+			res, err := store.Take(c.Request().Context(), k, policy)
+			if err != nil {
+				logging.FromContext(c.Request().Context()).Error("gagal memeriksa rate limit", "policy", policy.Name, "error", err)
+				return next(c)
+			}
+
+			h := c.Response().Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", ceilSeconds(res.Reset))
+			h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%s", policy.Limit, ceilSeconds(policy.Period)))
+
+			if !res.Allowed {
+				h.Set("Retry-After", ceilSeconds(res.RetryAfter))
+				logging.FromContext(c.Request().Context()).Warn("rate limit terlampaui", "policy", policy.Name, "key", k)
+				return c.JSON(http.StatusTooManyRequests, model.ErrorResponse{Remark: "Terlalu banyak request, coba lagi nanti"})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net"
+	"net/netip"
+
+	"github.com/labstack/echo/v4"
+)
+
+// IPExtractor decides what c.RealIP() returns, and with it the key of the
+// per-IP rate limits and login lockout. Without trusted proxies it is the
+// peer address; X-Forwarded-For and X-Real-IP are client-supplied and
+// ignored. With trusted proxies it is the right-most X-Forwarded-For
+// address that is not one of them. Entries are IPs or CIDR ranges and are
+// checked by config validation.
+func IPExtractor(trustedProxies []string) echo.IPExtractor {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range trustedProxies {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			addr, err := netip.ParseAddr(proxy)
+			if err != nil {
+				continue
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		_, ipNet, _ := net.ParseCIDR(prefix.Masked().String())
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
@@ -0,0 +1,39 @@
+package middleware_test
+
+import (
+	"gobanking/middleware"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestIPExtractor(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		remote  string
+		xff     string
+		want    string
+	}{
+		{"direct ignores XFF", nil, "203.0.113.7:5000", "198.51.100.1", "203.0.113.7"},
+		{"direct ignores XFF from private peer", nil, "10.0.0.5:5000", "198.51.100.1", "10.0.0.5"},
+		{"trusted proxy", []string{"10.0.0.0/8"}, "10.0.0.5:5000", "198.51.100.1", "198.51.100.1"},
+		{"spoofed hop before client", []string{"10.0.0.5"}, "10.0.0.5:5000", "1.2.3.4, 198.51.100.1", "198.51.100.1"},
+		{"untrusted peer", []string{"10.0.0.0/8"}, "203.0.113.7:5000", "198.51.100.1", "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.IPExtractor = middleware.IPExtractor(tt.proxies)
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remote
+			req.Header.Set(echo.HeaderXForwardedFor, tt.xff)
+			req.Header.Set(echo.HeaderXRealIP, "192.0.2.99")
+			c := e.NewContext(req, httptest.NewRecorder())
+			if got := c.RealIP(); got != tt.want {
+				t.Errorf("RealIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gobanking/config"
+	"gobanking/database"
+	"os"
+	"strconv"
+)
+
+const migrateUsage = `usage: gobanking migrate <command>
+
+commands:
+  up              apply all pending migrations
+  down [n]        roll back the last n migrations (default 1)
+  status          list migrations and when they were applied
+  create <name>   add an empty migration pair to database/migrations
+`
+
+// runMigrate implements the `migrate` subcommand and returns the exit code.
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	// create only writes files, so it needs neither config nor a database
+	if args[0] == "create" {
+		if len(args) != 2 {
+			fmt.Fprint(os.Stderr, migrateUsage)
+			return 2
+		}
+		up, down, err := database.CreateMigration("database/migrations", args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "gagal membuat migrasi:", err)
+			return 1
+		}
+		fmt.Println(up)
+		fmt.Println(down)
+		return 0
+	}
+
+	cfg, err := config.Load(nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "konfigurasi tidak valid:\n%v\n", err)
+		return 2
+	}
+	db, err := database.Connect(cfg)
+	if err != nil {
+		return 1
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp(ctx, db)
+		for _, m := range applied {
+			fmt.Printf("up   %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("tidak ada migrasi baru")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				fmt.Fprintln(os.Stderr, "jumlah langkah harus bilangan bulat positif")
+				return 2
+			}
+		}
+		reverted, err := database.MigrateDown(ctx, db, steps)
+		for _, m := range reverted {
+			fmt.Printf("down %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+	case "status":
+		status, err := database.Status(ctx, db)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, s := range status {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", s.Version, s.Name, applied)
+		}
+
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
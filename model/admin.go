@@ -0,0 +1,21 @@
+package model
+
+import "gorm.io/gorm"
+
+const (
+	AdminResultOK    = "ok"
+	AdminResultError = "error"
+)
+
+// AdminAuditLog records one `gobanking admin` command, including dry runs
+// and failures.
+type AdminAuditLog struct {
+	gorm.Model
+	Operator string `gorm:"not null;index" json:"operator"`
+	Command  string `gorm:"not null;index" json:"command"`
+	// Args is the JSON object of flags and arguments; secrets are masked.
+	Args   string `gorm:"type:text;not null" json:"args"`
+	DryRun bool   `gorm:"not null;default:false" json:"dry_run"`
+	Result string `gorm:"not null" json:"result"`
+	Error  string `json:"error,omitempty"`
+}
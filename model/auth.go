@@ -1,11 +1,46 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RoleUser  = "user"
+	RoleStaff = "staff"
+	RoleAdmin = "admin"
+)
+
+// Token purposes mark short-lived JWTs that only unlock the second login
+// step; they are rejected by the regular auth middleware.
+const (
+	TokenPurposeMFA      = "mfa"
+	TokenPurposeMFAEnrol = "mfa_enrol"
+)
 
 type User struct {
 	gorm.Model
 	Email    string `gorm:"unique;not null" json:"email"`
 	Password string `gorm:"not null" json:"-"`
+	Role     string `gorm:"not null;default:user" json:"role"`
+
+	TOTPSecret  string `json:"-"`
+	TOTPEnabled bool   `gorm:"not null;default:false" json:"-"`
+
+	// DisabledAt is set by `gobanking admin user disable`; disabled users
+	// cannot log in and their tokens are refused.
+	DisabledAt *time.Time `json:"-"`
+	// TokenVersion is signed into every token as "ver". Disabling the user
+	// or resetting their password bumps it, which revokes every token
+	// issued before.
+	TokenVersion int `gorm:"not null;default:0" json:"-"`
+
+	// MFAJTI is the ID of the MFA token of the login waiting for its second
+	// factor, empty once it completed or ran out of MFAAttempts. A newer
+	// login replaces it.
+	MFAJTI      string `gorm:"column:mfa_jti;not null;default:''" json:"-"`
+	MFAAttempts int    `gorm:"column:mfa_attempts;not null;default:0" json:"-"`
 }
 
 type LoginRequest struct {
@@ -21,3 +56,39 @@ type RegisterRequest struct {
 type TokenResponse struct {
 	Token string `json:"token"`
 }
+
+type BackupCode struct {
+	gorm.Model
+	UserID   uint   `gorm:"not null;index"`
+	CodeHash string `gorm:"not null"`
+	UsedAt   *time.Time
+}
+
+// MFAChallengeResponse is returned by /login instead of a token when a
+// second factor is needed. EnrolmentRequired means policy demands 2FA and
+// the user must enrol with MFAToken before a real token is issued.
+type MFAChallengeResponse struct {
+	MFARequired       bool      `json:"mfa_required"`
+	EnrolmentRequired bool      `json:"enrolment_required,omitempty"`
+	MFAToken          string    `json:"mfa_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type TwoFactorEnrolResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFactorEnabledResponse struct {
+	BackupCodes []string `json:"backup_codes"`
+	Token       string   `json:"token,omitempty"`
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	EODRunning   = "running"
+	EODFailed    = "failed"
+	EODCompleted = "completed"
+)
+
+// End-of-day steps, run in the order of LangkahEOD.
+const (
+	LangkahCutOff        = "cut_off"
+	LangkahBunga         = "akrual_bunga"
+	LangkahBiaya         = "biaya_admin"
+	LangkahDormansi      = "dormansi"
+	LangkahSnapshotSaldo = "snapshot_saldo"
+	LangkahRekeningKoran = "rekening_koran"
+	LangkahRollover      = "rollover"
+)
+
+var LangkahEOD = []string{
+	LangkahCutOff,
+	LangkahBunga,
+	LangkahBiaya,
+	LangkahDormansi,
+	LangkahSnapshotSaldo,
+	LangkahRekeningKoran,
+	LangkahRollover,
+}
+
+// TanggalBisnis is the bank's current business date, a single row. Until
+// the end-of-day run cuts it off, new transactions are booked on Tanggal;
+// after that on Berikutnya, the next business day by the holiday calendar.
+type TanggalBisnis struct {
+	ID         uint       `gorm:"primaryKey" json:"-"`
+	Tanggal    time.Time  `gorm:"type:date;not null" json:"tanggal"`
+	Berikutnya time.Time  `gorm:"type:date;not null" json:"berikutnya"`
+	CutOffAt   *time.Time `json:"cut_off_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Posting returns the business date a transaction booked now belongs to.
+func (t TanggalBisnis) Posting() time.Time {
+	if t.CutOffAt != nil {
+		return t.Berikutnya
+	}
+	return t.Tanggal
+}
+
+// EODRun is the end-of-day run of one business date. A failed run is
+// resumed from the step that failed; steps already completed are skipped.
+type EODRun struct {
+	gorm.Model
+	TanggalBisnis time.Time `gorm:"type:date;not null;uniqueIndex" json:"tanggal_bisnis"`
+	Status        string    `gorm:"not null;index" json:"status"`
+	// Langkah is the step running or, after a failure, the one that failed.
+	Langkah   string `json:"langkah,omitempty"`
+	Error     string `json:"error,omitempty"`
+	StartedBy uint   `gorm:"not null" json:"started_by"`
+	// LeaseUntil keeps a second replica from running the same date while
+	// this one is alive; an expired lease means the runner died.
+	LeaseUntil *time.Time   `json:"-"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+	Steps      []EODLangkah `gorm:"foreignKey:RunID" json:"steps"`
+}
+
+// EODLangkah records one step of a run. Jumlah is the number of accounts or
+// rows the step touched.
+type EODLangkah struct {
+	gorm.Model
+	RunID      uint       `gorm:"not null;uniqueIndex:idx_eod_langkahs_run_langkah" json:"-"`
+	Langkah    string     `gorm:"not null;uniqueIndex:idx_eod_langkahs_run_langkah" json:"langkah"`
+	Status     string     `gorm:"not null" json:"status"`
+	Jumlah     int        `gorm:"not null;default:0" json:"jumlah"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `gorm:"not null" json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// AkrualBunga is one business date's interest on an account, accrued on
+// the balance at the end of that date for every calendar day until the
+// next business date. Accruals are credited at month end; TransaksiID is
+// the bunga entry that paid them.
+type AkrualBunga struct {
+	gorm.Model
+	NasabahID     uint      `gorm:"not null;uniqueIndex:idx_akrual_bungas_nasabah_tanggal" json:"-"`
+	NoRekening    string    `gorm:"not null" json:"no_rekening"`
+	TanggalBisnis time.Time `gorm:"type:date;not null;uniqueIndex:idx_akrual_bungas_nasabah_tanggal" json:"tanggal_bisnis"`
+	Saldo         float64   `gorm:"not null" json:"saldo"`
+	Hari          int       `gorm:"not null" json:"hari"`
+	Bunga         float64   `gorm:"not null" json:"bunga"`
+	MataUang      string    `gorm:"size:3;not null;default:IDR" json:"mata_uang"`
+	TransaksiID   *uint     `gorm:"index" json:"transaksi_id,omitempty"`
+}
+
+// SaldoHarian is an account's balance at the end of a business date.
+type SaldoHarian struct {
+	gorm.Model
+	NasabahID     uint      `gorm:"not null;uniqueIndex:idx_saldo_harians_nasabah_tanggal" json:"-"`
+	NoRekening    string    `gorm:"not null;index" json:"no_rekening"`
+	TanggalBisnis time.Time `gorm:"type:date;not null;uniqueIndex:idx_saldo_harians_nasabah_tanggal" json:"tanggal_bisnis"`
+	Saldo         float64   `gorm:"not null" json:"saldo"`
+	MataUang      string    `gorm:"size:3;not null;default:IDR" json:"mata_uang"`
+}
+
+// RekeningKoran is an account's monthly statement, generated by the
+// end-of-day run of the month's last business date. Its lines are the
+// month's Transaksi by TanggalBisnis.
+type RekeningKoran struct {
+	gorm.Model
+	NasabahID  uint   `gorm:"not null;uniqueIndex:idx_rekening_korans_nasabah_periode" json:"-"`
+	NoRekening string `gorm:"not null;index" json:"no_rekening"`
+	// Periode is the month, as 2006-01.
+	Periode         string    `gorm:"not null;uniqueIndex:idx_rekening_korans_nasabah_periode" json:"periode"`
+	TanggalAwal     time.Time `gorm:"type:date;not null" json:"tanggal_awal"`
+	TanggalAkhir    time.Time `gorm:"type:date;not null" json:"tanggal_akhir"`
+	MataUang        string    `gorm:"size:3;not null;default:IDR" json:"mata_uang"`
+	SaldoAwal       float64   `gorm:"not null" json:"saldo_awal"`
+	TotalKredit     float64   `gorm:"not null" json:"total_kredit"`
+	TotalDebit      float64   `gorm:"not null" json:"total_debit"`
+	SaldoAkhir      float64   `gorm:"not null" json:"saldo_akhir"`
+	JumlahTransaksi int       `gorm:"not null" json:"jumlah_transaksi"`
+}
+
+type RekeningKoranResponse struct {
+	RekeningKoran
+	Transaksi []Transaksi `json:"transaksi"`
+}
+
+type TanggalBisnisResponse struct {
+	TanggalBisnis
+	// Posting is the date a transaction booked now gets.
+	Posting time.Time `json:"posting"`
+	// LastRun is the latest end-of-day run, if any.
+	LastRun *EODRun `json:"last_run,omitempty"`
+}
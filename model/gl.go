@@ -0,0 +1,141 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TipeAsset     = "asset"
+	TipeLiability = "liability"
+	TipeIncome    = "income"
+	TipeExpense   = "expense"
+	TipeEquity    = "equity"
+)
+
+// Default chart of accounts codes, seeded by migrations 0007 and 0012.
+const (
+	AkunKas              = "1100"
+	AkunKliring          = "1900"
+	AkunSimpananNasabah  = "2100"
+	AkunUtangPajak       = "2200"
+	AkunEkuitasSaldoAwal = "3100"
+	AkunPendapatanBiaya  = "4100"
+	AkunPendapatanKurs   = "4200"
+	AkunBebanBunga       = "5100"
+	AkunBebanKurs        = "5200"
+)
+
+// JenisLabaKurs and JenisRugiKurs post the FX gain or loss of a
+// KonversiValas in IDR. They have no Transaksi of their own; the journal
+// line hangs off the conversion's debit leg.
+const (
+	JenisLabaKurs = "laba_kurs"
+	JenisRugiKurs = "rugi_kurs"
+)
+
+// AkunGL is an account of the bank's chart of accounts.
+type AkunGL struct {
+	gorm.Model
+	Kode string `gorm:"not null;uniqueIndex" json:"kode"`
+	Nama string `gorm:"not null" json:"nama"`
+	// Tipe is asset, liability, equity, income or expense. Asset and
+	// expense accounts carry a debit balance, the others a credit balance.
+	Tipe string `gorm:"not null" json:"tipe"`
+	// SimpananNasabah marks the liability accounts holding customer
+	// deposits; the trial balance proves them against nasabahs.saldo.
+	SimpananNasabah bool `gorm:"not null;default:false" json:"simpanan_nasabah"`
+}
+
+// AturanPosting maps a Transaksi Jenis to the GL accounts it is posted
+// to. Changes apply to transactions booked afterwards.
+type AturanPosting struct {
+	gorm.Model
+	Jenis      string `gorm:"not null;uniqueIndex" json:"jenis"`
+	AkunDebit  string `gorm:"not null" json:"akun_debit"`
+	AkunKredit string `gorm:"not null" json:"akun_kredit"`
+}
+
+// JurnalGL is one balanced GL posting: Nominal debited to AkunDebit and
+// credited to AkunKredit. Every Transaksi has one, of its own Jenis and
+// currency; the debit leg of a currency conversion also carries a
+// laba_kurs or rugi_kurs line in IDR.
+type JurnalGL struct {
+	gorm.Model
+	TransaksiID uint    `gorm:"not null;uniqueIndex:idx_jurnal_gls_transaksi_jenis" json:"transaksi_id"`
+	Jenis       string  `gorm:"not null;uniqueIndex:idx_jurnal_gls_transaksi_jenis" json:"jenis"`
+	AkunDebit   string  `gorm:"not null;index" json:"akun_debit"`
+	AkunKredit  string  `gorm:"not null;index" json:"akun_kredit"`
+	Nominal     float64 `gorm:"not null" json:"nominal"`
+	MataUang    string  `gorm:"size:3;not null;default:IDR" json:"mata_uang"`
+	// Tanggal is when the transaction was booked.
+	Tanggal time.Time `gorm:"not null;index" json:"tanggal"`
+}
+
+// DefaultAturanPosting are the posting rules seeded with the default chart:
+// cash movements against the customer deposit account, transfers through
+// the clearing account, fees to income, interest to expense, withheld tax
+// to the tax payable account, opening balances against equity and FX gains
+// and losses between the clearing account and income or expense.
+var DefaultAturanPosting = []AturanPosting{
+	{Jenis: JenisTabung, AkunDebit: AkunKas, AkunKredit: AkunSimpananNasabah},
+	{Jenis: JenisTarik, AkunDebit: AkunSimpananNasabah, AkunKredit: AkunKas},
+	{Jenis: JenisTransferKeluar, AkunDebit: AkunSimpananNasabah, AkunKredit: AkunKliring},
+	{Jenis: JenisTransferMasuk, AkunDebit: AkunKliring, AkunKredit: AkunSimpananNasabah},
+	{Jenis: JenisKoreksiDebit, AkunDebit: AkunSimpananNasabah, AkunKredit: AkunKas},
+	{Jenis: JenisKoreksiKredit, AkunDebit: AkunKas, AkunKredit: AkunSimpananNasabah},
+	{Jenis: JenisBiaya, AkunDebit: AkunSimpananNasabah, AkunKredit: AkunPendapatanBiaya},
+	{Jenis: JenisBunga, AkunDebit: AkunBebanBunga, AkunKredit: AkunSimpananNasabah},
+	{Jenis: JenisPajak, AkunDebit: AkunSimpananNasabah, AkunKredit: AkunUtangPajak},
+	{Jenis: JenisSaldoAwal, AkunDebit: AkunEkuitasSaldoAwal, AkunKredit: AkunSimpananNasabah},
+	{Jenis: JenisLabaKurs, AkunDebit: AkunKliring, AkunKredit: AkunPendapatanKurs},
+	{Jenis: JenisRugiKurs, AkunDebit: AkunBebanKurs, AkunKredit: AkunKliring},
+}
+
+type AkunGLRequest struct {
+	Kode            string `json:"kode" validate:"required,max=20"`
+	Nama            string `json:"nama" validate:"required"`
+	Tipe            string `json:"tipe" validate:"required,oneof=asset liability equity income expense"`
+	SimpananNasabah bool   `json:"simpanan_nasabah"`
+}
+
+type AturanPostingRequest struct {
+	AkunDebit  string `json:"akun_debit" validate:"required"`
+	AkunKredit string `json:"akun_kredit" validate:"required,nefield=AkunDebit"`
+}
+
+// NeracaSaldoBaris is one account and currency of the trial balance. Saldo
+// is on the account's normal side: Debit - Kredit for asset and expense
+// accounts, Kredit - Debit otherwise.
+type NeracaSaldoBaris struct {
+	Kode     string  `json:"kode"`
+	Nama     string  `json:"nama"`
+	Tipe     string  `json:"tipe"`
+	MataUang string  `json:"mata_uang"`
+	Debit    float64 `json:"debit"`
+	Kredit   float64 `json:"kredit"`
+	Saldo    float64 `json:"saldo"`
+}
+
+// NeracaSaldoTotal checks one currency of the trial balance: debits equal
+// credits, and the customer deposit accounts equal the sum of the
+// customers' balances.
+type NeracaSaldoTotal struct {
+	MataUang string  `json:"mata_uang"`
+	Debit    float64 `json:"debit"`
+	Kredit   float64 `json:"kredit"`
+	// LiabilitasNasabah is the balance of the SimpananNasabah accounts and
+	// SaldoNasabah the sum of nasabahs.saldo.
+	LiabilitasNasabah float64 `json:"liabilitas_nasabah"`
+	SaldoNasabah      float64 `json:"saldo_nasabah"`
+	Seimbang          bool    `json:"seimbang"`
+}
+
+type NeracaSaldoResponse struct {
+	Per   time.Time          `json:"per"`
+	Baris []NeracaSaldoBaris `json:"baris"`
+	Total []NeracaSaldoTotal `json:"total"`
+	// Seimbang is true when every currency balances.
+	Seimbang bool `json:"seimbang"`
+}
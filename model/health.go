@@ -0,0 +1,20 @@
+package model
+
+const (
+	HealthUp          = "up"
+	HealthDown        = "down"
+	HealthOK          = "ok"
+	HealthUnavailable = "unavailable"
+	HealthDraining    = "draining"
+)
+
+type ComponentStatus struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type HealthResponse struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+}
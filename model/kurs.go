@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MataUangIDR is the bank's base currency. Rates are quoted against it and
+// FX gains and losses are booked in it.
+const MataUangIDR = "IDR"
+
+const (
+	KursSumberCSV = "csv"
+	KursSumberAPI = "api"
+)
+
+// Kurs is the IDR price of one unit of MataUang from BerlakuMulai until a
+// later rate for the same currency takes effect.
+type Kurs struct {
+	gorm.Model
+	MataUang string `gorm:"size:3;not null;uniqueIndex:idx_kurs_mata_uang_berlaku_mulai" json:"mata_uang"`
+	// Beli is what the bank pays when it buys the currency from a customer;
+	// Jual is what it charges when it sells. Jual >= Beli.
+	Beli         float64   `gorm:"not null" json:"beli"`
+	Jual         float64   `gorm:"not null" json:"jual"`
+	BerlakuMulai time.Time `gorm:"not null;uniqueIndex:idx_kurs_mata_uang_berlaku_mulai" json:"berlaku_mulai"`
+	// Sumber is "csv" or "api".
+	Sumber string `gorm:"not null" json:"sumber"`
+}
+
+// Tengah is the mid rate, used to value FX gains and losses.
+func (k Kurs) Tengah() float64 {
+	return (k.Beli + k.Jual) / 2
+}
+
+// KonversiValas records the FX side of a cross-currency transfer: the rates
+// applied to both legs and the bank's gain or loss on them.
+type KonversiValas struct {
+	gorm.Model
+	TransaksiDebitID  uint    `gorm:"not null;uniqueIndex" json:"transaksi_debit_id"`
+	TransaksiKreditID uint    `gorm:"not null" json:"transaksi_kredit_id"`
+	MataUangAsal      string  `gorm:"size:3;not null" json:"mata_uang_asal"`
+	NominalAsal       float64 `gorm:"not null" json:"nominal_asal"`
+	MataUangTujuan    string  `gorm:"size:3;not null" json:"mata_uang_tujuan"`
+	NominalTujuan     float64 `gorm:"not null" json:"nominal_tujuan"`
+	// KursAsalID and KursTujuanID are the rates used; nil for the IDR side.
+	KursAsalID   *uint `json:"kurs_asal_id,omitempty"`
+	KursTujuanID *uint `json:"kurs_tujuan_id,omitempty"`
+	// Kurs is the effective rate: units of MataUangTujuan per MataUangAsal.
+	Kurs float64 `gorm:"not null" json:"kurs"`
+	// LabaRugi is the bank's gain (positive) or loss in IDR: the mid-rate
+	// value of the debit minus that of the credit.
+	LabaRugi float64 `gorm:"not null" json:"laba_rugi"`
+}
+
+type KursRequest struct {
+	MataUang string  `json:"mata_uang" validate:"required,iso4217"`
+	Beli     float64 `json:"beli" validate:"required,gt=0"`
+	Jual     float64 `json:"jual" validate:"required,gtefield=Beli"`
+	// BerlakuMulai defaults to now.
+	BerlakuMulai *time.Time `json:"berlaku_mulai"`
+}
+
+type KursResponse struct {
+	ID           uint      `json:"id"`
+	MataUang     string    `json:"mata_uang"`
+	Beli         float64   `json:"beli"`
+	Jual         float64   `json:"jual"`
+	Tengah       float64   `json:"tengah"`
+	BerlakuMulai time.Time `json:"berlaku_mulai"`
+	Sumber       string    `json:"sumber"`
+	// Basis is the currency the rates are quoted in, always IDR.
+	Basis string `json:"basis"`
+}
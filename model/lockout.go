@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LoginThrottle counts recent failed logins for one key, either an email
+// ("email:<address>") or a client IP ("ip:<address>").
+type LoginThrottle struct {
+	gorm.Model
+	Key           string     `gorm:"uniqueIndex;not null"`
+	Failures      int        `gorm:"not null;default:0"`
+	LastFailureAt time.Time  `gorm:"not null"`
+	LockedUntil   *time.Time `gorm:"index"`
+}
+
+type UnlockRequest struct {
+	Email string `json:"email" validate:"omitempty,email"`
+	IP    string `json:"ip" validate:"omitempty,ip"`
+}
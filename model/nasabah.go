@@ -1,20 +1,53 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Nasabah struct {
 	gorm.Model
 	Nama       string  `gorm:"not null" json:"nama"`
 	NIK        string  `gorm:"unique;not null" json:"nik"`
 	NoHP       string  `gorm:"unique;not null" json:"no_hp"`
+	Email      string  `json:"email"`
+	Bahasa     string  `gorm:"not null;default:id" json:"bahasa"`
 	NoRekening string  `gorm:"unique;not null" json:"-"`
-	Saldo      float64 `gorm:"default:0;check:saldo >= 0" json:"-"`
+	Saldo      float64 `gorm:"default:0;check:chk_nasabahs_saldo,saldo >= 0 OR overdraft_at IS NOT NULL" json:"-"`
+	// MataUang is the ISO 4217 currency Saldo and every Transaksi of the
+	// account are denominated in.
+	MataUang string `gorm:"size:3;not null;default:IDR" json:"mata_uang"`
+	// FrozenAt is set by `gobanking admin account freeze`; a frozen account
+	// takes no deposits or withdrawals.
+	FrozenAt *time.Time `json:"-"`
+	// OverdraftAt is set when an approved reversal took Saldo below zero and
+	// cleared once the balance is back to zero or more. Only then may Saldo
+	// be negative.
+	OverdraftAt *time.Time `json:"-"`
+	// DormantAt is set by the end-of-day run once the customer has not
+	// deposited, withdrawn or transferred out for EOD_DORMANT_DAYS, and
+	// cleared by the first run after they do again.
+	DormantAt *time.Time `json:"-"`
 }
 
 type DaftarRequest struct {
-	Nama string `json:"nama" validate:"required"`
-	NIK  string `json:"nik" validate:"required"`
-	NoHP string `json:"no_hp" validate:"required"`
+	Nama   string `json:"nama" validate:"required"`
+	NIK    string `json:"nik" validate:"required"`
+	NoHP   string `json:"no_hp" validate:"required"`
+	Email  string `json:"email" validate:"omitempty,email"`
+	Bahasa string `json:"bahasa" validate:"omitempty,oneof=id en"`
+	// MataUang is the ISO 4217 account currency, IDR when empty.
+	MataUang string `json:"mata_uang" validate:"omitempty,iso4217"`
+}
+
+// TabungRequest is a deposit. Referensi is the channel's reference for it,
+// e.g. a partner's settlement reference; it is optional and unique per
+// account.
+type TabungRequest struct {
+	NoRekening string  `json:"no_rekening" validate:"required"`
+	Nominal    float64 `json:"nominal" validate:"required,gt=0"`
+	Referensi  string  `json:"referensi,omitempty" validate:"max=64"`
 }
 
 type TransaksiRequest struct {
@@ -27,9 +60,15 @@ type ErrorResponse struct {
 }
 
 type SaldoResponse struct {
-	Saldo float64 `json:"saldo"`
+	Saldo    float64 `json:"saldo"`
+	MataUang string  `json:"mata_uang"`
+	// Overdraft is true while a reversal has left the balance negative.
+	Overdraft bool `json:"overdraft,omitempty"`
+	// Dormant is true while the account is marked dormant.
+	Dormant bool `json:"dormant,omitempty"`
 }
 
 type RekeningResponse struct {
 	NoRekening string `json:"no_rekening"`
+	MataUang   string `json:"mata_uang"`
 }
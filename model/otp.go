@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	OTPMethodSMS  = "sms"
+	OTPMethodTOTP = "totp"
+)
+
+const (
+	OTPStatusPending  = "pending"
+	OTPStatusVerified = "verified"
+	OTPStatusFailed   = "failed"
+)
+
+type OTPChallenge struct {
+	gorm.Model
+	UserID      uint      `gorm:"not null;index"`
+	Purpose     string    `gorm:"not null"`
+	Method      string    `gorm:"not null"`
+	CodeHash    string    `gorm:"not null"`
+	Target      string    `gorm:"not null;default:''"`
+	Payload     string    `gorm:"type:text;not null"`
+	Status      string    `gorm:"not null;index"`
+	Attempts    int       `gorm:"not null;default:0"`
+	MaxAttempts int       `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	VerifiedAt  *time.Time
+}
+
+type OTPChallengeResponse struct {
+	Remark      string    `json:"remark"`
+	ChallengeID uint      `json:"challenge_id"`
+	Method      string    `json:"method"`
+	Target      string    `json:"target,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type OTPVerifyRequest struct {
+	ChallengeID uint   `json:"challenge_id" validate:"required"`
+	Code        string `json:"code" validate:"required"`
+}
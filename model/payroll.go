@@ -0,0 +1,107 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	PayrollStatusPending    = "pending"
+	PayrollStatusProcessing = "processing"
+	PayrollStatusCompleted  = "completed"
+)
+
+const (
+	PayrollItemPending = "pending"
+	PayrollItemSuccess = "success"
+	PayrollItemFailed  = "failed"
+)
+
+// PayrollBatch is a bulk disbursement from one source account. The totals
+// are recomputed from its items after every chunk, so SuccessNominal always
+// equals what has been debited from the source for this batch.
+type PayrollBatch struct {
+	gorm.Model
+	UserID           uint    `gorm:"not null;index" json:"-"`
+	NoRekeningSumber string  `gorm:"not null;index" json:"no_rekening_sumber"`
+	Keterangan       string  `json:"keterangan"`
+	Status           string  `gorm:"not null;index" json:"status"`
+	TotalItems       int     `gorm:"not null;default:0" json:"total_items"`
+	TotalNominal     float64 `gorm:"not null;default:0" json:"total_nominal"`
+	SuccessItems     int     `gorm:"not null;default:0" json:"success_items"`
+	SuccessNominal   float64 `gorm:"not null;default:0" json:"success_nominal"`
+	FailedItems      int     `gorm:"not null;default:0" json:"failed_items"`
+	FailedNominal    float64 `gorm:"not null;default:0" json:"failed_nominal"`
+	// ClaimedUntil hides a batch from other replicas while one processes it.
+	ClaimedUntil *time.Time `gorm:"index" json:"-"`
+	StartedAt    *time.Time `json:"started_at"`
+	CompletedAt  *time.Time `json:"completed_at"`
+	// MataUang is the source account's currency, which every Nominal of
+	// the batch is in.
+	MataUang string `gorm:"size:3;not null;default:IDR" json:"mata_uang"`
+}
+
+// PayrollItem is one row of a batch. Rows rejected by validation are stored
+// as failed straight away and never touch the ledger.
+type PayrollItem struct {
+	gorm.Model
+	BatchID     uint       `gorm:"not null;uniqueIndex:idx_payroll_items_batch_baris" json:"-"`
+	Baris       int        `gorm:"not null;uniqueIndex:idx_payroll_items_batch_baris" json:"baris"`
+	NoRekening  string     `gorm:"not null" json:"no_rekening"`
+	Nama        string     `json:"nama,omitempty"`
+	Nominal     float64    `gorm:"not null" json:"nominal"`
+	Status      string     `gorm:"not null" json:"status"`
+	Error       string     `json:"error,omitempty"`
+	TransaksiID *uint      `json:"transaksi_id,omitempty"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+}
+
+type PayrollItemRequest struct {
+	NoRekening string  `json:"no_rekening"`
+	Nama       string  `json:"nama"`
+	Nominal    float64 `json:"nominal"`
+}
+
+// PayrollRequest is the JSON form of a batch. Rows are validated one by one
+// after the batch itself is accepted, so they carry no validate tags.
+type PayrollRequest struct {
+	NoRekeningSumber string               `json:"no_rekening_sumber" validate:"required"`
+	Keterangan       string               `json:"keterangan"`
+	Items            []PayrollItemRequest `json:"items" validate:"required,min=1"`
+}
+
+type PayrollBatchResponse struct {
+	ID               uint    `json:"id"`
+	NoRekeningSumber string  `json:"no_rekening_sumber"`
+	Keterangan       string  `json:"keterangan,omitempty"`
+	Status           string  `json:"status"`
+	TotalItems       int     `json:"total_items"`
+	TotalNominal     float64 `json:"total_nominal"`
+	MataUang         string  `json:"mata_uang"`
+	SuccessItems     int     `json:"success_items"`
+	SuccessNominal   float64 `json:"success_nominal"`
+	FailedItems      int     `json:"failed_items"`
+	FailedNominal    float64 `json:"failed_nominal"`
+	// JournalNominal is what the ledger shows debited from the source for
+	// this batch; it matches SuccessNominal once the batch completes.
+	JournalNominal float64    `json:"journal_nominal"`
+	CreatedAt      time.Time  `json:"created_at"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	// Rejected lists the rows that failed validation; only returned when
+	// the batch is created.
+	Rejected []PayrollItemResponse `json:"rejected,omitempty"`
+}
+
+type PayrollItemResponse struct {
+	Baris       int        `json:"baris"`
+	NoRekening  string     `json:"no_rekening"`
+	Nama        string     `json:"nama,omitempty"`
+	Nominal     float64    `json:"nominal"`
+	MataUang    string     `json:"mata_uang"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	TransaksiID *uint      `json:"transaksi_id,omitempty"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "gorm.io/gorm"
+
+// Penerima is a saved transfer beneficiary of an account.
+type Penerima struct {
+	gorm.Model
+	NoRekening       string `gorm:"not null;uniqueIndex:idx_penerima_rekening_tujuan" json:"no_rekening"`
+	NoRekeningTujuan string `gorm:"not null;uniqueIndex:idx_penerima_rekening_tujuan" json:"no_rekening_tujuan"`
+	Alias            string `json:"alias"`
+}
+
+type PenerimaRequest struct {
+	NoRekening       string `json:"no_rekening" validate:"required"`
+	NoRekeningTujuan string `json:"no_rekening_tujuan" validate:"required,nefield=NoRekening"`
+	Alias            string `json:"alias"`
+}
+
+type UbahNoHPRequest struct {
+	NoRekening string `json:"no_rekening" validate:"required"`
+	NoHP       string `json:"no_hp" validate:"required"`
+}
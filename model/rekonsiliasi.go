@@ -0,0 +1,84 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RekonsiliasiCSV   = "csv"
+	RekonsiliasiMT940 = "mt940"
+)
+
+// Reconciliation outcomes of one settlement entry or deposit.
+const (
+	// RekonsiliasiMatched pairs an entry with a deposit of the same amount.
+	RekonsiliasiMatched = "matched"
+	// RekonsiliasiMissingInternal is an entry with no deposit in our books.
+	RekonsiliasiMissingInternal = "missing_internal"
+	// RekonsiliasiMissingExternal is a deposit in the file's period that
+	// the file does not contain.
+	RekonsiliasiMissingExternal = "missing_external"
+	// RekonsiliasiAmountMismatch pairs an entry with a deposit by reference
+	// whose amount differs by more than the tolerance.
+	RekonsiliasiAmountMismatch = "amount_mismatch"
+)
+
+// Rekonsiliasi is one imported settlement file of a partner and the
+// outcome of matching it against tabung entries. The counters are
+// recomputed whenever an item is resolved.
+type Rekonsiliasi struct {
+	gorm.Model
+	Mitra    string `gorm:"not null;index" json:"mitra"`
+	NamaFile string `json:"nama_file"`
+	Format   string `gorm:"not null" json:"format"`
+	// Checksum is the SHA-256 of the file, so the same file is not
+	// imported twice.
+	Checksum   string `gorm:"not null;uniqueIndex" json:"-"`
+	ImportedBy uint   `gorm:"not null" json:"imported_by"`
+	// TanggalAwal and TanggalAkhir bound the entries' dates; deposits
+	// booked in that range are expected in the file.
+	TanggalAwal     time.Time `gorm:"type:date;not null" json:"tanggal_awal"`
+	TanggalAkhir    time.Time `gorm:"type:date;not null" json:"tanggal_akhir"`
+	TotalEntri      int       `gorm:"not null;default:0" json:"total_entri"`
+	Diabaikan       int       `gorm:"not null;default:0" json:"diabaikan"`
+	Matched         int       `gorm:"not null;default:0" json:"matched"`
+	MissingInternal int       `gorm:"not null;default:0" json:"missing_internal"`
+	MissingExternal int       `gorm:"not null;default:0" json:"missing_external"`
+	AmountMismatch  int       `gorm:"not null;default:0" json:"amount_mismatch"`
+	// Open is the number of exceptions not yet resolved.
+	Open int `gorm:"not null;default:0" json:"open"`
+}
+
+// RekonsiliasiItem is one line of the result: a settlement entry, the
+// deposit it was paired with, or both. A deposit belongs to at most one
+// pairing; missing_external items do not claim theirs, so a later file
+// can still match it.
+type RekonsiliasiItem struct {
+	gorm.Model
+	RekonsiliasiID uint   `gorm:"not null;index" json:"-"`
+	Status         string `gorm:"not null;index" json:"status"`
+	// Baris is the entry's line in the file; 0 for missing_external.
+	Baris            int        `gorm:"not null;default:0" json:"baris,omitempty"`
+	Referensi        string     `gorm:"not null;default:'';index" json:"referensi,omitempty"`
+	NoRekening       string     `json:"no_rekening,omitempty"`
+	MataUang         string     `gorm:"size:3;not null;default:IDR" json:"mata_uang"`
+	NominalEksternal float64    `gorm:"not null;default:0" json:"nominal_eksternal"`
+	TanggalEksternal *time.Time `gorm:"type:date" json:"tanggal_eksternal,omitempty"`
+	TransaksiID      *uint      `gorm:"uniqueIndex:idx_rekonsiliasi_items_transaksi,where:status <> 'missing_external' AND deleted_at IS NULL" json:"transaksi_id,omitempty"`
+	NominalInternal  float64    `gorm:"not null;default:0" json:"nominal_internal"`
+	TanggalInternal  *time.Time `gorm:"type:date" json:"tanggal_internal,omitempty"`
+	// Selisih is NominalEksternal - NominalInternal.
+	Selisih    float64    `gorm:"not null;default:0" json:"selisih"`
+	Catatan    string     `json:"catatan,omitempty"`
+	ResolvedBy *uint      `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// ResolveRekonsiliasiRequest closes an exception. TransaksiID pairs a
+// missing_internal entry with a deposit found by hand.
+type ResolveRekonsiliasiRequest struct {
+	Catatan     string `json:"catatan" validate:"required,max=500"`
+	TransaksiID *uint  `json:"transaksi_id"`
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ReversalPending   = "pending"
+	ReversalCompleted = "completed"
+	ReversalRejected  = "rejected"
+)
+
+// Reversal is a request to undo a tabung or tarik by booking an equal and
+// opposite koreksi entry. Requests above REVERSAL_APPROVAL_THRESHOLD, and
+// any that may overdraw the account, wait as pending for a supervisor.
+type Reversal struct {
+	gorm.Model
+	// TransaksiID is the entry being reversed. At most one request per
+	// transaction can be pending.
+	TransaksiID uint    `gorm:"not null;index;uniqueIndex:idx_reversals_pending,where:status = 'pending'" json:"transaksi_id"`
+	NoRekening  string  `gorm:"not null;index" json:"no_rekening"`
+	Jenis       string  `gorm:"not null" json:"jenis"`
+	Nominal     float64 `gorm:"not null" json:"nominal"`
+	MataUang    string  `gorm:"size:3;not null;default:IDR" json:"mata_uang"`
+	Alasan      string  `gorm:"not null" json:"alasan"`
+	// AllowOverdraft lets the reversal of a deposit that was already spent
+	// take the balance below zero instead of being rejected.
+	AllowOverdraft bool   `gorm:"not null;default:false" json:"allow_overdraft"`
+	Status         string `gorm:"not null;index" json:"status"`
+	RequestedBy    uint   `gorm:"not null" json:"requested_by"`
+	// DecidedBy is the admin who approved or rejected the request; nil when
+	// it was posted without approval.
+	DecidedBy *uint      `json:"decided_by,omitempty"`
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+	// KoreksiID is the opposite entry booked on completion.
+	KoreksiID *uint `json:"koreksi_id,omitempty"`
+	// Overdraft records that the koreksi left the balance negative.
+	Overdraft bool   `gorm:"not null;default:false" json:"overdraft"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ReversalRequest struct {
+	TransaksiID    uint   `json:"transaksi_id" validate:"required"`
+	Alasan         string `json:"alasan" validate:"required,max=500"`
+	AllowOverdraft bool   `json:"allow_overdraft"`
+}
+
+type ReversalDecisionRequest struct {
+	Alasan string `json:"alasan" validate:"max=500"`
+}
+
+type ReversalResponse struct {
+	ID             uint       `json:"id"`
+	TransaksiID    uint       `json:"transaksi_id"`
+	NoRekening     string     `json:"no_rekening"`
+	Jenis          string     `json:"jenis"`
+	Nominal        float64    `json:"nominal"`
+	MataUang       string     `json:"mata_uang"`
+	Alasan         string     `json:"alasan"`
+	AllowOverdraft bool       `json:"allow_overdraft"`
+	Status         string     `json:"status"`
+	RequestedBy    uint       `json:"requested_by"`
+	DecidedBy      *uint      `json:"decided_by,omitempty"`
+	DecidedAt      *time.Time `json:"decided_at,omitempty"`
+	KoreksiID      *uint      `json:"koreksi_id,omitempty"`
+	Overdraft      bool       `json:"overdraft"`
+	Error          string     `json:"error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
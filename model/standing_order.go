@@ -0,0 +1,111 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ScheduleOnce    = "once"
+	ScheduleMonthly = "monthly"
+	ScheduleCron    = "cron"
+)
+
+const (
+	StandingOrderActive    = "active"
+	StandingOrderCompleted = "completed"
+	StandingOrderFailed    = "failed"
+	StandingOrderCancelled = "cancelled"
+)
+
+const (
+	StandingOrderRunSuccess = "success"
+	StandingOrderRunRetry   = "retry"
+	StandingOrderRunFailed  = "failed"
+)
+
+// StandingOrder is a transfer instruction executed on a schedule: once at
+// StartAt, monthly on DayOfMonth, or on a cron expression.
+type StandingOrder struct {
+	gorm.Model
+	UserID           uint    `gorm:"not null;index" json:"-"`
+	NoRekening       string  `gorm:"not null;index" json:"no_rekening"`
+	NoRekeningTujuan string  `gorm:"not null" json:"no_rekening_tujuan"`
+	Nominal          float64 `gorm:"not null" json:"nominal"`
+	Keterangan       string  `json:"keterangan"`
+	Schedule         string  `gorm:"not null" json:"schedule"`
+	// DayOfMonth is 1-31 for monthly orders; months without that day run on
+	// their last day.
+	DayOfMonth int        `gorm:"not null;default:0" json:"day_of_month"`
+	Cron       string     `json:"cron"`
+	StartAt    time.Time  `gorm:"not null" json:"start_at"`
+	EndAt      *time.Time `json:"end_at"`
+	Status     string     `gorm:"not null;index" json:"status"`
+	// NextRunAt is the occurrence being executed or waited for; nil once
+	// the order has ended.
+	NextRunAt *time.Time `json:"next_run_at"`
+	// Attempts counts the failed attempts at NextRunAt.
+	Attempts int `gorm:"not null;default:0" json:"attempts"`
+	// NextAttemptAt is when the executor picks the order up next. It moves
+	// forward while an attempt is in flight and between retries.
+	NextAttemptAt *time.Time `gorm:"index" json:"-"`
+	// MataUang is the source account's currency, which Nominal is in.
+	MataUang string `gorm:"size:3;not null;default:IDR" json:"mata_uang"`
+}
+
+// StandingOrderRun records one execution attempt of a StandingOrder.
+type StandingOrderRun struct {
+	gorm.Model
+	StandingOrderID uint      `gorm:"not null;index" json:"standing_order_id"`
+	ScheduledAt     time.Time `gorm:"not null" json:"scheduled_at"`
+	Attempt         int       `gorm:"not null" json:"attempt"`
+	Status          string    `gorm:"not null" json:"status"`
+	Error           string    `json:"error,omitempty"`
+	TransaksiID     *uint     `json:"transaksi_id,omitempty"`
+}
+
+type StandingOrderRequest struct {
+	NoRekening       string  `json:"no_rekening" validate:"required"`
+	NoRekeningTujuan string  `json:"no_rekening_tujuan" validate:"required,nefield=NoRekening"`
+	Nominal          float64 `json:"nominal" validate:"required,gt=0"`
+	Keterangan       string  `json:"keterangan"`
+	// Schedule is "once", "monthly" or "cron".
+	Schedule   string `json:"schedule" validate:"required,oneof=once monthly cron"`
+	DayOfMonth int    `json:"day_of_month"`
+	// Cron has five fields: minute hour day-of-month month day-of-week. The
+	// minute and hour must be single values, so it runs at most daily.
+	Cron string `json:"cron"`
+	// StartAt is the execution time of a one-off order and the earliest
+	// occurrence of a recurring one (default now).
+	StartAt *time.Time `json:"start_at"`
+	EndAt   *time.Time `json:"end_at"`
+}
+
+type StandingOrderResponse struct {
+	ID               uint       `json:"id"`
+	NoRekening       string     `json:"no_rekening"`
+	NoRekeningTujuan string     `json:"no_rekening_tujuan"`
+	Nominal          float64    `json:"nominal"`
+	MataUang         string     `json:"mata_uang"`
+	Keterangan       string     `json:"keterangan,omitempty"`
+	Schedule         string     `json:"schedule"`
+	DayOfMonth       int        `json:"day_of_month,omitempty"`
+	Cron             string     `json:"cron,omitempty"`
+	StartAt          time.Time  `json:"start_at"`
+	EndAt            *time.Time `json:"end_at,omitempty"`
+	Status           string     `json:"status"`
+	NextRunAt        *time.Time `json:"next_run_at,omitempty"`
+	Attempts         int        `json:"attempts"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type StandingOrderRunResponse struct {
+	ID          uint      `json:"id"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	Attempt     int       `json:"attempt"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	TransaksiID *uint     `json:"transaksi_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
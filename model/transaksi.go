@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	JenisTabung         = "tabung"
+	JenisTarik          = "tarik"
+	JenisTransferKeluar = "transfer_keluar"
+	JenisTransferMasuk  = "transfer_masuk"
+	// JenisKoreksiDebit and JenisKoreksiKredit are reversal entries: a
+	// tabung is reversed by a koreksi_debit, a tarik by a koreksi_kredit.
+	JenisKoreksiDebit  = "koreksi_debit"
+	JenisKoreksiKredit = "koreksi_kredit"
+	// JenisBiaya, JenisBunga and JenisPajak are bank-initiated entries: a
+	// fee charged, interest credited and tax withheld on that interest.
+	JenisBiaya = "biaya"
+	JenisBunga = "bunga"
+	JenisPajak = "pajak"
+	// JenisSaldoAwal is the opening balance of an account whose balance
+	// predates its journal, booked by migration 0012 ahead of its other
+	// entries. Nominal is negative for an account opened overdrawn.
+	JenisSaldoAwal = "saldo_awal"
+)
+
+// JenisKredit lists the Jenis that increase the balance; all others
+// decrease it.
+var JenisKredit = []string{JenisTabung, JenisTransferMasuk, JenisKoreksiKredit, JenisBunga, JenisSaldoAwal}
+
+type Transaksi struct {
+	gorm.Model
+	NasabahID  uint    `gorm:"not null;index" json:"-"`
+	NoRekening string  `gorm:"not null;index" json:"no_rekening"`
+	Jenis      string  `gorm:"not null" json:"jenis"`
+	Nominal    float64 `gorm:"not null" json:"nominal"`
+	SaldoAkhir float64 `gorm:"not null" json:"saldo_akhir"`
+	// MataUang is the account's currency; Nominal and SaldoAkhir are in it.
+	MataUang string `gorm:"size:3;not null;default:IDR" json:"mata_uang"`
+	// Referensi is the caller's idempotency key, unique per account when
+	// set.
+	Referensi string `gorm:"not null;default:''" json:"referensi,omitempty"`
+	// NoRekeningLawan is the other side of a transfer.
+	NoRekeningLawan string `gorm:"not null;default:''" json:"no_rekening_lawan,omitempty"`
+	// ReversalOfID links a koreksi entry to the Transaksi it reverses. It is
+	// unique, so a transaction can be reversed only once.
+	ReversalOfID *uint `gorm:"uniqueIndex" json:"reversal_of_id,omitempty"`
+	// TanggalBisnis is the business date the entry belongs to: the current
+	// one, or the next once today's cut-off has passed.
+	TanggalBisnis time.Time `gorm:"type:date;not null;index" json:"tanggal_bisnis"`
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	WebhookStatusPending  = "pending"
+	WebhookStatusRetrying = "retrying"
+	WebhookStatusSuccess  = "success"
+	WebhookStatusDead     = "dead"
+)
+
+type WebhookSubscription struct {
+	gorm.Model
+	UserID     uint   `gorm:"not null;index" json:"-"`
+	URL        string `gorm:"not null" json:"url"`
+	Events     string `gorm:"not null" json:"-"`
+	NoRekening string `gorm:"index" json:"no_rekening"`
+	Secret     string `gorm:"not null" json:"-"`
+	Aktif      bool   `gorm:"not null;default:true" json:"aktif"`
+}
+
+type WebhookDelivery struct {
+	gorm.Model
+	SubscriptionID uint       `gorm:"not null;index" json:"subscription_id"`
+	Event          string     `gorm:"not null" json:"event"`
+	Payload        string     `gorm:"type:text;not null" json:"payload"`
+	Status         string     `gorm:"not null;index" json:"status"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	ReplayOf       *uint      `json:"replay_of"`
+}
+
+type WebhookRequest struct {
+	URL        string   `json:"url" validate:"required,url"`
+	Events     []string `json:"events" validate:"required,min=1,dive,required"`
+	NoRekening string   `json:"no_rekening"`
+	Aktif      *bool    `json:"aktif"`
+}
+
+type WebhookResponse struct {
+	ID         uint      `json:"id"`
+	URL        string    `json:"url"`
+	Events     []string  `json:"events"`
+	NoRekening string    `json:"no_rekening,omitempty"`
+	Aktif      bool      `json:"aktif"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uint       `json:"id"`
+	Event          string     `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	ReplayOf       *uint      `json:"replay_of,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -0,0 +1,87 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Async queues messages and delivers them from background workers so the
+// caller never waits on, or fails because of, a notification.
+type Async struct {
+	next    Notifier
+	logger  *slog.Logger
+	timeout time.Duration
+	queue   chan Message
+	wg      sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewAsync(next Notifier, logger *slog.Logger, queueSize int) *Async {
+	return &Async{
+		next:    next,
+		logger:  logger,
+		timeout: 30 * time.Second,
+		queue:   make(chan Message, queueSize),
+	}
+}
+
+// Send enqueues msg and returns immediately. A full queue drops the message.
+func (a *Async) Send(ctx context.Context, msg Message) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		a.logger.Warn("notifikasi ditolak, antrian sudah ditutup", "channel", msg.Channel)
+		return nil
+	}
+
+	select {
+	case a.queue <- msg:
+	default:
+		a.logger.Warn("antrian notifikasi penuh, pesan dibuang",
+			"channel", msg.Channel,
+			"subject", msg.Subject,
+		)
+	}
+	return nil
+}
+
+// Start launches workers that deliver queued messages.
+func (a *Async) Start(workers int) {
+	for i := 0; i < workers; i++ {
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			for msg := range a.queue {
+				a.deliver(msg)
+			}
+		}()
+	}
+}
+
+// Close stops accepting messages and waits for the queue to drain.
+func (a *Async) Close() {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+	a.wg.Wait()
+}
+
+func (a *Async) deliver(msg Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+
+	if err := a.next.Send(ctx, msg); err != nil {
+		a.logger.Error("gagal mengirim notifikasi",
+			"channel", msg.Channel,
+			"subject", msg.Subject,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,176 @@
+package notifier
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+const (
+	LangID = "id"
+	LangEN = "en"
+)
+
+const (
+	TemplateTabung        = "tabung"
+	TemplateTarik         = "tarik"
+	TemplateTransferMasuk = "transfer_masuk"
+	TemplateKoreksi       = "koreksi"
+	TemplateOTP           = "otp"
+	TemplateLockout       = "lockout"
+
+	TemplateStandingOrderGagal = "standing_order_gagal"
+)
+
+type entry struct {
+	subject string
+	body    string
+}
+
+// catalog holds the message templates per template key and language.
+var catalog = map[string]map[string]entry{
+	TemplateTabung: {
+		LangID: {
+			subject: "Setoran diterima",
+			body:    "Halo {{.Nama}}, setoran {{uang .MataUang .Nominal}} ke rekening {{.NoRekening}} berhasil pada {{.Waktu}}. Saldo: {{uang .MataUang .Saldo}}.",
+		},
+		LangEN: {
+			subject: "Deposit received",
+			body:    "Hi {{.Nama}}, a deposit of {{amount .MataUang .Nominal}} to account {{.NoRekening}} succeeded at {{.Waktu}}. Balance: {{amount .MataUang .Saldo}}.",
+		},
+	},
+	TemplateTarik: {
+		LangID: {
+			subject: "Penarikan berhasil",
+			body:    "Halo {{.Nama}}, penarikan {{uang .MataUang .Nominal}} dari rekening {{.NoRekening}} berhasil pada {{.Waktu}}. Saldo: {{uang .MataUang .Saldo}}. Jika ini bukan Anda, hubungi bank segera.",
+		},
+		LangEN: {
+			subject: "Withdrawal completed",
+			body:    "Hi {{.Nama}}, a withdrawal of {{amount .MataUang .Nominal}} from account {{.NoRekening}} succeeded at {{.Waktu}}. Balance: {{amount .MataUang .Saldo}}. If this wasn't you, contact the bank immediately.",
+		},
+	},
+	TemplateTransferMasuk: {
+		LangID: {
+			subject: "Dana masuk",
+			body:    "Halo {{.Nama}}, rekening {{.NoRekening}} menerima transfer {{uang .MataUang .Nominal}} pada {{.Waktu}}. Saldo: {{uang .MataUang .Saldo}}.",
+		},
+		LangEN: {
+			subject: "Funds received",
+			body:    "Hi {{.Nama}}, account {{.NoRekening}} received a transfer of {{amount .MataUang .Nominal}} at {{.Waktu}}. Balance: {{amount .MataUang .Saldo}}.",
+		},
+	},
+	TemplateKoreksi: {
+		LangID: {
+			subject: "Koreksi transaksi",
+			body:    "Halo {{.Nama}}, transaksi {{uang .MataUang .Nominal}} pada rekening {{.NoRekening}} dibatalkan oleh bank pada {{.Waktu}}: {{.Alasan}}. Saldo: {{uang .MataUang .Saldo}}.",
+		},
+		LangEN: {
+			subject: "Transaction reversed",
+			body:    "Hi {{.Nama}}, a transaction of {{amount .MataUang .Nominal}} on account {{.NoRekening}} was reversed by the bank at {{.Waktu}}: {{.Alasan}}. Balance: {{amount .MataUang .Saldo}}.",
+		},
+	},
+	TemplateStandingOrderGagal: {
+		LangID: {
+			subject: "Transfer terjadwal gagal",
+			body:    "Halo {{.Nama}}, transfer terjadwal {{uang .MataUang .Nominal}} dari rekening {{.NoRekening}} ke {{.NoRekeningTujuan}} untuk jadwal {{.Jadwal}} gagal: {{.Alasan}}.",
+		},
+		LangEN: {
+			subject: "Scheduled transfer failed",
+			body:    "Hi {{.Nama}}, the scheduled transfer of {{amount .MataUang .Nominal}} from account {{.NoRekening}} to {{.NoRekeningTujuan}} due {{.Jadwal}} failed: {{.Alasan}}.",
+		},
+	},
+	TemplateOTP: {
+		LangID: {
+			subject: "Kode OTP",
+			body:    "Kode OTP Anda: {{.Kode}}. Berlaku {{.Menit}} menit. JANGAN berikan kode ini kepada siapa pun, termasuk petugas bank.",
+		},
+		LangEN: {
+			subject: "OTP code",
+			body:    "Your OTP code: {{.Kode}}. Valid for {{.Menit}} minutes. NEVER share this code with anyone, including bank staff.",
+		},
+	},
+	TemplateLockout: {
+		LangID: {
+			subject: "Akun Anda dikunci sementara",
+			body:    "Terdapat {{.Percobaan}} percobaan login gagal pada akun {{.Email}}. Akun dikunci hingga {{.Hingga}}. Jika ini bukan Anda, segera ganti password dan hubungi bank.",
+		},
+		LangEN: {
+			subject: "Your account has been temporarily locked",
+			body:    "There were {{.Percobaan}} failed login attempts on account {{.Email}}. The account is locked until {{.Hingga}}. If this wasn't you, change your password and contact the bank.",
+		},
+	},
+}
+
+// funcs format an amount with its ISO 4217 currency code, IDR when the
+// code is empty: uang the Indonesian way (Rp1.500,50; USD 12,50), amount the
+// English way (IDR 1,500.50; USD 12.50).
+var funcs = template.FuncMap{
+	"uang": func(mataUang string, v float64) string {
+		if mataUang == "" || mataUang == "IDR" {
+			if v < 0 {
+				// An overdrawn balance reads -Rp80.000, not Rp-80.000.
+				return "-Rp" + group(-v, ".", ",")
+			}
+			return "Rp" + group(v, ".", ",")
+		}
+		return mataUang + " " + group(v, ".", ",")
+	},
+	"amount": func(mataUang string, v float64) string {
+		if mataUang == "" {
+			mataUang = "IDR"
+		}
+		return mataUang + " " + group(v, ",", ".")
+	},
+}
+
+// Render fills the template for key in lang, falling back to Indonesian
+// when the language is unknown.
+func Render(key, lang string, data any) (subject, body string, err error) {
+	langs, ok := catalog[key]
+	if !ok {
+		return "", "", fmt.Errorf("template notifikasi tidak dikenal: %s", key)
+	}
+	e, ok := langs[lang]
+	if !ok {
+		e = langs[LangID]
+	}
+
+	tmpl, err := template.New(key).Funcs(funcs).Parse(e.body)
+	if err != nil {
+		return "", "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", "", err
+	}
+	return e.subject, buf.String(), nil
+}
+
+// group formats v with the given thousands and decimal separators, dropping
+// the fraction when it is zero.
+func group(v float64, thousands, decimal string) string {
+	s := fmt.Sprintf("%.2f", v)
+	whole, frac, _ := strings.Cut(s, ".")
+
+	neg := strings.HasPrefix(whole, "-")
+	whole = strings.TrimPrefix(whole, "-")
+
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(thousands)
+		}
+		b.WriteRune(r)
+	}
+
+	out := b.String()
+	if frac != "00" {
+		out += decimal + frac
+	}
+	if neg {
+		out = "-" + out
+	}
+	return out
+}
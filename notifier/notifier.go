@@ -0,0 +1,102 @@
+// Package notifier sends customer notifications over SMS and email.
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gobanking/config"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Channel string
+
+const (
+	ChannelSMS   Channel = "sms"
+	ChannelEmail Channel = "email"
+)
+
+type Message struct {
+	Channel Channel
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers a single message. Implementations may block on network
+// I/O; wrap them in Async when the caller must not wait.
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+var ErrChannelUnavailable = errors.New("channel notifikasi tidak tersedia")
+
+// Router hands each message to the notifier registered for its channel.
+type Router struct {
+	channels map[Channel]Notifier
+}
+
+func NewRouter() *Router {
+	return &Router{channels: make(map[Channel]Notifier)}
+}
+
+func (r *Router) Register(channel Channel, n Notifier) {
+	r.channels[channel] = n
+}
+
+func (r *Router) Send(ctx context.Context, msg Message) error {
+	n, ok := r.channels[msg.Channel]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrChannelUnavailable, msg.Channel)
+	}
+	return n.Send(ctx, msg)
+}
+
+// LogNotifier only writes messages to the logger. It stands in for channels
+// that are not configured, e.g. during local development.
+type LogNotifier struct {
+	Logger *slog.Logger
+}
+
+func (n LogNotifier) Send(ctx context.Context, msg Message) error {
+	n.Logger.Info("notifikasi (log)",
+		"channel", msg.Channel,
+		"to", msg.To,
+		"subject", msg.Subject,
+	)
+	return nil
+}
+
+// New builds a Router from configuration. Channels without configuration
+// fall back to LogNotifier.
+func New(cfg *config.Config) *Router {
+	r := NewRouter()
+	logOnly := LogNotifier{Logger: cfg.Logger}
+
+	if cfg.Notifier.SMTPHost != "" {
+		r.Register(ChannelEmail, SMTPNotifier{
+			Addr:     net.JoinHostPort(cfg.Notifier.SMTPHost, strconv.Itoa(cfg.Notifier.SMTPPort)),
+			From:     cfg.Notifier.SMTPFrom,
+			Username: cfg.Notifier.SMTPUser,
+			Password: cfg.Notifier.SMTPPassword,
+		})
+	} else {
+		r.Register(ChannelEmail, logOnly)
+	}
+
+	if cfg.Notifier.SMSGatewayURL != "" {
+		r.Register(ChannelSMS, SMSGateway{
+			URL:    cfg.Notifier.SMSGatewayURL,
+			APIKey: cfg.Notifier.SMSGatewayAPIKey,
+			Client: &http.Client{Timeout: 10 * time.Second},
+		})
+	} else {
+		r.Register(ChannelSMS, logOnly)
+	}
+
+	return r
+}
@@ -0,0 +1,131 @@
+// Package notifiertest provides a fake SMTP server for exercising
+// notifier.SMTPNotifier without a real mail relay.
+package notifiertest
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Mail is a message accepted by the fake server.
+type Mail struct {
+	From string
+	To   []string
+	Data string
+}
+
+// SMTPServer speaks just enough SMTP for net/smtp.SendMail without auth.
+type SMTPServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	mails    []Mail
+	wg       sync.WaitGroup
+}
+
+func NewSMTPServer() (*SMTPServer, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &SMTPServer{listener: l}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+func (s *SMTPServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *SMTPServer) Mails() []Mail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Mail(nil), s.mails...)
+}
+
+func (s *SMTPServer) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *SMTPServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *SMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(format string, args ...any) {
+		fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+
+	reply("220 notifiertest ESMTP")
+	var mail Mail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		upper := strings.ToUpper(cmd)
+
+		switch {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 notifiertest")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			mail = Mail{From: trimAddr(cmd[len("MAIL FROM:"):])}
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			mail.To = append(mail.To, trimAddr(cmd[len("RCPT TO:"):]))
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			mail.Data = data.String()
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+			reply("250 OK")
+		case upper == "RSET", upper == "NOOP":
+			reply("250 OK")
+		case upper == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func trimAddr(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		s = s[:i]
+	}
+	return strings.Trim(s, "<>")
+}
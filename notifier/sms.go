@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SMSGateway posts messages to an HTTP SMS provider as
+// {"to": "...", "message": "..."} with a bearer API key.
+type SMSGateway struct {
+	URL    string
+	APIKey string
+	Client *http.Client
+}
+
+func (g SMSGateway) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(map[string]string{
+		"to":      msg.To,
+		"message": msg.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.APIKey)
+	}
+
+	client := g.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sms gateway membalas status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package notifier_test
+
+import (
+	"context"
+	"gobanking/notifier"
+	"gobanking/notifier/notifiertest"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newSMTPServer(t *testing.T) *notifiertest.SMTPServer {
+	t.Helper()
+	srv, err := notifiertest.NewSMTPServer()
+	if err != nil {
+		t.Fatalf("NewSMTPServer: %v", err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	return srv
+}
+
+func TestSMTPNotifierSends(t *testing.T) {
+	srv := newSMTPServer(t)
+	n := notifier.SMTPNotifier{Addr: srv.Addr(), From: "bank@example.com"}
+
+	subject, body, err := notifier.Render(notifier.TemplateTabung, notifier.LangID, map[string]any{
+		"Nama":       "Budi",
+		"NoRekening": "******7890",
+		"MataUang":   "IDR",
+		"Nominal":    150000.0,
+		"Saldo":      1250000.5,
+		"Waktu":      "01-02-2024 09:30",
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	err = n.Send(context.Background(), notifier.Message{
+		Channel: notifier.ChannelEmail,
+		To:      "budi@example.com",
+		Subject: subject,
+		Body:    body,
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	mails := srv.Mails()
+	if len(mails) != 1 {
+		t.Fatalf("server got %d mails, want 1", len(mails))
+	}
+	m := mails[0]
+	if m.From != "bank@example.com" || len(m.To) != 1 || m.To[0] != "budi@example.com" {
+		t.Fatalf("envelope = %s -> %v, want bank@example.com -> [budi@example.com]", m.From, m.To)
+	}
+	for _, want := range []string{
+		"Subject: Setoran diterima\r\n",
+		"To: budi@example.com\r\n",
+		"Content-Type: text/plain; charset=UTF-8\r\n",
+		"Rp150.000",
+		"Rp1.250.000,50",
+	} {
+		if !strings.Contains(m.Data, want) {
+			t.Errorf("mail does not contain %q:\n%s", want, m.Data)
+		}
+	}
+}
+
+// TestAsyncNeverBlocksOnSMTP checks that a relay that cannot be reached
+// neither fails nor delays the sender, and that queued mail is delivered
+// once the queue is drained.
+func TestAsyncNeverBlocksOnSMTP(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := newSMTPServer(t)
+
+	down := notifier.NewAsync(notifier.SMTPNotifier{Addr: "127.0.0.1:1", From: "bank@example.com"}, logger, 8)
+	down.Start(1)
+	start := time.Now()
+	if err := down.Send(context.Background(), notifier.Message{Channel: notifier.ChannelEmail, To: "a@example.com"}); err != nil {
+		t.Fatalf("Send to unreachable relay: %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatal("Send waited on the relay")
+	}
+	down.Close()
+
+	up := notifier.NewAsync(notifier.SMTPNotifier{Addr: srv.Addr(), From: "bank@example.com"}, logger, 8)
+	up.Start(2)
+	for _, to := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		up.Send(context.Background(), notifier.Message{Channel: notifier.ChannelEmail, To: to, Subject: "tes", Body: "isi"})
+	}
+	up.Close()
+	if n := len(srv.Mails()); n != 3 {
+		t.Fatalf("server got %d mails after Close, want 3", n)
+	}
+}
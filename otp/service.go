@@ -0,0 +1,192 @@
+package otp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gobanking/config"
+	"gobanking/model"
+	"gobanking/notifier"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	PurposeTarik          = "tarik"
+	PurposeTambahPenerima = "tambah_penerima"
+	PurposeUbahNoHP       = "ubah_no_hp"
+	PurposePayroll        = "payroll"
+	PurposeBuatStanding   = "buat_standing_order"
+	PurposeUbahStanding   = "ubah_standing_order"
+)
+
+var (
+	ErrChallengeNotFound = errors.New("challenge OTP tidak ditemukan")
+	ErrChallengeExpired  = errors.New("OTP sudah kedaluwarsa")
+	ErrChallengeUsed     = errors.New("challenge OTP sudah tidak berlaku")
+	ErrTooManyAttempts   = errors.New("percobaan OTP melebihi batas")
+	ErrInvalidCode       = errors.New("kode OTP salah")
+)
+
+// Recipient identifies where an SMS code goes and in which language.
+type Recipient struct {
+	NoHP   string
+	Bahasa string
+}
+
+type Service struct {
+	db       *gorm.DB
+	cfg      *config.Config
+	notifier notifier.Notifier
+}
+
+func NewService(db *gorm.DB, cfg *config.Config, n notifier.Notifier) *Service {
+	return &Service{
+		db:       db,
+		cfg:      cfg,
+		notifier: n,
+	}
+}
+
+// Challenge stores a pending operation for user and starts the OTP step-up.
+// Users with an enrolled authenticator answer with a TOTP code; everyone
+// else receives a random code by SMS at to.
+func (s *Service) Challenge(ctx context.Context, userID uint, purpose string, payload any, to Recipient) (model.OTPChallenge, error) {
+	var user model.User
+	if err := s.db.WithContext(ctx).First(&user, userID).Error; err != nil {
+		return model.OTPChallenge{}, err
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return model.OTPChallenge{}, err
+	}
+
+	challenge := model.OTPChallenge{
+		UserID:      userID,
+		Purpose:     purpose,
+		Payload:     string(body),
+		Status:      model.OTPStatusPending,
+		MaxAttempts: s.cfg.OTP.MaxAttempts,
+		ExpiresAt:   time.Now().Add(s.cfg.OTP.TTL),
+	}
+
+	var code string
+	if user.TOTPEnabled {
+		challenge.Method = model.OTPMethodTOTP
+		challenge.CodeHash = "-"
+	} else {
+		code, err = RandomCode(Digits)
+		if err != nil {
+			return model.OTPChallenge{}, err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+		if err != nil {
+			return model.OTPChallenge{}, err
+		}
+		challenge.Method = model.OTPMethodSMS
+		challenge.CodeHash = string(hash)
+		challenge.Target = maskPhone(to.NoHP)
+	}
+
+	if err := s.db.WithContext(ctx).Create(&challenge).Error; err != nil {
+		return model.OTPChallenge{}, err
+	}
+
+	if challenge.Method == model.OTPMethodSMS {
+		subject, text, err := notifier.Render(notifier.TemplateOTP, to.Bahasa, map[string]any{
+			"Kode":  code,
+			"Menit": int(s.cfg.OTP.TTL.Minutes()),
+		})
+		if err != nil {
+			return model.OTPChallenge{}, err
+		}
+		s.notifier.Send(ctx, notifier.Message{
+			Channel: notifier.ChannelSMS,
+			To:      to.NoHP,
+			Subject: subject,
+			Body:    text,
+		})
+	}
+
+	return challenge, nil
+}
+
+// Verify checks code against the challenge and, on success, marks it used
+// so the guarded operation can run exactly once. Every wrong code counts
+// against the attempt limit.
+func (s *Service) Verify(ctx context.Context, userID, challengeID uint, code string) (model.OTPChallenge, error) {
+	var challenge model.OTPChallenge
+	// A wrong code must still commit the attempt counter, so it is reported
+	// through result instead of rolling the transaction back.
+	var result error
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", challengeID, userID).
+			First(&challenge).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrChallengeNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		if challenge.Status != model.OTPStatusPending {
+			return ErrChallengeUsed
+		}
+		if time.Now().After(challenge.ExpiresAt) {
+			return ErrChallengeExpired
+		}
+
+		ok, err := s.check(tx, challenge, code)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			challenge.Attempts++
+			if challenge.Attempts >= challenge.MaxAttempts {
+				challenge.Status = model.OTPStatusFailed
+			}
+			if err := tx.Save(&challenge).Error; err != nil {
+				return err
+			}
+			result = ErrInvalidCode
+			if challenge.Status == model.OTPStatusFailed {
+				result = ErrTooManyAttempts
+			}
+			return nil
+		}
+
+		now := time.Now()
+		challenge.Status = model.OTPStatusVerified
+		challenge.VerifiedAt = &now
+		return tx.Save(&challenge).Error
+	})
+
+	if err != nil {
+		return challenge, err
+	}
+	return challenge, result
+}
+
+func (s *Service) check(tx *gorm.DB, challenge model.OTPChallenge, code string) (bool, error) {
+	if challenge.Method == model.OTPMethodTOTP {
+		var user model.User
+		if err := tx.First(&user, challenge.UserID).Error; err != nil {
+			return false, err
+		}
+		return ValidateTOTP(user.TOTPSecret, code, time.Now()), nil
+	}
+	return bcrypt.CompareHashAndPassword([]byte(challenge.CodeHash), []byte(code)) == nil, nil
+}
+
+func maskPhone(noHP string) string {
+	if len(noHP) <= 4 {
+		return noHP
+	}
+	return "****" + noHP[len(noHP)-4:]
+}
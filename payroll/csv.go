@@ -0,0 +1,123 @@
+package payroll
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gobanking/model"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCSV = errors.New("file CSV tidak valid")
+
+// ParseCSV reads a batch with a header row naming at least no_rekening and
+// nominal, plus an optional nama, in any order. A row that cannot be read
+// comes back with Error set; only a malformed file, a missing column or
+// more than maxRows rows fail the whole upload.
+func ParseCSV(r io.Reader, maxRows int) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrEmptyBatch
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheet exports often start with a byte order mark.
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"no_rekening", "nominal"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: kolom %s tidak ada", ErrInvalidCSV, required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("%w (%d)", ErrTooManyRows, maxRows)
+		}
+
+		row := Row{
+			Baris:      len(rows) + 1,
+			NoRekening: field(record, "no_rekening"),
+			Nama:       field(record, "nama"),
+		}
+		nominal, err := strconv.ParseFloat(field(record, "nominal"), 64)
+		if err != nil || math.IsNaN(nominal) || math.IsInf(nominal, 0) {
+			row.Error = "nominal tidak valid"
+			nominal = 0
+		}
+		row.Nominal = nominal
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	return rows, nil
+}
+
+// WriteResultCSV writes one line per item with its outcome. mataUang is the
+// batch currency.
+func WriteResultCSV(w io.Writer, mataUang string, items []model.PayrollItem) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"baris", "no_rekening", "nama", "nominal", "mata_uang", "status", "error", "transaksi_id", "processed_at"})
+	for _, item := range items {
+		var transaksiID, processedAt string
+		if item.TransaksiID != nil {
+			transaksiID = strconv.FormatUint(uint64(*item.TransaksiID), 10)
+		}
+		if item.ProcessedAt != nil {
+			processedAt = item.ProcessedAt.Format(time.RFC3339)
+		}
+		writer.Write([]string{
+			strconv.Itoa(item.Baris),
+			cell(item.NoRekening),
+			cell(item.Nama),
+			strconv.FormatFloat(item.Nominal, 'f', -1, 64),
+			mataUang,
+			item.Status,
+			cell(item.Error),
+			transaksiID,
+			processedAt,
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// cell stops client-supplied text from being read as a formula when the
+// result file is opened in a spreadsheet.
+func cell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
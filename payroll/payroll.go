@@ -0,0 +1,269 @@
+// Package payroll disburses one source account to many destination accounts.
+// A batch is validated row by row when it is submitted; rejected rows are
+// stored as failed and the rest are settled asynchronously by Processor,
+// each row as its own account.Service transfer.
+package payroll
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBatchNotFound = errors.New("batch payroll tidak ditemukan")
+	ErrEmptyBatch    = errors.New("batch payroll tidak berisi baris")
+	ErrTooManyRows   = errors.New("jumlah baris melebihi batas")
+)
+
+// Row is one requested credit, numbered from 1 in submission order.
+type Row struct {
+	Baris      int
+	NoRekening string
+	Nama       string
+	Nominal    float64
+	// Error is set by the parser when the row could not be read.
+	Error string
+}
+
+type Service struct {
+	db       *gorm.DB
+	cfg      *config.Config
+	accounts *account.Service
+}
+
+func NewService(db *gorm.DB, cfg *config.Config, accounts *account.Service) *Service {
+	return &Service{db: db, cfg: cfg, accounts: accounts}
+}
+
+// Submit validates every row and stores the batch for the Processor. The
+// source account must exist and not be frozen; a row that fails validation,
+// including one crediting an account that is not a saved beneficiary of the
+// source, is stored as failed and returned in rejected without aborting the
+// batch. Callers step up with an OTP when Total is above the threshold.
+func (s *Service) Submit(ctx context.Context, userID uint, sumber, keterangan string, rows []Row) (model.PayrollBatch, []model.PayrollItem, error) {
+	if len(rows) == 0 {
+		return model.PayrollBatch{}, nil, ErrEmptyBatch
+	}
+	if len(rows) > s.cfg.Payroll.MaxItems {
+		return model.PayrollBatch{}, nil, fmt.Errorf("%w (%d)", ErrTooManyRows, s.cfg.Payroll.MaxItems)
+	}
+
+	source, err := s.accounts.Account(ctx, sumber)
+	if err != nil {
+		return model.PayrollBatch{}, nil, err
+	}
+	if source.FrozenAt != nil {
+		return model.PayrollBatch{}, nil, account.ErrAccountFrozen
+	}
+
+	destinations, err := s.destinations(ctx, rows)
+	if err != nil {
+		return model.PayrollBatch{}, nil, err
+	}
+	beneficiaries, err := s.accounts.BeneficiarySet(ctx, sumber)
+	if err != nil {
+		return model.PayrollBatch{}, nil, err
+	}
+
+	batch := model.PayrollBatch{
+		UserID:           userID,
+		NoRekeningSumber: sumber,
+		Keterangan:       keterangan,
+		Status:           model.PayrollStatusPending,
+		MataUang:         source.MataUang,
+	}
+	items := make([]model.PayrollItem, len(rows))
+	// seen maps a destination to the first payable row crediting it.
+	seen := make(map[string]int, len(rows))
+	for i, row := range rows {
+		item := model.PayrollItem{
+			Baris:      row.Baris,
+			NoRekening: row.NoRekening,
+			Nama:       row.Nama,
+			Nominal:    row.Nominal,
+			Status:     model.PayrollItemPending,
+			Error:      validate(row, sumber, seen, destinations, beneficiaries),
+		}
+		if item.Error != "" {
+			item.Status = model.PayrollItemFailed
+		} else {
+			seen[row.NoRekening] = row.Baris
+		}
+		items[i] = item
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&batch).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].BatchID = batch.ID
+		}
+		if err := tx.CreateInBatches(&items, 500).Error; err != nil {
+			return err
+		}
+		return refreshTotals(tx, &batch)
+	})
+	if err != nil {
+		return model.PayrollBatch{}, nil, err
+	}
+
+	var rejected []model.PayrollItem
+	for _, item := range items {
+		if item.Status == model.PayrollItemFailed {
+			rejected = append(rejected, item)
+		}
+	}
+	return batch, rejected, nil
+}
+
+// destinations loads every destination account of the batch in one query.
+func (s *Service) destinations(ctx context.Context, rows []Row) (map[string]model.Nasabah, error) {
+	numbers := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.NoRekening != "" {
+			numbers = append(numbers, row.NoRekening)
+		}
+	}
+
+	found := make(map[string]model.Nasabah, len(numbers))
+	if len(numbers) == 0 {
+		return found, nil
+	}
+	var nasabahs []model.Nasabah
+	if err := s.db.WithContext(ctx).Where("no_rekening IN ?", numbers).Find(&nasabahs).Error; err != nil {
+		return nil, err
+	}
+	for _, n := range nasabahs {
+		found[n.NoRekening] = n
+	}
+	return found, nil
+}
+
+// validate returns why row cannot be paid, or "" if it can. The same checks
+// run again when the row is settled; this pass only spares the worker the
+// rows that are bound to fail.
+func validate(row Row, sumber string, seen map[string]int, destinations map[string]model.Nasabah, beneficiaries map[string]bool) string {
+	if row.Error != "" {
+		return row.Error
+	}
+	if row.NoRekening == "" {
+		return "no_rekening wajib diisi"
+	}
+	if row.Nominal <= 0 {
+		return "nominal harus lebih dari 0"
+	}
+	if row.NoRekening == sumber {
+		return "rekening tujuan sama dengan rekening sumber"
+	}
+	if first, dup := seen[row.NoRekening]; dup {
+		return fmt.Sprintf("rekening tujuan ganda dengan baris %d", first)
+	}
+	tujuan, ok := destinations[row.NoRekening]
+	if !ok {
+		return "rekening tujuan tidak ditemukan"
+	}
+	if !beneficiaries[row.NoRekening] {
+		return account.ErrNotBeneficiary.Error()
+	}
+	if tujuan.FrozenAt != nil {
+		return "rekening tujuan dibekukan"
+	}
+	return ""
+}
+
+// Total is the most a batch of rows can debit from its source account: the
+// sum of every positive amount, before any row is rejected.
+func Total(rows []Row) float64 {
+	var total float64
+	for _, row := range rows {
+		if row.Nominal > 0 {
+			total += row.Nominal
+		}
+	}
+	return total
+}
+
+// Batch returns a batch owned by userID.
+func (s *Service) Batch(ctx context.Context, userID, id uint) (model.PayrollBatch, error) {
+	var batch model.PayrollBatch
+	err := s.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&batch).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return batch, ErrBatchNotFound
+	}
+	return batch, err
+}
+
+// Items returns every row of the batch in submission order.
+func (s *Service) Items(ctx context.Context, batchID uint) ([]model.PayrollItem, error) {
+	var items []model.PayrollItem
+	err := s.db.WithContext(ctx).Where("batch_id = ?", batchID).Order("baris").Find(&items).Error
+	return items, err
+}
+
+// JournalNominal sums the transfer_keluar entries this batch left on its
+// source account. Once no row is pending it equals SuccessNominal.
+func (s *Service) JournalNominal(ctx context.Context, batch model.PayrollBatch) (float64, error) {
+	var total float64
+	err := s.db.WithContext(ctx).Model(&model.Transaksi{}).
+		Select("COALESCE(SUM(nominal), 0)").
+		Where("no_rekening = ? AND jenis = ? AND referensi LIKE ?",
+			batch.NoRekeningSumber, model.JenisTransferKeluar, referensiPrefix(batch.ID)+"%").
+		Scan(&total).Error
+	return total, err
+}
+
+// referensi is the idempotency key of one row's transfer, so a row retried
+// after a crash is never paid twice.
+func referensi(batchID uint, baris int) string {
+	return fmt.Sprintf("%s%d", referensiPrefix(batchID), baris)
+}
+
+func referensiPrefix(batchID uint) string {
+	return fmt.Sprintf("payroll-%d-", batchID)
+}
+
+// refreshTotals recomputes the batch counters from its items.
+func refreshTotals(tx *gorm.DB, batch *model.PayrollBatch) error {
+	var totals []struct {
+		Status  string
+		Items   int
+		Nominal float64
+	}
+	err := tx.Model(&model.PayrollItem{}).
+		Select("status, COUNT(*) AS items, COALESCE(SUM(nominal), 0) AS nominal").
+		Where("batch_id = ?", batch.ID).
+		Group("status").
+		Scan(&totals).Error
+	if err != nil {
+		return err
+	}
+
+	batch.TotalItems, batch.TotalNominal = 0, 0
+	batch.SuccessItems, batch.SuccessNominal = 0, 0
+	batch.FailedItems, batch.FailedNominal = 0, 0
+	for _, t := range totals {
+		batch.TotalItems += t.Items
+		batch.TotalNominal += t.Nominal
+		switch t.Status {
+		case model.PayrollItemSuccess:
+			batch.SuccessItems, batch.SuccessNominal = t.Items, t.Nominal
+		case model.PayrollItemFailed:
+			batch.FailedItems, batch.FailedNominal = t.Items, t.Nominal
+		}
+	}
+	return tx.Model(batch).Updates(map[string]any{
+		"total_items":     batch.TotalItems,
+		"total_nominal":   batch.TotalNominal,
+		"success_items":   batch.SuccessItems,
+		"success_nominal": batch.SuccessNominal,
+		"failed_items":    batch.FailedItems,
+		"failed_nominal":  batch.FailedNominal,
+	}).Error
+}
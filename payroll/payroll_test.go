@@ -0,0 +1,102 @@
+package payroll_test
+
+import (
+	"context"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/database/databasetest"
+	"gobanking/model"
+	"gobanking/notifier"
+	"gobanking/payroll"
+	"gobanking/repository"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestTotal(t *testing.T) {
+	rows := []payroll.Row{
+		{Baris: 1, NoRekening: "a", Nominal: 100000},
+		{Baris: 2, NoRekening: "b", Nominal: -5000},
+		{Baris: 3, NoRekening: "c", Nominal: 0},
+		{Baris: 4, Error: "nominal tidak valid"},
+		{Baris: 5, NoRekening: "d", Nominal: 250000},
+	}
+	if got := payroll.Total(rows); got != 350000 {
+		t.Fatalf("Total = %v, want 350000", got)
+	}
+	if got := payroll.Total(nil); got != 0 {
+		t.Fatalf("Total(nil) = %v, want 0", got)
+	}
+}
+
+func openAccount(t *testing.T, s *account.Service, suffix string, saldo float64) model.Nasabah {
+	t.Helper()
+	ctx := context.Background()
+	n, err := s.OpenAccount(ctx, account.OpenAccountInput{
+		Nama: "Nasabah " + suffix,
+		NIK:  "nik-" + suffix,
+		NoHP: "08" + suffix,
+	})
+	if err != nil {
+		t.Fatalf("OpenAccount(%s): %v", suffix, err)
+	}
+	if saldo > 0 {
+		if n, err = s.Deposit(ctx, n.NoRekening, saldo, ""); err != nil {
+			t.Fatalf("Deposit(%s): %v", suffix, err)
+		}
+	}
+	return n
+}
+
+// TestBatchPaysOnlySavedBeneficiaries submits one row to a saved
+// beneficiary and one to an account that is not, then settles the batch.
+func TestBatchPaysOnlySavedBeneficiaries(t *testing.T) {
+	ctx := context.Background()
+	db := databasetest.Open(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		Logger:  logger,
+		Payroll: config.PayrollConfig{MaxItems: 100, ChunkSize: 10, PollInterval: time.Second},
+	}
+	accounts := account.NewService(repository.NewGormStore(db, nil), notifier.LogNotifier{Logger: logger})
+	sumber := openAccount(t, accounts, "1", 1000000)
+	saved := openAccount(t, accounts, "2", 0)
+	stranger := openAccount(t, accounts, "3", 0)
+	if _, err := accounts.AddBeneficiary(ctx, sumber.NoRekening, saved.NoRekening, "karyawan"); err != nil {
+		t.Fatalf("AddBeneficiary: %v", err)
+	}
+
+	service := payroll.NewService(db, cfg, accounts)
+	batch, rejected, err := service.Submit(ctx, 1, sumber.NoRekening, "gaji", []payroll.Row{
+		{Baris: 1, NoRekening: saved.NoRekening, Nominal: 300000},
+		{Baris: 2, NoRekening: stranger.NoRekening, Nominal: 200000},
+	})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if len(rejected) != 1 || rejected[0].Baris != 2 || rejected[0].Error != account.ErrNotBeneficiary.Error() {
+		t.Fatalf("rejected = %+v, want row 2 refused as not a beneficiary", rejected)
+	}
+
+	if err := payroll.NewProcessor(db, cfg, accounts).ProcessDue(ctx); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	batch, err = service.Batch(ctx, 1, batch.ID)
+	if err != nil {
+		t.Fatalf("Batch: %v", err)
+	}
+	if batch.Status != model.PayrollStatusCompleted || batch.SuccessItems != 1 || batch.FailedItems != 1 {
+		t.Fatalf("batch = %+v, want completed with 1 paid and 1 failed row", batch)
+	}
+	if saldo, _ := accounts.Balance(ctx, saved.NoRekening); saldo != 300000 {
+		t.Fatalf("beneficiary saldo = %v, want 300000", saldo)
+	}
+	if saldo, _ := accounts.Balance(ctx, stranger.NoRekening); saldo != 0 {
+		t.Fatalf("non-beneficiary saldo = %v, want 0", saldo)
+	}
+	if journal, _ := service.JournalNominal(ctx, batch); journal != batch.SuccessNominal {
+		t.Fatalf("JournalNominal = %v, want SuccessNominal %v", journal, batch.SuccessNominal)
+	}
+}
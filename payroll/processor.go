@@ -0,0 +1,212 @@
+package payroll
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/metrics"
+	"gobanking/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// claimLease is how long a claimed batch stays invisible to other replicas
+// while one of its chunks is being settled.
+const claimLease = 5 * time.Minute
+
+// Processor settles pending batches chunk by chunk. Each row is its own
+// transfer, keyed by the batch and row number, so a row interrupted by a
+// crash or shutdown is retried without paying twice.
+type Processor struct {
+	db       *gorm.DB
+	cfg      *config.Config
+	accounts *account.Service
+}
+
+func NewProcessor(db *gorm.DB, cfg *config.Config, accounts *account.Service) *Processor {
+	return &Processor{db: db, cfg: cfg, accounts: accounts}
+}
+
+// Run polls for batches until ctx is cancelled.
+func (p *Processor) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Payroll.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.ProcessDue(ctx); err != nil {
+			p.cfg.Logger.Error("gagal memproses payroll", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue settles chunks until no batch is left to claim. Batches take
+// turns chunk by chunk, so a large batch does not hold up a small one. A
+// batch whose chunk fails keeps its lease and is retried once it expires,
+// letting the other batches go first.
+func (p *Processor) ProcessDue(ctx context.Context) error {
+	for ctx.Err() == nil {
+		batch, ok, err := p.claim()
+		if err != nil || !ok {
+			return err
+		}
+		if err := p.processChunk(ctx, batch); err != nil {
+			p.cfg.Logger.Error("gagal memproses chunk payroll, dicoba lagi nanti",
+				"batch_id", batch.ID,
+				"retry_after", claimLease.String(),
+				"error", err,
+			)
+		}
+	}
+	return nil
+}
+
+// claim locks the oldest unfinished batch no other replica is working on
+// and leases it.
+func (p *Processor) claim() (model.PayrollBatch, bool, error) {
+	var batch model.PayrollBatch
+	found := false
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND (claimed_until IS NULL OR claimed_until < ?)",
+				[]string{model.PayrollStatusPending, model.PayrollStatusProcessing}, now).
+			Order("id").
+			Limit(1).
+			Find(&batch)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		found = true
+
+		until := now.Add(claimLease)
+		updates := map[string]any{"status": model.PayrollStatusProcessing, "claimed_until": until}
+		if batch.StartedAt == nil {
+			batch.StartedAt = &now
+			updates["started_at"] = now
+		}
+		batch.Status, batch.ClaimedUntil = model.PayrollStatusProcessing, &until
+		return tx.Model(&batch).Updates(updates).Error
+	})
+	return batch, found, err
+}
+
+// processChunk settles up to ChunkSize pending rows, then updates the
+// totals and either completes the batch or hands it back to the queue.
+// When it returns an error the batch is left leased.
+func (p *Processor) processChunk(ctx context.Context, batch model.PayrollBatch) error {
+	var items []model.PayrollItem
+	err := p.db.Where("batch_id = ? AND status = ?", batch.ID, model.PayrollItemPending).
+		Order("baris").
+		Limit(p.cfg.Payroll.ChunkSize).
+		Find(&items).Error
+	if err != nil {
+		return err
+	}
+	beneficiaries, err := p.accounts.BeneficiarySet(ctx, batch.NoRekeningSumber)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := p.settle(ctx, batch, item, beneficiaries); err != nil {
+			if ctx.Err() != nil {
+				// Shutting down; the row is retried after restart.
+				break
+			}
+			return err
+		}
+	}
+
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if err := refreshTotals(tx, &batch); err != nil {
+			return err
+		}
+
+		var pending int64
+		if err := tx.Model(&model.PayrollItem{}).
+			Where("batch_id = ? AND status = ?", batch.ID, model.PayrollItemPending).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			return tx.Model(&batch).Update("claimed_until", nil).Error
+		}
+
+		now := time.Now()
+		p.cfg.Logger.Info("batch payroll selesai",
+			"batch_id", batch.ID,
+			"success_items", batch.SuccessItems,
+			"success_nominal", batch.SuccessNominal,
+			"failed_items", batch.FailedItems,
+		)
+		return tx.Model(&batch).Updates(map[string]any{
+			"status":        model.PayrollStatusCompleted,
+			"completed_at":  now,
+			"claimed_until": nil,
+		}).Error
+	})
+}
+
+// settle transfers one row and records the outcome. Rule violations fail
+// the row; anything else leaves it pending and is returned so the chunk
+// stops.
+func (p *Processor) settle(ctx context.Context, batch model.PayrollBatch, item model.PayrollItem, beneficiaries map[string]bool) error {
+	var (
+		result account.TransferResult
+		err    error
+	)
+	if beneficiaries[item.NoRekening] {
+		result, err = p.accounts.Transfer(ctx, account.TransferInput{
+			Sumber:    batch.NoRekeningSumber,
+			Tujuan:    item.NoRekening,
+			Nominal:   item.Nominal,
+			Referensi: referensi(batch.ID, item.Baris),
+		})
+	} else {
+		err = account.ErrNotBeneficiary
+	}
+
+	status, reason := model.PayrollItemSuccess, ""
+	switch {
+	case err == nil, errors.Is(err, account.ErrDuplicateReference):
+		// A duplicate reference means an earlier attempt already paid the
+		// row before its outcome was recorded.
+	case errors.Is(err, account.ErrInsufficientFunds),
+		errors.Is(err, account.ErrAccountFrozen),
+		errors.Is(err, account.ErrAccountNotFound),
+		errors.Is(err, account.ErrBeneficiaryNotFound),
+		errors.Is(err, account.ErrNotBeneficiary),
+		errors.Is(err, account.ErrInvalidAmount),
+		errors.Is(err, account.ErrSameAccount),
+		errors.Is(err, account.ErrRateNotFound):
+		status, reason = model.PayrollItemFailed, err.Error()
+	case ctx.Err() != nil:
+		return err
+	default:
+		return fmt.Errorf("baris %d: %w", item.Baris, err)
+	}
+
+	now := time.Now()
+	updates := map[string]any{"status": status, "error": reason, "processed_at": now}
+	if status == model.PayrollItemSuccess && result.Debit.ID != 0 {
+		updates["transaksi_id"] = result.Debit.ID
+	}
+	if err := p.db.Model(&item).Updates(updates).Error; err != nil {
+		return err
+	}
+	metrics.PayrollItemsTotal.WithLabelValues(status).Inc()
+	return nil
+}
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"gobanking/logging"
+	"gobanking/model"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so that every
+// replica shares the same limits. Each Take locks only its own row.
+type PostgresStore struct {
+	db *gorm.DB
+
+	mu sync.Mutex
+	// longest is the longest policy period seen; a bucket idle for longer
+	// has refilled under any policy.
+	longest time.Duration
+	calls   int
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, p Policy) (Result, error) {
+	var res Result
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.RateLimitBucket{Key: key, Tokens: float64(p.Limit)}).Error
+		if err != nil {
+			return err
+		}
+
+		// A prune may delete the row between the insert and the lock; the
+		// bucket was full then and starts over.
+		var b model.RateLimitBucket
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("key = ?", key).
+			First(&b).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		now := time.Now()
+		b.Tokens, res = take(b.Tokens, b.UpdatedAt, now, p)
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"tokens", "updated_at"}),
+		}).Create(&model.RateLimitBucket{Key: key, Tokens: b.Tokens, UpdatedAt: now}).Error
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if idle, ok := s.due(p); ok {
+		go s.prune(logging.FromContext(ctx), idle)
+	}
+	return res, nil
+}
+
+// due counts a call and reports, every pruneEvery calls, how long a bucket
+// must have been idle to be pruned.
+func (s *PostgresStore) due(p Policy) (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.Period > s.longest {
+		s.longest = p.Period
+	}
+	s.calls++
+	return s.longest, s.calls%pruneEvery == 0
+}
+
+// prune deletes buckets idle long enough to have refilled completely, so
+// the table does not keep a row for every client ever seen. It runs in the
+// background and only logs failures.
+func (s *PostgresStore) prune(logger *slog.Logger, idle time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := s.db.WithContext(ctx).
+		Where("updated_at < ?", time.Now().Add(-idle)).
+		Delete(&model.RateLimitBucket{}).Error
+	if err != nil {
+		logger.Error("gagal membersihkan bucket rate limit", "error", err)
+	}
+}
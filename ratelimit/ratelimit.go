@@ -0,0 +1,65 @@
+// Package ratelimit implements token-bucket rate limiting with in-memory
+// and PostgreSQL-backed stores.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Policy allows Limit requests per Period, refilled continuously. A client
+// may burst up to Limit requests at once.
+type Policy struct {
+	Name   string
+	Limit  int
+	Period time.Duration
+}
+
+func (p Policy) rate() float64 {
+	return float64(p.Limit) / p.Period.Seconds()
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed; zero when
+	// Allowed.
+	RetryAfter time.Duration
+}
+
+// Store takes one token for key under policy p.
+type Store interface {
+	Take(ctx context.Context, key string, p Policy) (Result, error)
+}
+
+// take refills a bucket holding tokens since last and tries to spend one.
+// It returns the new token count with the result so stores only have to
+// persist state.
+func take(tokens float64, last, now time.Time, p Policy) (float64, Result) {
+	capacity := float64(p.Limit)
+	if !last.IsZero() {
+		tokens = math.Min(capacity, tokens+now.Sub(last).Seconds()*p.rate())
+	} else {
+		tokens = capacity
+	}
+
+	res := Result{Limit: p.Limit}
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - tokens) / p.rate())
+	}
+
+	res.Remaining = int(math.Floor(tokens))
+	res.Reset = seconds((capacity - tokens) / p.rate())
+	return tokens, res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,52 @@
+package recon
+
+import (
+	"encoding/csv"
+	"gobanking/model"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteExceptionsCSV writes the exception report: one line per item that
+// is not matched, with both sides, the difference and how it was resolved.
+func WriteExceptionsCSV(w io.Writer, items []model.RekonsiliasiItem) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{
+		"item_id", "status", "baris", "referensi", "no_rekening", "mata_uang",
+		"nominal_eksternal", "tanggal_eksternal", "transaksi_id", "nominal_internal", "tanggal_internal",
+		"selisih", "resolved_at", "catatan",
+	})
+	for _, item := range items {
+		baris := ""
+		if item.Baris > 0 {
+			baris = strconv.Itoa(item.Baris)
+		}
+		transaksiID := ""
+		if item.TransaksiID != nil {
+			transaksiID = strconv.FormatUint(uint64(*item.TransaksiID), 10)
+		}
+		resolvedAt := ""
+		if item.ResolvedAt != nil {
+			resolvedAt = item.ResolvedAt.Format(time.RFC3339)
+		}
+		writer.Write([]string{
+			strconv.FormatUint(uint64(item.ID), 10), item.Status, baris, item.Referensi, item.NoRekening, item.MataUang,
+			amount(item.NominalEksternal), date(item.TanggalEksternal), transaksiID, amount(item.NominalInternal), date(item.TanggalInternal),
+			amount(item.Selisih), resolvedAt, item.Catatan,
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func amount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func date(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
@@ -0,0 +1,155 @@
+package recon
+
+import (
+	"gobanking/model"
+	"math"
+	"sort"
+	"time"
+)
+
+// amountEpsilon absorbs float noise when comparing against the tolerance.
+const amountEpsilon = 1e-9
+
+// tolerance is how far a settlement entry and a deposit may differ and
+// still be the same money.
+type tolerance struct {
+	nominal float64
+	hari    int
+}
+
+func (t tolerance) sameAmount(a, b float64) bool {
+	return math.Abs(a-b) <= t.nominal+amountEpsilon
+}
+
+func (t tolerance) sameDate(a, b time.Time) bool {
+	return math.Abs(a.Sub(b).Hours()/24) <= float64(t.hari)
+}
+
+// match pairs entries with deposits in two passes. First by reference:
+// the deposit with the entry's reference is the same money whatever its
+// date, and an amount outside the tolerance makes the pair an
+// amount_mismatch. Then the entries left, e.g. those whose reference the
+// partner mangled, are paired with a remaining deposit of the same amount
+// within the date tolerance, closest date first. An entry naming an
+// account or currency only pairs with deposits on that account or in that
+// currency.
+//
+// Entries left over are missing_internal. Deposits left over are
+// missing_external if their business date lies within [awal, akhir];
+// deposits outside it were only candidates because of the date tolerance.
+func match(entries []Entry, deposits []model.Transaksi, tol tolerance, awal, akhir time.Time) []model.RekonsiliasiItem {
+	used := make([]bool, len(deposits))
+	byRef := make(map[string][]int)
+	for i, d := range deposits {
+		byRef[d.Referensi] = append(byRef[d.Referensi], i)
+	}
+	fits := func(e Entry, d model.Transaksi) bool {
+		return (e.NoRekening == "" || e.NoRekening == d.NoRekening) && e.MataUang == d.MataUang
+	}
+
+	items := make([]model.RekonsiliasiItem, 0, len(entries))
+	var rest []Entry
+	for _, e := range entries {
+		found := -1
+		if e.Referensi != "" {
+			for _, i := range byRef[e.Referensi] {
+				if !used[i] && fits(e, deposits[i]) {
+					found = i
+					break
+				}
+			}
+		}
+		if found < 0 {
+			rest = append(rest, e)
+			continue
+		}
+		used[found] = true
+		status := model.RekonsiliasiMatched
+		if !tol.sameAmount(e.Nominal, deposits[found].Nominal) {
+			status = model.RekonsiliasiAmountMismatch
+		}
+		items = append(items, pair(status, e, deposits[found]))
+	}
+
+	// Deposits by amount, so each entry only looks at those within the
+	// amount tolerance.
+	byNominal := make([]int, len(deposits))
+	for i := range byNominal {
+		byNominal[i] = i
+	}
+	sort.SliceStable(byNominal, func(a, b int) bool {
+		return deposits[byNominal[a]].Nominal < deposits[byNominal[b]].Nominal
+	})
+
+	for _, e := range rest {
+		found := -1
+		var best time.Duration
+		start := sort.Search(len(byNominal), func(k int) bool {
+			return deposits[byNominal[k]].Nominal >= e.Nominal-tol.nominal-amountEpsilon
+		})
+		for _, i := range byNominal[start:] {
+			d := deposits[i]
+			if !tol.sameAmount(e.Nominal, d.Nominal) {
+				break
+			}
+			if used[i] || !fits(e, d) || !tol.sameDate(e.Tanggal, d.TanggalBisnis) {
+				continue
+			}
+			gap := e.Tanggal.Sub(d.TanggalBisnis).Abs()
+			if found < 0 || gap < best {
+				found, best = i, gap
+			}
+		}
+		if found < 0 {
+			tanggal := e.Tanggal
+			items = append(items, model.RekonsiliasiItem{
+				Status:           model.RekonsiliasiMissingInternal,
+				Baris:            e.Baris,
+				Referensi:        e.Referensi,
+				NoRekening:       e.NoRekening,
+				MataUang:         e.MataUang,
+				NominalEksternal: e.Nominal,
+				TanggalEksternal: &tanggal,
+				Selisih:          e.Nominal,
+			})
+			continue
+		}
+		used[found] = true
+		items = append(items, pair(model.RekonsiliasiMatched, e, deposits[found]))
+	}
+
+	for i, d := range deposits {
+		if used[i] || d.TanggalBisnis.Before(awal) || d.TanggalBisnis.After(akhir) {
+			continue
+		}
+		id, tanggal := d.ID, d.TanggalBisnis
+		items = append(items, model.RekonsiliasiItem{
+			Status:          model.RekonsiliasiMissingExternal,
+			Referensi:       d.Referensi,
+			NoRekening:      d.NoRekening,
+			MataUang:        d.MataUang,
+			TransaksiID:     &id,
+			NominalInternal: d.Nominal,
+			TanggalInternal: &tanggal,
+			Selisih:         -d.Nominal,
+		})
+	}
+	return items
+}
+
+func pair(status string, e Entry, d model.Transaksi) model.RekonsiliasiItem {
+	id, eksternal, internal := d.ID, e.Tanggal, d.TanggalBisnis
+	return model.RekonsiliasiItem{
+		Status:           status,
+		Baris:            e.Baris,
+		Referensi:        e.Referensi,
+		NoRekening:       d.NoRekening,
+		MataUang:         e.MataUang,
+		NominalEksternal: e.Nominal,
+		TanggalEksternal: &eksternal,
+		TransaksiID:      &id,
+		NominalInternal:  d.Nominal,
+		TanggalInternal:  &internal,
+		Selisih:          e.Nominal - d.Nominal,
+	}
+}
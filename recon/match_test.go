@@ -0,0 +1,157 @@
+package recon
+
+import (
+	"fmt"
+	"gobanking/model"
+	"slices"
+	"testing"
+	"time"
+)
+
+func tanggal(hari int) time.Time {
+	return time.Date(2026, 1, hari, 0, 0, 0, 0, time.UTC)
+}
+
+func deposit(id uint, referensi string, nominal float64, hari int) model.Transaksi {
+	d := model.Transaksi{
+		NoRekening:    "1001",
+		Jenis:         model.JenisTabung,
+		Nominal:       nominal,
+		MataUang:      "IDR",
+		Referensi:     referensi,
+		TanggalBisnis: tanggal(hari),
+	}
+	d.ID = id
+	return d
+}
+
+func entry(baris int, referensi string, nominal float64, hari int) Entry {
+	return Entry{Baris: baris, Referensi: referensi, MataUang: "IDR", Nominal: nominal, Tanggal: tanggal(hari)}
+}
+
+// TestMatch pairs files dated 10 January, with a tolerance of 100 and two
+// days. Outcomes are "status baris transaksi_id".
+func TestMatch(t *testing.T) {
+	tol := tolerance{nominal: 100, hari: 2}
+	usd := entry(2, "", 50, 10)
+	usd.MataUang = "USD"
+	other := entry(1, "R1", 100000, 10)
+	other.NoRekening = "2002"
+
+	for _, tt := range []struct {
+		name     string
+		entries  []Entry
+		deposits []model.Transaksi
+		want     []string
+	}{
+		{
+			name:     "reference wins over a closer amount and date",
+			entries:  []Entry{entry(1, "R1", 100000, 10)},
+			deposits: []model.Transaksi{deposit(1, "X", 100000, 10), deposit(2, "R1", 100000, 1)},
+			want:     []string{"matched 1 2", "missing_external 0 1"},
+		},
+		{
+			name:     "reference at the amount tolerance",
+			entries:  []Entry{entry(1, "R1", 100100, 10)},
+			deposits: []model.Transaksi{deposit(1, "R1", 100000, 10)},
+			want:     []string{"matched 1 1"},
+		},
+		{
+			name:     "reference just past the amount tolerance",
+			entries:  []Entry{entry(1, "R1", 100100.01, 10)},
+			deposits: []model.Transaksi{deposit(1, "R1", 100000, 10)},
+			want:     []string{"amount_mismatch 1 1"},
+		},
+		{
+			name:     "mangled reference paired by amount and date",
+			entries:  []Entry{entry(1, "r-1", 99900, 10)},
+			deposits: []model.Transaksi{deposit(1, "R1", 100000, 12)},
+			want:     []string{"matched 1 1"},
+		},
+		{
+			name:     "amount just past the tolerance",
+			entries:  []Entry{entry(1, "", 99899.99, 10)},
+			deposits: []model.Transaksi{deposit(1, "R1", 100000, 10)},
+			want:     []string{"missing_internal 1 0", "missing_external 0 1"},
+		},
+		{
+			name:     "date just past the tolerance",
+			entries:  []Entry{entry(1, "", 100000, 10)},
+			deposits: []model.Transaksi{deposit(1, "R1", 100000, 13)},
+			want:     []string{"missing_internal 1 0"},
+		},
+		{
+			name:    "closest date of several candidates",
+			entries: []Entry{entry(1, "", 100000, 10)},
+			deposits: []model.Transaksi{
+				deposit(1, "R1", 100000, 8), deposit(2, "R2", 100050, 11), deposit(3, "R3", 100000, 12),
+			},
+			want: []string{"matched 1 2"},
+		},
+		{
+			name:     "equal candidates are each used once",
+			entries:  []Entry{entry(1, "", 100000, 10), entry(2, "", 100000, 10), entry(3, "", 100000, 10)},
+			deposits: []model.Transaksi{deposit(1, "R1", 100000, 10), deposit(2, "R2", 100000, 10)},
+			want:     []string{"matched 1 1", "matched 2 2", "missing_internal 3 0"},
+		},
+		{
+			name:     "a deposit taken by reference is not paired again",
+			entries:  []Entry{entry(1, "", 100000, 10), entry(2, "R1", 100000, 10)},
+			deposits: []model.Transaksi{deposit(1, "R1", 100000, 10)},
+			want:     []string{"matched 2 1", "missing_internal 1 0"},
+		},
+		{
+			name:     "an entry naming another account",
+			entries:  []Entry{other},
+			deposits: []model.Transaksi{deposit(1, "R1", 100000, 10)},
+			want:     []string{"missing_internal 1 0", "missing_external 0 1"},
+		},
+		{
+			name:     "an entry in another currency",
+			entries:  []Entry{usd},
+			deposits: []model.Transaksi{deposit(1, "", 50, 10)},
+			want:     []string{"missing_internal 2 0", "missing_external 0 1"},
+		},
+		{
+			name:     "deposits outside the file's dates are not missing",
+			entries:  []Entry{entry(1, "R1", 100000, 10)},
+			deposits: []model.Transaksi{deposit(1, "R1", 100000, 10), deposit(2, "R2", 5000, 9), deposit(3, "R3", 5000, 11)},
+			want:     []string{"matched 1 1"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, item := range match(tt.entries, tt.deposits, tol, tanggal(10), tanggal(10)) {
+				var id uint
+				if item.TransaksiID != nil {
+					id = *item.TransaksiID
+				}
+				got = append(got, fmt.Sprintf("%s %d %d", item.Status, item.Baris, id))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("match = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchSelisih(t *testing.T) {
+	items := match(
+		[]Entry{entry(1, "R1", 90000, 10), entry(2, "", 7000, 10)},
+		[]model.Transaksi{deposit(1, "R1", 100000, 10), deposit(2, "R2", 5000, 10)},
+		tolerance{nominal: 100, hari: 2}, tanggal(10), tanggal(10),
+	)
+	want := map[string]float64{
+		model.RekonsiliasiAmountMismatch:  -10000,
+		model.RekonsiliasiMissingInternal: 7000,
+		model.RekonsiliasiMissingExternal: -5000,
+	}
+	if len(items) != len(want) {
+		t.Fatalf("items = %+v, want %d", items, len(want))
+	}
+	for _, item := range items {
+		if item.Selisih != want[item.Status] {
+			t.Errorf("%s selisih = %v, want %v", item.Status, item.Selisih, want[item.Status])
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package recon
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"gobanking/model"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidFile    = errors.New("file settlement tidak valid")
+	ErrEmptyFile      = errors.New("file settlement tidak berisi entri kredit")
+	ErrTooManyEntries = errors.New("jumlah entri file settlement melebihi batas")
+	ErrUnknownFormat  = errors.New("format file settlement harus csv atau mt940")
+)
+
+// Entry is one credit in a settlement file: money the partner collected
+// for a deposit.
+type Entry struct {
+	Baris      int
+	Referensi  string
+	NoRekening string
+	MataUang   string
+	Nominal    float64
+	Tanggal    time.Time
+}
+
+// Parsed is the content of a settlement file. Diabaikan counts the lines
+// that are not deposits, such as MT940 debits and reversals.
+type Parsed struct {
+	Entries   []Entry
+	Diabaikan int
+}
+
+// DetectFormat guesses the format of a file from its name, then from its
+// first bytes: MT940 starts with a SWIFT block or the :20: tag.
+func DetectFormat(name string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return model.RekonsiliasiCSV
+	case ".sta", ".mt940", ".940":
+		return model.RekonsiliasiMT940
+	}
+	head := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	if bytes.HasPrefix(head, []byte("{1:")) || bytes.HasPrefix(head, []byte(":20:")) {
+		return model.RekonsiliasiMT940
+	}
+	return model.RekonsiliasiCSV
+}
+
+// Parse reads a settlement file in format, failing on the first bad line:
+// a half-read file would report every entry after it as missing.
+func Parse(format string, r io.Reader, maxEntries int) (Parsed, error) {
+	var (
+		parsed Parsed
+		err    error
+	)
+	switch format {
+	case model.RekonsiliasiCSV:
+		parsed, err = parseCSV(r, maxEntries)
+	case model.RekonsiliasiMT940:
+		parsed, err = parseMT940(r, maxEntries)
+	default:
+		return Parsed{}, ErrUnknownFormat
+	}
+	if err != nil {
+		return Parsed{}, err
+	}
+	if len(parsed.Entries) == 0 {
+		return Parsed{}, ErrEmptyFile
+	}
+	return parsed, nil
+}
+
+// parseCSV reads a file with a header row naming referensi, nominal and
+// tanggal (2006-01-02), plus optional no_rekening and mata_uang. Nominal
+// uses a dot as decimal separator and no thousands separator.
+func parseCSV(r io.Reader, maxEntries int) (Parsed, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return Parsed{}, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheet exports often start with a byte order mark.
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"referensi", "nominal", "tanggal"} {
+		if _, ok := columns[required]; !ok {
+			return Parsed{}, fmt.Errorf("%w: kolom %s tidak ada", ErrInvalidFile, required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var parsed Parsed
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Parsed{}, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(parsed.Entries) == maxEntries {
+			return Parsed{}, fmt.Errorf("%w (%d)", ErrTooManyEntries, maxEntries)
+		}
+
+		nominal, err := strconv.ParseFloat(field(record, "nominal"), 64)
+		if err != nil || nominal <= 0 {
+			return Parsed{}, fmt.Errorf("%w: baris %d: nominal %q", ErrInvalidFile, line, field(record, "nominal"))
+		}
+		tanggal, err := time.Parse(time.DateOnly, field(record, "tanggal"))
+		if err != nil {
+			return Parsed{}, fmt.Errorf("%w: baris %d: tanggal %q", ErrInvalidFile, line, field(record, "tanggal"))
+		}
+		mataUang := strings.ToUpper(field(record, "mata_uang"))
+		if mataUang == "" {
+			mataUang = "IDR"
+		}
+		parsed.Entries = append(parsed.Entries, Entry{
+			Baris:      line,
+			Referensi:  field(record, "referensi"),
+			NoRekening: field(record, "no_rekening"),
+			MataUang:   mataUang,
+			Nominal:    nominal,
+			Tanggal:    tanggal,
+		})
+	}
+	return parsed, nil
+}
+
+// statementLine is the first line of an MT940 :61: field: value date,
+// optional entry date, debit/credit mark, funds code, amount, transaction
+// type and the references.
+var statementLine = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d+,\d{0,2})[NSF][A-Z0-9]{3}(.*)$`)
+
+// parseMT940 reads the :61: statement lines of a SWIFT MT940 file. Only
+// credits are entries; the reference is the customer reference, or the
+// bank reference after // when that is NONREF. The currency comes from
+// the statement's opening balance.
+func parseMT940(r io.Reader, maxEntries int) (Parsed, error) {
+	var (
+		parsed   Parsed
+		mataUang = "IDR"
+	)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if i := strings.Index(text, "{4:"); i >= 0 {
+			// SWIFT envelope; the text block starts on the next line.
+			text = text[i+len("{4:"):]
+		}
+		switch {
+		case strings.HasPrefix(text, ":60F:"), strings.HasPrefix(text, ":60M:"):
+			// C or D, YYMMDD, then the currency.
+			value := text[len(":60F:"):]
+			if len(value) < 10 {
+				return Parsed{}, fmt.Errorf("%w: baris %d: saldo awal %q", ErrInvalidFile, line, value)
+			}
+			mataUang = value[7:10]
+
+		case strings.HasPrefix(text, ":61:"):
+			m := statementLine.FindStringSubmatch(text[len(":61:"):])
+			if m == nil {
+				return Parsed{}, fmt.Errorf("%w: baris %d: %q", ErrInvalidFile, line, text)
+			}
+			if m[3] != "C" {
+				parsed.Diabaikan++
+				continue
+			}
+			if len(parsed.Entries) == maxEntries {
+				return Parsed{}, fmt.Errorf("%w (%d)", ErrTooManyEntries, maxEntries)
+			}
+
+			tanggal, err := time.Parse("060102", m[1])
+			if err != nil {
+				return Parsed{}, fmt.Errorf("%w: baris %d: tanggal %q", ErrInvalidFile, line, m[1])
+			}
+			nominal, err := strconv.ParseFloat(strings.Replace(m[5], ",", ".", 1), 64)
+			if err != nil || nominal <= 0 {
+				return Parsed{}, fmt.Errorf("%w: baris %d: nominal %q", ErrInvalidFile, line, m[5])
+			}
+			referensi, bankRef, _ := strings.Cut(m[6], "//")
+			referensi = strings.TrimSpace(referensi)
+			if referensi == "" || strings.EqualFold(referensi, "NONREF") {
+				referensi = strings.TrimSpace(bankRef)
+			}
+			parsed.Entries = append(parsed.Entries, Entry{
+				Baris:     line,
+				Referensi: referensi,
+				MataUang:  mataUang,
+				Nominal:   nominal,
+				Tanggal:   tanggal,
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Parsed{}, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,418 @@
+// Package recon reconciles partners' settlement files against the tabung
+// entries in our books. Deposits carrying a referensi are expected in some
+// partner's file; teller deposits without one are not reconciled.
+package recon
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gobanking/config"
+	"gobanking/metrics"
+	"gobanking/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// refChunk bounds the IN list of one reference lookup.
+const refChunk = 1000
+
+var (
+	ErrNotFound          = errors.New("rekonsiliasi tidak ditemukan")
+	ErrItemNotFound      = errors.New("item rekonsiliasi tidak ditemukan")
+	ErrDuplicateFile     = errors.New("file settlement sudah pernah diimpor")
+	ErrNotException      = errors.New("item sudah cocok")
+	ErrAlreadyResolved   = errors.New("item sudah diselesaikan")
+	ErrNotLinkable       = errors.New("hanya item missing_internal yang dapat dipasangkan dengan transaksi")
+	ErrTransaksiNotFound = errors.New("transaksi tabung tidak ditemukan")
+	ErrTransaksiClaimed  = errors.New("transaksi sudah dipasangkan dengan entri settlement lain")
+)
+
+type Service struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewService(db *gorm.DB, cfg *config.Config) *Service {
+	return &Service{db: db, cfg: cfg}
+}
+
+// ImportInput is an uploaded settlement file. An empty Format is detected
+// from the name and content.
+type ImportInput struct {
+	Mitra    string
+	NamaFile string
+	Format   string
+	Data     []byte
+}
+
+// Import parses a settlement file, matches it against the tabung entries
+// and stores the result. Deposits are candidates if no other file has
+// claimed them and either their referensi appears in the file or their
+// business date is within the file's dates widened by the date tolerance.
+// An earlier missing_external item whose deposit this file matches is
+// resolved along the way.
+func (s *Service) Import(ctx context.Context, userID uint, in ImportInput) (model.Rekonsiliasi, error) {
+	format := in.Format
+	if format == "" {
+		format = DetectFormat(in.NamaFile, in.Data)
+	}
+	parsed, err := Parse(format, bytes.NewReader(in.Data), s.cfg.Recon.MaxEntries)
+	if err != nil {
+		return model.Rekonsiliasi{}, err
+	}
+	sum := sha256.Sum256(in.Data)
+
+	rec := model.Rekonsiliasi{
+		Mitra:        in.Mitra,
+		NamaFile:     in.NamaFile,
+		Format:       format,
+		Checksum:     hex.EncodeToString(sum[:]),
+		ImportedBy:   userID,
+		TanggalAwal:  parsed.Entries[0].Tanggal,
+		TanggalAkhir: parsed.Entries[0].Tanggal,
+		TotalEntri:   len(parsed.Entries),
+		Diabaikan:    parsed.Diabaikan,
+	}
+	refs := make([]string, 0, len(parsed.Entries))
+	for _, e := range parsed.Entries {
+		if e.Tanggal.Before(rec.TanggalAwal) {
+			rec.TanggalAwal = e.Tanggal
+		}
+		if e.Tanggal.After(rec.TanggalAkhir) {
+			rec.TanggalAkhir = e.Tanggal
+		}
+		if e.Referensi != "" {
+			refs = append(refs, e.Referensi)
+		}
+	}
+	tol := tolerance{nominal: s.cfg.Recon.AmountTolerance, hari: s.cfg.Recon.DateToleranceDays}
+
+	var items []model.RekonsiliasiItem
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&model.Rekonsiliasi{}).Where("checksum = ?", rec.Checksum).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrDuplicateFile
+		}
+
+		deposits, err := candidates(tx, refs,
+			rec.TanggalAwal.AddDate(0, 0, -tol.hari), rec.TanggalAkhir.AddDate(0, 0, tol.hari))
+		if err != nil {
+			return err
+		}
+		items = match(parsed.Entries, deposits, tol, rec.TanggalAwal, rec.TanggalAkhir)
+
+		if err := tx.Create(&rec).Error; err != nil {
+			return err
+		}
+		var claimed []uint
+		for i := range items {
+			items[i].RekonsiliasiID = rec.ID
+			if items[i].TransaksiID != nil && items[i].Status != model.RekonsiliasiMissingExternal {
+				claimed = append(claimed, *items[i].TransaksiID)
+			}
+		}
+		if err := tx.CreateInBatches(items, 500).Error; err != nil {
+			return err
+		}
+		note := fmt.Sprintf("Cocok di rekonsiliasi #%d", rec.ID)
+		if err := settleMissing(tx, userID, note, claimed); err != nil {
+			return err
+		}
+		return recount(tx, &rec)
+	})
+	if err != nil {
+		return model.Rekonsiliasi{}, err
+	}
+
+	for _, item := range items {
+		metrics.ReconItemsTotal.WithLabelValues(item.Status).Inc()
+	}
+	s.cfg.Logger.Info("file settlement diimpor",
+		"rekonsiliasi_id", rec.ID,
+		"mitra", rec.Mitra,
+		"format", rec.Format,
+		"total_entri", rec.TotalEntri,
+		"matched", rec.Matched,
+		"missing_internal", rec.MissingInternal,
+		"missing_external", rec.MissingExternal,
+		"amount_mismatch", rec.AmountMismatch,
+	)
+	return rec, nil
+}
+
+// candidates loads the unclaimed tabung entries with a referensi that is
+// either in refs or booked on a business date within [from, to].
+func candidates(tx *gorm.DB, refs []string, from, to time.Time) ([]model.Transaksi, error) {
+	unclaimed := func() *gorm.DB {
+		return tx.Model(&model.Transaksi{}).
+			Where("jenis = ? AND referensi <> ''", model.JenisTabung).
+			Where("NOT EXISTS (SELECT 1 FROM rekonsiliasi_items r WHERE r.transaksi_id = transaksis.id AND r.status <> ? AND r.deleted_at IS NULL)",
+				model.RekonsiliasiMissingExternal)
+	}
+
+	var deposits []model.Transaksi
+	if err := unclaimed().Where("tanggal_bisnis BETWEEN ? AND ?", from, to).Order("id").Find(&deposits).Error; err != nil {
+		return nil, err
+	}
+	seen := make(map[uint]bool, len(deposits))
+	for _, d := range deposits {
+		seen[d.ID] = true
+	}
+	for start := 0; start < len(refs); start += refChunk {
+		end := min(start+refChunk, len(refs))
+		var byRef []model.Transaksi
+		if err := unclaimed().Where("referensi IN ?", refs[start:end]).Order("id").Find(&byRef).Error; err != nil {
+			return nil, err
+		}
+		for _, d := range byRef {
+			if !seen[d.ID] {
+				seen[d.ID] = true
+				deposits = append(deposits, d)
+			}
+		}
+	}
+	return deposits, nil
+}
+
+// settleMissing resolves the open missing_external items of the deposits
+// in transaksiIDs, which have now been paired with a settlement entry.
+func settleMissing(tx *gorm.DB, userID uint, note string, transaksiIDs []uint) error {
+	if len(transaksiIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	var affected []uint
+	for start := 0; start < len(transaksiIDs); start += refChunk {
+		end := min(start+refChunk, len(transaksiIDs))
+		open := func() *gorm.DB {
+			return tx.Model(&model.RekonsiliasiItem{}).
+				Where("status = ? AND resolved_at IS NULL AND transaksi_id IN ?",
+					model.RekonsiliasiMissingExternal, transaksiIDs[start:end])
+		}
+		var ids []uint
+		if err := open().Distinct().Pluck("rekonsiliasi_id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		affected = append(affected, ids...)
+		err := open().Updates(map[string]any{
+			"catatan":     note,
+			"resolved_by": userID,
+			"resolved_at": now,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return recountIDs(tx, affected)
+}
+
+func recountIDs(tx *gorm.DB, ids []uint) error {
+	done := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if done[id] {
+			continue
+		}
+		done[id] = true
+		var rec model.Rekonsiliasi
+		if err := tx.First(&rec, id).Error; err != nil {
+			return err
+		}
+		if err := recount(tx, &rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recount recomputes the counters of rec from its items.
+func recount(tx *gorm.DB, rec *model.Rekonsiliasi) error {
+	var rows []struct {
+		Status string
+		Total  int
+		Open   int
+	}
+	err := tx.Model(&model.RekonsiliasiItem{}).
+		Select("status, COUNT(*) AS total, SUM(CASE WHEN resolved_at IS NULL THEN 1 ELSE 0 END) AS open").
+		Where("rekonsiliasi_id = ?", rec.ID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	rec.Matched, rec.MissingInternal, rec.MissingExternal, rec.AmountMismatch, rec.Open = 0, 0, 0, 0, 0
+	for _, r := range rows {
+		switch r.Status {
+		case model.RekonsiliasiMatched:
+			rec.Matched = r.Total
+			continue
+		case model.RekonsiliasiMissingInternal:
+			rec.MissingInternal = r.Total
+		case model.RekonsiliasiMissingExternal:
+			rec.MissingExternal = r.Total
+		case model.RekonsiliasiAmountMismatch:
+			rec.AmountMismatch = r.Total
+		}
+		rec.Open += r.Open
+	}
+	return tx.Model(rec).Updates(map[string]any{
+		"matched":          rec.Matched,
+		"missing_internal": rec.MissingInternal,
+		"missing_external": rec.MissingExternal,
+		"amount_mismatch":  rec.AmountMismatch,
+		"open":             rec.Open,
+	}).Error
+}
+
+// List returns the latest 100 reconciliations, newest first.
+func (s *Service) List(ctx context.Context) ([]model.Rekonsiliasi, error) {
+	var recs []model.Rekonsiliasi
+	err := s.db.WithContext(ctx).Order("id DESC").Limit(100).Find(&recs).Error
+	return recs, err
+}
+
+func (s *Service) Get(ctx context.Context, id uint) (model.Rekonsiliasi, error) {
+	var rec model.Rekonsiliasi
+	err := s.db.WithContext(ctx).First(&rec, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return rec, ErrNotFound
+	}
+	return rec, err
+}
+
+// Items returns the items of a reconciliation in file order, deposits
+// missing from the file last. A non-empty status filters by outcome; open
+// keeps only unresolved exceptions.
+func (s *Service) Items(ctx context.Context, id uint, status string, open bool) ([]model.RekonsiliasiItem, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	query := s.db.WithContext(ctx).Where("rekonsiliasi_id = ?", id)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if open {
+		query = query.Where("status <> ? AND resolved_at IS NULL", model.RekonsiliasiMatched)
+	}
+	var items []model.RekonsiliasiItem
+	err := query.Order("baris = 0, baris, id").Find(&items).Error
+	return items, err
+}
+
+// Exceptions returns every item that is not matched, resolved or not.
+func (s *Service) Exceptions(ctx context.Context, id uint) ([]model.RekonsiliasiItem, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	var items []model.RekonsiliasiItem
+	err := s.db.WithContext(ctx).
+		Where("rekonsiliasi_id = ? AND status <> ?", id, model.RekonsiliasiMatched).
+		Order("status, baris, id").
+		Find(&items).Error
+	return items, err
+}
+
+// Resolve closes an exception with the operator's note. Pairing a
+// missing_internal entry with a deposit found by hand claims the deposit,
+// which must be an unclaimed tabung in the entry's currency, and resolves
+// the missing_external items other files hold for it.
+func (s *Service) Resolve(ctx context.Context, userID, recID, itemID uint, req model.ResolveRekonsiliasiRequest) (model.RekonsiliasiItem, error) {
+	var item model.RekonsiliasiItem
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND rekonsiliasi_id = ?", itemID, recID).
+			First(&item).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrItemNotFound
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case item.Status == model.RekonsiliasiMatched:
+			return ErrNotException
+		case item.ResolvedAt != nil:
+			return ErrAlreadyResolved
+		}
+
+		now := time.Now()
+		if req.TransaksiID != nil {
+			if item.Status != model.RekonsiliasiMissingInternal {
+				return ErrNotLinkable
+			}
+			var transaksi model.Transaksi
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ? AND jenis = ? AND mata_uang = ?", *req.TransaksiID, model.JenisTabung, item.MataUang).
+				First(&transaksi).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTransaksiNotFound
+			}
+			if err != nil {
+				return err
+			}
+			var claimed int64
+			err = tx.Model(&model.RekonsiliasiItem{}).
+				Where("transaksi_id = ? AND status <> ?", transaksi.ID, model.RekonsiliasiMissingExternal).
+				Count(&claimed).Error
+			if err != nil {
+				return err
+			}
+			if claimed > 0 {
+				return ErrTransaksiClaimed
+			}
+
+			tanggal := transaksi.TanggalBisnis
+			item.TransaksiID = &transaksi.ID
+			item.NoRekening = transaksi.NoRekening
+			item.NominalInternal = transaksi.Nominal
+			item.TanggalInternal = &tanggal
+			item.Selisih = item.NominalEksternal - transaksi.Nominal
+		}
+
+		item.Catatan, item.ResolvedBy, item.ResolvedAt = req.Catatan, &userID, &now
+		err = tx.Model(&item).Updates(map[string]any{
+			"transaksi_id":     item.TransaksiID,
+			"no_rekening":      item.NoRekening,
+			"nominal_internal": item.NominalInternal,
+			"tanggal_internal": item.TanggalInternal,
+			"selisih":          item.Selisih,
+			"catatan":          item.Catatan,
+			"resolved_by":      userID,
+			"resolved_at":      now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if req.TransaksiID != nil {
+			note := fmt.Sprintf("Dipasangkan manual di rekonsiliasi #%d", recID)
+			if err := settleMissing(tx, userID, note, []uint{*req.TransaksiID}); err != nil {
+				return err
+			}
+		}
+		return recountIDs(tx, []uint{recID})
+	})
+	if err != nil {
+		return model.RekonsiliasiItem{}, err
+	}
+
+	s.cfg.Logger.Info("item rekonsiliasi diselesaikan",
+		"rekonsiliasi_id", recID,
+		"item_id", item.ID,
+		"status", item.Status,
+		"transaksi_id", item.TransaksiID,
+		"resolved_by", userID,
+	)
+	return item, nil
+}
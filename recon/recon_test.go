@@ -0,0 +1,223 @@
+package recon_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/database/databasetest"
+	"gobanking/model"
+	"gobanking/notifier"
+	"gobanking/recon"
+	"gobanking/repository"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const operator uint = 1
+
+type fixture struct {
+	db       *gorm.DB
+	accounts *account.Service
+	service  *recon.Service
+	rekening string
+}
+
+// newFixture matches amounts exactly and dates within a day.
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	db := databasetest.Open(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		Logger: logger,
+		Recon:  config.ReconConfig{DateToleranceDays: 1, MaxEntries: 1000},
+	}
+	accounts := account.NewService(repository.NewGormStore(db, nil), notifier.LogNotifier{Logger: logger})
+	n, err := accounts.OpenAccount(context.Background(), account.OpenAccountInput{Nama: "Budi", NIK: "nik-1", NoHP: "081"})
+	if err != nil {
+		t.Fatalf("OpenAccount: %v", err)
+	}
+	return fixture{db: db, accounts: accounts, service: recon.NewService(db, cfg), rekening: n.NoRekening}
+}
+
+// deposit books a referenced deposit and returns its tabung entry.
+func (f fixture) deposit(t *testing.T, referensi string, nominal float64) model.Transaksi {
+	t.Helper()
+	if _, err := f.accounts.Deposit(context.Background(), f.rekening, nominal, referensi); err != nil {
+		t.Fatalf("Deposit(%s): %v", referensi, err)
+	}
+	var d model.Transaksi
+	if err := f.db.Where("referensi = ?", referensi).First(&d).Error; err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// file is a settlement CSV of "referensi nominal" lines dated tanggal.
+func file(tanggal time.Time, lines ...string) []byte {
+	var b strings.Builder
+	b.WriteString("referensi,nominal,tanggal\n")
+	for _, line := range lines {
+		referensi, nominal, _ := strings.Cut(line, " ")
+		fmt.Fprintf(&b, "%s,%s,%s\n", referensi, nominal, tanggal.Format(time.DateOnly))
+	}
+	return []byte(b.String())
+}
+
+func (f fixture) importFile(t *testing.T, data []byte) model.Rekonsiliasi {
+	t.Helper()
+	rec, err := f.service.Import(context.Background(), operator, recon.ImportInput{Mitra: "alfamart", NamaFile: "settlement.csv", Data: data})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	return rec
+}
+
+func (f fixture) item(t *testing.T, recID uint, status string) model.RekonsiliasiItem {
+	t.Helper()
+	items, err := f.service.Items(context.Background(), recID, status, false)
+	if err != nil {
+		t.Fatalf("Items: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("%s items of #%d = %+v, want one", status, recID, items)
+	}
+	return items[0]
+}
+
+func TestImportMatchesInTwoPasses(t *testing.T) {
+	f := newFixture(t)
+	r1 := f.deposit(t, "R1", 100000)
+	r2 := f.deposit(t, "R2", 250000)
+	r3 := f.deposit(t, "R3", 50000)
+	data := file(r1.TanggalBisnis, "R1 100000", "r2/mangled 250000", "R9 70000")
+
+	rec := f.importFile(t, data)
+	if rec.TotalEntri != 3 || rec.Matched != 2 || rec.MissingInternal != 1 || rec.MissingExternal != 1 || rec.AmountMismatch != 0 || rec.Open != 2 {
+		t.Fatalf("rekonsiliasi = %+v, want 2 matched, 1 missing each way, 2 open", rec)
+	}
+	items, err := f.service.Items(context.Background(), rec.ID, model.RekonsiliasiMatched, false)
+	if err != nil {
+		t.Fatalf("Items: %v", err)
+	}
+	if len(items) != 2 || *items[0].TransaksiID != r1.ID || *items[1].TransaksiID != r2.ID {
+		t.Fatalf("matched = %+v, want R1 by reference and R2 by amount", items)
+	}
+	if missing := f.item(t, rec.ID, model.RekonsiliasiMissingExternal); *missing.TransaksiID != r3.ID {
+		t.Fatalf("missing_external = %+v, want R3", missing)
+	}
+
+	// The same bytes again, under another name, are refused by checksum.
+	_, err = f.service.Import(context.Background(), operator, recon.ImportInput{Mitra: "alfamart", NamaFile: "ulang.csv", Data: data})
+	if !errors.Is(err, recon.ErrDuplicateFile) {
+		t.Fatalf("second Import: err = %v, want ErrDuplicateFile", err)
+	}
+	var n int64
+	f.db.Model(&model.Rekonsiliasi{}).Count(&n)
+	if n != 1 {
+		t.Fatalf("rekonsiliasis = %d, want 1", n)
+	}
+}
+
+// TestLaterFileSettlesMissing imports a file without R2, then one with it:
+// the first file's missing_external item is resolved, and R1, already
+// claimed, is no longer a candidate.
+func TestLaterFileSettlesMissing(t *testing.T) {
+	f := newFixture(t)
+	r1 := f.deposit(t, "R1", 100000)
+	r2 := f.deposit(t, "R2", 250000)
+
+	first := f.importFile(t, file(r1.TanggalBisnis, "R1 100000"))
+	if first.MissingExternal != 1 || first.Open != 1 {
+		t.Fatalf("first = %+v, want R2 missing", first)
+	}
+	second := f.importFile(t, file(r1.TanggalBisnis, "R2 250000", "R1 100000"))
+	if second.Matched != 1 || second.MissingInternal != 1 || second.MissingExternal != 0 {
+		t.Fatalf("second = %+v, want R2 matched and R1 missing_internal", second)
+	}
+
+	first, err := f.service.Get(context.Background(), first.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if first.Open != 0 {
+		t.Fatalf("first open = %d after R2 arrived, want 0", first.Open)
+	}
+	settled := f.item(t, first.ID, model.RekonsiliasiMissingExternal)
+	want := fmt.Sprintf("Cocok di rekonsiliasi #%d", second.ID)
+	if *settled.TransaksiID != r2.ID || settled.ResolvedAt == nil || settled.ResolvedBy == nil || settled.Catatan != want {
+		t.Fatalf("settled = %+v, want resolved with %q", settled, want)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	r1 := f.deposit(t, "R1", 100000)
+	r5 := f.deposit(t, "R5", 80000)
+	r6 := f.deposit(t, "R6", 60000)
+	rec := f.importFile(t, file(r1.TanggalBisnis, "R1 100000", "ZZ 79999"))
+	if rec.MissingInternal != 1 || rec.MissingExternal != 2 || rec.Open != 3 {
+		t.Fatalf("rekonsiliasi = %+v, want ZZ missing_internal, R5 and R6 missing_external", rec)
+	}
+	zz := f.item(t, rec.ID, model.RekonsiliasiMissingInternal)
+	matched := f.item(t, rec.ID, model.RekonsiliasiMatched)
+	items, _ := f.service.Items(ctx, rec.ID, model.RekonsiliasiMissingExternal, false)
+	var missingR6 model.RekonsiliasiItem
+	for _, item := range items {
+		if *item.TransaksiID == r6.ID {
+			missingR6 = item
+		}
+	}
+
+	resolve := func(itemID uint, transaksiID *uint) (model.RekonsiliasiItem, error) {
+		return f.service.Resolve(ctx, operator, rec.ID, itemID, model.ResolveRekonsiliasiRequest{Catatan: "dicek manual", TransaksiID: transaksiID})
+	}
+	unknown := uint(999999)
+	for _, tt := range []struct {
+		name        string
+		itemID      uint
+		transaksiID *uint
+		want        error
+	}{
+		{"matched item", matched.ID, nil, recon.ErrNotException},
+		{"unknown item", 999999, nil, recon.ErrItemNotFound},
+		{"link a missing_external item", missingR6.ID, &r5.ID, recon.ErrNotLinkable},
+		{"link an unknown deposit", zz.ID, &unknown, recon.ErrTransaksiNotFound},
+		{"link a claimed deposit", zz.ID, &r1.ID, recon.ErrTransaksiClaimed},
+	} {
+		if _, err := resolve(tt.itemID, tt.transaksiID); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// Pairing ZZ with R5 by hand settles R5's missing_external item.
+	linked, err := resolve(zz.ID, &r5.ID)
+	if err != nil {
+		t.Fatalf("Resolve with R5: %v", err)
+	}
+	if linked.TransaksiID == nil || *linked.TransaksiID != r5.ID || linked.Selisih != -1 || linked.ResolvedAt == nil {
+		t.Fatalf("linked = %+v, want R5 with selisih -1, resolved", linked)
+	}
+	if _, err := resolve(zz.ID, nil); !errors.Is(err, recon.ErrAlreadyResolved) {
+		t.Fatalf("Resolve again: err = %v, want ErrAlreadyResolved", err)
+	}
+	rec, _ = f.service.Get(ctx, rec.ID)
+	if rec.Open != 1 {
+		t.Fatalf("open = %d after pairing R5, want R6 only", rec.Open)
+	}
+
+	if _, err := resolve(missingR6.ID, nil); err != nil {
+		t.Fatalf("Resolve R6: %v", err)
+	}
+	rec, _ = f.service.Get(ctx, rec.ID)
+	if rec.Open != 0 || rec.MissingExternal != 2 || rec.MissingInternal != 1 {
+		t.Fatalf("rekonsiliasi = %+v, want every exception kept and none open", rec)
+	}
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gobanking/model"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnqueueFunc stores an outbox event using the given transaction, e.g.
+// webhook.Dispatcher.Enqueue.
+type EnqueueFunc func(tx *gorm.DB, event, noRekening string, data any) error
+
+// GormStore is the Postgres implementation of Store.
+type GormStore struct {
+	db      *gorm.DB
+	enqueue EnqueueFunc
+}
+
+func NewGormStore(db *gorm.DB, enqueue EnqueueFunc) *GormStore {
+	return &GormStore{db: db, enqueue: enqueue}
+}
+
+func (s *GormStore) Nasabah() NasabahRepository   { return gormNasabah{s.db} }
+func (s *GormStore) Users() UserRepository        { return gormUsers{s.db} }
+func (s *GormStore) Outbox() Outbox               { return gormOutbox{s.db, s.enqueue} }
+func (s *GormStore) FX() FXRepository             { return gormFX{s.db} }
+func (s *GormStore) GL() GLRepository             { return gormGL{s.db} }
+func (s *GormStore) Calendar() CalendarRepository { return gormCalendar{s.db} }
+
+func (s *GormStore) WithinTx(ctx context.Context, fn func(tx Store) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&GormStore{db: tx, enqueue: s.enqueue})
+	})
+}
+
+// translate maps driver errors onto the repository's sentinel errors.
+func translate(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation
+			return ErrDuplicate
+		case "23514": // check_violation, only chk_nasabahs_saldo today
+			return ErrNegativeBalance
+		}
+	}
+	return err
+}
+
+type gormNasabah struct{ db *gorm.DB }
+
+func (r gormNasabah) Create(ctx context.Context, nasabah *model.Nasabah) error {
+	if nasabah.Saldo < 0 {
+		return ErrNegativeBalance
+	}
+	return translate(r.db.WithContext(ctx).Create(nasabah).Error)
+}
+
+func (r gormNasabah) FindByNoRekening(ctx context.Context, noRekening string) (model.Nasabah, error) {
+	var nasabah model.Nasabah
+	err := r.db.WithContext(ctx).Where("no_rekening = ?", noRekening).First(&nasabah).Error
+	return nasabah, translate(err)
+}
+
+func (r gormNasabah) LockByNoRekening(ctx context.Context, noRekening string) (model.Nasabah, error) {
+	var nasabah model.Nasabah
+	err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("no_rekening = ?", noRekening).
+		First(&nasabah).Error
+	return nasabah, translate(err)
+}
+
+func (r gormNasabah) ExistsByNIKOrNoHP(ctx context.Context, nik, noHP string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Nasabah{}).
+		Where("nik = ? OR no_hp = ?", nik, noHP).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r gormNasabah) NoHPUsedByOther(ctx context.Context, noHP, noRekening string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Nasabah{}).
+		Where("no_hp = ? AND no_rekening <> ?", noHP, noRekening).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r gormNasabah) UpdateNoHP(ctx context.Context, noRekening, noHP string) error {
+	result := r.db.WithContext(ctx).Model(&model.Nasabah{}).
+		Where("no_rekening = ?", noRekening).
+		Update("no_hp", noHP)
+	if result.Error != nil {
+		return translate(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// UpdateSaldo leaves a negative saldo to chk_nasabahs_saldo, which allows
+// it only while overdraft_at is set.
+func (r gormNasabah) UpdateSaldo(ctx context.Context, id uint, saldo float64) error {
+	updates := map[string]any{"saldo": saldo}
+	if saldo >= 0 {
+		updates["overdraft_at"] = nil
+	}
+	return r.updateSaldo(ctx, id, updates)
+}
+
+func (r gormNasabah) Overdraw(ctx context.Context, id uint, saldo float64) error {
+	return r.updateSaldo(ctx, id, map[string]any{
+		"saldo":        saldo,
+		"overdraft_at": gorm.Expr("COALESCE(overdraft_at, ?)", time.Now()),
+	})
+}
+
+func (r gormNasabah) updateSaldo(ctx context.Context, id uint, updates map[string]any) error {
+	result := r.db.WithContext(ctx).Model(&model.Nasabah{}).
+		Where("id = ?", id).
+		Updates(updates)
+	if result.Error != nil {
+		return translate(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r gormNasabah) CreateTransaksi(ctx context.Context, transaksi *model.Transaksi) error {
+	return translate(r.db.WithContext(ctx).Create(transaksi).Error)
+}
+
+func (r gormNasabah) FindTransaksiByID(ctx context.Context, id uint) (model.Transaksi, error) {
+	var transaksi model.Transaksi
+	err := r.db.WithContext(ctx).First(&transaksi, id).Error
+	return transaksi, translate(err)
+}
+
+func (r gormNasabah) FindReversalOf(ctx context.Context, transaksiID uint) (model.Transaksi, error) {
+	var transaksi model.Transaksi
+	err := r.db.WithContext(ctx).Where("reversal_of_id = ?", transaksiID).First(&transaksi).Error
+	return transaksi, translate(err)
+}
+
+func (r gormNasabah) FindTransaksiByReferensi(ctx context.Context, noRekening, referensi string) (model.Transaksi, error) {
+	var transaksi model.Transaksi
+	err := r.db.WithContext(ctx).
+		Where("no_rekening = ? AND referensi = ? AND referensi <> ''", noRekening, referensi).
+		First(&transaksi).Error
+	return transaksi, translate(err)
+}
+
+func (r gormNasabah) CreatePenerima(ctx context.Context, penerima *model.Penerima) error {
+	return translate(r.db.WithContext(ctx).Create(penerima).Error)
+}
+
+func (r gormNasabah) ListPenerima(ctx context.Context, noRekening string) ([]model.Penerima, error) {
+	var penerima []model.Penerima
+	err := r.db.WithContext(ctx).Where("no_rekening = ?", noRekening).Order("id").Find(&penerima).Error
+	return penerima, err
+}
+
+type gormUsers struct{ db *gorm.DB }
+
+func (r gormUsers) Create(ctx context.Context, user *model.User) error {
+	return translate(r.db.WithContext(ctx).Create(user).Error)
+}
+
+func (r gormUsers) FindByID(ctx context.Context, id uint) (model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).First(&user, id).Error
+	return user, translate(err)
+}
+
+func (r gormUsers) FindByEmail(ctx context.Context, email string) (model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	return user, translate(err)
+}
+
+func (r gormUsers) SetTOTPSecret(ctx context.Context, id uint, secret string) error {
+	return r.update(ctx, id, map[string]any{"totp_secret": secret})
+}
+
+func (r gormUsers) EnableTOTP(ctx context.Context, id uint, backupHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", id).Delete(&model.BackupCode{}).Error; err != nil {
+			return err
+		}
+		for _, hash := range backupHashes {
+			if err := tx.Create(&model.BackupCode{UserID: id, CodeHash: hash}).Error; err != nil {
+				return err
+			}
+		}
+		return gormUsers{tx}.update(ctx, id, map[string]any{"totp_enabled": true})
+	})
+}
+
+func (r gormUsers) DisableTOTP(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", id).Delete(&model.BackupCode{}).Error; err != nil {
+			return err
+		}
+		return gormUsers{tx}.update(ctx, id, map[string]any{"totp_enabled": false, "totp_secret": ""})
+	})
+}
+
+func (r gormUsers) UnusedBackupCodes(ctx context.Context, userID uint) ([]model.BackupCode, error) {
+	var backups []model.BackupCode
+	err := r.db.WithContext(ctx).Where("user_id = ? AND used_at IS NULL", userID).Order("id").Find(&backups).Error
+	return backups, err
+}
+
+func (r gormUsers) UseBackupCode(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.BackupCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r gormUsers) StartMFALogin(ctx context.Context, id uint, jti string) error {
+	return r.update(ctx, id, map[string]any{"mfa_jti": jti, "mfa_attempts": 0})
+}
+
+func (r gormUsers) ReserveMFAAttempt(ctx context.Context, id uint, jti string, maxAttempts int) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id = ? AND mfa_jti = ? AND mfa_jti <> '' AND mfa_attempts < ?", id, jti, maxAttempts).
+		Update("mfa_attempts", gorm.Expr("mfa_attempts + 1"))
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r gormUsers) FinishMFALogin(ctx context.Context, id uint, jti string) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id = ? AND mfa_jti = ? AND mfa_jti <> ''", id, jti).
+		Update("mfa_jti", "")
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r gormUsers) update(ctx context.Context, id uint, updates map[string]any) error {
+	result := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return translate(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type gormFX struct{ db *gorm.DB }
+
+func (r gormFX) CreateKurs(ctx context.Context, kurs *model.Kurs) error {
+	return translate(r.db.WithContext(ctx).Create(kurs).Error)
+}
+
+func (r gormFX) KursAt(ctx context.Context, mataUang string, t time.Time) (model.Kurs, error) {
+	var kurs model.Kurs
+	err := r.db.WithContext(ctx).
+		Where("mata_uang = ? AND berlaku_mulai <= ?", mataUang, t).
+		Order("berlaku_mulai DESC").
+		First(&kurs).Error
+	return kurs, translate(err)
+}
+
+func (r gormFX) ListKursAt(ctx context.Context, t time.Time) ([]model.Kurs, error) {
+	var rates []model.Kurs
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT * FROM kurs AS k
+		WHERE k.deleted_at IS NULL AND k.berlaku_mulai = (
+			SELECT MAX(berlaku_mulai) FROM kurs
+			WHERE mata_uang = k.mata_uang AND berlaku_mulai <= ? AND deleted_at IS NULL
+		)
+		ORDER BY k.mata_uang`, t).
+		Scan(&rates).Error
+	return rates, err
+}
+
+func (r gormFX) CreateKonversi(ctx context.Context, konversi *model.KonversiValas) error {
+	return translate(r.db.WithContext(ctx).Create(konversi).Error)
+}
+
+type gormGL struct{ db *gorm.DB }
+
+func (r gormGL) AturanPosting(ctx context.Context, jenis string) (model.AturanPosting, error) {
+	var aturan model.AturanPosting
+	err := r.db.WithContext(ctx).Where("jenis = ?", jenis).First(&aturan).Error
+	return aturan, translate(err)
+}
+
+func (r gormGL) CreateJurnal(ctx context.Context, jurnal *model.JurnalGL) error {
+	return translate(r.db.WithContext(ctx).Create(jurnal).Error)
+}
+
+// CalendarLockKey is the pg_advisory_xact_lock key that orders bookings
+// against the end-of-day cut-off. Bookings hold it shared, so they never
+// wait on each other; the cut-off takes it exclusively and so waits for
+// every transaction that already read the old business date.
+const CalendarLockKey = 7_362_041_340
+
+type gormCalendar struct{ db *gorm.DB }
+
+func (r gormCalendar) TanggalBisnis(ctx context.Context) (model.TanggalBisnis, error) {
+	var tanggal model.TanggalBisnis
+	db := r.db.WithContext(ctx)
+	if err := db.Exec("SELECT pg_advisory_xact_lock_shared(?)", CalendarLockKey).Error; err != nil {
+		return tanggal, err
+	}
+	err := db.First(&tanggal).Error
+	return tanggal, translate(err)
+}
+
+type gormOutbox struct {
+	db      *gorm.DB
+	enqueue EnqueueFunc
+}
+
+func (o gormOutbox) Enqueue(ctx context.Context, event, noRekening string, data any) error {
+	if o.enqueue == nil {
+		return nil
+	}
+	return o.enqueue(o.db.WithContext(ctx), event, noRekening, data)
+}
@@ -0,0 +1,669 @@
+package repository
+
+import (
+	"context"
+	"gobanking/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Event is an outbox entry recorded by MemoryStore.
+type Event struct {
+	Event      string
+	NoRekening string
+	Data       any
+}
+
+// MemoryStore is an in-process Store for tests and tools. It enforces the
+// same unique constraints and balance check as Postgres, and starts with
+// the default posting rules and today as the business date, like a freshly
+// migrated database. Transactions
+// are serialised: WithinTx works on a copy of the data under a store-wide
+// lock and swaps it in on commit.
+type MemoryStore struct {
+	mu   *sync.Mutex
+	data *memoryData
+	// root is the store a transaction commits into; nil outside one.
+	root *MemoryStore
+}
+
+type memoryData struct {
+	lastID    uint
+	nasabah   map[uint]model.Nasabah
+	transaksi []model.Transaksi
+	penerima  []model.Penerima
+	users     map[uint]model.User
+	backups   map[uint]model.BackupCode
+	events    []Event
+	kurs      []model.Kurs
+	konversi  []model.KonversiValas
+	aturan    map[string]model.AturanPosting
+	jurnal    []model.JurnalGL
+	tanggal   model.TanggalBisnis
+}
+
+func NewMemoryStore() *MemoryStore {
+	d := &memoryData{
+		nasabah: make(map[uint]model.Nasabah),
+		users:   make(map[uint]model.User),
+		backups: make(map[uint]model.BackupCode),
+		aturan:  make(map[string]model.AturanPosting),
+	}
+	for _, a := range model.DefaultAturanPosting {
+		a.ID = d.nextID()
+		d.aturan[a.Jenis] = a
+	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	next := today.AddDate(0, 0, 1)
+	for next.Weekday() == time.Saturday || next.Weekday() == time.Sunday {
+		next = next.AddDate(0, 0, 1)
+	}
+	d.tanggal = model.TanggalBisnis{ID: 1, Tanggal: today, Berikutnya: next, UpdatedAt: now}
+	return &MemoryStore{mu: &sync.Mutex{}, data: d}
+}
+
+func (d *memoryData) clone() *memoryData {
+	c := *d
+	c.nasabah = make(map[uint]model.Nasabah, len(d.nasabah))
+	for k, v := range d.nasabah {
+		c.nasabah[k] = v
+	}
+	c.users = make(map[uint]model.User, len(d.users))
+	for k, v := range d.users {
+		c.users[k] = v
+	}
+	c.backups = make(map[uint]model.BackupCode, len(d.backups))
+	for k, v := range d.backups {
+		c.backups[k] = v
+	}
+	c.transaksi = append([]model.Transaksi(nil), d.transaksi...)
+	c.penerima = append([]model.Penerima(nil), d.penerima...)
+	c.events = append([]Event(nil), d.events...)
+	c.kurs = append([]model.Kurs(nil), d.kurs...)
+	c.konversi = append([]model.KonversiValas(nil), d.konversi...)
+	// Posting rules are never changed through the Store, so they are
+	// shared rather than copied.
+	c.jurnal = append([]model.JurnalGL(nil), d.jurnal...)
+	return &c
+}
+
+func (d *memoryData) nextID() uint {
+	d.lastID++
+	return d.lastID
+}
+
+// do runs fn against the current data, taking the lock unless the store is
+// already inside a transaction that holds it.
+func (s *MemoryStore) do(fn func(d *memoryData) error) error {
+	if s.root == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	return fn(s.data)
+}
+
+func (s *MemoryStore) Nasabah() NasabahRepository   { return memoryNasabah{s} }
+func (s *MemoryStore) Users() UserRepository        { return memoryUsers{s} }
+func (s *MemoryStore) Outbox() Outbox               { return memoryOutbox{s} }
+func (s *MemoryStore) FX() FXRepository             { return memoryFX{s} }
+func (s *MemoryStore) GL() GLRepository             { return memoryGL{s} }
+func (s *MemoryStore) Calendar() CalendarRepository { return memoryCalendar{s} }
+
+func (s *MemoryStore) WithinTx(ctx context.Context, fn func(tx Store) error) error {
+	if s.root != nil {
+		return fn(s)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := &MemoryStore{mu: s.mu, data: s.data.clone(), root: s}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	s.data = tx.data
+	return nil
+}
+
+// SetTanggalBisnis replaces the business date, as the end-of-day run does.
+func (s *MemoryStore) SetTanggalBisnis(tanggal model.TanggalBisnis) {
+	s.do(func(d *memoryData) error {
+		tanggal.ID = 1
+		d.tanggal = tanggal
+		return nil
+	})
+}
+
+// Jurnal returns the GL postings committed so far.
+func (s *MemoryStore) Jurnal() []model.JurnalGL {
+	var jurnal []model.JurnalGL
+	s.do(func(d *memoryData) error {
+		jurnal = append(jurnal, d.jurnal...)
+		return nil
+	})
+	return jurnal
+}
+
+// Events returns the outbox entries committed so far.
+func (s *MemoryStore) Events() []Event {
+	var events []Event
+	s.do(func(d *memoryData) error {
+		events = append(events, d.events...)
+		return nil
+	})
+	return events
+}
+
+type memoryNasabah struct{ s *MemoryStore }
+
+func (r memoryNasabah) Create(ctx context.Context, nasabah *model.Nasabah) error {
+	return r.s.do(func(d *memoryData) error {
+		if nasabah.Saldo < 0 {
+			return ErrNegativeBalance
+		}
+		for _, n := range d.nasabah {
+			if n.NIK == nasabah.NIK || n.NoHP == nasabah.NoHP || n.NoRekening == nasabah.NoRekening {
+				return ErrDuplicate
+			}
+		}
+		if nasabah.Bahasa == "" {
+			nasabah.Bahasa = "id"
+		}
+		if nasabah.MataUang == "" {
+			nasabah.MataUang = model.MataUangIDR
+		}
+		now := time.Now()
+		nasabah.ID = d.nextID()
+		nasabah.CreatedAt, nasabah.UpdatedAt = now, now
+		d.nasabah[nasabah.ID] = *nasabah
+		return nil
+	})
+}
+
+func (r memoryNasabah) FindByNoRekening(ctx context.Context, noRekening string) (model.Nasabah, error) {
+	var found model.Nasabah
+	err := r.s.do(func(d *memoryData) error {
+		for _, n := range d.nasabah {
+			if n.NoRekening == noRekening {
+				found = n
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+	return found, err
+}
+
+// LockByNoRekening needs no extra locking: a transaction already holds the
+// store-wide lock.
+func (r memoryNasabah) LockByNoRekening(ctx context.Context, noRekening string) (model.Nasabah, error) {
+	return r.FindByNoRekening(ctx, noRekening)
+}
+
+func (r memoryNasabah) ExistsByNIKOrNoHP(ctx context.Context, nik, noHP string) (bool, error) {
+	var exists bool
+	err := r.s.do(func(d *memoryData) error {
+		for _, n := range d.nasabah {
+			if n.NIK == nik || n.NoHP == noHP {
+				exists = true
+			}
+		}
+		return nil
+	})
+	return exists, err
+}
+
+func (r memoryNasabah) NoHPUsedByOther(ctx context.Context, noHP, noRekening string) (bool, error) {
+	var used bool
+	err := r.s.do(func(d *memoryData) error {
+		for _, n := range d.nasabah {
+			if n.NoHP == noHP && n.NoRekening != noRekening {
+				used = true
+			}
+		}
+		return nil
+	})
+	return used, err
+}
+
+func (r memoryNasabah) UpdateNoHP(ctx context.Context, noRekening, noHP string) error {
+	return r.s.do(func(d *memoryData) error {
+		var target *model.Nasabah
+		for id, n := range d.nasabah {
+			if n.NoHP == noHP && n.NoRekening != noRekening {
+				return ErrDuplicate
+			}
+			if n.NoRekening == noRekening {
+				n := d.nasabah[id]
+				target = &n
+			}
+		}
+		if target == nil {
+			return ErrNotFound
+		}
+		target.NoHP = noHP
+		target.UpdatedAt = time.Now()
+		d.nasabah[target.ID] = *target
+		return nil
+	})
+}
+
+func (r memoryNasabah) UpdateSaldo(ctx context.Context, id uint, saldo float64) error {
+	return r.s.do(func(d *memoryData) error {
+		n, ok := d.nasabah[id]
+		if !ok {
+			return ErrNotFound
+		}
+		if saldo < 0 && n.OverdraftAt == nil {
+			return ErrNegativeBalance
+		}
+		if saldo >= 0 {
+			n.OverdraftAt = nil
+		}
+		n.Saldo = saldo
+		n.UpdatedAt = time.Now()
+		d.nasabah[id] = n
+		return nil
+	})
+}
+
+func (r memoryNasabah) Overdraw(ctx context.Context, id uint, saldo float64) error {
+	return r.s.do(func(d *memoryData) error {
+		n, ok := d.nasabah[id]
+		if !ok {
+			return ErrNotFound
+		}
+		now := time.Now()
+		if n.OverdraftAt == nil {
+			n.OverdraftAt = &now
+		}
+		n.Saldo = saldo
+		n.UpdatedAt = now
+		d.nasabah[id] = n
+		return nil
+	})
+}
+
+func (r memoryNasabah) CreateTransaksi(ctx context.Context, transaksi *model.Transaksi) error {
+	return r.s.do(func(d *memoryData) error {
+		for _, t := range d.transaksi {
+			if transaksi.Referensi != "" && t.NoRekening == transaksi.NoRekening && t.Referensi == transaksi.Referensi {
+				return ErrDuplicate
+			}
+			if transaksi.ReversalOfID != nil && t.ReversalOfID != nil && *t.ReversalOfID == *transaksi.ReversalOfID {
+				return ErrDuplicate
+			}
+		}
+		if transaksi.MataUang == "" {
+			transaksi.MataUang = model.MataUangIDR
+		}
+		now := time.Now()
+		transaksi.ID = d.nextID()
+		transaksi.CreatedAt, transaksi.UpdatedAt = now, now
+		d.transaksi = append(d.transaksi, *transaksi)
+		return nil
+	})
+}
+
+func (r memoryNasabah) FindTransaksiByID(ctx context.Context, id uint) (model.Transaksi, error) {
+	return r.findTransaksi(func(t model.Transaksi) bool { return t.ID == id })
+}
+
+func (r memoryNasabah) FindReversalOf(ctx context.Context, transaksiID uint) (model.Transaksi, error) {
+	return r.findTransaksi(func(t model.Transaksi) bool {
+		return t.ReversalOfID != nil && *t.ReversalOfID == transaksiID
+	})
+}
+
+func (r memoryNasabah) findTransaksi(match func(t model.Transaksi) bool) (model.Transaksi, error) {
+	var found model.Transaksi
+	err := r.s.do(func(d *memoryData) error {
+		for _, t := range d.transaksi {
+			if match(t) {
+				found = t
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+	return found, err
+}
+
+func (r memoryNasabah) FindTransaksiByReferensi(ctx context.Context, noRekening, referensi string) (model.Transaksi, error) {
+	var found model.Transaksi
+	err := r.s.do(func(d *memoryData) error {
+		for _, t := range d.transaksi {
+			if referensi != "" && t.NoRekening == noRekening && t.Referensi == referensi {
+				found = t
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+	return found, err
+}
+
+func (r memoryNasabah) CreatePenerima(ctx context.Context, penerima *model.Penerima) error {
+	return r.s.do(func(d *memoryData) error {
+		for _, p := range d.penerima {
+			if p.NoRekening == penerima.NoRekening && p.NoRekeningTujuan == penerima.NoRekeningTujuan {
+				return ErrDuplicate
+			}
+		}
+		now := time.Now()
+		penerima.ID = d.nextID()
+		penerima.CreatedAt, penerima.UpdatedAt = now, now
+		d.penerima = append(d.penerima, *penerima)
+		return nil
+	})
+}
+
+func (r memoryNasabah) ListPenerima(ctx context.Context, noRekening string) ([]model.Penerima, error) {
+	list := []model.Penerima{}
+	err := r.s.do(func(d *memoryData) error {
+		for _, p := range d.penerima {
+			if p.NoRekening == noRekening {
+				list = append(list, p)
+			}
+		}
+		return nil
+	})
+	return list, err
+}
+
+type memoryUsers struct{ s *MemoryStore }
+
+func (r memoryUsers) Create(ctx context.Context, user *model.User) error {
+	return r.s.do(func(d *memoryData) error {
+		for _, u := range d.users {
+			if u.Email == user.Email {
+				return ErrDuplicate
+			}
+		}
+		if user.Role == "" {
+			user.Role = model.RoleUser
+		}
+		now := time.Now()
+		user.ID = d.nextID()
+		user.CreatedAt, user.UpdatedAt = now, now
+		d.users[user.ID] = *user
+		return nil
+	})
+}
+
+func (r memoryUsers) FindByID(ctx context.Context, id uint) (model.User, error) {
+	var user model.User
+	err := r.s.do(func(d *memoryData) error {
+		u, ok := d.users[id]
+		if !ok {
+			return ErrNotFound
+		}
+		user = u
+		return nil
+	})
+	return user, err
+}
+
+func (r memoryUsers) FindByEmail(ctx context.Context, email string) (model.User, error) {
+	var user model.User
+	err := r.s.do(func(d *memoryData) error {
+		for _, u := range d.users {
+			if u.Email == email {
+				user = u
+				return nil
+			}
+		}
+		return ErrNotFound
+	})
+	return user, err
+}
+
+func (r memoryUsers) SetTOTPSecret(ctx context.Context, id uint, secret string) error {
+	return r.update(id, func(u *model.User) { u.TOTPSecret = secret })
+}
+
+func (r memoryUsers) EnableTOTP(ctx context.Context, id uint, backupHashes []string) error {
+	return r.s.WithinTx(ctx, func(tx Store) error {
+		r := tx.(*MemoryStore)
+		if err := (memoryUsers{r}).update(id, func(u *model.User) { u.TOTPEnabled = true }); err != nil {
+			return err
+		}
+		return r.do(func(d *memoryData) error {
+			deleteBackups(d, id)
+			now := time.Now()
+			for _, hash := range backupHashes {
+				code := model.BackupCode{UserID: id, CodeHash: hash}
+				code.ID = d.nextID()
+				code.CreatedAt, code.UpdatedAt = now, now
+				d.backups[code.ID] = code
+			}
+			return nil
+		})
+	})
+}
+
+func (r memoryUsers) DisableTOTP(ctx context.Context, id uint) error {
+	return r.s.WithinTx(ctx, func(tx Store) error {
+		r := tx.(*MemoryStore)
+		err := (memoryUsers{r}).update(id, func(u *model.User) {
+			u.TOTPEnabled = false
+			u.TOTPSecret = ""
+		})
+		if err != nil {
+			return err
+		}
+		return r.do(func(d *memoryData) error {
+			deleteBackups(d, id)
+			return nil
+		})
+	})
+}
+
+func (r memoryUsers) UnusedBackupCodes(ctx context.Context, userID uint) ([]model.BackupCode, error) {
+	codes := []model.BackupCode{}
+	err := r.s.do(func(d *memoryData) error {
+		for _, code := range d.backups {
+			if code.UserID == userID && code.UsedAt == nil {
+				codes = append(codes, code)
+			}
+		}
+		return nil
+	})
+	sort.Slice(codes, func(i, j int) bool { return codes[i].ID < codes[j].ID })
+	return codes, err
+}
+
+func (r memoryUsers) UseBackupCode(ctx context.Context, id uint) (bool, error) {
+	var used bool
+	err := r.s.do(func(d *memoryData) error {
+		code, ok := d.backups[id]
+		if !ok || code.UsedAt != nil {
+			return nil
+		}
+		now := time.Now()
+		code.UsedAt = &now
+		d.backups[id] = code
+		used = true
+		return nil
+	})
+	return used, err
+}
+
+func (r memoryUsers) StartMFALogin(ctx context.Context, id uint, jti string) error {
+	return r.update(id, func(u *model.User) {
+		u.MFAJTI = jti
+		u.MFAAttempts = 0
+	})
+}
+
+func (r memoryUsers) ReserveMFAAttempt(ctx context.Context, id uint, jti string, maxAttempts int) (bool, error) {
+	var reserved bool
+	err := r.s.do(func(d *memoryData) error {
+		u, ok := d.users[id]
+		if !ok || jti == "" || u.MFAJTI != jti || u.MFAAttempts >= maxAttempts {
+			return nil
+		}
+		u.MFAAttempts++
+		d.users[id] = u
+		reserved = true
+		return nil
+	})
+	return reserved, err
+}
+
+func (r memoryUsers) FinishMFALogin(ctx context.Context, id uint, jti string) (bool, error) {
+	var finished bool
+	err := r.s.do(func(d *memoryData) error {
+		u, ok := d.users[id]
+		if !ok || jti == "" || u.MFAJTI != jti {
+			return nil
+		}
+		u.MFAJTI = ""
+		d.users[id] = u
+		finished = true
+		return nil
+	})
+	return finished, err
+}
+
+func (r memoryUsers) update(id uint, fn func(u *model.User)) error {
+	return r.s.do(func(d *memoryData) error {
+		u, ok := d.users[id]
+		if !ok {
+			return ErrNotFound
+		}
+		fn(&u)
+		u.UpdatedAt = time.Now()
+		d.users[id] = u
+		return nil
+	})
+}
+
+func deleteBackups(d *memoryData, userID uint) {
+	for id, code := range d.backups {
+		if code.UserID == userID {
+			delete(d.backups, id)
+		}
+	}
+}
+
+type memoryOutbox struct{ s *MemoryStore }
+
+func (o memoryOutbox) Enqueue(ctx context.Context, event, noRekening string, data any) error {
+	return o.s.do(func(d *memoryData) error {
+		d.events = append(d.events, Event{Event: event, NoRekening: noRekening, Data: data})
+		return nil
+	})
+}
+
+type memoryFX struct{ s *MemoryStore }
+
+func (r memoryFX) CreateKurs(ctx context.Context, kurs *model.Kurs) error {
+	return r.s.do(func(d *memoryData) error {
+		for _, k := range d.kurs {
+			if k.MataUang == kurs.MataUang && k.BerlakuMulai.Equal(kurs.BerlakuMulai) {
+				return ErrDuplicate
+			}
+		}
+		now := time.Now()
+		kurs.ID = d.nextID()
+		kurs.CreatedAt, kurs.UpdatedAt = now, now
+		d.kurs = append(d.kurs, *kurs)
+		return nil
+	})
+}
+
+func (r memoryFX) KursAt(ctx context.Context, mataUang string, t time.Time) (model.Kurs, error) {
+	var found model.Kurs
+	err := r.s.do(func(d *memoryData) error {
+		for _, k := range d.kurs {
+			if k.MataUang == mataUang && !k.BerlakuMulai.After(t) && k.BerlakuMulai.After(found.BerlakuMulai) {
+				found = k
+			}
+		}
+		if found.ID == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+	return found, err
+}
+
+func (r memoryFX) ListKursAt(ctx context.Context, t time.Time) ([]model.Kurs, error) {
+	latest := make(map[string]model.Kurs)
+	r.s.do(func(d *memoryData) error {
+		for _, k := range d.kurs {
+			if !k.BerlakuMulai.After(t) && k.BerlakuMulai.After(latest[k.MataUang].BerlakuMulai) {
+				latest[k.MataUang] = k
+			}
+		}
+		return nil
+	})
+	rates := make([]model.Kurs, 0, len(latest))
+	for _, k := range latest {
+		rates = append(rates, k)
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].MataUang < rates[j].MataUang })
+	return rates, nil
+}
+
+func (r memoryFX) CreateKonversi(ctx context.Context, konversi *model.KonversiValas) error {
+	return r.s.do(func(d *memoryData) error {
+		for _, k := range d.konversi {
+			if k.TransaksiDebitID == konversi.TransaksiDebitID {
+				return ErrDuplicate
+			}
+		}
+		now := time.Now()
+		konversi.ID = d.nextID()
+		konversi.CreatedAt, konversi.UpdatedAt = now, now
+		d.konversi = append(d.konversi, *konversi)
+		return nil
+	})
+}
+
+type memoryGL struct{ s *MemoryStore }
+
+func (r memoryGL) AturanPosting(ctx context.Context, jenis string) (model.AturanPosting, error) {
+	var aturan model.AturanPosting
+	err := r.s.do(func(d *memoryData) error {
+		a, ok := d.aturan[jenis]
+		if !ok {
+			return ErrNotFound
+		}
+		aturan = a
+		return nil
+	})
+	return aturan, err
+}
+
+func (r memoryGL) CreateJurnal(ctx context.Context, jurnal *model.JurnalGL) error {
+	return r.s.do(func(d *memoryData) error {
+		for _, j := range d.jurnal {
+			if j.TransaksiID == jurnal.TransaksiID && j.Jenis == jurnal.Jenis {
+				return ErrDuplicate
+			}
+		}
+		if jurnal.MataUang == "" {
+			jurnal.MataUang = model.MataUangIDR
+		}
+		now := time.Now()
+		jurnal.ID = d.nextID()
+		jurnal.CreatedAt, jurnal.UpdatedAt = now, now
+		d.jurnal = append(d.jurnal, *jurnal)
+		return nil
+	})
+}
+
+type memoryCalendar struct{ s *MemoryStore }
+
+func (r memoryCalendar) TanggalBisnis(ctx context.Context) (model.TanggalBisnis, error) {
+	var tanggal model.TanggalBisnis
+	err := r.s.do(func(d *memoryData) error {
+		tanggal = d.tanggal
+		return nil
+	})
+	return tanggal, err
+}
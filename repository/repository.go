@@ -0,0 +1,135 @@
+// Package repository hides persistence behind interfaces so handlers and
+// services can run against Postgres or an in-memory store with the same
+// semantics.
+package repository
+
+import (
+	"context"
+	"errors"
+	"gobanking/model"
+	"time"
+)
+
+var (
+	ErrNotFound        = errors.New("data tidak ditemukan")
+	ErrDuplicate       = errors.New("data sudah terdaftar")
+	ErrNegativeBalance = errors.New("saldo tidak boleh negatif")
+)
+
+// Store groups the repositories. Methods called on the Store passed to
+// WithinTx's callback take part in that transaction.
+type Store interface {
+	Nasabah() NasabahRepository
+	Users() UserRepository
+	Outbox() Outbox
+	FX() FXRepository
+	GL() GLRepository
+	Calendar() CalendarRepository
+
+	// WithinTx runs fn in a transaction that commits if fn returns nil and
+	// rolls back otherwise. Nested calls join the outer transaction.
+	WithinTx(ctx context.Context, fn func(tx Store) error) error
+}
+
+type NasabahRepository interface {
+	// Create assigns the ID and returns ErrDuplicate if the NIK, NoHP or
+	// NoRekening is already taken. An empty Bahasa defaults to "id" and an
+	// empty MataUang to "IDR".
+	Create(ctx context.Context, nasabah *model.Nasabah) error
+	FindByNoRekening(ctx context.Context, noRekening string) (model.Nasabah, error)
+	// LockByNoRekening is FindByNoRekening holding a row lock until the
+	// surrounding transaction ends; call it inside WithinTx.
+	LockByNoRekening(ctx context.Context, noRekening string) (model.Nasabah, error)
+	ExistsByNIKOrNoHP(ctx context.Context, nik, noHP string) (bool, error)
+	// NoHPUsedByOther reports whether noHP belongs to an account other
+	// than noRekening.
+	NoHPUsedByOther(ctx context.Context, noHP, noRekening string) (bool, error)
+	UpdateNoHP(ctx context.Context, noRekening, noHP string) error
+	// UpdateSaldo returns ErrNegativeBalance rather than store saldo < 0,
+	// unless the account is already overdrawn. A saldo of zero or more
+	// clears OverdraftAt.
+	UpdateSaldo(ctx context.Context, id uint, saldo float64) error
+	// Overdraw stores a saldo that may be negative and flags the account
+	// with OverdraftAt. Only reversals approved for overdraft use it.
+	Overdraw(ctx context.Context, id uint, saldo float64) error
+
+	// CreateTransaksi returns ErrDuplicate if the account already has a
+	// Transaksi with the same non-empty Referensi, or if ReversalOfID has
+	// already been reversed. An empty MataUang defaults to "IDR".
+	CreateTransaksi(ctx context.Context, transaksi *model.Transaksi) error
+	FindTransaksiByID(ctx context.Context, id uint) (model.Transaksi, error)
+	FindTransaksiByReferensi(ctx context.Context, noRekening, referensi string) (model.Transaksi, error)
+	// FindReversalOf returns the koreksi entry reversing transaksiID.
+	FindReversalOf(ctx context.Context, transaksiID uint) (model.Transaksi, error)
+
+	// CreatePenerima returns ErrDuplicate if the pair is already saved.
+	CreatePenerima(ctx context.Context, penerima *model.Penerima) error
+	ListPenerima(ctx context.Context, noRekening string) ([]model.Penerima, error)
+}
+
+type UserRepository interface {
+	// Create assigns the ID and returns ErrDuplicate if the email is taken.
+	// An empty Role defaults to model.RoleUser.
+	Create(ctx context.Context, user *model.User) error
+	FindByID(ctx context.Context, id uint) (model.User, error)
+	FindByEmail(ctx context.Context, email string) (model.User, error)
+
+	SetTOTPSecret(ctx context.Context, id uint, secret string) error
+	// EnableTOTP turns 2FA on and replaces the user's backup codes.
+	EnableTOTP(ctx context.Context, id uint, backupHashes []string) error
+	// DisableTOTP turns 2FA off, clears the secret and the backup codes.
+	DisableTOTP(ctx context.Context, id uint) error
+	UnusedBackupCodes(ctx context.Context, userID uint) ([]model.BackupCode, error)
+	// UseBackupCode marks a code used, returning false if it already was.
+	UseBackupCode(ctx context.Context, id uint) (bool, error)
+
+	// StartMFALogin makes jti the user's pending MFA login with no attempts
+	// spent, replacing any earlier one.
+	StartMFALogin(ctx context.Context, id uint, jti string) error
+	// ReserveMFAAttempt spends one of maxAttempts code attempts on the
+	// pending login jti. It returns false if jti is not pending or has no
+	// attempts left.
+	ReserveMFAAttempt(ctx context.Context, id uint, jti string, maxAttempts int) (bool, error)
+	// FinishMFALogin clears the pending login jti, returning false if it
+	// was not pending, so a token completes at most one login.
+	FinishMFALogin(ctx context.Context, id uint, jti string) (bool, error)
+}
+
+// FXRepository holds exchange rates and the conversions booked with them.
+type FXRepository interface {
+	// CreateKurs returns ErrDuplicate if the currency already has a rate
+	// taking effect at the same BerlakuMulai.
+	CreateKurs(ctx context.Context, kurs *model.Kurs) error
+	// KursAt returns the rate of mataUang in effect at t: the one with the
+	// latest BerlakuMulai not after t.
+	KursAt(ctx context.Context, mataUang string, t time.Time) (model.Kurs, error)
+	// ListKursAt returns the rate in effect at t of every currency that has
+	// one, ordered by MataUang.
+	ListKursAt(ctx context.Context, t time.Time) ([]model.Kurs, error)
+	// CreateKonversi returns ErrDuplicate if the debit already has one.
+	CreateKonversi(ctx context.Context, konversi *model.KonversiValas) error
+}
+
+// GLRepository posts transactions to the general ledger.
+type GLRepository interface {
+	// AturanPosting returns the posting rule of a Transaksi Jenis.
+	AturanPosting(ctx context.Context, jenis string) (model.AturanPosting, error)
+	// CreateJurnal returns ErrDuplicate if the transaction is already
+	// posted.
+	CreateJurnal(ctx context.Context, jurnal *model.JurnalGL) error
+}
+
+// CalendarRepository reads the business date.
+type CalendarRepository interface {
+	// TanggalBisnis returns the business date. Inside WithinTx it holds a
+	// shared lock until the transaction ends, so the end-of-day cut-off
+	// waits for transactions still booking on the old date while bookings
+	// never wait on each other.
+	TanggalBisnis(ctx context.Context) (model.TanggalBisnis, error)
+}
+
+// Outbox records events for delivery after the surrounding transaction
+// commits.
+type Outbox interface {
+	Enqueue(ctx context.Context, event, noRekening string, data any) error
+}
@@ -0,0 +1,531 @@
+// Package repositorytest is a conformance suite every repository.Store
+// implementation must pass, so the in-memory store can stand in for
+// Postgres in handler tests.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"gobanking/model"
+	"gobanking/repository"
+	"testing"
+	"time"
+)
+
+// Run exercises store semantics. newStore must return an empty store each
+// time it is called, e.g. repository.NewMemoryStore or a GormStore over a
+// freshly migrated database.
+func Run(t *testing.T, newStore func(t *testing.T) repository.Store) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s repository.Store)
+	}{
+		{"NasabahCreateAndFind", nasabahCreateAndFind},
+		{"NasabahUniqueConstraints", nasabahUniqueConstraints},
+		{"NasabahNotFound", nasabahNotFound},
+		{"NasabahNonNegativeBalance", nasabahNonNegativeBalance},
+		{"NasabahOverdraft", nasabahOverdraft},
+		{"NasabahUpdateNoHP", nasabahUpdateNoHP},
+		{"PenerimaUniquePair", penerimaUniquePair},
+		{"TransaksiUniqueReferensi", transaksiUniqueReferensi},
+		{"TransaksiReversedOnce", transaksiReversedOnce},
+		{"TransactionCommit", transactionCommit},
+		{"TransactionRollback", transactionRollback},
+		{"UserUniqueEmail", userUniqueEmail},
+		{"UserTwoFactor", userTwoFactor},
+		{"UserMFALogin", userMFALogin},
+		{"KursInEffect", kursInEffect},
+		{"KonversiUniqueDebit", konversiUniqueDebit},
+		{"JurnalUniqueTransaksi", jurnalUniqueTransaksi},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStore(t))
+		})
+	}
+}
+
+func newNasabah(suffix string) *model.Nasabah {
+	return &model.Nasabah{
+		Nama:       "Nasabah " + suffix,
+		NIK:        "nik-" + suffix,
+		NoHP:       "08" + suffix,
+		NoRekening: "rek-" + suffix,
+	}
+}
+
+func mustCreate(t *testing.T, s repository.Store, n *model.Nasabah) {
+	t.Helper()
+	if err := s.Nasabah().Create(context.Background(), n); err != nil {
+		t.Fatalf("Create(%s): %v", n.NoRekening, err)
+	}
+}
+
+func nasabahCreateAndFind(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	n := newNasabah("1")
+	mustCreate(t, s, n)
+	if n.ID == 0 {
+		t.Fatal("Create did not assign an ID")
+	}
+
+	got, err := s.Nasabah().FindByNoRekening(ctx, n.NoRekening)
+	if err != nil {
+		t.Fatalf("FindByNoRekening: %v", err)
+	}
+	if got.ID != n.ID || got.NIK != n.NIK || got.Saldo != 0 {
+		t.Fatalf("FindByNoRekening = %+v, want %+v", got, n)
+	}
+	if got.Bahasa != "id" {
+		t.Fatalf("Bahasa = %q, want default %q", got.Bahasa, "id")
+	}
+	if got.MataUang != model.MataUangIDR {
+		t.Fatalf("MataUang = %q, want default %q", got.MataUang, model.MataUangIDR)
+	}
+
+	exists, err := s.Nasabah().ExistsByNIKOrNoHP(ctx, "other", n.NoHP)
+	if err != nil || !exists {
+		t.Fatalf("ExistsByNIKOrNoHP by NoHP = %v, %v; want true", exists, err)
+	}
+	exists, err = s.Nasabah().ExistsByNIKOrNoHP(ctx, "other", "other")
+	if err != nil || exists {
+		t.Fatalf("ExistsByNIKOrNoHP unknown = %v, %v; want false", exists, err)
+	}
+}
+
+func nasabahUniqueConstraints(t *testing.T, s repository.Store) {
+	mustCreate(t, s, newNasabah("1"))
+
+	for name, n := range map[string]*model.Nasabah{
+		"nik":         {Nama: "x", NIK: "nik-1", NoHP: "08x1", NoRekening: "rek-x1"},
+		"no_hp":       {Nama: "x", NIK: "nik-x2", NoHP: "081", NoRekening: "rek-x2"},
+		"no_rekening": {Nama: "x", NIK: "nik-x3", NoHP: "08x3", NoRekening: "rek-1"},
+	} {
+		if err := s.Nasabah().Create(context.Background(), n); !errors.Is(err, repository.ErrDuplicate) {
+			t.Errorf("duplicate %s: err = %v, want ErrDuplicate", name, err)
+		}
+	}
+}
+
+func nasabahNotFound(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	if _, err := s.Nasabah().FindByNoRekening(ctx, "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("FindByNoRekening: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Nasabah().UpdateNoHP(ctx, "missing", "0899"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("UpdateNoHP: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Nasabah().UpdateSaldo(ctx, 999999, 10); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("UpdateSaldo: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Users().FindByEmail(ctx, "missing@example.com"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("FindByEmail: err = %v, want ErrNotFound", err)
+	}
+}
+
+func nasabahNonNegativeBalance(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	n := newNasabah("1")
+	mustCreate(t, s, n)
+
+	if err := s.Nasabah().UpdateSaldo(ctx, n.ID, 100); err != nil {
+		t.Fatalf("UpdateSaldo(100): %v", err)
+	}
+	if err := s.Nasabah().UpdateSaldo(ctx, n.ID, -1); !errors.Is(err, repository.ErrNegativeBalance) {
+		t.Fatalf("UpdateSaldo(-1): err = %v, want ErrNegativeBalance", err)
+	}
+	got, _ := s.Nasabah().FindByNoRekening(ctx, n.NoRekening)
+	if got.Saldo != 100 {
+		t.Fatalf("Saldo = %v after rejected update, want 100", got.Saldo)
+	}
+}
+
+func nasabahOverdraft(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	n := newNasabah("1")
+	mustCreate(t, s, n)
+
+	if err := s.Nasabah().Overdraw(ctx, n.ID, -40); err != nil {
+		t.Fatalf("Overdraw(-40): %v", err)
+	}
+	got, _ := s.Nasabah().FindByNoRekening(ctx, n.NoRekening)
+	if got.Saldo != -40 || got.OverdraftAt == nil {
+		t.Fatalf("after Overdraw saldo = %v, overdraft_at = %v; want -40 and set", got.Saldo, got.OverdraftAt)
+	}
+	// An overdrawn account may stay negative until it is paid back.
+	if err := s.Nasabah().UpdateSaldo(ctx, n.ID, -10); err != nil {
+		t.Fatalf("UpdateSaldo(-10) while overdrawn: %v", err)
+	}
+	if err := s.Nasabah().UpdateSaldo(ctx, n.ID, 5); err != nil {
+		t.Fatalf("UpdateSaldo(5): %v", err)
+	}
+	got, _ = s.Nasabah().FindByNoRekening(ctx, n.NoRekening)
+	if got.Saldo != 5 || got.OverdraftAt != nil {
+		t.Fatalf("after repayment saldo = %v, overdraft_at = %v; want 5 and cleared", got.Saldo, got.OverdraftAt)
+	}
+	if err := s.Nasabah().UpdateSaldo(ctx, n.ID, -1); !errors.Is(err, repository.ErrNegativeBalance) {
+		t.Fatalf("UpdateSaldo(-1) after repayment: err = %v, want ErrNegativeBalance", err)
+	}
+}
+
+func nasabahUpdateNoHP(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	a, b := newNasabah("1"), newNasabah("2")
+	mustCreate(t, s, a)
+	mustCreate(t, s, b)
+
+	if used, err := s.Nasabah().NoHPUsedByOther(ctx, b.NoHP, a.NoRekening); err != nil || !used {
+		t.Fatalf("NoHPUsedByOther = %v, %v; want true", used, err)
+	}
+	if used, err := s.Nasabah().NoHPUsedByOther(ctx, a.NoHP, a.NoRekening); err != nil || used {
+		t.Fatalf("NoHPUsedByOther own number = %v, %v; want false", used, err)
+	}
+	if err := s.Nasabah().UpdateNoHP(ctx, a.NoRekening, b.NoHP); !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("UpdateNoHP to taken number: err = %v, want ErrDuplicate", err)
+	}
+	if err := s.Nasabah().UpdateNoHP(ctx, a.NoRekening, "0899"); err != nil {
+		t.Fatalf("UpdateNoHP: %v", err)
+	}
+	got, _ := s.Nasabah().FindByNoRekening(ctx, a.NoRekening)
+	if got.NoHP != "0899" {
+		t.Fatalf("NoHP = %q, want 0899", got.NoHP)
+	}
+}
+
+func penerimaUniquePair(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	p := &model.Penerima{NoRekening: "rek-1", NoRekeningTujuan: "rek-2", Alias: "Ibu"}
+	if err := s.Nasabah().CreatePenerima(ctx, p); err != nil {
+		t.Fatalf("CreatePenerima: %v", err)
+	}
+	dup := &model.Penerima{NoRekening: "rek-1", NoRekeningTujuan: "rek-2"}
+	if err := s.Nasabah().CreatePenerima(ctx, dup); !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("duplicate CreatePenerima: err = %v, want ErrDuplicate", err)
+	}
+	if err := s.Nasabah().CreatePenerima(ctx, &model.Penerima{NoRekening: "rek-1", NoRekeningTujuan: "rek-3"}); err != nil {
+		t.Fatalf("CreatePenerima second: %v", err)
+	}
+
+	list, err := s.Nasabah().ListPenerima(ctx, "rek-1")
+	if err != nil || len(list) != 2 || list[0].NoRekeningTujuan != "rek-2" {
+		t.Fatalf("ListPenerima = %+v, %v; want rek-2 then rek-3", list, err)
+	}
+	empty, err := s.Nasabah().ListPenerima(ctx, "rek-9")
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Fatalf("ListPenerima unknown = %#v, %v; want empty non-nil slice", empty, err)
+	}
+}
+
+func transaksiUniqueReferensi(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	a, b := newNasabah("1"), newNasabah("2")
+	mustCreate(t, s, a)
+	mustCreate(t, s, b)
+
+	create := func(n *model.Nasabah, referensi string) error {
+		return s.Nasabah().CreateTransaksi(ctx, &model.Transaksi{
+			NasabahID: n.ID, NoRekening: n.NoRekening, Jenis: model.JenisTabung, Nominal: 1, SaldoAkhir: 1, Referensi: referensi,
+		})
+	}
+	if err := create(a, "ref-1"); err != nil {
+		t.Fatalf("CreateTransaksi: %v", err)
+	}
+	if err := create(a, "ref-1"); !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("duplicate Referensi: err = %v, want ErrDuplicate", err)
+	}
+	// The key is per account, and an empty key is never a duplicate.
+	if err := create(b, "ref-1"); err != nil {
+		t.Fatalf("same Referensi on another account: %v", err)
+	}
+	if err := create(a, ""); err != nil {
+		t.Fatalf("empty Referensi: %v", err)
+	}
+	if err := create(a, ""); err != nil {
+		t.Fatalf("second empty Referensi: %v", err)
+	}
+
+	got, err := s.Nasabah().FindTransaksiByReferensi(ctx, a.NoRekening, "ref-1")
+	if err != nil || got.NasabahID != a.ID {
+		t.Fatalf("FindTransaksiByReferensi = %+v, %v; want the first transaksi", got, err)
+	}
+	if _, err := s.Nasabah().FindTransaksiByReferensi(ctx, a.NoRekening, ""); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("FindTransaksiByReferensi empty: err = %v, want ErrNotFound", err)
+	}
+}
+
+func transaksiReversedOnce(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	n := newNasabah("1")
+	mustCreate(t, s, n)
+
+	original := &model.Transaksi{NasabahID: n.ID, NoRekening: n.NoRekening, Jenis: model.JenisTabung, Nominal: 10, SaldoAkhir: 10}
+	if err := s.Nasabah().CreateTransaksi(ctx, original); err != nil {
+		t.Fatalf("CreateTransaksi: %v", err)
+	}
+	got, err := s.Nasabah().FindTransaksiByID(ctx, original.ID)
+	if err != nil || got.Nominal != 10 {
+		t.Fatalf("FindTransaksiByID = %+v, %v", got, err)
+	}
+	if _, err := s.Nasabah().FindReversalOf(ctx, original.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("FindReversalOf before reversal: err = %v, want ErrNotFound", err)
+	}
+
+	koreksi := func() *model.Transaksi {
+		return &model.Transaksi{
+			NasabahID: n.ID, NoRekening: n.NoRekening, Jenis: model.JenisKoreksiDebit, Nominal: 10, ReversalOfID: &original.ID,
+		}
+	}
+	first := koreksi()
+	if err := s.Nasabah().CreateTransaksi(ctx, first); err != nil {
+		t.Fatalf("CreateTransaksi koreksi: %v", err)
+	}
+	if err := s.Nasabah().CreateTransaksi(ctx, koreksi()); !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("second koreksi: err = %v, want ErrDuplicate", err)
+	}
+	if got, err := s.Nasabah().FindReversalOf(ctx, original.ID); err != nil || got.ID != first.ID {
+		t.Fatalf("FindReversalOf = %+v, %v; want the first koreksi", got, err)
+	}
+}
+
+func transactionCommit(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	n := newNasabah("1")
+	mustCreate(t, s, n)
+
+	err := s.WithinTx(ctx, func(tx repository.Store) error {
+		locked, err := tx.Nasabah().LockByNoRekening(ctx, n.NoRekening)
+		if err != nil {
+			return err
+		}
+		if err := tx.Nasabah().UpdateSaldo(ctx, locked.ID, locked.Saldo+50); err != nil {
+			return err
+		}
+		return tx.Nasabah().CreateTransaksi(ctx, &model.Transaksi{
+			NasabahID: locked.ID, NoRekening: locked.NoRekening, Jenis: model.JenisTabung, Nominal: 50, SaldoAkhir: 50,
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithinTx: %v", err)
+	}
+
+	got, _ := s.Nasabah().FindByNoRekening(ctx, n.NoRekening)
+	if got.Saldo != 50 {
+		t.Fatalf("Saldo = %v after commit, want 50", got.Saldo)
+	}
+}
+
+func transactionRollback(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	n := newNasabah("1")
+	mustCreate(t, s, n)
+
+	errBoom := errors.New("boom")
+	err := s.WithinTx(ctx, func(tx repository.Store) error {
+		if err := tx.Nasabah().UpdateSaldo(ctx, n.ID, 75); err != nil {
+			return err
+		}
+		if err := tx.Nasabah().Create(ctx, newNasabah("2")); err != nil {
+			return err
+		}
+		if err := tx.Outbox().Enqueue(ctx, "transaksi.tabung", n.NoRekening, nil); err != nil {
+			return err
+		}
+		// Nested calls join the outer transaction.
+		return tx.WithinTx(ctx, func(tx repository.Store) error { return errBoom })
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("WithinTx: err = %v, want %v", err, errBoom)
+	}
+
+	got, _ := s.Nasabah().FindByNoRekening(ctx, n.NoRekening)
+	if got.Saldo != 0 {
+		t.Fatalf("Saldo = %v after rollback, want 0", got.Saldo)
+	}
+	if _, err := s.Nasabah().FindByNoRekening(ctx, "rek-2"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("nasabah created in rolled-back transaction: err = %v, want ErrNotFound", err)
+	}
+	if mem, ok := s.(*repository.MemoryStore); ok && len(mem.Events()) != 0 {
+		t.Fatalf("outbox kept %d events after rollback", len(mem.Events()))
+	}
+}
+
+func userUniqueEmail(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	u := &model.User{Email: "a@example.com", Password: "hash"}
+	if err := s.Users().Create(ctx, u); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if u.ID == 0 {
+		t.Fatal("Create did not assign an ID")
+	}
+	if err := s.Users().Create(ctx, &model.User{Email: "a@example.com", Password: "x"}); !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("duplicate email: err = %v, want ErrDuplicate", err)
+	}
+
+	got, err := s.Users().FindByEmail(ctx, "a@example.com")
+	if err != nil || got.ID != u.ID || got.Role != model.RoleUser {
+		t.Fatalf("FindByEmail = %+v, %v; want id %d with role user", got, err, u.ID)
+	}
+	if _, err := s.Users().FindByID(ctx, u.ID); err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+}
+
+func userTwoFactor(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	u := &model.User{Email: "a@example.com", Password: "hash"}
+	if err := s.Users().Create(ctx, u); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := s.Users().SetTOTPSecret(ctx, u.ID, "SECRET"); err != nil {
+		t.Fatalf("SetTOTPSecret: %v", err)
+	}
+	if err := s.Users().EnableTOTP(ctx, u.ID, []string{"h1", "h2"}); err != nil {
+		t.Fatalf("EnableTOTP: %v", err)
+	}
+	got, _ := s.Users().FindByID(ctx, u.ID)
+	if !got.TOTPEnabled || got.TOTPSecret != "SECRET" {
+		t.Fatalf("after EnableTOTP user = %+v", got)
+	}
+
+	codes, err := s.Users().UnusedBackupCodes(ctx, u.ID)
+	if err != nil || len(codes) != 2 {
+		t.Fatalf("UnusedBackupCodes = %d, %v; want 2", len(codes), err)
+	}
+	if used, err := s.Users().UseBackupCode(ctx, codes[0].ID); err != nil || !used {
+		t.Fatalf("UseBackupCode = %v, %v; want true", used, err)
+	}
+	if used, err := s.Users().UseBackupCode(ctx, codes[0].ID); err != nil || used {
+		t.Fatalf("UseBackupCode again = %v, %v; want false", used, err)
+	}
+	if codes, _ := s.Users().UnusedBackupCodes(ctx, u.ID); len(codes) != 1 {
+		t.Fatalf("UnusedBackupCodes after use = %d, want 1", len(codes))
+	}
+
+	if err := s.Users().DisableTOTP(ctx, u.ID); err != nil {
+		t.Fatalf("DisableTOTP: %v", err)
+	}
+	got, _ = s.Users().FindByID(ctx, u.ID)
+	if got.TOTPEnabled || got.TOTPSecret != "" {
+		t.Fatalf("after DisableTOTP user = %+v", got)
+	}
+	if codes, _ := s.Users().UnusedBackupCodes(ctx, u.ID); len(codes) != 0 {
+		t.Fatalf("UnusedBackupCodes after disable = %d, want 0", len(codes))
+	}
+}
+
+func userMFALogin(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	u := &model.User{Email: "a@example.com", Password: "hash"}
+	if err := s.Users().Create(ctx, u); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if ok, err := s.Users().ReserveMFAAttempt(ctx, u.ID, "", 3); err != nil || ok {
+		t.Fatalf("ReserveMFAAttempt without login = %v, %v; want false", ok, err)
+	}
+	if err := s.Users().StartMFALogin(ctx, u.ID, "jti-1"); err != nil {
+		t.Fatalf("StartMFALogin: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if ok, err := s.Users().ReserveMFAAttempt(ctx, u.ID, "jti-1", 2); err != nil || !ok {
+			t.Fatalf("ReserveMFAAttempt %d = %v, %v; want true", i+1, ok, err)
+		}
+	}
+	if ok, _ := s.Users().ReserveMFAAttempt(ctx, u.ID, "jti-1", 2); ok {
+		t.Fatal("ReserveMFAAttempt past the limit = true, want false")
+	}
+
+	// A new login replaces the old token and resets the attempts.
+	if err := s.Users().StartMFALogin(ctx, u.ID, "jti-2"); err != nil {
+		t.Fatalf("StartMFALogin: %v", err)
+	}
+	if ok, _ := s.Users().ReserveMFAAttempt(ctx, u.ID, "jti-1", 2); ok {
+		t.Fatal("ReserveMFAAttempt with replaced token = true, want false")
+	}
+	if ok, _ := s.Users().ReserveMFAAttempt(ctx, u.ID, "jti-2", 2); !ok {
+		t.Fatal("ReserveMFAAttempt with new token = false, want true")
+	}
+	if ok, err := s.Users().FinishMFALogin(ctx, u.ID, "jti-2"); err != nil || !ok {
+		t.Fatalf("FinishMFALogin = %v, %v; want true", ok, err)
+	}
+	if ok, _ := s.Users().FinishMFALogin(ctx, u.ID, "jti-2"); ok {
+		t.Fatal("FinishMFALogin twice = true, want false")
+	}
+	if ok, _ := s.Users().ReserveMFAAttempt(ctx, u.ID, "jti-2", 2); ok {
+		t.Fatal("ReserveMFAAttempt after finish = true, want false")
+	}
+}
+
+func kursInEffect(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, k := range []model.Kurs{
+		{MataUang: "USD", Beli: 15000, Jual: 15100, BerlakuMulai: t0, Sumber: model.KursSumberCSV},
+		{MataUang: "USD", Beli: 15200, Jual: 15300, BerlakuMulai: t0.Add(time.Hour), Sumber: model.KursSumberAPI},
+		{MataUang: "SGD", Beli: 11000, Jual: 11100, BerlakuMulai: t0.Add(2 * time.Hour), Sumber: model.KursSumberCSV},
+	} {
+		if err := s.FX().CreateKurs(ctx, &k); err != nil {
+			t.Fatalf("CreateKurs(%s): %v", k.MataUang, err)
+		}
+	}
+	dup := model.Kurs{MataUang: "USD", Beli: 1, Jual: 1, BerlakuMulai: t0, Sumber: model.KursSumberAPI}
+	if err := s.FX().CreateKurs(ctx, &dup); !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("duplicate rate: err = %v, want ErrDuplicate", err)
+	}
+
+	got, err := s.FX().KursAt(ctx, "USD", t0.Add(30*time.Minute))
+	if err != nil || got.Beli != 15000 {
+		t.Fatalf("KursAt(USD, +30m) = %+v, %v; want beli 15000", got, err)
+	}
+	// A rate takes effect at BerlakuMulai inclusive.
+	if got, err := s.FX().KursAt(ctx, "USD", t0.Add(time.Hour)); err != nil || got.Beli != 15200 {
+		t.Fatalf("KursAt(USD, +1h) = %+v, %v; want beli 15200", got, err)
+	}
+	if _, err := s.FX().KursAt(ctx, "USD", t0.Add(-time.Second)); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("KursAt before the first rate: err = %v, want ErrNotFound", err)
+	}
+
+	rates, err := s.FX().ListKursAt(ctx, t0.Add(90*time.Minute))
+	if err != nil || len(rates) != 1 || rates[0].MataUang != "USD" || rates[0].Beli != 15200 {
+		t.Fatalf("ListKursAt(+90m) = %+v, %v; want only USD at 15200", rates, err)
+	}
+	rates, _ = s.FX().ListKursAt(ctx, t0.Add(3*time.Hour))
+	if len(rates) != 2 || rates[0].MataUang != "SGD" || rates[1].MataUang != "USD" {
+		t.Fatalf("ListKursAt(+3h) = %+v; want SGD and USD in that order", rates)
+	}
+}
+
+func konversiUniqueDebit(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	k := &model.KonversiValas{TransaksiDebitID: 1, TransaksiKreditID: 2, MataUangAsal: "USD", NominalAsal: 10, MataUangTujuan: "IDR", NominalTujuan: 150000, Kurs: 15000}
+	if err := s.FX().CreateKonversi(ctx, k); err != nil || k.ID == 0 {
+		t.Fatalf("CreateKonversi = %v, id %d", err, k.ID)
+	}
+	again := &model.KonversiValas{TransaksiDebitID: 1, TransaksiKreditID: 3, MataUangAsal: "USD", NominalAsal: 10, MataUangTujuan: "IDR", NominalTujuan: 150000, Kurs: 15000}
+	if err := s.FX().CreateKonversi(ctx, again); !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("second conversion for one debit: err = %v, want ErrDuplicate", err)
+	}
+}
+
+func jurnalUniqueTransaksi(t *testing.T, s repository.Store) {
+	ctx := context.Background()
+	if _, err := s.GL().AturanPosting(ctx, "tidak-ada"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("AturanPosting unknown: err = %v, want ErrNotFound", err)
+	}
+
+	jurnal := func() *model.JurnalGL {
+		return &model.JurnalGL{
+			TransaksiID: 1, Jenis: model.JenisTabung, AkunDebit: model.AkunKas, AkunKredit: model.AkunSimpananNasabah,
+			Nominal: 10, MataUang: model.MataUangIDR, Tanggal: time.Now(),
+		}
+	}
+	first := jurnal()
+	if err := s.GL().CreateJurnal(ctx, first); err != nil || first.ID == 0 {
+		t.Fatalf("CreateJurnal = %v, id %d", err, first.ID)
+	}
+	if err := s.GL().CreateJurnal(ctx, jurnal()); !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("second jurnal for one transaksi: err = %v, want ErrDuplicate", err)
+	}
+}
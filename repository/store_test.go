@@ -0,0 +1,24 @@
+package repository_test
+
+import (
+	"gobanking/database/databasetest"
+	"gobanking/repository"
+	"gobanking/repository/repositorytest"
+	"os"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repository.Store { return repository.NewMemoryStore() })
+}
+
+// TestGormStore runs the same suite against Postgres when TEST_DATABASE_URL
+// is set, each case on its own freshly migrated schema.
+func TestGormStore(t *testing.T) {
+	if os.Getenv(databasetest.EnvURL) == "" {
+		t.Skipf("%s tidak diisi, test Postgres dilewati", databasetest.EnvURL)
+	}
+	repositorytest.Run(t, func(t *testing.T) repository.Store {
+		return repository.NewGormStore(databasetest.Open(t), nil)
+	})
+}
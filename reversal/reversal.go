@@ -0,0 +1,230 @@
+// Package reversal handles requests to undo a posted tabung or tarik. Small
+// reversals are booked straight away; those above
+// REVERSAL_APPROVAL_THRESHOLD, or that may overdraw the account, wait for a
+// second admin. Every request is kept as a model.Reversal, linked to the
+// original entry and to the koreksi entry that undid it.
+package reversal
+
+import (
+	"context"
+	"errors"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/metrics"
+	"gobanking/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReversalNotFound = errors.New("permintaan koreksi tidak ditemukan")
+	ErrPendingExists    = errors.New("transaksi sudah memiliki permintaan koreksi yang menunggu persetujuan")
+	ErrNotPending       = errors.New("permintaan koreksi sudah diputuskan")
+	ErrSelfApproval     = errors.New("permintaan koreksi harus disetujui oleh admin lain")
+)
+
+type Service struct {
+	db       *gorm.DB
+	cfg      *config.Config
+	accounts *account.Service
+}
+
+func NewService(db *gorm.DB, cfg *config.Config, accounts *account.Service) *Service {
+	return &Service{db: db, cfg: cfg, accounts: accounts}
+}
+
+// Request records a reversal of req.TransaksiID by userID. It is posted at
+// once unless it needs approval, in which case it is returned pending.
+//
+// A reversal that is posted at once and fails on a rule (for example
+// account.ErrInsufficientFunds) is stored as rejected and the rule's error
+// returned with it.
+func (s *Service) Request(ctx context.Context, userID uint, req model.ReversalRequest) (model.Reversal, error) {
+	original, err := s.accounts.Transaksi(ctx, req.TransaksiID)
+	if err != nil {
+		return model.Reversal{}, err
+	}
+	if original.Jenis != model.JenisTabung && original.Jenis != model.JenisTarik {
+		return model.Reversal{}, account.ErrNotReversible
+	}
+	var reversed int64
+	err = s.db.WithContext(ctx).Model(&model.Transaksi{}).Where("reversal_of_id = ?", original.ID).Count(&reversed).Error
+	if err != nil {
+		return model.Reversal{}, err
+	}
+	if reversed > 0 {
+		return model.Reversal{}, account.ErrAlreadyReversed
+	}
+
+	approval := req.AllowOverdraft
+	if !approval {
+		approval, err = s.accounts.ExceedsIDR(ctx, original.MataUang, original.Nominal, s.cfg.Reversal.ApprovalThreshold)
+		if err != nil {
+			return model.Reversal{}, err
+		}
+	}
+
+	reversal := model.Reversal{
+		TransaksiID:    original.ID,
+		NoRekening:     original.NoRekening,
+		Jenis:          original.Jenis,
+		Nominal:        original.Nominal,
+		MataUang:       original.MataUang,
+		Alasan:         req.Alasan,
+		AllowOverdraft: req.AllowOverdraft,
+		Status:         model.ReversalPending,
+		RequestedBy:    userID,
+	}
+	// The request is stored pending first even when it is posted at once,
+	// so a crash in between leaves a record that Approve can settle.
+	if err := s.db.WithContext(ctx).Create(&reversal).Error; err != nil {
+		var pending int64
+		s.db.WithContext(ctx).Model(&model.Reversal{}).
+			Where("transaksi_id = ? AND status = ?", original.ID, model.ReversalPending).
+			Count(&pending)
+		if pending > 0 {
+			return model.Reversal{}, ErrPendingExists
+		}
+		return model.Reversal{}, err
+	}
+	if approval {
+		metrics.ReversalsTotal.WithLabelValues(model.ReversalPending).Inc()
+		return reversal, nil
+	}
+	return s.execute(ctx, reversal, nil)
+}
+
+// Approve posts a pending reversal. The approver must be someone other
+// than the requester. A rule violation at posting time rejects the request
+// and is returned with it.
+func (s *Service) Approve(ctx context.Context, approverID, id uint) (model.Reversal, error) {
+	reversal, err := s.pending(ctx, id)
+	if err != nil {
+		return reversal, err
+	}
+	if reversal.RequestedBy == approverID {
+		return reversal, ErrSelfApproval
+	}
+	return s.execute(ctx, reversal, &approverID)
+}
+
+// Reject closes a pending reversal without touching the ledger. alasan is
+// kept in Error.
+func (s *Service) Reject(ctx context.Context, userID, id uint, alasan string) (model.Reversal, error) {
+	reversal, err := s.pending(ctx, id)
+	if err != nil {
+		return reversal, err
+	}
+	if alasan == "" {
+		alasan = "ditolak"
+	}
+	if err := s.decide(ctx, &reversal, model.ReversalRejected, &userID, alasan); err != nil {
+		return reversal, err
+	}
+	return reversal, nil
+}
+
+// List returns the latest reversals, newest first, optionally only those
+// with status.
+func (s *Service) List(ctx context.Context, status string) ([]model.Reversal, error) {
+	query := s.db.WithContext(ctx).Order("id DESC").Limit(100)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var reversals []model.Reversal
+	err := query.Find(&reversals).Error
+	return reversals, err
+}
+
+func (s *Service) Get(ctx context.Context, id uint) (model.Reversal, error) {
+	var reversal model.Reversal
+	err := s.db.WithContext(ctx).First(&reversal, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return reversal, ErrReversalNotFound
+	}
+	return reversal, err
+}
+
+func (s *Service) pending(ctx context.Context, id uint) (model.Reversal, error) {
+	reversal, err := s.Get(ctx, id)
+	if err != nil {
+		return reversal, err
+	}
+	if reversal.Status != model.ReversalPending {
+		return reversal, ErrNotPending
+	}
+	return reversal, nil
+}
+
+// execute books the koreksi entry for a pending reversal and records the
+// outcome. Errors other than the account rules leave it pending so it can
+// be approved again.
+func (s *Service) execute(ctx context.Context, reversal model.Reversal, approverID *uint) (model.Reversal, error) {
+	result, err := s.accounts.Reverse(ctx, account.ReverseInput{
+		TransaksiID:    reversal.TransaksiID,
+		Alasan:         reversal.Alasan,
+		AllowOverdraft: reversal.AllowOverdraft,
+	})
+	switch {
+	case err == nil:
+		reversal.KoreksiID, reversal.Overdraft = &result.Koreksi.ID, result.Overdraft
+		return reversal, s.decide(ctx, &reversal, model.ReversalCompleted, approverID, "")
+
+	case errors.Is(err, account.ErrAlreadyReversed) && result.Koreksi.ID != 0:
+		// Either an earlier attempt of this request posted the koreksi and
+		// failed to record it, or another request got there first.
+		var owners int64
+		dbErr := s.db.WithContext(ctx).Model(&model.Reversal{}).
+			Where("koreksi_id = ? AND id <> ?", result.Koreksi.ID, reversal.ID).
+			Count(&owners).Error
+		if dbErr != nil {
+			return reversal, dbErr
+		}
+		if owners > 0 {
+			if dbErr := s.decide(ctx, &reversal, model.ReversalRejected, approverID, err.Error()); dbErr != nil {
+				return reversal, dbErr
+			}
+			return reversal, err
+		}
+		reversal.KoreksiID = &result.Koreksi.ID
+		reversal.Overdraft = result.Koreksi.SaldoAkhir < 0
+		return reversal, s.decide(ctx, &reversal, model.ReversalCompleted, approverID, "")
+
+	case errors.Is(err, account.ErrInsufficientFunds),
+		errors.Is(err, account.ErrAlreadyReversed),
+		errors.Is(err, account.ErrAccountNotFound),
+		errors.Is(err, account.ErrTransaksiNotFound),
+		errors.Is(err, account.ErrNotReversible):
+		if dbErr := s.decide(ctx, &reversal, model.ReversalRejected, approverID, err.Error()); dbErr != nil {
+			return reversal, dbErr
+		}
+		return reversal, err
+	}
+	return reversal, err
+}
+
+// decide moves a pending reversal to status. A request decided
+// concurrently by someone else is reported as ErrNotPending.
+func (s *Service) decide(ctx context.Context, reversal *model.Reversal, status string, by *uint, reason string) error {
+	now := time.Now()
+	result := s.db.WithContext(ctx).Model(&model.Reversal{}).
+		Where("id = ? AND status = ?", reversal.ID, model.ReversalPending).
+		Updates(map[string]any{
+			"status":     status,
+			"decided_by": by,
+			"decided_at": now,
+			"koreksi_id": reversal.KoreksiID,
+			"overdraft":  reversal.Overdraft,
+			"error":      reason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotPending
+	}
+	reversal.Status, reversal.DecidedBy, reversal.DecidedAt, reversal.Error = status, by, &now, reason
+	metrics.ReversalsTotal.WithLabelValues(status).Inc()
+	return nil
+}
@@ -0,0 +1,200 @@
+package reversal_test
+
+import (
+	"context"
+	"errors"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/database/databasetest"
+	"gobanking/model"
+	"gobanking/notifier"
+	"gobanking/repository"
+	"gobanking/reversal"
+	"io"
+	"log/slog"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+const (
+	requester uint = 1
+	approver  uint = 2
+)
+
+type fixture struct {
+	db       *gorm.DB
+	accounts *account.Service
+	service  *reversal.Service
+}
+
+// newFixture posts reversals above 500000 IDR, or that may overdraw, only
+// after approval.
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	db := databasetest.Open(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{
+		Logger:   logger,
+		Reversal: config.ReversalConfig{ApprovalThreshold: 500000},
+	}
+	accounts := account.NewService(repository.NewGormStore(db, nil), notifier.LogNotifier{Logger: logger})
+	return fixture{db: db, accounts: accounts, service: reversal.NewService(db, cfg, accounts)}
+}
+
+// deposit opens an account and returns it with the tabung entry that
+// funded it.
+func (f fixture) deposit(t *testing.T, nominal float64) (model.Nasabah, model.Transaksi) {
+	t.Helper()
+	ctx := context.Background()
+	n, err := f.accounts.OpenAccount(ctx, account.OpenAccountInput{Nama: "Budi", NIK: "nik-1", NoHP: "081"})
+	if err != nil {
+		t.Fatalf("OpenAccount: %v", err)
+	}
+	if n, err = f.accounts.Deposit(ctx, n.NoRekening, nominal, ""); err != nil {
+		t.Fatalf("Deposit: %v", err)
+	}
+	var tabung model.Transaksi
+	if err := f.db.Where("no_rekening = ? AND jenis = ?", n.NoRekening, model.JenisTabung).Last(&tabung).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n, tabung
+}
+
+func (f fixture) saldo(t *testing.T, noRekening string) float64 {
+	t.Helper()
+	saldo, err := f.accounts.Balance(context.Background(), noRekening)
+	if err != nil {
+		t.Fatalf("Balance: %v", err)
+	}
+	return saldo
+}
+
+func TestSmallReversalIsPostedAtOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	n, tabung := f.deposit(t, 100000)
+
+	r, err := f.service.Request(ctx, requester, model.ReversalRequest{TransaksiID: tabung.ID, Alasan: "salah setor"})
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if r.Status != model.ReversalCompleted || r.KoreksiID == nil || r.DecidedBy != nil {
+		t.Fatalf("reversal = %+v, want completed without an approver", r)
+	}
+	var koreksi model.Transaksi
+	f.db.First(&koreksi, *r.KoreksiID)
+	if koreksi.Jenis != model.JenisKoreksiDebit || koreksi.ReversalOfID == nil || *koreksi.ReversalOfID != tabung.ID {
+		t.Fatalf("koreksi = %+v, want a koreksi_debit of %d", koreksi, tabung.ID)
+	}
+	if got := f.saldo(t, n.NoRekening); got != 0 {
+		t.Fatalf("saldo = %v, want 0", got)
+	}
+
+	if _, err := f.service.Request(ctx, requester, model.ReversalRequest{TransaksiID: tabung.ID, Alasan: "lagi"}); !errors.Is(err, account.ErrAlreadyReversed) {
+		t.Fatalf("second Request: err = %v, want ErrAlreadyReversed", err)
+	}
+	if _, err := f.service.Request(ctx, requester, model.ReversalRequest{TransaksiID: koreksi.ID, Alasan: "koreksi"}); !errors.Is(err, account.ErrNotReversible) {
+		t.Fatalf("Request for the koreksi itself: err = %v, want ErrNotReversible", err)
+	}
+}
+
+func TestLargeReversalNeedsAnotherAdmin(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	n, tabung := f.deposit(t, 1000000)
+
+	r, err := f.service.Request(ctx, requester, model.ReversalRequest{TransaksiID: tabung.ID, Alasan: "salah setor"})
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+	if r.Status != model.ReversalPending || r.KoreksiID != nil {
+		t.Fatalf("reversal = %+v, want pending above the threshold", r)
+	}
+	if got := f.saldo(t, n.NoRekening); got != 1000000 {
+		t.Fatalf("saldo while pending = %v, want 1000000", got)
+	}
+	if _, err := f.service.Request(ctx, approver, model.ReversalRequest{TransaksiID: tabung.ID, Alasan: "lagi"}); !errors.Is(err, reversal.ErrPendingExists) {
+		t.Fatalf("second Request while pending: err = %v, want ErrPendingExists", err)
+	}
+	if _, err := f.service.Approve(ctx, requester, r.ID); !errors.Is(err, reversal.ErrSelfApproval) {
+		t.Fatalf("Approve by the requester: err = %v, want ErrSelfApproval", err)
+	}
+
+	r, err = f.service.Approve(ctx, approver, r.ID)
+	if err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if r.Status != model.ReversalCompleted || r.KoreksiID == nil || r.DecidedBy == nil || *r.DecidedBy != approver {
+		t.Fatalf("reversal = %+v, want completed by %d", r, approver)
+	}
+	if got := f.saldo(t, n.NoRekening); got != 0 {
+		t.Fatalf("saldo = %v, want 0", got)
+	}
+	if _, err := f.service.Approve(ctx, approver, r.ID); !errors.Is(err, reversal.ErrNotPending) {
+		t.Fatalf("second Approve: err = %v, want ErrNotPending", err)
+	}
+}
+
+// TestSpentDepositReversal reverses a deposit that was mostly withdrawn:
+// posted at once it is rejected, and with allow_overdraft it waits for
+// approval and then overdraws the account.
+func TestSpentDepositReversal(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	n, tabung := f.deposit(t, 100000)
+	if _, err := f.accounts.Withdraw(ctx, n.NoRekening, 80000); err != nil {
+		t.Fatalf("Withdraw: %v", err)
+	}
+
+	r, err := f.service.Request(ctx, requester, model.ReversalRequest{TransaksiID: tabung.ID, Alasan: "salah setor"})
+	if !errors.Is(err, account.ErrInsufficientFunds) || r.Status != model.ReversalRejected {
+		t.Fatalf("Request without overdraft = %s, %v; want rejected with ErrInsufficientFunds", r.Status, err)
+	}
+
+	r, err = f.service.Request(ctx, requester, model.ReversalRequest{TransaksiID: tabung.ID, Alasan: "salah setor", AllowOverdraft: true})
+	if err != nil {
+		t.Fatalf("Request with overdraft: %v", err)
+	}
+	if r.Status != model.ReversalPending {
+		t.Fatalf("reversal = %s, want pending: overdrafts always need approval", r.Status)
+	}
+	r, err = f.service.Approve(ctx, approver, r.ID)
+	if err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if r.Status != model.ReversalCompleted || !r.Overdraft {
+		t.Fatalf("reversal = %+v, want completed with overdraft", r)
+	}
+	got, err := f.accounts.Account(ctx, n.NoRekening)
+	if err != nil {
+		t.Fatalf("Account: %v", err)
+	}
+	if got.Saldo != -80000 || got.OverdraftAt == nil {
+		t.Fatalf("account = saldo %v, overdraft_at %v; want -80000 and set", got.Saldo, got.OverdraftAt)
+	}
+}
+
+func TestRejectLeavesLedgerUntouched(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	n, tabung := f.deposit(t, 1000000)
+	r, err := f.service.Request(ctx, requester, model.ReversalRequest{TransaksiID: tabung.ID, Alasan: "salah setor"})
+	if err != nil {
+		t.Fatalf("Request: %v", err)
+	}
+
+	r, err = f.service.Reject(ctx, approver, r.ID, "setoran sah")
+	if err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if r.Status != model.ReversalRejected || r.Error != "setoran sah" || r.KoreksiID != nil {
+		t.Fatalf("reversal = %+v, want rejected with the reason", r)
+	}
+	if got := f.saldo(t, n.NoRekening); got != 1000000 {
+		t.Fatalf("saldo = %v, want 1000000", got)
+	}
+	if _, err := f.service.Approve(ctx, requester, r.ID); !errors.Is(err, reversal.ErrNotPending) {
+		t.Fatalf("Approve after Reject: err = %v, want ErrNotPending", err)
+	}
+}
@@ -1,32 +1,146 @@
 package router
 
 import (
+	"gobanking/account"
 	"gobanking/config"
+	"gobanking/eod"
+	"gobanking/gl"
 	"gobanking/handler"
+	"gobanking/lockout"
 	"gobanking/middleware"
+	"gobanking/model"
+	"gobanking/notifier"
+	"gobanking/otp"
+	"gobanking/payroll"
+	"gobanking/ratelimit"
+	"gobanking/recon"
+	"gobanking/repository"
+	"gobanking/reversal"
+	"gobanking/schedule"
+	"gobanking/webhook"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
-func Setup(e *echo.Echo, db *gorm.DB, cfg *config.Config) {
+func Setup(e *echo.Echo, db *gorm.DB, cfg *config.Config, store repository.Store, accounts *account.Service, dispatcher *webhook.Dispatcher, n notifier.Notifier, health *handler.HealthHandler, limiter ratelimit.Store) {
+	// Client IPs only come from forwarding headers set by our own proxies
+	e.IPExtractor = middleware.IPExtractor(cfg.Server.TrustedProxies)
+
 	// Setup middleware
 	middleware.SetupMiddleware(e, cfg)
-	
+
+	// Health probes
+	e.GET("/healthz", health.Live)
+	e.GET("/readyz", health.Ready)
+
+	// Rate limits
+	limit := func(name string, p config.RateLimitPolicy, key middleware.RateLimitKey) echo.MiddlewareFunc {
+		return middleware.RateLimit(limiter, ratelimit.Policy{Name: name, Limit: p.Limit, Period: p.Period}, key)
+	}
+
 	// Auth routes
-	authHandler := handler.NewAuthHandler(db, cfg)
-	e.POST("/register", authHandler.Register)
-	e.POST("/login", authHandler.Login)
+	guard := lockout.NewGuard(db, cfg, n)
+	authHandler := handler.NewAuthHandler(store.Users(), cfg, guard)
+	e.POST("/register", authHandler.Register, limit("register", cfg.RateLimit.Auth, middleware.ByIP))
+	e.POST("/login", authHandler.Login, limit("login", cfg.RateLimit.Auth, middleware.ByIP))
+	e.POST("/login/mfa", authHandler.LoginMFA, limit("login_mfa", cfg.RateLimit.Auth, middleware.ByIP))
+
+	// 2FA enrolment also accepts the enrolment token issued by /login
+	twoFactor := e.Group("/2fa")
+	twoFactor.Use(middleware.EnrolmentAuthMiddleware(cfg, store.Users()))
+	twoFactor.POST("/enrol", authHandler.EnrolTwoFactor)
+	twoFactor.POST("/verify", authHandler.VerifyTwoFactor)
+	twoFactor.POST("/disable", authHandler.DisableTwoFactor)
 
 	// Protected routes
-	nasabahHandler := handler.NewNasabahHandler(db, cfg)
-	
+	otpHandler := handler.NewOTPHandler(cfg, otp.NewService(db, cfg, n))
+	nasabahHandler := handler.NewNasabahHandler(accounts, cfg, otpHandler)
+	webhookHandler := handler.NewWebhookHandler(db, cfg, dispatcher)
+	payrollHandler := handler.NewPayrollHandler(payroll.NewService(db, cfg, accounts), accounts, cfg, otpHandler)
+	standingOrderHandler := handler.NewStandingOrderHandler(schedule.NewService(db, cfg, accounts), accounts, cfg, otpHandler)
+	fxHandler := handler.NewFXHandler(accounts)
+	reversalHandler := handler.NewReversalHandler(reversal.NewService(db, cfg, accounts))
+	glHandler := handler.NewGLHandler(gl.NewService(db))
+	eodHandler := handler.NewEODHandler(eod.NewService(db, cfg, accounts))
+	reconHandler := handler.NewReconHandler(recon.NewService(db, cfg))
+
 	// Create a group for protected routes
 	protected := e.Group("")
-	protected.Use(middleware.AuthMiddleware(cfg))
+	protected.Use(middleware.AuthMiddleware(cfg, store.Users()))
+	protected.Use(limit("user", cfg.RateLimit.User, middleware.ByUser))
 
 	protected.POST("/daftar", nasabahHandler.Daftar)
 	protected.POST("/tabung", nasabahHandler.Tabung)
-	protected.POST("/tarik", nasabahHandler.Tarik)
+	protected.POST("/tarik", nasabahHandler.Tarik, limit("tarik", cfg.RateLimit.Tarik, middleware.ByUser))
 	protected.GET("/saldo/:no_rekening", nasabahHandler.Saldo)
+	protected.POST("/penerima", nasabahHandler.TambahPenerima)
+	protected.GET("/penerima/:no_rekening", nasabahHandler.DaftarPenerima)
+	protected.POST("/ubah-no-hp", nasabahHandler.UbahNoHP)
+	protected.POST("/otp/verify", otpHandler.Verify)
+	protected.POST("/payroll", payrollHandler.Create)
+	protected.POST("/payroll/csv", payrollHandler.CreateCSV)
+	protected.GET("/payroll/:id", payrollHandler.Get)
+	protected.GET("/payroll/:id/result.csv", payrollHandler.Result)
+	protected.POST("/standing-orders", standingOrderHandler.Create)
+	protected.GET("/standing-orders", standingOrderHandler.List)
+	protected.GET("/standing-orders/:id", standingOrderHandler.Get)
+	protected.PUT("/standing-orders/:id", standingOrderHandler.Update)
+	protected.DELETE("/standing-orders/:id", standingOrderHandler.Cancel)
+	protected.GET("/standing-orders/:id/runs", standingOrderHandler.Runs)
+	protected.GET("/fx-rates", fxHandler.List)
+	protected.GET("/rekening-koran/:no_rekening/:periode", eodHandler.Statement)
+
+	if cfg.Features.Webhooks {
+		protected.POST("/webhooks", webhookHandler.Create)
+		protected.GET("/webhooks", webhookHandler.List)
+		protected.GET("/webhooks/:id", webhookHandler.Get)
+		protected.PUT("/webhooks/:id", webhookHandler.Update)
+		protected.DELETE("/webhooks/:id", webhookHandler.Delete)
+		protected.GET("/webhooks/:id/deliveries", webhookHandler.Deliveries)
+		protected.POST("/webhooks/:id/deliveries/:delivery_id/replay", webhookHandler.Replay)
+	}
+
+	// Reversals are requested by staff and approved by an admin
+	reversals := protected.Group("/reversals", middleware.RequireRole(model.RoleStaff, model.RoleAdmin))
+	reversals.POST("", reversalHandler.Create)
+	reversals.GET("", reversalHandler.List)
+	reversals.GET("/:id", reversalHandler.Get)
+	reversals.POST("/:id/approve", reversalHandler.Approve, middleware.RequireRole(model.RoleAdmin))
+	reversals.POST("/:id/reject", reversalHandler.Reject, middleware.RequireRole(model.RoleAdmin))
+
+	// Settlement files are reconciled by operations staff
+	reconciliations := protected.Group("/reconciliations", middleware.RequireRole(model.RoleStaff, model.RoleAdmin))
+	reconciliations.POST("", reconHandler.Import)
+	reconciliations.GET("", reconHandler.List)
+	reconciliations.GET("/:id", reconHandler.Get)
+	reconciliations.GET("/:id/items", reconHandler.Items)
+	reconciliations.GET("/:id/exceptions.csv", reconHandler.Exceptions)
+	reconciliations.POST("/:id/items/:item_id/resolve", reconHandler.Resolve)
+
+	// Admin routes
+	adminHandler := handler.NewAdminHandler(cfg, guard)
+	admin := protected.Group("/admin", middleware.RequireRole(model.RoleAdmin))
+	admin.POST("/lockouts/unlock", adminHandler.Unlock)
+	admin.POST("/fx-rates", fxHandler.Load)
+	admin.GET("/gl/accounts", glHandler.Accounts)
+	admin.POST("/gl/accounts", glHandler.CreateAccount)
+	admin.GET("/gl/posting-rules", glHandler.Rules)
+	admin.PUT("/gl/posting-rules/:jenis", glHandler.SetRule)
+	admin.GET("/gl/trial-balance", glHandler.TrialBalance)
+	admin.GET("/gl/trial-balance.csv", glHandler.TrialBalanceCSV)
+	admin.GET("/gl/journal.csv", glHandler.JournalCSV)
+	admin.GET("/eod", eodHandler.TanggalBisnis)
+	admin.POST("/eod/run", eodHandler.Run)
+	admin.GET("/eod/runs", eodHandler.Runs)
+	admin.GET("/eod/runs/:id", eodHandler.Get)
+}
+
+// RateLimitStore returns the bucket store RATE_LIMIT_STORE selects. Build it
+// once and hand it to Setup and the gRPC server so they share buckets.
+func RateLimitStore(db *gorm.DB, cfg *config.Config) ratelimit.Store {
+	if cfg.RateLimit.Store == "postgres" {
+		return ratelimit.NewPostgresStore(db)
+	}
+	return ratelimit.NewMemoryStore()
 }
@@ -0,0 +1,287 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/metrics"
+	"gobanking/model"
+	"gobanking/notifier"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// claimLease is how long a claimed order stays invisible to other replicas
+// while its transfer runs.
+const claimLease = 5 * time.Minute
+
+// Executor carries out due standing orders. Each occurrence is transferred
+// with a reference derived from the order and its scheduled time, so an
+// attempt interrupted by a crash, or picked up twice, never pays twice.
+type Executor struct {
+	db       *gorm.DB
+	cfg      *config.Config
+	accounts *account.Service
+	loc      *time.Location
+}
+
+func NewExecutor(db *gorm.DB, cfg *config.Config, accounts *account.Service) *Executor {
+	return &Executor{db: db, cfg: cfg, accounts: accounts, loc: location(cfg)}
+}
+
+// Run polls for due orders until ctx is cancelled.
+func (e *Executor) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.cfg.Schedule.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.ProcessDue(ctx); err != nil {
+			e.cfg.Logger.Error("gagal menjalankan standing order", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue executes orders until none is due. An order whose attempt
+// fails for an infrastructure reason keeps its lease and is retried once
+// it expires.
+func (e *Executor) ProcessDue(ctx context.Context) error {
+	for ctx.Err() == nil {
+		order, ok, err := e.claim()
+		if err != nil || !ok {
+			return err
+		}
+		if err := e.execute(ctx, order); err != nil {
+			e.cfg.Logger.Error("gagal menjalankan standing order, dicoba lagi nanti",
+				"standing_order_id", order.ID,
+				"retry_after", claimLease.String(),
+				"error", err,
+			)
+		}
+	}
+	return nil
+}
+
+// claim locks the most overdue active order no other replica is working on
+// and leases it by moving its next attempt past the lease.
+func (e *Executor) claim() (model.StandingOrder, bool, error) {
+	var order model.StandingOrder
+	found := false
+	err := e.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.StandingOrderActive, now).
+			Order("next_attempt_at").
+			Limit(1).
+			Find(&order)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		found = true
+		return tx.Model(&order).Update("next_attempt_at", now.Add(claimLease)).Error
+	})
+	return order, found && order.NextRunAt != nil, err
+}
+
+// execute transfers the pending occurrence of order and records the run.
+func (e *Executor) execute(ctx context.Context, order model.StandingOrder) error {
+	scheduled := *order.NextRunAt
+	// Orders stored before beneficiaries were required are held to the
+	// same rule when they come due.
+	var result account.TransferResult
+	err := e.accounts.CheckRegisteredBeneficiary(ctx, order.NoRekening, order.NoRekeningTujuan)
+	if err == nil {
+		result, err = e.accounts.Transfer(ctx, account.TransferInput{
+			Sumber:    order.NoRekening,
+			Tujuan:    order.NoRekeningTujuan,
+			Nominal:   order.Nominal,
+			Referensi: referensi(order.ID, scheduled),
+		})
+	}
+
+	now := time.Now()
+	run := model.StandingOrderRun{
+		StandingOrderID: order.ID,
+		ScheduledAt:     scheduled,
+		Attempt:         order.Attempts + 1,
+		Status:          model.StandingOrderRunSuccess,
+	}
+	var updates map[string]any
+	switch {
+	case err == nil, errors.Is(err, account.ErrDuplicateReference):
+		// A duplicate reference means an earlier attempt already paid this
+		// occurrence before its outcome was recorded.
+		if result.Debit.ID != 0 {
+			run.TransaksiID = &result.Debit.ID
+		}
+		updates = e.advance(order, scheduled, now, model.StandingOrderRunSuccess)
+	case errors.Is(err, account.ErrInsufficientFunds),
+		errors.Is(err, account.ErrAccountFrozen),
+		errors.Is(err, account.ErrRateNotFound):
+		run.Error = err.Error()
+		if run.Attempt <= e.cfg.Schedule.RetryDays {
+			run.Status = model.StandingOrderRunRetry
+			updates = map[string]any{
+				"attempts":        run.Attempt,
+				"next_attempt_at": now.Add(e.cfg.Schedule.RetryInterval),
+			}
+		} else {
+			run.Status = model.StandingOrderRunFailed
+			updates = e.advance(order, scheduled, now, model.StandingOrderRunFailed)
+		}
+	case errors.Is(err, account.ErrAccountNotFound),
+		errors.Is(err, account.ErrBeneficiaryNotFound),
+		errors.Is(err, account.ErrNotBeneficiary),
+		errors.Is(err, account.ErrInvalidAmount),
+		errors.Is(err, account.ErrSameAccount):
+		// The instruction itself can no longer be carried out.
+		run.Status, run.Error = model.StandingOrderRunFailed, err.Error()
+		updates = map[string]any{
+			"status":          model.StandingOrderFailed,
+			"attempts":        run.Attempt,
+			"next_run_at":     nil,
+			"next_attempt_at": nil,
+		}
+	case ctx.Err() != nil:
+		return err
+	default:
+		return fmt.Errorf("jadwal %s: %w", scheduled.Format(time.RFC3339), err)
+	}
+
+	if err := e.record(order, &run, updates); err != nil {
+		return err
+	}
+	metrics.StandingOrderRunsTotal.WithLabelValues(run.Status).Inc()
+	e.cfg.Logger.Info("standing order dijalankan",
+		"standing_order_id", order.ID,
+		"scheduled_at", scheduled,
+		"attempt", run.Attempt,
+		"status", run.Status,
+		"error", run.Error,
+	)
+	if run.Status == model.StandingOrderRunFailed {
+		e.notifyFailure(ctx, order, scheduled, err)
+	}
+	return nil
+}
+
+// advance moves order past scheduled. Occurrences that already passed while
+// scheduled was being retried, or while no executor was running, are
+// skipped rather than paid in a burst. The order ends when no occurrence is
+// left, as failed if its last run failed.
+func (e *Executor) advance(order model.StandingOrder, scheduled, now time.Time, status string) map[string]any {
+	r, err := newRule(order, e.loc)
+	if err != nil {
+		// Rules are validated when stored; treat a broken one as finished.
+		e.cfg.Logger.Error("aturan standing order tidak valid", "standing_order_id", order.ID, "error", err)
+	}
+
+	next, ok := time.Time{}, false
+	if err == nil {
+		next, ok = r.next(scheduled)
+		if ok && next.Before(now) {
+			skipped := next
+			next, ok = r.next(now)
+			e.cfg.Logger.Warn("jadwal standing order terlewat dilompati",
+				"standing_order_id", order.ID,
+				"from", skipped,
+				"next_run_at", next,
+			)
+		}
+	}
+
+	if !ok {
+		final := model.StandingOrderCompleted
+		if status == model.StandingOrderRunFailed {
+			final = model.StandingOrderFailed
+		}
+		return map[string]any{"status": final, "attempts": 0, "next_run_at": nil, "next_attempt_at": nil}
+	}
+	return map[string]any{"attempts": 0, "next_run_at": next, "next_attempt_at": next}
+}
+
+// record stores run and applies updates in one transaction. If the order
+// was cancelled or rescheduled while the transfer ran, only the run is
+// stored: the customer's edit wins.
+func (e *Executor) record(order model.StandingOrder, run *model.StandingOrderRun, updates map[string]any) error {
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+
+		var current model.StandingOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, order.ID).Error; err != nil {
+			return err
+		}
+		if current.Status != model.StandingOrderActive || current.NextRunAt == nil ||
+			!current.NextRunAt.Equal(*order.NextRunAt) {
+			e.cfg.Logger.Warn("standing order berubah selama eksekusi, jadwal tidak dimajukan",
+				"standing_order_id", order.ID,
+				"status", current.Status,
+			)
+			return nil
+		}
+		return tx.Model(&current).Updates(updates).Error
+	})
+}
+
+// notifyFailure tells the source account holder that an occurrence was
+// given up.
+func (e *Executor) notifyFailure(ctx context.Context, order model.StandingOrder, scheduled time.Time, cause error) {
+	nasabah, err := e.accounts.Account(ctx, order.NoRekening)
+	if err != nil {
+		e.cfg.Logger.Warn("gagal mengirim notifikasi standing order", "standing_order_id", order.ID, "error", err)
+		return
+	}
+	e.accounts.NotifyCustomer(ctx, nasabah, notifier.TemplateStandingOrderGagal, map[string]any{
+		"Nominal":          order.Nominal,
+		"NoRekeningTujuan": account.MaskNoRekening(order.NoRekeningTujuan),
+		"Jadwal":           scheduled.In(e.loc).Format("02-01-2006 15:04"),
+		"Alasan":           reason(cause, nasabah.Bahasa),
+	})
+}
+
+// reason explains cause to the customer in lang.
+func reason(cause error, lang string) string {
+	en := lang == notifier.LangEN
+	switch {
+	case errors.Is(cause, account.ErrInsufficientFunds):
+		if en {
+			return "insufficient balance"
+		}
+		return "saldo tidak mencukupi"
+	case errors.Is(cause, account.ErrAccountFrozen):
+		if en {
+			return "an account is frozen"
+		}
+		return "rekening dibekukan"
+	case errors.Is(cause, account.ErrBeneficiaryNotFound):
+		if en {
+			return "destination account not found"
+		}
+		return "rekening tujuan tidak ditemukan"
+	case errors.Is(cause, account.ErrRateNotFound):
+		if en {
+			return "no exchange rate was available"
+		}
+		return "kurs tidak tersedia"
+	}
+	if en {
+		return "the instruction can no longer be carried out"
+	}
+	return "instruksi tidak dapat dijalankan lagi"
+}
+
+// referensi is the idempotency key of one occurrence's transfer.
+func referensi(orderID uint, scheduled time.Time) string {
+	return fmt.Sprintf("so-%d-%d", orderID, scheduled.Unix())
+}
@@ -0,0 +1,91 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"gobanking/model"
+	"time"
+)
+
+var ErrInvalidSchedule = errors.New("jadwal tidak valid")
+
+// rule yields the occurrences of a standing order in the configured zone.
+type rule struct {
+	schedule string
+	day      int
+	cron     cronExpr
+	start    time.Time
+	end      *time.Time
+	loc      *time.Location
+}
+
+func newRule(order model.StandingOrder, loc *time.Location) (rule, error) {
+	r := rule{schedule: order.Schedule, start: order.StartAt, end: order.EndAt, loc: loc}
+	switch order.Schedule {
+	case model.ScheduleOnce:
+	case model.ScheduleMonthly:
+		if order.DayOfMonth < 1 || order.DayOfMonth > 31 {
+			return rule{}, fmt.Errorf("%w: day_of_month harus antara 1 dan 31", ErrInvalidSchedule)
+		}
+		r.day = order.DayOfMonth
+	case model.ScheduleCron:
+		expr, err := parseCron(order.Cron)
+		if err != nil {
+			return rule{}, fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+		if !single(expr.minute) || !single(expr.hour) {
+			return rule{}, fmt.Errorf("%w: menit dan jam cron harus satu nilai", ErrInvalidSchedule)
+		}
+		r.cron = expr
+	default:
+		return rule{}, fmt.Errorf("%w: schedule %q tidak dikenal", ErrInvalidSchedule, order.Schedule)
+	}
+	return r, nil
+}
+
+// next returns the first occurrence strictly after t, or false when the
+// order has none left.
+func (r rule) next(t time.Time) (time.Time, bool) {
+	if t.Before(r.start) {
+		// Occurrences start at StartAt inclusive.
+		t = r.start.Add(-time.Nanosecond)
+	}
+
+	var (
+		at time.Time
+		ok bool
+	)
+	switch r.schedule {
+	case model.ScheduleOnce:
+		at, ok = r.start, r.start.After(t)
+	case model.ScheduleMonthly:
+		at, ok = r.nextMonthly(t.In(r.loc)), true
+	case model.ScheduleCron:
+		at, ok = r.cron.next(t.In(r.loc))
+	}
+	if !ok || (r.end != nil && at.After(*r.end)) {
+		return time.Time{}, false
+	}
+	return at, true
+}
+
+// nextMonthly runs at midnight on the order's day, or on the last day of
+// months that are shorter.
+func (r rule) nextMonthly(t time.Time) time.Time {
+	year, month, _ := t.Date()
+	for {
+		day := min(r.day, daysIn(year, month))
+		at := time.Date(year, month, day, 0, 0, 0, 0, r.loc)
+		if at.After(t) {
+			return at
+		}
+		month++
+		if month > time.December {
+			year, month = year+1, time.January
+		}
+	}
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,167 @@
+package schedule_test
+
+import (
+	"context"
+	"errors"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/database/databasetest"
+	"gobanking/model"
+	"gobanking/notifier"
+	"gobanking/repository"
+	"gobanking/schedule"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{
+		Logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Schedule: config.ScheduleConfig{
+			TimeZone:      "Asia/Jakarta",
+			RetryDays:     3,
+			RetryInterval: time.Hour,
+		},
+	}
+}
+
+func openAccount(t *testing.T, s *account.Service, suffix string, saldo float64) model.Nasabah {
+	t.Helper()
+	ctx := context.Background()
+	n, err := s.OpenAccount(ctx, account.OpenAccountInput{
+		Nama: "Nasabah " + suffix,
+		NIK:  "nik-" + suffix,
+		NoHP: "08" + suffix,
+	})
+	if err != nil {
+		t.Fatalf("OpenAccount(%s): %v", suffix, err)
+	}
+	if saldo > 0 {
+		if n, err = s.Deposit(ctx, n.NoRekening, saldo, ""); err != nil {
+			t.Fatalf("Deposit(%s): %v", suffix, err)
+		}
+	}
+	return n
+}
+
+func TestCheckRequiresSavedBeneficiary(t *testing.T) {
+	ctx := context.Background()
+	cfg := testConfig()
+	accounts := account.NewService(repository.NewMemoryStore(), notifier.LogNotifier{Logger: cfg.Logger})
+	// Check never touches the orders table, so no database is needed.
+	orders := schedule.NewService(nil, cfg, accounts)
+	sumber := openAccount(t, accounts, "1", 0)
+	tujuan := openAccount(t, accounts, "2", 0)
+	req := model.StandingOrderRequest{
+		NoRekening:       sumber.NoRekening,
+		NoRekeningTujuan: tujuan.NoRekening,
+		Nominal:          50000,
+		Schedule:         model.ScheduleMonthly,
+		DayOfMonth:       25,
+	}
+
+	if err := orders.Check(ctx, req); !errors.Is(err, account.ErrNotBeneficiary) {
+		t.Fatalf("Check before AddBeneficiary: err = %v, want ErrNotBeneficiary", err)
+	}
+	if _, err := accounts.AddBeneficiary(ctx, sumber.NoRekening, tujuan.NoRekening, "gaji"); err != nil {
+		t.Fatalf("AddBeneficiary: %v", err)
+	}
+	if err := orders.Check(ctx, req); err != nil {
+		t.Fatalf("Check after AddBeneficiary: %v", err)
+	}
+
+	missing := req
+	missing.NoRekeningTujuan = "tidak-ada"
+	if err := orders.Check(ctx, missing); !errors.Is(err, account.ErrBeneficiaryNotFound) {
+		t.Fatalf("Check with unknown destination: err = %v, want ErrBeneficiaryNotFound", err)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	once := req
+	once.Schedule, once.DayOfMonth, once.StartAt = model.ScheduleOnce, 0, &past
+	if err := orders.Check(ctx, once); !errors.Is(err, schedule.ErrInvalidSchedule) {
+		t.Fatalf("Check with a past one-off: err = %v, want ErrInvalidSchedule", err)
+	}
+}
+
+// dueOrder stores an active order due now directly, as one saved before
+// beneficiaries were required would be.
+func dueOrder(t *testing.T, db *gorm.DB, sumber, tujuan string, nominal float64) model.StandingOrder {
+	t.Helper()
+	now := time.Now().Add(-time.Minute)
+	order := model.StandingOrder{
+		UserID:           1,
+		NoRekening:       sumber,
+		NoRekeningTujuan: tujuan,
+		Nominal:          nominal,
+		Schedule:         model.ScheduleOnce,
+		StartAt:          now,
+		Status:           model.StandingOrderActive,
+		NextRunAt:        &now,
+		NextAttemptAt:    &now,
+		MataUang:         "IDR",
+	}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("Create order: %v", err)
+	}
+	return order
+}
+
+func TestExecutorRefusesUnsavedBeneficiary(t *testing.T) {
+	ctx := context.Background()
+	db := databasetest.Open(t)
+	cfg := testConfig()
+	accounts := account.NewService(repository.NewGormStore(db, nil), notifier.LogNotifier{Logger: cfg.Logger})
+	sumber := openAccount(t, accounts, "1", 100000)
+	tujuan := openAccount(t, accounts, "2", 0)
+	order := dueOrder(t, db, sumber.NoRekening, tujuan.NoRekening, 50000)
+
+	if err := schedule.NewExecutor(db, cfg, accounts).ProcessDue(ctx); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+
+	var got model.StandingOrder
+	db.First(&got, order.ID)
+	if got.Status != model.StandingOrderFailed || got.NextAttemptAt != nil {
+		t.Fatalf("order status = %s, next attempt %v; want failed and no next attempt", got.Status, got.NextAttemptAt)
+	}
+	var runs []model.StandingOrderRun
+	db.Where("standing_order_id = ?", order.ID).Find(&runs)
+	if len(runs) != 1 || runs[0].Status != model.StandingOrderRunFailed ||
+		runs[0].Error != account.ErrNotBeneficiary.Error() || runs[0].TransaksiID != nil {
+		t.Fatalf("runs = %+v, want one failed run refused for the beneficiary", runs)
+	}
+	if saldo, _ := accounts.Balance(ctx, sumber.NoRekening); saldo != 100000 {
+		t.Fatalf("source saldo = %v, want 100000 untouched", saldo)
+	}
+}
+
+func TestExecutorPaysSavedBeneficiary(t *testing.T) {
+	ctx := context.Background()
+	db := databasetest.Open(t)
+	cfg := testConfig()
+	accounts := account.NewService(repository.NewGormStore(db, nil), notifier.LogNotifier{Logger: cfg.Logger})
+	sumber := openAccount(t, accounts, "1", 100000)
+	tujuan := openAccount(t, accounts, "2", 0)
+	if _, err := accounts.AddBeneficiary(ctx, sumber.NoRekening, tujuan.NoRekening, "sewa"); err != nil {
+		t.Fatalf("AddBeneficiary: %v", err)
+	}
+	order := dueOrder(t, db, sumber.NoRekening, tujuan.NoRekening, 50000)
+
+	if err := schedule.NewExecutor(db, cfg, accounts).ProcessDue(ctx); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+
+	var runs []model.StandingOrderRun
+	db.Where("standing_order_id = ?", order.ID).Find(&runs)
+	if len(runs) != 1 || runs[0].Status != model.StandingOrderRunSuccess || runs[0].TransaksiID == nil {
+		t.Fatalf("runs = %+v, want one successful run with its transaksi", runs)
+	}
+	if saldo, _ := accounts.Balance(ctx, tujuan.NoRekening); saldo != 50000 {
+		t.Fatalf("destination saldo = %v, want 50000", saldo)
+	}
+}
@@ -0,0 +1,205 @@
+// Package schedule runs standing orders: transfers repeated monthly or on a
+// cron rule, and one-off transfers dated in the future. Service manages the
+// instructions; Executor carries them out and records every attempt as a
+// model.StandingOrderRun.
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gobanking/account"
+	"gobanking/config"
+	"gobanking/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrOrderNotFound = errors.New("standing order tidak ditemukan")
+	ErrOrderClosed   = errors.New("standing order sudah tidak aktif")
+)
+
+type Service struct {
+	db       *gorm.DB
+	cfg      *config.Config
+	accounts *account.Service
+	loc      *time.Location
+}
+
+func NewService(db *gorm.DB, cfg *config.Config, accounts *account.Service) *Service {
+	return &Service{db: db, cfg: cfg, accounts: accounts, loc: location(cfg)}
+}
+
+// location returns SCHEDULE_TIMEZONE, which config validation has already
+// checked.
+func location(cfg *config.Config) *time.Location {
+	loc, err := time.LoadLocation(cfg.Schedule.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Create stores a new active order. Both accounts must exist, the source
+// must not be frozen and the destination must be a saved beneficiary of the
+// source; the schedule must have at least one occurrence.
+func (s *Service) Create(ctx context.Context, userID uint, req model.StandingOrderRequest) (model.StandingOrder, error) {
+	order := model.StandingOrder{UserID: userID, Status: model.StandingOrderActive}
+	if err := s.apply(ctx, &order, req); err != nil {
+		return model.StandingOrder{}, err
+	}
+	if err := s.db.WithContext(ctx).Create(&order).Error; err != nil {
+		return model.StandingOrder{}, err
+	}
+	return order, nil
+}
+
+// Update replaces the instruction of an active order and reschedules it.
+// Failed attempts of the pending occurrence are forgotten.
+func (s *Service) Update(ctx context.Context, userID, id uint, req model.StandingOrderRequest) (model.StandingOrder, error) {
+	var order model.StandingOrder
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if order, err = lockOrder(tx, userID, id); err != nil {
+			return err
+		}
+		if order.Status != model.StandingOrderActive {
+			return ErrOrderClosed
+		}
+		if err := s.apply(ctx, &order, req); err != nil {
+			return err
+		}
+		return tx.Save(&order).Error
+	})
+	return order, err
+}
+
+// Cancel stops an active order. An attempt already in flight still
+// finishes and is recorded.
+func (s *Service) Cancel(ctx context.Context, userID, id uint) (model.StandingOrder, error) {
+	var order model.StandingOrder
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if order, err = lockOrder(tx, userID, id); err != nil {
+			return err
+		}
+		if order.Status != model.StandingOrderActive {
+			return ErrOrderClosed
+		}
+		order.Status, order.NextRunAt, order.NextAttemptAt = model.StandingOrderCancelled, nil, nil
+		return tx.Model(&order).Updates(map[string]any{
+			"status":          order.Status,
+			"next_run_at":     nil,
+			"next_attempt_at": nil,
+		}).Error
+	})
+	return order, err
+}
+
+// List returns every order of userID, oldest first.
+func (s *Service) List(ctx context.Context, userID uint) ([]model.StandingOrder, error) {
+	var orders []model.StandingOrder
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&orders).Error
+	return orders, err
+}
+
+// Order returns an order owned by userID.
+func (s *Service) Order(ctx context.Context, userID, id uint) (model.StandingOrder, error) {
+	var order model.StandingOrder
+	err := s.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&order).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return order, ErrOrderNotFound
+	}
+	return order, err
+}
+
+// Check validates req as Create and Update would without storing anything,
+// so a request held back for an OTP is refused before the code is sent.
+func (s *Service) Check(ctx context.Context, req model.StandingOrderRequest) error {
+	var order model.StandingOrder
+	return s.apply(ctx, &order, req)
+}
+
+// Runs returns the latest execution attempts of an order, newest first.
+func (s *Service) Runs(ctx context.Context, orderID uint) ([]model.StandingOrderRun, error) {
+	var runs []model.StandingOrderRun
+	err := s.db.WithContext(ctx).Where("standing_order_id = ?", orderID).
+		Order("id DESC").
+		Limit(100).
+		Find(&runs).Error
+	return runs, err
+}
+
+// apply validates req and copies it onto order together with its first
+// occurrence.
+func (s *Service) apply(ctx context.Context, order *model.StandingOrder, req model.StandingOrderRequest) error {
+	source, err := s.accounts.Account(ctx, req.NoRekening)
+	if err != nil {
+		return err
+	}
+	if source.FrozenAt != nil {
+		return account.ErrAccountFrozen
+	}
+	if _, err := s.accounts.Account(ctx, req.NoRekeningTujuan); err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) {
+			return account.ErrBeneficiaryNotFound
+		}
+		return err
+	}
+	if err := s.accounts.CheckRegisteredBeneficiary(ctx, req.NoRekening, req.NoRekeningTujuan); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	order.NoRekening = req.NoRekening
+	order.NoRekeningTujuan = req.NoRekeningTujuan
+	order.Nominal = req.Nominal
+	order.MataUang = source.MataUang
+	order.Keterangan = req.Keterangan
+	order.Schedule = req.Schedule
+	order.DayOfMonth, order.Cron, order.EndAt = 0, "", req.EndAt
+	order.StartAt = now
+	if req.StartAt != nil {
+		order.StartAt = *req.StartAt
+	}
+
+	switch req.Schedule {
+	case model.ScheduleOnce:
+		if req.StartAt == nil || !req.StartAt.After(now) {
+			return fmt.Errorf("%w: start_at harus di masa depan", ErrInvalidSchedule)
+		}
+		order.EndAt = nil
+	case model.ScheduleMonthly:
+		order.DayOfMonth = req.DayOfMonth
+	case model.ScheduleCron:
+		order.Cron = req.Cron
+	}
+	if order.EndAt != nil && !order.EndAt.After(order.StartAt) {
+		return fmt.Errorf("%w: end_at harus setelah start_at", ErrInvalidSchedule)
+	}
+
+	r, err := newRule(*order, s.loc)
+	if err != nil {
+		return err
+	}
+	next, ok := r.next(now)
+	if !ok {
+		return fmt.Errorf("%w: tidak ada jadwal eksekusi sebelum end_at", ErrInvalidSchedule)
+	}
+	order.NextRunAt, order.NextAttemptAt, order.Attempts = &next, &next, 0
+	return nil
+}
+
+func lockOrder(tx *gorm.DB, userID, id uint) (model.StandingOrder, error) {
+	var order model.StandingOrder
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&order).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return order, ErrOrderNotFound
+	}
+	return order, err
+}
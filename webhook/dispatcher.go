@@ -0,0 +1,296 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gobanking/config"
+	"gobanking/model"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxBackoff caps the exponential retry delay.
+const maxBackoff = 6 * time.Hour
+
+// claimMargin is added to WEBHOOK_TIMEOUT per delivery of a claimed batch
+// for the database round trips around each send.
+const claimMargin = 5 * time.Second
+
+type Envelope struct {
+	ID        uint      `json:"id"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type Dispatcher struct {
+	db     *gorm.DB
+	cfg    *config.Config
+	client *http.Client
+}
+
+func NewDispatcher(db *gorm.DB, cfg *config.Config) *Dispatcher {
+	client := &http.Client{Timeout: cfg.Webhook.Timeout}
+	if !cfg.Webhook.AllowPrivateTargets {
+		client.Transport = guardedTransport()
+	}
+	return &Dispatcher{
+		db:     db,
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+// Enqueue records a delivery for every active subscription matching the
+// event and account. It must be called with the transaction that moves the
+// money so that an event is stored if and only if the mutation commits.
+// Subscriptions to every account only count while their owner is an admin.
+func (d *Dispatcher) Enqueue(tx *gorm.DB, event, noRekening string, data any) error {
+	if !d.cfg.Features.Webhooks {
+		return nil
+	}
+
+	var subs []model.WebhookSubscription
+	err := tx.Where("aktif = ? AND (no_rekening = ? OR (no_rekening = '' AND user_id IN (SELECT id FROM users WHERE role = ? AND deleted_at IS NULL)))",
+		true, noRekening, model.RoleAdmin).
+		Find(&subs).Error
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, sub := range subs {
+		if !subscribed(sub, event) {
+			continue
+		}
+
+		delivery := model.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			Event:          event,
+			Payload:        "{}",
+			Status:         model.WebhookStatusPending,
+			NextAttemptAt:  now,
+		}
+		if err := tx.Create(&delivery).Error; err != nil {
+			return err
+		}
+
+		payload, err := json.Marshal(Envelope{
+			ID:        delivery.ID,
+			Event:     event,
+			CreatedAt: now,
+			Data:      data,
+		})
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&delivery).Update("payload", string(payload)).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Replay queues a fresh copy of an earlier delivery. The original row is
+// kept untouched so the delivery log stays complete.
+func (d *Dispatcher) Replay(original model.WebhookDelivery) (model.WebhookDelivery, error) {
+	delivery := model.WebhookDelivery{
+		SubscriptionID: original.SubscriptionID,
+		Event:          original.Event,
+		Payload:        original.Payload,
+		Status:         model.WebhookStatusPending,
+		NextAttemptAt:  time.Now(),
+		ReplayOf:       &original.ID,
+	}
+	err := d.db.Create(&delivery).Error
+	return delivery, err
+}
+
+// Run polls for due deliveries until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.Webhook.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.ProcessDue(ctx); err != nil {
+			d.cfg.Logger.Error("gagal memproses webhook", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue sends every delivery whose next attempt is due and returns once
+// the batch has been handled.
+func (d *Dispatcher) ProcessDue(ctx context.Context) error {
+	deliveries, err := d.claim(50)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return nil
+		}
+		d.attempt(ctx, delivery)
+	}
+	return nil
+}
+
+// claim locks a batch of due deliveries and pushes their next attempt into
+// the future so that other replicas skip them while they are in flight.
+// The batch is sent in order, so the i-th delivery may wait for i sends to
+// time out before its own does; its lease covers all of them.
+func (d *Dispatcher) claim(limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_attempt_at <= ?",
+				[]string{model.WebhookStatusPending, model.WebhookStatusRetrying}, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		for i, delivery := range deliveries {
+			lease := time.Duration(i+1) * (d.cfg.Webhook.Timeout + claimMargin)
+			err := tx.Model(&model.WebhookDelivery{}).
+				Where("id = ?", delivery.ID).
+				Update("next_attempt_at", now.Add(lease)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return deliveries, err
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, delivery model.WebhookDelivery) {
+	var sub model.WebhookSubscription
+	if err := d.db.First(&sub, delivery.SubscriptionID).Error; err != nil {
+		d.cfg.Logger.Warn("langganan webhook tidak ditemukan, delivery dihentikan",
+			"delivery_id", delivery.ID,
+			"subscription_id", delivery.SubscriptionID,
+		)
+		d.finish(delivery, model.WebhookStatusDead, 0, "langganan tidak ditemukan")
+		return
+	}
+
+	statusCode, err := d.send(ctx, sub, delivery)
+	delivery.Attempts++
+
+	if err == nil {
+		now := time.Now()
+		delivery.DeliveredAt = &now
+		d.finish(delivery, model.WebhookStatusSuccess, statusCode, "")
+		d.cfg.Logger.Info("webhook terkirim",
+			"delivery_id", delivery.ID,
+			"event", delivery.Event,
+			"attempts", delivery.Attempts,
+		)
+		return
+	}
+
+	if delivery.Attempts >= d.cfg.Webhook.MaxAttempts {
+		d.finish(delivery, model.WebhookStatusDead, statusCode, err.Error())
+		d.cfg.Logger.Warn("webhook dipindahkan ke dead-letter",
+			"delivery_id", delivery.ID,
+			"event", delivery.Event,
+			"attempts", delivery.Attempts,
+			"error", err,
+		)
+		return
+	}
+
+	delivery.NextAttemptAt = time.Now().Add(Backoff(d.cfg.Webhook.BaseBackoff, delivery.Attempts))
+	d.finish(delivery, model.WebhookStatusRetrying, statusCode, err.Error())
+	d.cfg.Logger.Info("webhook gagal, dijadwalkan ulang",
+		"delivery_id", delivery.ID,
+		"event", delivery.Event,
+		"attempts", delivery.Attempts,
+		"next_attempt_at", delivery.NextAttemptAt,
+		"error", err,
+	)
+}
+
+func (d *Dispatcher) send(ctx context.Context, sub model.WebhookSubscription, delivery model.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver membalas status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) finish(delivery model.WebhookDelivery, status string, statusCode int, lastError string) {
+	updates := map[string]any{
+		"status":           status,
+		"attempts":         delivery.Attempts,
+		"last_status_code": statusCode,
+		"last_error":       lastError,
+		"delivered_at":     delivery.DeliveredAt,
+		"next_attempt_at":  delivery.NextAttemptAt,
+	}
+	if err := d.db.Model(&model.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(updates).Error; err != nil {
+		d.cfg.Logger.Error("gagal memperbarui status webhook", "delivery_id", delivery.ID, "error", err)
+	}
+}
+
+// Backoff returns the delay before the next attempt: base doubled for every
+// attempt already made, capped at maxBackoff.
+func Backoff(base time.Duration, attempts int) time.Duration {
+	if attempts < 1 {
+		return base
+	}
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
+
+func subscribed(sub model.WebhookSubscription, event string) bool {
+	for _, e := range SplitEvents(sub.Events) {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"gobanking/config"
+	"gobanking/database/databasetest"
+	"gobanking/model"
+	"gobanking/webhook"
+	"gobanking/webhook/webhooktest"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const secret = "rahasia-webhook"
+
+func testConfig(allowPrivate bool) *config.Config {
+	return &config.Config{
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Features: config.FeatureConfig{Webhooks: true},
+		Webhook: config.WebhookConfig{
+			MaxAttempts:         2,
+			Timeout:             5 * time.Second,
+			PollInterval:        time.Second,
+			AllowPrivateTargets: allowPrivate,
+		},
+	}
+}
+
+func subscribe(t *testing.T, db *gorm.DB, userID uint, url, noRekening string) model.WebhookSubscription {
+	t.Helper()
+	sub := model.WebhookSubscription{
+		UserID:     userID,
+		URL:        url,
+		Events:     webhook.JoinEvents([]string{"transaksi.tabung"}),
+		NoRekening: noRekening,
+		Secret:     secret,
+		Aktif:      true,
+	}
+	if err := db.Create(&sub).Error; err != nil {
+		t.Fatalf("Create subscription: %v", err)
+	}
+	return sub
+}
+
+func enqueue(t *testing.T, db *gorm.DB, d *webhook.Dispatcher, noRekening string) {
+	t.Helper()
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return d.Enqueue(tx, "transaksi.tabung", noRekening, map[string]any{"nominal": 50000})
+	})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+}
+
+func deliveries(t *testing.T, db *gorm.DB) []model.WebhookDelivery {
+	t.Helper()
+	var out []model.WebhookDelivery
+	if err := db.Order("id").Find(&out).Error; err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestDispatcherDeliversSignedEvent(t *testing.T) {
+	db := databasetest.Open(t)
+	receiver := webhooktest.NewReceiver(secret)
+	defer receiver.Close()
+	d := webhook.NewDispatcher(db, testConfig(true))
+	subscribe(t, db, 1, receiver.URL(), "1234567890")
+
+	enqueue(t, db, d, "1234567890")
+	enqueue(t, db, d, "0987654321") // another account, not subscribed
+	if err := d.ProcessDue(context.Background()); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+
+	reqs := receiver.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(reqs))
+	}
+	if !reqs[0].SignatureValid || reqs[0].Event != "transaksi.tabung" {
+		t.Fatalf("request = %+v, want a validly signed transaksi.tabung", reqs[0])
+	}
+	var envelope webhook.Envelope
+	if err := json.Unmarshal(reqs[0].Body, &envelope); err != nil {
+		t.Fatalf("body: %v", err)
+	}
+	got := deliveries(t, db)
+	if len(got) != 1 || got[0].Status != model.WebhookStatusSuccess || got[0].DeliveredAt == nil {
+		t.Fatalf("deliveries = %+v, want one successful delivery", got)
+	}
+	if envelope.ID != got[0].ID {
+		t.Fatalf("envelope ID = %d, want delivery %d", envelope.ID, got[0].ID)
+	}
+}
+
+func TestDispatcherRetriesThenDeadLetters(t *testing.T) {
+	db := databasetest.Open(t)
+	receiver := webhooktest.NewReceiver(secret)
+	defer receiver.Close()
+	receiver.FailNext(2)
+	// A zero base backoff makes each retry due immediately.
+	d := webhook.NewDispatcher(db, testConfig(true))
+	subscribe(t, db, 1, receiver.URL(), "1234567890")
+	enqueue(t, db, d, "1234567890")
+
+	ctx := context.Background()
+	if err := d.ProcessDue(ctx); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	if got := deliveries(t, db); got[0].Status != model.WebhookStatusRetrying || got[0].LastStatusCode != 500 {
+		t.Fatalf("after first attempt = %+v, want retrying with 500", got[0])
+	}
+	if err := d.ProcessDue(ctx); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	got := deliveries(t, db)
+	if got[0].Status != model.WebhookStatusDead || got[0].Attempts != 2 {
+		t.Fatalf("after MaxAttempts = %+v, want dead after 2 attempts", got[0])
+	}
+
+	replay, err := d.Replay(got[0])
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if err := d.ProcessDue(ctx); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	var delivered model.WebhookDelivery
+	db.First(&delivered, replay.ID)
+	if delivered.Status != model.WebhookStatusSuccess || delivered.ReplayOf == nil || *delivered.ReplayOf != got[0].ID {
+		t.Fatalf("replay = %+v, want a successful copy of %d", delivered, got[0].ID)
+	}
+	if n := len(receiver.Requests()); n != 3 {
+		t.Fatalf("receiver got %d requests, want 3", n)
+	}
+}
+
+// TestDispatcherAllAccountsNeedsAdmin checks that a subscription without
+// NoRekening only receives events while its owner is an admin.
+func TestDispatcherAllAccountsNeedsAdmin(t *testing.T) {
+	db := databasetest.Open(t)
+	receiver := webhooktest.NewReceiver(secret)
+	defer receiver.Close()
+	d := webhook.NewDispatcher(db, testConfig(true))
+
+	admin := model.User{Email: "admin@example.com", Password: "x", Role: model.RoleAdmin}
+	user := model.User{Email: "user@example.com", Password: "x", Role: model.RoleUser}
+	if err := db.Create(&admin).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	adminSub := subscribe(t, db, admin.ID, receiver.URL(), "")
+	subscribe(t, db, user.ID, receiver.URL(), "")
+
+	enqueue(t, db, d, "1234567890")
+	got := deliveries(t, db)
+	if len(got) != 1 || got[0].SubscriptionID != adminSub.ID {
+		t.Fatalf("deliveries = %+v, want one for the admin's subscription", got)
+	}
+}
+
+// TestDispatcherRefusesPrivateTargets checks that, outside development,
+// a subscription pointing at loopback is never reached.
+func TestDispatcherRefusesPrivateTargets(t *testing.T) {
+	db := databasetest.Open(t)
+	receiver := webhooktest.NewReceiver(secret)
+	defer receiver.Close()
+	d := webhook.NewDispatcher(db, testConfig(false))
+	subscribe(t, db, 1, receiver.URL(), "1234567890")
+	enqueue(t, db, d, "1234567890")
+
+	if err := d.ProcessDue(context.Background()); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	if n := len(receiver.Requests()); n != 0 {
+		t.Fatalf("receiver got %d requests, want none", n)
+	}
+	if got := deliveries(t, db); got[0].Status != model.WebhookStatusRetrying || got[0].LastError == "" {
+		t.Fatalf("delivery = %+v, want a failed attempt", got[0])
+	}
+}
+
+// TestDispatcherLeaseCoversBatch holds the first send of a batch and checks
+// that every claimed delivery stays hidden for as long as the sends before
+// it may take to time out.
+func TestDispatcherLeaseCoversBatch(t *testing.T) {
+	db := databasetest.Open(t)
+	arrived, release := make(chan struct{}, 3), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+		<-release
+	}))
+	defer server.Close()
+	cfg := testConfig(true)
+	cfg.Webhook.Timeout = 30 * time.Second
+	d := webhook.NewDispatcher(db, cfg)
+	subscribe(t, db, 1, server.URL, "1234567890")
+	for i := 0; i < 3; i++ {
+		enqueue(t, db, d, "1234567890")
+	}
+
+	claimed := time.Now()
+	done := make(chan error)
+	go func() { done <- d.ProcessDue(context.Background()) }()
+	<-arrived
+	for i, delivery := range deliveries(t, db) {
+		if want := claimed.Add(time.Duration(i+1) * cfg.Webhook.Timeout); delivery.NextAttemptAt.Before(want) {
+			t.Errorf("delivery %d hidden until %v, want at least %v", i, delivery.NextAttemptAt, want)
+		}
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var ErrTargetNotAllowed = errors.New("URL webhook tidak boleh mengarah ke alamat internal")
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which
+// net/netip does not count as private.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// internal reports whether ip must not receive webhooks: loopback,
+// private, link-local, unspecified, multicast and CGNAT addresses.
+func internal(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip)
+}
+
+// CheckTarget rejects a subscription URL that is not http(s) or whose host
+// is, or resolves to, an internal address. It gives the subscriber an early
+// answer; the dispatcher checks every connection again, since DNS may
+// change after the subscription is saved.
+func CheckTarget(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: skema %q", ErrTargetNotAllowed, u.Scheme)
+	}
+	host := u.Hostname()
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if internal(ip) {
+			return ErrTargetNotAllowed
+		}
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("host %q tidak dapat di-resolve: %w", host, err)
+	}
+	for _, ip := range addrs {
+		if internal(ip) {
+			return ErrTargetNotAllowed
+		}
+	}
+	return nil
+}
+
+// guardedTransport refuses to connect to internal addresses. The check runs
+// on the resolved address of every dial, redirects included. Deliveries
+// connect directly rather than through HTTP_PROXY, whose address would be
+// checked instead of the receiver's.
+func guardedTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip, err := netip.ParseAddr(host)
+			if err != nil || internal(ip) {
+				return ErrTargetNotAllowed
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
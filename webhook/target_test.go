@@ -0,0 +1,36 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"gobanking/webhook"
+	"testing"
+)
+
+func TestCheckTarget(t *testing.T) {
+	blocked := []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://[::1]/hook",
+		"http://10.1.2.3/hook",
+		"http://172.16.0.1/hook",
+		"http://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://100.64.0.1/hook",
+		"http://0.0.0.0/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+		"http://[fe80::1]/hook",
+		"ftp://93.184.216.34/hook",
+	}
+	for _, url := range blocked {
+		if err := webhook.CheckTarget(context.Background(), url); !errors.Is(err, webhook.ErrTargetNotAllowed) {
+			t.Errorf("CheckTarget(%q) = %v, want ErrTargetNotAllowed", url, err)
+		}
+	}
+
+	for _, url := range []string{"https://93.184.216.34/hook", "http://[2606:2800:220:1::1]:8443/hook"} {
+		if err := webhook.CheckTarget(context.Background(), url); err != nil {
+			t.Errorf("CheckTarget(%q) = %v, want nil", url, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+const (
+	EventTabung         = "transaksi.tabung"
+	EventTarik          = "transaksi.tarik"
+	EventTransferKeluar = "transaksi.transfer_keluar"
+	EventTransferMasuk  = "transaksi.transfer_masuk"
+	EventKoreksi        = "transaksi.koreksi"
+	EventBunga          = "transaksi.bunga"
+	EventBiaya          = "transaksi.biaya"
+	EventPajak          = "transaksi.pajak"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Events lists every event a subscription may filter on.
+var Events = []string{EventTabung, EventTarik, EventTransferKeluar, EventTransferMasuk, EventKoreksi, EventBunga, EventBiaya, EventPajak}
+
+func ValidEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign returns the value of the signature header for a payload sent at the
+// given unix timestamp. The timestamp is part of the signed content so a
+// receiver can reject replayed requests.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	expected := Sign(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func JoinEvents(events []string) string {
+	return strings.Join(events, ",")
+}
+
+func SplitEvents(events string) []string {
+	if events == "" {
+		return nil
+	}
+	return strings.Split(events, ",")
+}
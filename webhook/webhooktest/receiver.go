@@ -0,0 +1,100 @@
+// Package webhooktest provides an httptest-backed receiving server for
+// exercising webhook deliveries end to end.
+package webhooktest
+
+import (
+	"gobanking/webhook"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+)
+
+// Request is a single delivery observed by the Receiver.
+type Request struct {
+	Event          string
+	DeliveryID     string
+	Timestamp      int64
+	Signature      string
+	Body           []byte
+	SignatureValid bool
+	StatusCode     int
+}
+
+// Receiver records every webhook it receives and checks its signature
+// against Secret. It can be told to fail a number of requests to exercise
+// the retry path.
+type Receiver struct {
+	Secret string
+
+	server   *httptest.Server
+	mu       sync.Mutex
+	failNext int
+	requests []Request
+	received chan struct{}
+}
+
+func NewReceiver(secret string) *Receiver {
+	r := &Receiver{
+		Secret:   secret,
+		received: make(chan struct{}, 1024),
+	}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serve))
+	return r
+}
+
+func (r *Receiver) URL() string {
+	return r.server.URL
+}
+
+func (r *Receiver) Close() {
+	r.server.Close()
+}
+
+// FailNext makes the next n requests answer with 500.
+func (r *Receiver) FailNext(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failNext = n
+}
+
+func (r *Receiver) Requests() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Request(nil), r.requests...)
+}
+
+// Received is signalled once per request, successful or not.
+func (r *Receiver) Received() <-chan struct{} {
+	return r.received
+}
+
+func (r *Receiver) serve(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	timestamp, _ := strconv.ParseInt(req.Header.Get(webhook.HeaderTimestamp), 10, 64)
+	signature := req.Header.Get(webhook.HeaderSignature)
+
+	r.mu.Lock()
+	status := http.StatusOK
+	if r.failNext > 0 {
+		r.failNext--
+		status = http.StatusInternalServerError
+	}
+	r.requests = append(r.requests, Request{
+		Event:          req.Header.Get(webhook.HeaderEvent),
+		DeliveryID:     req.Header.Get(webhook.HeaderDelivery),
+		Timestamp:      timestamp,
+		Signature:      signature,
+		Body:           body,
+		SignatureValid: webhook.Verify(r.Secret, timestamp, body, signature),
+		StatusCode:     status,
+	})
+	r.mu.Unlock()
+
+	w.WriteHeader(status)
+	select {
+	case r.received <- struct{}{}:
+	default:
+	}
+}